@@ -0,0 +1,122 @@
+package controllers
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Threqt1/HACApi/app/models"
+	"github.com/Threqt1/HACApi/pkg/repository"
+	"github.com/Threqt1/HACApi/pkg/utils"
+	"github.com/Threqt1/HACApi/platform/calendar"
+	"github.com/gocolly/colly"
+	"github.com/gofiber/fiber/v2"
+)
+
+// GetAssignmentCalendar handles GET and POST requests to the assignment calendar endpoint.
+//
+//	@Description	Returns an iCalendar file with an all-day event, or a to-do if kind is "todo", on the due date of every assignment in the marking periods specified.
+//	@Description	If no marking periods are specified, the assignments of the current marking period are returned.
+//	@Description	Calendar apps can subscribe with a GET request, passing the params and a calendar token from POST /calendar/token as query parameters, like ?token=...&markingPeriods=1,2. Credentials are only accepted in POST bodies.
+//	@Description	Calendar tokens last a year, unless revoked with DELETE /calendar/token. Session tokens from /login are also accepted, but expire 24 hours after logging in, so subscriptions made with them stop working the next day.
+//	@Tags			calendar
+//	@Param			request	body	models.AssignmentCalendarRequestBody	false	"Body Params"
+//	@Param			Authorization	header	string	false	"Bearer calendar token from /calendar/token, or session token from /login, used instead of the credentials in the body"
+//	@Accept			json
+//	@Produce		text/calendar
+//	@Success		200	{string}	string
+//	@Failure		400	{object}	models.CalendarResponse
+//	@Router			/calendar/assignments.ics [get]
+//	@Router			/calendar/assignments.ics [post]
+func GetAssignmentCalendar(server *repository.Server, ctx *fiber.Ctx) error {
+	// Parse params.
+	params := new(models.AssignmentCalendarRequestBody)
+
+	// Check if parsing the params succeeded.
+	if err := utils.ParseCalendarParams(ctx, params); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(models.CalendarResponse{
+			HTTPError: utils.NewHTTPError(ctx, repository.ErrorBadBodyParams),
+		})
+	}
+
+	// Credentials are never taken from the query string, so GET requests need a token.
+	token := utils.GetCalendarToken(ctx)
+	if ctx.Method() == fiber.MethodGet {
+		params.BaseRequestBody = models.BaseRequestBody{}
+
+		if token == "" {
+			return ctx.Status(fiber.StatusUnauthorized).JSON(models.CalendarResponse{
+				HTTPError: utils.NewHTTPError(ctx, repository.ErrorInvalidSession),
+			})
+		}
+	}
+
+	// Fill in the credentials bound to the calendar or session token, if one was passed.
+	if token != "" {
+		credentials, err := utils.GetCalendarCredentials(server.Cache, token)
+
+		// Error out if the token is invalid or expired.
+		if err != nil {
+			return ctx.Status(fiber.StatusUnauthorized).JSON(models.CalendarResponse{
+				HTTPError: utils.NewHTTPError(ctx, repository.ErrorInvalidSession),
+			})
+		}
+
+		params.BaseRequestBody = credentials
+	}
+
+	// Verify the validity of the params.
+	if err := server.Validator.Struct(params); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(models.CalendarResponse{
+			HTTPError: utils.NewHTTPError(ctx, repository.ErrorBadBodyParams, utils.ValidationFields(err)...),
+		})
+	}
+
+	// Verify the base is an allowed HAC URL, and normalize it.
+	base, err := server.BaseURLs.Validate(params.Base)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(models.CalendarResponse{
+			HTTPError: utils.NewHTTPError(ctx, fmt.Errorf("%w: %s", repository.ErrorInvalidBase, err), "base"),
+		})
+	}
+	params.Base = base
+
+	// Tag the logs with the district and a hash of the user.
+	utils.SetLogIdentity(ctx, params.Username, params.Base)
+
+	// Form a cache key.
+	cacheKey := fmt.Sprintf("%s\n%s\n%s", params.Username, params.Password, params.Base)
+
+	// Try logging in, or grab the cached collector.
+	collector, err := server.Cache.GetOrLogin(ctx.UserContext(), cacheKey)
+
+	// Error out if the login fails.
+	if err != nil {
+		status, loginErr := utils.LoginErrorResponse(err)
+		return ctx.Status(status).JSON(models.CalendarResponse{
+			HTTPError: utils.NewHTTPError(ctx, loginErr),
+		})
+	}
+
+	// Get the classwork of the marking periods.
+	classworkParams := models.ClassworkRequestBody{
+		BaseRequestBody: params.BaseRequestBody,
+		MarkingPeriods:  params.MarkingPeriods,
+	}
+	classwork, err := utils.RetryOnExpiredSession(ctx.UserContext(), server.Cache, cacheKey, collector, func(collector *colly.Collector) ([]models.Classwork, error) {
+		classwork, _, err := server.Querier.GetClasswork(ctx.UserContext(), collector, classworkParams)
+		return classwork, err
+	})
+
+	// Check if getting the classwork succeeded.
+	if err != nil {
+		status, queryErr := utils.QueryErrorResponse(err)
+		return ctx.Status(status).JSON(models.CalendarResponse{
+			HTTPError: utils.NewHTTPError(ctx, queryErr),
+		})
+	}
+
+	// Return the assignments as a calendar.
+	utils.SetCalendarHeaders(ctx, "assignments.ics")
+	ctx.Status(fiber.StatusOK)
+	return calendar.Assignments(classwork, params.Kind, params.Username+"\n"+params.Base, time.Now()).Write(ctx)
+}
@@ -0,0 +1,172 @@
+package controllers
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Threqt1/HACApi/app/models"
+	"github.com/Threqt1/HACApi/app/queries"
+	"github.com/Threqt1/HACApi/pkg/repository"
+	"github.com/Threqt1/HACApi/pkg/utils"
+	"github.com/Threqt1/HACApi/platform/cache"
+	"github.com/bytedance/sonic"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/go-cmp/cmp"
+)
+
+// newTestCalendarServer sets up a testing server with a
+// calendar endpoint registered for GET and POST requests.
+func newTestCalendarServer(controller func(*repository.Server, *fiber.Ctx) error) *repository.Server {
+	server := &repository.Server{
+		App: fiber.New(fiber.Config{
+			JSONEncoder: sonic.Marshal,
+			JSONDecoder: sonic.Unmarshal,
+		}),
+		Querier:   queries.NewTestQuerier(),
+		Validator: utils.NewValidator(),
+		Cache:     cache.NewTestCache(),
+		BaseURLs:  utils.NewTestBaseURLPolicy(),
+	}
+
+	server.App.Get("/", utils.WrapController(server, controller))
+	server.App.Post("/", utils.WrapController(server, controller))
+
+	return server
+}
+
+// testCalendar_ExpectCalendar confirms a response is an iCalendar file.
+func testCalendar_ExpectCalendar(t *testing.T, name string, resp *http.Response) {
+	body, _ := io.ReadAll(resp.Body)
+
+	if resp.StatusCode != fiber.StatusOK || !strings.HasPrefix(resp.Header.Get(fiber.HeaderContentType), "text/calendar") {
+		t.Fatalf("Failed for %s, expected a calendar, got %d %s\n%s", name, resp.StatusCode, resp.Header.Get(fiber.HeaderContentType), body)
+	}
+	if !strings.HasPrefix(string(body), "BEGIN:VCALENDAR\r\n") || !strings.HasSuffix(string(body), "END:VCALENDAR\r\n") {
+		t.Fatalf("Failed for %s, expected a calendar, got\n%s", name, body)
+	}
+}
+
+// testCalendar_ExpectError confirms a response is the given error.
+func testCalendar_ExpectError(t *testing.T, name string, resp *http.Response, expected utils.ExpectedServerResponse[models.CalendarResponse]) {
+	resBody, _ := io.ReadAll(resp.Body)
+	res := models.CalendarResponse{}
+
+	sonic.Unmarshal(resBody, &res)
+
+	// Convert response to a comparable struct.
+	got := utils.ExpectedServerResponse[models.CalendarResponse]{
+		Status: resp.StatusCode,
+		Body:   res,
+	}
+
+	if diff := cmp.Diff(expected, got); diff != "" {
+		t.Fatalf("Failed for %s (-want, +got)\n%s", name, diff)
+	}
+}
+
+// Test if GetAssignmentCalendar() sends back a calendar
+// for a POST request with valid inputs.
+func TestGetAssignmentCalendar_Post(t *testing.T) {
+	server := newTestCalendarServer(GetAssignmentCalendar)
+
+	// Create request data.
+	bodyData := models.AssignmentCalendarRequestBody{
+		BaseRequestBody: models.BaseRequestBody{
+			Username: repository.FakeUsername,
+			Password: repository.FakePassword,
+			Base:     repository.FakeBase,
+		},
+		MarkingPeriods: []int{1, 2},
+		Kind:           models.CalendarKindTodo,
+	}
+	body, _ := sonic.Marshal(bodyData)
+
+	// Create a test request.
+	req := httptest.NewRequest("POST", "http://fake.url/", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	// Test the request.
+	resp, _ := server.App.Test(req)
+
+	testCalendar_ExpectCalendar(t, "GetAssignmentCalendar() Post", resp)
+}
+
+// Test if GetAssignmentCalendar() sends back a calendar for a GET
+// request with the session token in the query string, like calendar
+// apps subscribe with.
+func TestGetAssignmentCalendar_GetWithToken(t *testing.T) {
+	server := newTestCalendarServer(GetAssignmentCalendar)
+
+	// Create a test request.
+	req := httptest.NewRequest("GET", "http://fake.url/?token="+repository.FakeToken+"&markingPeriods=1,2&kind=event", nil)
+
+	// Test the request.
+	resp, _ := server.App.Test(req)
+
+	testCalendar_ExpectCalendar(t, "GetAssignmentCalendar() Get With Token", resp)
+}
+
+// Test if GetAssignmentCalendar() sends back a calendar for a GET
+// request with a calendar token in the query string.
+func TestGetAssignmentCalendar_GetWithCalendarToken(t *testing.T) {
+	server := newTestCalendarServer(GetAssignmentCalendar)
+
+	// Create a test request.
+	req := httptest.NewRequest("GET", "http://fake.url/?token="+repository.FakeCalendarToken, nil)
+
+	// Test the request.
+	resp, _ := server.App.Test(req)
+
+	testCalendar_ExpectCalendar(t, "GetAssignmentCalendar() Get With Calendar Token", resp)
+}
+
+// Test if GetAssignmentCalendar() refuses GET requests
+// passing credentials instead of a session token.
+func TestGetAssignmentCalendar_GetWithCredentials(t *testing.T) {
+	server := newTestCalendarServer(GetAssignmentCalendar)
+
+	// Create a test request.
+	req := httptest.NewRequest("GET", "http://fake.url/?username="+repository.FakeUsername+"&password="+repository.FakePassword+"&base="+repository.FakeBase, nil)
+
+	// Test the request.
+	resp, _ := server.App.Test(req)
+
+	testCalendar_ExpectError(t, "GetAssignmentCalendar() Get With Credentials", resp, utils.ExpectedServerResponse[models.CalendarResponse]{
+		Status: fiber.StatusUnauthorized,
+		Body: models.CalendarResponse{
+			HTTPError: models.HTTPError{
+				Error:   true,
+				Message: repository.ErrorInvalidSession.Error(),
+				Code:    models.ErrorCodeSessionExpired,
+			},
+		},
+	})
+}
+
+// Test if GetAssignmentCalendar() errors out
+// if the kind of entries is invalid.
+func TestGetAssignmentCalendar_InvalidKind(t *testing.T) {
+	server := newTestCalendarServer(GetAssignmentCalendar)
+
+	// Create a test request.
+	req := httptest.NewRequest("GET", "http://fake.url/?token="+repository.FakeToken+"&kind=journal", nil)
+
+	// Test the request.
+	resp, _ := server.App.Test(req)
+
+	testCalendar_ExpectError(t, "GetAssignmentCalendar() Invalid Kind", resp, utils.ExpectedServerResponse[models.CalendarResponse]{
+		Status: fiber.StatusBadRequest,
+		Body: models.CalendarResponse{
+			HTTPError: models.HTTPError{
+				Error:   true,
+				Message: repository.ErrorBadBodyParams.Error(),
+				Code:    models.ErrorCodeBadRequestField,
+				Fields:  []string{"kind"},
+			},
+		},
+	})
+}
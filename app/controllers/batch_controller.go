@@ -0,0 +1,207 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/Threqt1/HACApi/app/models"
+	"github.com/Threqt1/HACApi/pkg/repository"
+	"github.com/Threqt1/HACApi/pkg/utils"
+	"github.com/gocolly/colly"
+	"github.com/gofiber/fiber/v2"
+)
+
+// PostBatch handles POST requests to the batch endpoint.
+//
+//	@Description	Returns several resources at once, fetched concurrently under a single login.
+//	@Description	Each request names a resource (classwork, ipr, schedule, reportcard or transcript), along with the marking periods for classwork or the date for ipr.
+//	@Description	Results are returned in the order requested. A resource failing doesn't fail the others, its result carries the error and the status code its own endpoint would have responded with.
+//	@Description	If the normalize parameter is true, it applies to every resource which supports it.
+//	@Tags			batch
+//	@Param			request	body	models.BatchRequestBody	false	"Body Params"
+//	@Param			Authorization	header	string	false	"Bearer session token from /login, used instead of the credentials in the body"
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{object}	models.BatchResponse
+//	@Router			/batch [post]
+func PostBatch(server *repository.Server, ctx *fiber.Ctx) error {
+	// Parse body.
+	params := new(models.BatchRequestBody)
+
+	// Check if parsing body parameters succeeded.
+	if err := utils.ParseBody(ctx, params); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(models.BatchResponse{
+			HTTPError: utils.NewHTTPError(ctx, repository.ErrorBadBodyParams),
+		})
+	}
+
+	// Fill in the credentials bound to the session token, if one was passed.
+	if token := utils.GetBearerToken(ctx); token != "" {
+		credentials, err := server.Cache.GetSession(token)
+
+		// Error out if the session is invalid or expired.
+		if err != nil {
+			return ctx.Status(fiber.StatusUnauthorized).JSON(models.BatchResponse{
+				HTTPError: utils.NewHTTPError(ctx, repository.ErrorInvalidSession),
+			})
+		}
+
+		params.BaseRequestBody = credentials
+	}
+
+	// Verify the validity of the body parameters.
+	valid := true
+	var fields []string
+
+	if err := server.Validator.Struct(params); err != nil {
+		valid = false
+		fields = utils.ValidationFields(err)
+	}
+
+	// Confirm the IPR dates are valid.
+	for i, request := range params.Requests {
+		if _, err := time.Parse("01/02/2006", request.Date); request.Resource == models.BatchResourceIPR && len(request.Date) > 0 && err != nil {
+			valid = false
+			fields = append(fields, fmt.Sprintf("requests[%d].date", i))
+		}
+	}
+
+	// If they aren't valid, send back an error.
+	if !valid {
+		return ctx.Status(fiber.StatusBadRequest).JSON(models.BatchResponse{
+			HTTPError: utils.NewHTTPError(ctx, repository.ErrorBadBodyParams, fields...),
+		})
+	}
+
+	// Verify the base is an allowed HAC URL, and normalize it.
+	base, err := server.BaseURLs.Validate(params.Base)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(models.BatchResponse{
+			HTTPError: utils.NewHTTPError(ctx, fmt.Errorf("%w: %s", repository.ErrorInvalidBase, err), "base"),
+		})
+	}
+	params.Base = base
+
+	// Tag the logs with the district and a hash of the user.
+	utils.SetLogIdentity(ctx, params.Username, params.Base)
+
+	// Form a cache key.
+	cacheKey := fmt.Sprintf("%s\n%s\n%s", params.Username, params.Password, params.Base)
+
+	// Try logging in, or grab the cached collector.
+	collector, err := server.Cache.GetOrLogin(ctx.UserContext(), cacheKey)
+
+	// Error out if the login fails.
+	if err != nil {
+		status, loginErr := utils.LoginErrorResponse(err)
+		return ctx.Status(status).JSON(models.BatchResponse{
+			HTTPError: utils.NewHTTPError(ctx, loginErr),
+		})
+	}
+
+	// Fetch every resource concurrently with the same collector.
+	results := make([]models.BatchResult, len(params.Requests))
+	errs := make([]error, len(params.Requests))
+	pending := make([]int, len(params.Requests))
+	for i := range pending {
+		pending[i] = i
+	}
+
+	runBatch(ctx.UserContext(), server, collector, params, pending, results, errs)
+
+	// If the session expired, log in again once, and retry the resources which hit it.
+	expired := make([]int, 0)
+	for i, err := range errs {
+		if errors.Is(err, utils.ErrorSessionExpired) {
+			expired = append(expired, i)
+		}
+	}
+
+	if len(expired) > 0 {
+		collector, err := server.Cache.Relogin(ctx.UserContext(), cacheKey)
+
+		if err != nil {
+			for _, i := range expired {
+				errs[i] = err
+			}
+		} else {
+			runBatch(ctx.UserContext(), server, collector, params, expired, results, errs)
+		}
+	}
+
+	// Attach the error of each resource which failed.
+	for i, err := range errs {
+		results[i].Resource = params.Requests[i].Resource
+		results[i].Status = fiber.StatusOK
+
+		if err != nil {
+			status, queryErr := utils.QueryErrorResponse(err)
+			results[i].Status = status
+			results[i].HTTPError = utils.NewHTTPError(ctx, queryErr)
+		}
+	}
+
+	// Return the results.
+	return ctx.Status(fiber.StatusOK).JSON(models.BatchResponse{
+		Results: results,
+	})
+}
+
+// runBatch fetches the requested resources at the given indexes concurrently,
+// storing each one's result and error at its index.
+func runBatch(ctx context.Context, server *repository.Server, collector *colly.Collector, params *models.BatchRequestBody, indexes []int, results []models.BatchResult, errs []error) {
+	var wg sync.WaitGroup
+
+	for _, i := range indexes {
+		wg.Add(1)
+
+		go func(i int) {
+			defer wg.Done()
+			results[i], errs[i] = fetchBatchResource(ctx, server, collector, params, params.Requests[i])
+		}(i)
+	}
+
+	wg.Wait()
+}
+
+// fetchBatchResource fetches a single resource requested in a batch.
+func fetchBatchResource(ctx context.Context, server *repository.Server, collector *colly.Collector, params *models.BatchRequestBody, request models.BatchResourceRequest) (models.BatchResult, error) {
+	var result models.BatchResult
+	var err error
+
+	switch request.Resource {
+	case models.BatchResourceClasswork:
+		result.Classwork, _, err = server.Querier.GetClasswork(ctx, collector, models.ClassworkRequestBody{
+			BaseRequestBody:      params.BaseRequestBody,
+			NormalizeRequestBody: params.NormalizeRequestBody,
+			MarkingPeriods:       request.MarkingPeriods,
+		})
+	case models.BatchResourceIPR:
+		result.IPR, _, err = server.Querier.GetIPR(ctx, collector, models.IprRequestBody{
+			BaseRequestBody:      params.BaseRequestBody,
+			NormalizeRequestBody: params.NormalizeRequestBody,
+			Date:                 request.Date,
+		})
+	case models.BatchResourceSchedule:
+		result.Schedule, err = server.Querier.GetSchedule(ctx, collector, models.ScheduleRequestBody{
+			BaseRequestBody: params.BaseRequestBody,
+		})
+	case models.BatchResourceReportCard:
+		result.ReportCard, err = server.Querier.GetReportCard(ctx, collector, models.ReportCardRequestBody{
+			BaseRequestBody:      params.BaseRequestBody,
+			NormalizeRequestBody: params.NormalizeRequestBody,
+		})
+	case models.BatchResourceTranscript:
+		result.Transcript, err = server.Querier.GetTranscript(ctx, collector, models.TranscriptRequestBody{
+			BaseRequestBody:      params.BaseRequestBody,
+			NormalizeRequestBody: params.NormalizeRequestBody,
+		})
+	default:
+		err = repository.ErrorBadBodyParams
+	}
+
+	return result, err
+}
@@ -0,0 +1,532 @@
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/Threqt1/HACApi/app/models"
+	"github.com/Threqt1/HACApi/app/queries"
+	"github.com/Threqt1/HACApi/pkg/repository"
+	"github.com/Threqt1/HACApi/pkg/utils"
+	"github.com/Threqt1/HACApi/platform/cache"
+	"github.com/bytedance/sonic"
+	"github.com/gocolly/colly"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/go-cmp/cmp"
+)
+
+// testBatch_ExpiringQuerier is a test querier whose
+// schedule query hits an expired session the first time.
+type testBatch_ExpiringQuerier struct {
+	queries.TestQuerier
+	Calls *int32 // The amount of schedule queries made.
+}
+
+func (querier testBatch_ExpiringQuerier) GetSchedule(ctx context.Context, collector *colly.Collector, params models.ScheduleRequestBody) ([]models.Schedule, error) {
+	if atomic.AddInt32(querier.Calls, 1) == 1 {
+		return nil, utils.ErrorSessionExpired
+	}
+	return querier.TestQuerier.GetSchedule(ctx, collector, params)
+}
+
+// Test if PostBatch() returns every requested resource,
+// in the order requested.
+func TestPostBatch_AllValidInputs(t *testing.T) {
+	// Set up testing server.
+	server := &repository.Server{
+		App: fiber.New(fiber.Config{
+			JSONEncoder: sonic.Marshal,
+			JSONDecoder: sonic.Unmarshal,
+		}),
+		Querier:   queries.NewTestQuerier(),
+		Validator: utils.NewValidator(),
+		Cache:     cache.NewTestCache(),
+		BaseURLs:  utils.NewTestBaseURLPolicy(),
+	}
+
+	// Register PostBatch() as the handler
+	// for the default route.
+	server.App.Post("/", utils.WrapController(server, PostBatch))
+
+	// Create request data.
+	bodyData := models.BatchRequestBody{
+		BaseRequestBody: models.BaseRequestBody{
+			Username: repository.FakeUsername,
+			Password: repository.FakePassword,
+			Base:     repository.FakeBase,
+		},
+		Requests: []models.BatchResourceRequest{
+			{Resource: models.BatchResourceClasswork, MarkingPeriods: []int{1, 2}},
+			{Resource: models.BatchResourceIPR, Date: "09/06/2022"},
+			{Resource: models.BatchResourceSchedule},
+			{Resource: models.BatchResourceReportCard},
+			{Resource: models.BatchResourceTranscript},
+		},
+	}
+	body, _ := sonic.Marshal(bodyData)
+
+	// Create a test request.
+	req := httptest.NewRequest("POST", "http://fake.url/", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	// Test the request.
+	resp, _ := server.App.Test(req)
+
+	// Parse the body.
+	resBody, _ := io.ReadAll(resp.Body)
+	res := models.BatchResponse{}
+
+	sonic.Unmarshal(resBody, &res)
+
+	// Make expected body.
+	expected := utils.ExpectedServerResponse[models.BatchResponse]{
+		Status: fiber.StatusOK,
+		Body: models.BatchResponse{
+			Results: []models.BatchResult{
+				{Resource: models.BatchResourceClasswork, Status: fiber.StatusOK, Classwork: []models.Classwork{{}, {}}},
+				{Resource: models.BatchResourceIPR, Status: fiber.StatusOK, IPR: []models.IPR{{}}},
+				{Resource: models.BatchResourceSchedule, Status: fiber.StatusOK, Schedule: []models.Schedule{{}}},
+				{Resource: models.BatchResourceReportCard, Status: fiber.StatusOK, ReportCard: []models.ReportCard{{}}},
+				{Resource: models.BatchResourceTranscript, Status: fiber.StatusOK, Transcript: []models.Transcript{{}}},
+			},
+		},
+	}
+
+	// Convert response to a comparable struct.
+	got := utils.ExpectedServerResponse[models.BatchResponse]{
+		Status: resp.StatusCode,
+		Body:   res,
+	}
+
+	// Test.
+	if diff := cmp.Diff(expected, got); diff != "" {
+		t.Fatalf("Failed for PostBatch() All Valid Inputs (-want, +got)\n%s", diff)
+	}
+}
+
+// Test if PostBatch() logs in again and retries
+// the resources which hit an expired session.
+func TestPostBatch_ExpiredSession(t *testing.T) {
+	// Set up testing server.
+	server := &repository.Server{
+		App: fiber.New(fiber.Config{
+			JSONEncoder: sonic.Marshal,
+			JSONDecoder: sonic.Unmarshal,
+		}),
+		Querier:   testBatch_ExpiringQuerier{Calls: new(int32)},
+		Validator: utils.NewValidator(),
+		Cache:     cache.NewTestCache(),
+		BaseURLs:  utils.NewTestBaseURLPolicy(),
+	}
+
+	// Register PostBatch() as the handler
+	// for the default route.
+	server.App.Post("/", utils.WrapController(server, PostBatch))
+
+	// Create request data.
+	bodyData := models.BatchRequestBody{
+		BaseRequestBody: models.BaseRequestBody{
+			Username: repository.FakeUsername,
+			Password: repository.FakePassword,
+			Base:     repository.FakeBase,
+		},
+		Requests: []models.BatchResourceRequest{
+			{Resource: models.BatchResourceSchedule},
+			{Resource: models.BatchResourceTranscript},
+		},
+	}
+	body, _ := sonic.Marshal(bodyData)
+
+	// Create a test request.
+	req := httptest.NewRequest("POST", "http://fake.url/", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	// Test the request.
+	resp, _ := server.App.Test(req)
+
+	// Parse the body.
+	resBody, _ := io.ReadAll(resp.Body)
+	res := models.BatchResponse{}
+
+	sonic.Unmarshal(resBody, &res)
+
+	// Make expected body.
+	expected := utils.ExpectedServerResponse[models.BatchResponse]{
+		Status: fiber.StatusOK,
+		Body: models.BatchResponse{
+			Results: []models.BatchResult{
+				{Resource: models.BatchResourceSchedule, Status: fiber.StatusOK, Schedule: []models.Schedule{{}}},
+				{Resource: models.BatchResourceTranscript, Status: fiber.StatusOK, Transcript: []models.Transcript{{}}},
+			},
+		},
+	}
+
+	// Convert response to a comparable struct.
+	got := utils.ExpectedServerResponse[models.BatchResponse]{
+		Status: resp.StatusCode,
+		Body:   res,
+	}
+
+	// Test.
+	if diff := cmp.Diff(expected, got); diff != "" {
+		t.Fatalf("Failed for PostBatch() Expired Session (-want, +got)\n%s", diff)
+	}
+}
+
+// Test if PostBatch() errors out due to
+// an unknown resource.
+func TestPostBatch_BadBodyParams_UnknownResource(t *testing.T) {
+	// Set up testing server.
+	server := &repository.Server{
+		App: fiber.New(fiber.Config{
+			JSONEncoder: sonic.Marshal,
+			JSONDecoder: sonic.Unmarshal,
+		}),
+		Querier:   queries.NewTestQuerier(),
+		Validator: utils.NewValidator(),
+		Cache:     cache.NewTestCache(),
+		BaseURLs:  utils.NewTestBaseURLPolicy(),
+	}
+
+	// Register PostBatch() as the handler
+	// for the default route.
+	server.App.Post("/", utils.WrapController(server, PostBatch))
+
+	// Create request data.
+	bodyData := models.BatchRequestBody{
+		BaseRequestBody: models.BaseRequestBody{
+			Username: repository.FakeUsername,
+			Password: repository.FakePassword,
+			Base:     repository.FakeBase,
+		},
+		Requests: []models.BatchResourceRequest{
+			{Resource: "weekview"},
+		},
+	}
+	body, _ := sonic.Marshal(bodyData)
+
+	// Create a test request.
+	req := httptest.NewRequest("POST", "http://fake.url/", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	// Test the request.
+	resp, _ := server.App.Test(req)
+
+	// Parse the body.
+	resBody, _ := io.ReadAll(resp.Body)
+	res := models.BatchResponse{}
+
+	sonic.Unmarshal(resBody, &res)
+
+	// Make expected body.
+	expected := utils.ExpectedServerResponse[models.BatchResponse]{
+		Status: fiber.StatusBadRequest,
+		Body: models.BatchResponse{
+			HTTPError: models.HTTPError{
+				Error:   true,
+				Message: repository.ErrorBadBodyParams.Error(),
+				Code:    models.ErrorCodeBadRequestField,
+				Fields:  []string{"requests[0].resource"},
+			},
+		},
+	}
+
+	// Convert response to a comparable struct.
+	got := utils.ExpectedServerResponse[models.BatchResponse]{
+		Status: resp.StatusCode,
+		Body:   res,
+	}
+
+	// Test.
+	if diff := cmp.Diff(expected, got); diff != "" {
+		t.Fatalf("Failed for PostBatch() Bad Body Params, Unknown Resource (-want, +got)\n%s", diff)
+	}
+}
+
+// Test if PostBatch() errors out due to
+// an invalid IPR date.
+func TestPostBatch_BadBodyParams_InvalidDate(t *testing.T) {
+	// Set up testing server.
+	server := &repository.Server{
+		App: fiber.New(fiber.Config{
+			JSONEncoder: sonic.Marshal,
+			JSONDecoder: sonic.Unmarshal,
+		}),
+		Querier:   queries.NewTestQuerier(),
+		Validator: utils.NewValidator(),
+		Cache:     cache.NewTestCache(),
+		BaseURLs:  utils.NewTestBaseURLPolicy(),
+	}
+
+	// Register PostBatch() as the handler
+	// for the default route.
+	server.App.Post("/", utils.WrapController(server, PostBatch))
+
+	// Create request data.
+	bodyData := models.BatchRequestBody{
+		BaseRequestBody: models.BaseRequestBody{
+			Username: repository.FakeUsername,
+			Password: repository.FakePassword,
+			Base:     repository.FakeBase,
+		},
+		Requests: []models.BatchResourceRequest{
+			{Resource: models.BatchResourceIPR, Date: "2022-09-06"},
+		},
+	}
+	body, _ := sonic.Marshal(bodyData)
+
+	// Create a test request.
+	req := httptest.NewRequest("POST", "http://fake.url/", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	// Test the request.
+	resp, _ := server.App.Test(req)
+
+	// Parse the body.
+	resBody, _ := io.ReadAll(resp.Body)
+	res := models.BatchResponse{}
+
+	sonic.Unmarshal(resBody, &res)
+
+	// Make expected body.
+	expected := utils.ExpectedServerResponse[models.BatchResponse]{
+		Status: fiber.StatusBadRequest,
+		Body: models.BatchResponse{
+			HTTPError: models.HTTPError{
+				Error:   true,
+				Message: repository.ErrorBadBodyParams.Error(),
+				Code:    models.ErrorCodeBadRequestField,
+				Fields:  []string{"requests[0].date"},
+			},
+		},
+	}
+
+	// Convert response to a comparable struct.
+	got := utils.ExpectedServerResponse[models.BatchResponse]{
+		Status: resp.StatusCode,
+		Body:   res,
+	}
+
+	// Test.
+	if diff := cmp.Diff(expected, got); diff != "" {
+		t.Fatalf("Failed for PostBatch() Bad Body Params, Invalid Date (-want, +got)\n%s", diff)
+	}
+}
+
+// Test if PostBatch() errors out due to
+// no resources being requested.
+func TestPostBatch_BadBodyParams_NoRequests(t *testing.T) {
+	// Set up testing server.
+	server := &repository.Server{
+		App: fiber.New(fiber.Config{
+			JSONEncoder: sonic.Marshal,
+			JSONDecoder: sonic.Unmarshal,
+		}),
+		Querier:   queries.NewTestQuerier(),
+		Validator: utils.NewValidator(),
+		Cache:     cache.NewTestCache(),
+		BaseURLs:  utils.NewTestBaseURLPolicy(),
+	}
+
+	// Register PostBatch() as the handler
+	// for the default route.
+	server.App.Post("/", utils.WrapController(server, PostBatch))
+
+	// Create request data.
+	bodyData := models.BatchRequestBody{
+		BaseRequestBody: models.BaseRequestBody{
+			Username: repository.FakeUsername,
+			Password: repository.FakePassword,
+			Base:     repository.FakeBase,
+		},
+		Requests: []models.BatchResourceRequest{},
+	}
+	body, _ := sonic.Marshal(bodyData)
+
+	// Create a test request.
+	req := httptest.NewRequest("POST", "http://fake.url/", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	// Test the request.
+	resp, _ := server.App.Test(req)
+
+	// Parse the body.
+	resBody, _ := io.ReadAll(resp.Body)
+	res := models.BatchResponse{}
+
+	sonic.Unmarshal(resBody, &res)
+
+	// Make expected body.
+	expected := utils.ExpectedServerResponse[models.BatchResponse]{
+		Status: fiber.StatusBadRequest,
+		Body: models.BatchResponse{
+			HTTPError: models.HTTPError{
+				Error:   true,
+				Message: repository.ErrorBadBodyParams.Error(),
+				Code:    models.ErrorCodeBadRequestField,
+				Fields:  []string{"requests"},
+			},
+		},
+	}
+
+	// Convert response to a comparable struct.
+	got := utils.ExpectedServerResponse[models.BatchResponse]{
+		Status: resp.StatusCode,
+		Body:   res,
+	}
+
+	// Test.
+	if diff := cmp.Diff(expected, got); diff != "" {
+		t.Fatalf("Failed for PostBatch() Bad Body Params, No Requests (-want, +got)\n%s", diff)
+	}
+}
+
+// Test if PostBatch() errors out due to
+// invalid credentials.
+func TestPostBatch_InvalidCredentials(t *testing.T) {
+	// Set up testing server.
+	server := &repository.Server{
+		App: fiber.New(fiber.Config{
+			JSONEncoder: sonic.Marshal,
+			JSONDecoder: sonic.Unmarshal,
+		}),
+		Querier:   queries.NewTestQuerier(),
+		Validator: utils.NewValidator(),
+		Cache:     cache.NewTestCache(),
+		BaseURLs:  utils.NewTestBaseURLPolicy(),
+	}
+
+	// Register PostBatch() as the handler
+	// for the default route.
+	server.App.Post("/", utils.WrapController(server, PostBatch))
+
+	// Create request data.
+	bodyData := models.BatchRequestBody{
+		BaseRequestBody: models.BaseRequestBody{
+			Username: "bad username",
+			Password: repository.FakePassword,
+			Base:     repository.FakeBase,
+		},
+		Requests: []models.BatchResourceRequest{
+			{Resource: models.BatchResourceSchedule},
+		},
+	}
+	body, _ := sonic.Marshal(bodyData)
+
+	// Create a test request.
+	req := httptest.NewRequest("POST", "http://fake.url/", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	// Test the request.
+	resp, _ := server.App.Test(req)
+
+	// Parse the body.
+	resBody, _ := io.ReadAll(resp.Body)
+	res := models.BatchResponse{}
+
+	sonic.Unmarshal(resBody, &res)
+
+	// Make expected body.
+	expected := utils.ExpectedServerResponse[models.BatchResponse]{
+		Status: fiber.StatusBadRequest,
+		Body: models.BatchResponse{
+			HTTPError: models.HTTPError{
+				Error:   true,
+				Message: repository.ErrorInvalidAuthentication.Error(),
+				Code:    models.ErrorCodeInvalidCredentials,
+			},
+		},
+	}
+
+	// Convert response to a comparable struct.
+	got := utils.ExpectedServerResponse[models.BatchResponse]{
+		Status: resp.StatusCode,
+		Body:   res,
+	}
+
+	// Test.
+	if diff := cmp.Diff(expected, got); diff != "" {
+		t.Fatalf("Failed for PostBatch() Invalid Credentials (-want, +got)\n%s", diff)
+	}
+}
+
+// Test if PostBatch() returns an error for
+// each resource which failed.
+func TestPostBatch_InternalError(t *testing.T) {
+	// Set up testing server.
+	server := &repository.Server{
+		App: fiber.New(fiber.Config{
+			JSONEncoder: sonic.Marshal,
+			JSONDecoder: sonic.Unmarshal,
+		}),
+		Querier:   queries.NewTestErrorQuerier(),
+		Validator: utils.NewValidator(),
+		Cache:     cache.NewTestCache(),
+		BaseURLs:  utils.NewTestBaseURLPolicy(),
+	}
+
+	// Register PostBatch() as the handler
+	// for the default route.
+	server.App.Post("/", utils.WrapController(server, PostBatch))
+
+	// Create request data.
+	bodyData := models.BatchRequestBody{
+		BaseRequestBody: models.BaseRequestBody{
+			Username: repository.FakeUsername,
+			Password: repository.FakePassword,
+			Base:     repository.FakeBase,
+		},
+		Requests: []models.BatchResourceRequest{
+			{Resource: models.BatchResourceSchedule},
+			{Resource: models.BatchResourceTranscript},
+		},
+	}
+	body, _ := sonic.Marshal(bodyData)
+
+	// Create a test request.
+	req := httptest.NewRequest("POST", "http://fake.url/", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	// Test the request.
+	resp, _ := server.App.Test(req)
+
+	// Parse the body.
+	resBody, _ := io.ReadAll(resp.Body)
+	res := models.BatchResponse{}
+
+	sonic.Unmarshal(resBody, &res)
+
+	// Make expected body.
+	expected := utils.ExpectedServerResponse[models.BatchResponse]{
+		Status: fiber.StatusOK,
+		Body: models.BatchResponse{
+			Results: []models.BatchResult{
+				{
+					HTTPError: models.HTTPError{Error: true, Message: repository.ErrorInternalError.Error(), Code: models.ErrorCodeInternal},
+					Resource:  models.BatchResourceSchedule,
+					Status:    fiber.StatusInternalServerError,
+				},
+				{
+					HTTPError: models.HTTPError{Error: true, Message: repository.ErrorInternalError.Error(), Code: models.ErrorCodeInternal},
+					Resource:  models.BatchResourceTranscript,
+					Status:    fiber.StatusInternalServerError,
+				},
+			},
+		},
+	}
+
+	// Convert response to a comparable struct.
+	got := utils.ExpectedServerResponse[models.BatchResponse]{
+		Status: resp.StatusCode,
+		Body:   res,
+	}
+
+	// Test.
+	if diff := cmp.Diff(expected, got); diff != "" {
+		t.Fatalf("Failed for PostBatch() Internal Error (-want, +got)\n%s", diff)
+	}
+}
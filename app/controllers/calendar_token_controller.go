@@ -0,0 +1,120 @@
+package controllers
+
+import (
+	"fmt"
+
+	"github.com/Threqt1/HACApi/app/models"
+	"github.com/Threqt1/HACApi/pkg/repository"
+	"github.com/Threqt1/HACApi/pkg/utils"
+	"github.com/gofiber/fiber/v2"
+)
+
+// PostCalendarToken handles POST requests to the calendar token endpoint.
+//
+//	@Description	Mints a calendar token, which calendar apps can subscribe to the calendar endpoints with as the token query parameter.
+//	@Description	Calendar tokens are only accepted by the calendar endpoints, and last a year, unlike session tokens which expire 24 hours after /login. They can be revoked with DELETE /calendar/token.
+//	@Tags			calendar
+//	@Param			request	body	models.CalendarTokenRequestBody	false	"Body Params"
+//	@Param			Authorization	header	string	false	"Bearer session token from /login, used instead of the credentials in the body"
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{object}	models.CalendarTokenResponse
+//	@Router			/calendar/token [post]
+func PostCalendarToken(server *repository.Server, ctx *fiber.Ctx) error {
+	// Parse body.
+	params := new(models.CalendarTokenRequestBody)
+
+	// Check if parsing was successful.
+	if err := utils.ParseBody(ctx, params); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(models.CalendarTokenResponse{
+			HTTPError: utils.NewHTTPError(ctx, repository.ErrorBadBodyParams),
+		})
+	}
+
+	// Fill in the credentials bound to the session token, if one was passed.
+	if token := utils.GetBearerToken(ctx); token != "" {
+		credentials, err := server.Cache.GetSession(token)
+
+		// Error out if the session is invalid or expired.
+		if err != nil {
+			return ctx.Status(fiber.StatusUnauthorized).JSON(models.CalendarTokenResponse{
+				HTTPError: utils.NewHTTPError(ctx, repository.ErrorInvalidSession),
+			})
+		}
+
+		params.BaseRequestBody = credentials
+	}
+
+	// Verify the validity of the body params.
+	if err := server.Validator.Struct(params); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(models.CalendarTokenResponse{
+			HTTPError: utils.NewHTTPError(ctx, repository.ErrorBadBodyParams, utils.ValidationFields(err)...),
+		})
+	}
+
+	// Verify the base is an allowed HAC URL, and normalize it.
+	base, err := server.BaseURLs.Validate(params.Base)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(models.CalendarTokenResponse{
+			HTTPError: utils.NewHTTPError(ctx, fmt.Errorf("%w: %s", repository.ErrorInvalidBase, err), "base"),
+		})
+	}
+	params.Base = base
+
+	// Tag the logs with the district and a hash of the user.
+	utils.SetLogIdentity(ctx, params.Username, params.Base)
+
+	// Form a cache key.
+	cacheKey := fmt.Sprintf("%s\n%s\n%s", params.Username, params.Password, params.Base)
+
+	// Log in, so calendar tokens can't be minted for credentials which don't work.
+	if _, err := server.Cache.GetOrLogin(ctx.UserContext(), cacheKey); err != nil {
+		status, loginErr := utils.LoginErrorResponse(err)
+		return ctx.Status(status).JSON(models.CalendarTokenResponse{
+			HTTPError: utils.NewHTTPError(ctx, loginErr),
+		})
+	}
+
+	// Mint a calendar token for the credentials.
+	session, err := server.Cache.NewCalendarSession(params.BaseRequestBody)
+	if err != nil {
+		return ctx.Status(fiber.StatusInternalServerError).JSON(models.CalendarTokenResponse{
+			HTTPError: utils.NewHTTPError(ctx, repository.ErrorInternalError),
+		})
+	}
+
+	// Return the calendar token.
+	return ctx.Status(fiber.StatusOK).JSON(models.CalendarTokenResponse{
+		CalendarToken: &models.CalendarToken{Token: session.Token, Expires: session.Expires},
+	})
+}
+
+// DeleteCalendarToken handles DELETE requests to the calendar token endpoint.
+//
+//	@Description	Revokes the calendar token passed in the "Authorization: Bearer" header, so calendar apps can't subscribe with it anymore.
+//	@Tags			calendar
+//	@Param			Authorization	header	string	true	"Bearer calendar token from /calendar/token"
+//	@Produce		json
+//	@Success		200	{object}	models.CalendarTokenResponse
+//	@Router			/calendar/token [delete]
+func DeleteCalendarToken(server *repository.Server, ctx *fiber.Ctx) error {
+	// Get the calendar token.
+	token := utils.GetBearerToken(ctx)
+
+	// Check if a token was passed.
+	if token == "" {
+		return ctx.Status(fiber.StatusUnauthorized).JSON(models.CalendarTokenResponse{
+			HTTPError: utils.NewHTTPError(ctx, repository.ErrorInvalidSession),
+		})
+	}
+
+	// Revoke the calendar token.
+	if err := server.Cache.DeleteCalendarSession(token); err != nil {
+		return ctx.Status(fiber.StatusUnauthorized).JSON(models.CalendarTokenResponse{
+			HTTPError: utils.NewHTTPError(ctx, repository.ErrorInvalidSession),
+		})
+	}
+
+	// Confirm the revocation.
+	return ctx.Status(fiber.StatusOK).JSON(models.CalendarTokenResponse{})
+}
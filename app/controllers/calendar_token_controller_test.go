@@ -0,0 +1,151 @@
+package controllers
+
+import (
+	"bytes"
+	"io"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Threqt1/HACApi/app/models"
+	"github.com/Threqt1/HACApi/app/queries"
+	"github.com/Threqt1/HACApi/pkg/repository"
+	"github.com/Threqt1/HACApi/pkg/utils"
+	"github.com/Threqt1/HACApi/platform/cache"
+	"github.com/bytedance/sonic"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/go-cmp/cmp"
+)
+
+// newTestCalendarTokenServer sets up a testing server with
+// the calendar token endpoints registered.
+func newTestCalendarTokenServer() *repository.Server {
+	server := &repository.Server{
+		App: fiber.New(fiber.Config{
+			JSONEncoder: sonic.Marshal,
+			JSONDecoder: sonic.Unmarshal,
+		}),
+		Querier:   queries.NewTestQuerier(),
+		Validator: utils.NewValidator(),
+		Cache:     cache.NewTestCache(),
+		BaseURLs:  utils.NewTestBaseURLPolicy(),
+	}
+
+	server.App.Post("/", utils.WrapController(server, PostCalendarToken))
+	server.App.Delete("/", utils.WrapController(server, DeleteCalendarToken))
+
+	return server
+}
+
+// sendTestCalendarToken sends a request to a testing server.
+func sendTestCalendarToken(server *repository.Server, method string, body []byte, token string) utils.ExpectedServerResponse[models.CalendarTokenResponse] {
+	// Create a test request.
+	req := httptest.NewRequest(method, "http://fake.url/", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	// Test the request.
+	resp, _ := server.App.Test(req)
+
+	// Parse the body.
+	resBody, _ := io.ReadAll(resp.Body)
+	res := models.CalendarTokenResponse{}
+
+	sonic.Unmarshal(resBody, &res)
+
+	return utils.ExpectedServerResponse[models.CalendarTokenResponse]{
+		Status: resp.StatusCode,
+		Body:   res,
+	}
+}
+
+// Test if PostCalendarToken() mints a calendar token
+// given all valid inputs.
+func TestPostCalendarToken_AllValidInputs(t *testing.T) {
+	server := newTestCalendarTokenServer()
+
+	// Create request data.
+	body, _ := sonic.Marshal(models.CalendarTokenRequestBody{
+		BaseRequestBody: models.BaseRequestBody{
+			Username: repository.FakeUsername,
+			Password: repository.FakePassword,
+			Base:     repository.FakeBase,
+		},
+	})
+
+	got := sendTestCalendarToken(server, "POST", body, "")
+
+	// Make expected body.
+	expected := utils.ExpectedServerResponse[models.CalendarTokenResponse]{
+		Status: fiber.StatusOK,
+		Body: models.CalendarTokenResponse{
+			CalendarToken: &models.CalendarToken{Token: repository.FakeCalendarToken},
+		},
+	}
+
+	// Test.
+	if diff := cmp.Diff(expected, got); diff != "" {
+		t.Fatalf("Failed for PostCalendarToken() All Valid Inputs (-want, +got)\n%s", diff)
+	}
+}
+
+// Test if PostCalendarToken() errors out
+// with an invalid session token.
+func TestPostCalendarToken_InvalidSession(t *testing.T) {
+	server := newTestCalendarTokenServer()
+
+	got := sendTestCalendarToken(server, "POST", []byte("{}"), "invalid-token")
+
+	// Make expected body.
+	expected := utils.ExpectedServerResponse[models.CalendarTokenResponse]{
+		Status: fiber.StatusUnauthorized,
+		Body: models.CalendarTokenResponse{
+			HTTPError: models.HTTPError{
+				Error:   true,
+				Message: repository.ErrorInvalidSession.Error(),
+				Code:    models.ErrorCodeSessionExpired,
+			},
+		},
+	}
+
+	// Test.
+	if diff := cmp.Diff(expected, got); diff != "" {
+		t.Fatalf("Failed for PostCalendarToken() Invalid Session (-want, +got)\n%s", diff)
+	}
+}
+
+// Test if DeleteCalendarToken() revokes a calendar token,
+// and refuses session tokens.
+func TestDeleteCalendarToken(t *testing.T) {
+	server := newTestCalendarTokenServer()
+
+	expected := []utils.ExpectedServerResponse[models.CalendarTokenResponse]{
+		// Revoked.
+		{
+			Status: fiber.StatusOK,
+			Body:   models.CalendarTokenResponse{},
+		},
+		// Not a calendar token.
+		{
+			Status: fiber.StatusUnauthorized,
+			Body: models.CalendarTokenResponse{
+				HTTPError: models.HTTPError{
+					Error:   true,
+					Message: repository.ErrorInvalidSession.Error(),
+					Code:    models.ErrorCodeSessionExpired,
+				},
+			},
+		},
+	}
+
+	got := []utils.ExpectedServerResponse[models.CalendarTokenResponse]{
+		sendTestCalendarToken(server, "DELETE", nil, repository.FakeCalendarToken),
+		sendTestCalendarToken(server, "DELETE", nil, repository.FakeToken),
+	}
+
+	// Test.
+	if diff := cmp.Diff(expected, got); diff != "" {
+		t.Fatalf("Failed for DeleteCalendarToken() (-want, +got)\n%s", diff)
+	}
+}
@@ -5,6 +5,9 @@ import (
 
 	"github.com/Threqt1/HACApi/app/models"
 	"github.com/Threqt1/HACApi/pkg/repository"
+	"github.com/Threqt1/HACApi/pkg/utils"
+	"github.com/Threqt1/HACApi/platform/export"
+	"github.com/gocolly/colly"
 	"github.com/gofiber/fiber/v2"
 )
 
@@ -12,10 +15,14 @@ import (
 //
 //	@Description	Returns classwork for the marking periods specified.
 //	@Description	If no marking periods are specified, the classwork for the current marking period is returned.
+//	@Description	If the normalize parameter is true, typed grade and ISO-8601 date fields are added under "normalized" alongside the raw strings.
+//	@Description	If the partial parameter is true, marking periods which fail to load are left out and listed under "errors", instead of failing the request.
+//	@Description	If the format parameter is "csv" or "xlsx", or the Accept header prefers text/csv or the XLSX MIME type, the assignments are sent back as a CSV file or spreadsheet instead, with a sheet per marking period.
 //	@Tags			classwork
 //	@Param			request	body	models.ClassworkRequestBody	false	"Body Params"
+//	@Param			Authorization	header	string	false	"Bearer session token from /login, used instead of the credentials in the body"
 //	@Accept			json
-//	@Produce		json
+//	@Produce		json,text/csv,application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
 //	@Success		200	{object}	models.ClassworkResponse
 //	@Router			/classwork [post]
 func PostClasswork(server *repository.Server, ctx *fiber.Ctx) error {
@@ -23,56 +30,83 @@ func PostClasswork(server *repository.Server, ctx *fiber.Ctx) error {
 	params := new(models.ClassworkRequestBody)
 
 	// Check if parsing body parameters succeeded.
-	if err := ctx.BodyParser(params); err != nil {
+	if err := utils.ParseBody(ctx, params); err != nil {
 		return ctx.Status(fiber.StatusBadRequest).JSON(models.ClassworkResponse{
-			HTTPError: models.HTTPError{
-				Error:   true,
-				Message: repository.ErrorBadBodyParams.Error(),
-			},
+			HTTPError: utils.NewHTTPError(ctx, repository.ErrorBadBodyParams),
 		})
 	}
 
+	// Fill in the credentials bound to the session token, if one was passed.
+	if token := utils.GetBearerToken(ctx); token != "" {
+		credentials, err := server.Cache.GetSession(token)
+
+		// Error out if the session is invalid or expired.
+		if err != nil {
+			return ctx.Status(fiber.StatusUnauthorized).JSON(models.ClassworkResponse{
+				HTTPError: utils.NewHTTPError(ctx, repository.ErrorInvalidSession),
+			})
+		}
+
+		params.BaseRequestBody = credentials
+	}
+
 	// Verify the validity of the body params.
 	if err := server.Validator.Struct(params); err != nil {
 		return ctx.Status(fiber.StatusBadRequest).JSON(models.ClassworkResponse{
-			HTTPError: models.HTTPError{
-				Error:   true,
-				Message: repository.ErrorBadBodyParams.Error(),
-			},
+			HTTPError: utils.NewHTTPError(ctx, repository.ErrorBadBodyParams, utils.ValidationFields(err)...),
+		})
+	}
+
+	// Verify the base is an allowed HAC URL, and normalize it.
+	base, err := server.BaseURLs.Validate(params.Base)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(models.ClassworkResponse{
+			HTTPError: utils.NewHTTPError(ctx, fmt.Errorf("%w: %s", repository.ErrorInvalidBase, err), "base"),
 		})
 	}
+	params.Base = base
+
+	// Tag the logs with the district and a hash of the user.
+	utils.SetLogIdentity(ctx, params.Username, params.Base)
 
 	// Form a cache key.
 	cacheKey := fmt.Sprintf("%s\n%s\n%s", params.Username, params.Password, params.Base)
 
 	// Try logging in, or grab the cached collector.
-	collector, err := server.Cache.GetOrLogin(cacheKey)
+	collector, err := server.Cache.GetOrLogin(ctx.UserContext(), cacheKey)
 
 	// Error out if the login fails.
 	if err != nil {
-		return ctx.Status(fiber.StatusBadRequest).JSON(models.ClassworkResponse{
-			HTTPError: models.HTTPError{
-				Error:   true,
-				Message: repository.ErrorInvalidAuthentication.Error(),
-			},
+		status, loginErr := utils.LoginErrorResponse(err)
+		return ctx.Status(status).JSON(models.ClassworkResponse{
+			HTTPError: utils.NewHTTPError(ctx, loginErr),
 		})
 	}
 
-	// Get the classwork.
-	classwork, err := server.Querier.GetClasswork(collector, *params)
+	// Get the classwork, and the marking periods which failed if partial results were requested.
+	var itemErrors []models.ItemError
+	classwork, err := utils.RetryOnExpiredSession(ctx.UserContext(), server.Cache, cacheKey, collector, func(collector *colly.Collector) ([]models.Classwork, error) {
+		classwork, errs, err := server.Querier.GetClasswork(ctx.UserContext(), collector, *params)
+		itemErrors = errs
+		return classwork, err
+	})
 
 	// Check if returned value was nil, and if so error out.
 	if err != nil {
-		return ctx.Status(fiber.StatusInternalServerError).JSON(models.ClassworkResponse{
-			HTTPError: models.HTTPError{
-				Error:   true,
-				Message: repository.ErrorInternalError.Error(),
-			},
+		status, queryErr := utils.QueryErrorResponse(err)
+		return ctx.Status(status).JSON(models.ClassworkResponse{
+			HTTPError: utils.NewHTTPError(ctx, queryErr),
 		})
 	}
 
+	// Send back a CSV file or spreadsheet, if one was asked for.
+	if format := utils.ResponseFormat(ctx, params.Format); format != models.FormatJSON {
+		return utils.SendWorkbook(ctx.Status(fiber.StatusOK), export.Classwork(classwork), format, "classwork")
+	}
+
 	// Return the recieved classwork.
 	return ctx.Status(fiber.StatusOK).JSON(models.ClassworkResponse{
 		Classwork: classwork,
+		Errors:    itemErrors,
 	})
 }
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"io"
 	"net/http/httptest"
+	"strconv"
 	"testing"
 
 	"github.com/Threqt1/HACApi/app/models"
@@ -12,7 +13,6 @@ import (
 	"github.com/Threqt1/HACApi/pkg/utils"
 	"github.com/Threqt1/HACApi/platform/cache"
 	"github.com/bytedance/sonic"
-	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/go-cmp/cmp"
 )
@@ -28,8 +28,9 @@ func TestPostClasswork_AllValidInputs_NoMarkingPeriods(t *testing.T) {
 			JSONDecoder: sonic.Unmarshal,
 		}),
 		Querier:   queries.NewTestQuerier(),
-		Validator: validator.New(),
+		Validator: utils.NewValidator(),
 		Cache:     cache.NewTestCache(),
+		BaseURLs:  utils.NewTestBaseURLPolicy(),
 	}
 
 	// Register PostClasswork() as the handler
@@ -90,8 +91,9 @@ func TestPostClasswork_AllValidInputs_WithMarkingPeriods(t *testing.T) {
 			JSONDecoder: sonic.Unmarshal,
 		}),
 		Querier:   queries.NewTestQuerier(),
-		Validator: validator.New(),
+		Validator: utils.NewValidator(),
 		Cache:     cache.NewTestCache(),
+		BaseURLs:  utils.NewTestBaseURLPolicy(),
 	}
 
 	// Register PostClasswork() as the handler
@@ -142,6 +144,74 @@ func TestPostClasswork_AllValidInputs_WithMarkingPeriods(t *testing.T) {
 	}
 }
 
+// Test if PostClasswork() returns the marking periods
+// which succeeded, and lists the ones which failed,
+// when partial results are requested.
+func TestPostClasswork_AllValidInputs_Partial(t *testing.T) {
+	// Set up testing server.
+	server := &repository.Server{
+		App: fiber.New(fiber.Config{
+			JSONEncoder: sonic.Marshal,
+			JSONDecoder: sonic.Unmarshal,
+		}),
+		Querier:   queries.NewTestQuerier(),
+		Validator: utils.NewValidator(),
+		Cache:     cache.NewTestCache(),
+		BaseURLs:  utils.NewTestBaseURLPolicy(),
+	}
+
+	// Register PostClasswork() as the handler
+	// for the default route.
+	server.App.Post("/", utils.WrapController(server, PostClasswork))
+
+	// Create request data.
+	bodyData := models.ClassworkRequestBody{
+		BaseRequestBody: models.BaseRequestBody{
+			Username: repository.FakeUsername,
+			Password: repository.FakePassword,
+			Base:     repository.FakeBase,
+		},
+		PartialRequestBody: models.PartialRequestBody{
+			Partial: true,
+		},
+		MarkingPeriods: []int{1, 2, 3},
+	}
+	body, _ := sonic.Marshal(bodyData)
+
+	// Create a test request.
+	req := httptest.NewRequest("POST", "http://fake.url/", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	// Test the request.
+	resp, _ := server.App.Test(req)
+
+	// Parse the body.
+	resBody, _ := io.ReadAll(resp.Body)
+	res := models.ClassworkResponse{}
+
+	sonic.Unmarshal(resBody, &res)
+
+	// Make expected body.
+	expected := utils.ExpectedServerResponse[models.ClassworkResponse]{
+		Status: fiber.StatusOK,
+		Body: models.ClassworkResponse{
+			Classwork: []models.Classwork{{}, {}},
+			Errors:    []models.ItemError{{Item: "3", Message: repository.ErrorServerUnreachable.Error(), Code: models.ErrorCodeHACUnavailable}},
+		},
+	}
+
+	// Convert response to a comparable struct.
+	got := utils.ExpectedServerResponse[models.ClassworkResponse]{
+		Status: resp.StatusCode,
+		Body:   res,
+	}
+
+	// Test.
+	if diff := cmp.Diff(expected, got); diff != "" {
+		t.Fatalf("Failed for PostClasswork() All Valid Inputs, Partial (-want, +got)\n%s", diff)
+	}
+}
+
 // Test if PostClasswork() errors out due to bad
 // body parameters.
 func TestPostClasswork_BadBodyParams(t *testing.T) {
@@ -152,8 +222,9 @@ func TestPostClasswork_BadBodyParams(t *testing.T) {
 			JSONDecoder: sonic.Unmarshal,
 		}),
 		Querier:   queries.NewTestQuerier(),
-		Validator: validator.New(),
+		Validator: utils.NewValidator(),
 		Cache:     cache.NewTestCache(),
+		BaseURLs:  utils.NewTestBaseURLPolicy(),
 	}
 
 	// Register PostClasswork() as the handler
@@ -189,6 +260,7 @@ func TestPostClasswork_BadBodyParams(t *testing.T) {
 			HTTPError: models.HTTPError{
 				Error:   true,
 				Message: repository.ErrorBadBodyParams.Error(),
+				Code:    models.ErrorCodeBadRequestField,
 			},
 			Classwork: nil,
 		},
@@ -216,8 +288,9 @@ func TestPostClasswork_BadBodyParams_InvalidModel(t *testing.T) {
 			JSONDecoder: sonic.Unmarshal,
 		}),
 		Querier:   queries.NewTestQuerier(),
-		Validator: validator.New(),
+		Validator: utils.NewValidator(),
 		Cache:     cache.NewTestCache(),
+		BaseURLs:  utils.NewTestBaseURLPolicy(),
 	}
 
 	// Register PostClasswork() as the handler
@@ -254,6 +327,8 @@ func TestPostClasswork_BadBodyParams_InvalidModel(t *testing.T) {
 			HTTPError: models.HTTPError{
 				Error:   true,
 				Message: repository.ErrorBadBodyParams.Error(),
+				Code:    models.ErrorCodeBadRequestField,
+				Fields:  []string{"username", "password", "base"},
 			},
 			Classwork: nil,
 		},
@@ -282,8 +357,9 @@ func TestPostClasswork_InvalidBodyParams_GreaterThan6MarkingPeriods(t *testing.T
 			JSONDecoder: sonic.Unmarshal,
 		}),
 		Querier:   queries.NewTestQuerier(),
-		Validator: validator.New(),
+		Validator: utils.NewValidator(),
 		Cache:     cache.NewTestCache(),
+		BaseURLs:  utils.NewTestBaseURLPolicy(),
 	}
 
 	// Register PostClasswork() as the handler
@@ -321,6 +397,8 @@ func TestPostClasswork_InvalidBodyParams_GreaterThan6MarkingPeriods(t *testing.T
 			HTTPError: models.HTTPError{
 				Error:   true,
 				Message: repository.ErrorBadBodyParams.Error(),
+				Code:    models.ErrorCodeBadRequestField,
+				Fields:  []string{"markingPeriods"},
 			},
 			Classwork: nil,
 		},
@@ -349,8 +427,9 @@ func TestPostClasswork_InvalidBodyParams_MarkingPeriodOutOfRange(t *testing.T) {
 			JSONDecoder: sonic.Unmarshal,
 		}),
 		Querier:   queries.NewTestQuerier(),
-		Validator: validator.New(),
+		Validator: utils.NewValidator(),
 		Cache:     cache.NewTestCache(),
+		BaseURLs:  utils.NewTestBaseURLPolicy(),
 	}
 
 	// Register PostClasswork() as the handler
@@ -388,6 +467,8 @@ func TestPostClasswork_InvalidBodyParams_MarkingPeriodOutOfRange(t *testing.T) {
 			HTTPError: models.HTTPError{
 				Error:   true,
 				Message: repository.ErrorBadBodyParams.Error(),
+				Code:    models.ErrorCodeBadRequestField,
+				Fields:  []string{"markingPeriods[0]", "markingPeriods[1]", "markingPeriods[2]", "markingPeriods[3]", "markingPeriods[4]"},
 			},
 			Classwork: nil,
 		},
@@ -415,8 +496,9 @@ func TestPostClasswork_InvalidCredentials(t *testing.T) {
 			JSONDecoder: sonic.Unmarshal,
 		}),
 		Querier:   queries.NewTestQuerier(),
-		Validator: validator.New(),
+		Validator: utils.NewValidator(),
 		Cache:     cache.NewTestCache(),
+		BaseURLs:  utils.NewTestBaseURLPolicy(),
 	}
 
 	// Register PostClasswork() as the handler
@@ -428,7 +510,7 @@ func TestPostClasswork_InvalidCredentials(t *testing.T) {
 		BaseRequestBody: models.BaseRequestBody{
 			Username: "bad username",
 			Password: "bad password",
-			Base:     "bad base",
+			Base:     repository.FakeBase,
 		},
 	}
 	body, _ := sonic.Marshal(bodyData)
@@ -453,6 +535,7 @@ func TestPostClasswork_InvalidCredentials(t *testing.T) {
 			HTTPError: models.HTTPError{
 				Error:   true,
 				Message: repository.ErrorInvalidAuthentication.Error(),
+				Code:    models.ErrorCodeInvalidCredentials,
 			},
 			Classwork: nil,
 		},
@@ -480,8 +563,9 @@ func TestPostClasswork_InternalError(t *testing.T) {
 			JSONDecoder: sonic.Unmarshal,
 		}),
 		Querier:   queries.NewTestErrorQuerier(),
-		Validator: validator.New(),
+		Validator: utils.NewValidator(),
 		Cache:     cache.NewTestCache(),
+		BaseURLs:  utils.NewTestBaseURLPolicy(),
 	}
 
 	// Register PostClasswork() as the handler
@@ -518,6 +602,7 @@ func TestPostClasswork_InternalError(t *testing.T) {
 			HTTPError: models.HTTPError{
 				Error:   true,
 				Message: repository.ErrorInternalError.Error(),
+				Code:    models.ErrorCodeInternal,
 			},
 			Classwork: nil,
 		},
@@ -534,3 +619,174 @@ func TestPostClasswork_InternalError(t *testing.T) {
 		t.Fatalf("Failed for PostClasswork() Internal Error (-want, +got)\n%s", diff)
 	}
 }
+
+// Test if PostClasswork() functions correctly
+// with a session token instead of credentials.
+func TestPostClasswork_WithSessionToken(t *testing.T) {
+	// Set up testing server.
+	server := &repository.Server{
+		App: fiber.New(fiber.Config{
+			JSONEncoder: sonic.Marshal,
+			JSONDecoder: sonic.Unmarshal,
+		}),
+		Querier:   queries.NewTestQuerier(),
+		Validator: utils.NewValidator(),
+		Cache:     cache.NewTestCache(),
+		BaseURLs:  utils.NewTestBaseURLPolicy(),
+	}
+
+	// Register PostClasswork() as the handler
+	// for the default route.
+	server.App.Post("/", utils.WrapController(server, PostClasswork))
+
+	// Create request data without any credentials.
+	bodyData := models.ClassworkRequestBody{}
+	body, _ := sonic.Marshal(bodyData)
+
+	// Create a test request with the session token.
+	req := httptest.NewRequest("POST", "http://fake.url/", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+repository.FakeToken)
+
+	// Test the request.
+	resp, _ := server.App.Test(req)
+
+	// Parse the body.
+	resBody, _ := io.ReadAll(resp.Body)
+	res := models.ClassworkResponse{}
+
+	sonic.Unmarshal(resBody, &res)
+
+	// Make expected body.
+	expected := utils.ExpectedServerResponse[models.ClassworkResponse]{
+		Status: fiber.StatusOK,
+		Body: models.ClassworkResponse{
+			Classwork: []models.Classwork{{}},
+		},
+	}
+
+	// Convert response to a comparable struct.
+	got := utils.ExpectedServerResponse[models.ClassworkResponse]{
+		Status: resp.StatusCode,
+		Body:   res,
+	}
+
+	// Test.
+	if diff := cmp.Diff(expected, got); diff != "" {
+		t.Fatalf("Failed for PostClasswork() With Session Token (-want, +got)\n%s", diff)
+	}
+}
+
+// Test if PostClasswork() errors out due to an
+// invalid session token.
+func TestPostClasswork_InvalidSessionToken(t *testing.T) {
+	// Set up testing server.
+	server := &repository.Server{
+		App: fiber.New(fiber.Config{
+			JSONEncoder: sonic.Marshal,
+			JSONDecoder: sonic.Unmarshal,
+		}),
+		Querier:   queries.NewTestQuerier(),
+		Validator: utils.NewValidator(),
+		Cache:     cache.NewTestCache(),
+		BaseURLs:  utils.NewTestBaseURLPolicy(),
+	}
+
+	// Register PostClasswork() as the handler
+	// for the default route.
+	server.App.Post("/", utils.WrapController(server, PostClasswork))
+
+	// Create request data without any credentials.
+	bodyData := models.ClassworkRequestBody{}
+	body, _ := sonic.Marshal(bodyData)
+
+	// Create a test request with a bad session token.
+	req := httptest.NewRequest("POST", "http://fake.url/", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer bad-token")
+
+	// Test the request.
+	resp, _ := server.App.Test(req)
+
+	// Parse the body.
+	resBody, _ := io.ReadAll(resp.Body)
+	res := models.ClassworkResponse{}
+
+	sonic.Unmarshal(resBody, &res)
+
+	// Make expected body.
+	expected := utils.ExpectedServerResponse[models.ClassworkResponse]{
+		Status: fiber.StatusUnauthorized,
+		Body: models.ClassworkResponse{
+			HTTPError: models.HTTPError{
+				Error:   true,
+				Message: repository.ErrorInvalidSession.Error(),
+				Code:    models.ErrorCodeSessionExpired,
+			},
+		},
+	}
+
+	// Convert response to a comparable struct.
+	got := utils.ExpectedServerResponse[models.ClassworkResponse]{
+		Status: resp.StatusCode,
+		Body:   res,
+	}
+
+	// Test.
+	if diff := cmp.Diff(expected, got); diff != "" {
+		t.Fatalf("Failed for PostClasswork() Invalid Session Token (-want, +got)\n%s", diff)
+	}
+}
+
+// Test if PostClasswork() sends back a CSV file
+// if the format parameter asks for one.
+func TestPostClasswork_FormatCSV(t *testing.T) {
+	// Set up testing server.
+	server := &repository.Server{
+		App: fiber.New(fiber.Config{
+			JSONEncoder: sonic.Marshal,
+			JSONDecoder: sonic.Unmarshal,
+		}),
+		Querier:   queries.NewTestQuerier(),
+		Validator: utils.NewValidator(),
+		Cache:     cache.NewTestCache(),
+		BaseURLs:  utils.NewTestBaseURLPolicy(),
+	}
+
+	// Register PostClasswork() as the handler
+	// for the default route.
+	server.App.Post("/", utils.WrapController(server, PostClasswork))
+
+	// Create request data.
+	bodyData := models.ClassworkRequestBody{
+		BaseRequestBody: models.BaseRequestBody{
+			Username: repository.FakeUsername,
+			Password: repository.FakePassword,
+			Base:     repository.FakeBase,
+		},
+		FormatRequestBody: models.FormatRequestBody{Format: models.FormatCSV},
+	}
+	body, _ := sonic.Marshal(bodyData)
+
+	// Create a test request.
+	req := httptest.NewRequest("POST", "http://fake.url/", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	// Test the request.
+	resp, _ := server.App.Test(req)
+	resBody, _ := io.ReadAll(resp.Body)
+
+	// Make expected response.
+	expected := []string{
+		"200",
+		"text/csv; charset=utf-8",
+		`attachment; filename="classwork.csv"`,
+		"Marking Period,Course,Class,Period,Teacher,Average,Assignment,Category,Assigned Date,Due Date,Grade,Total Points,Dropped\n",
+	}
+	got := []string{strconv.Itoa(resp.StatusCode), resp.Header.Get("Content-Type"), resp.Header.Get("Content-Disposition"), string(resBody)}
+
+	// Test.
+	if diff := cmp.Diff(expected, got); diff != "" {
+		t.Fatalf("Failed for PostClasswork() Format CSV (-want, +got)\n%s", diff)
+	}
+}
@@ -0,0 +1,91 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Threqt1/HACApi/app/models"
+	"github.com/Threqt1/HACApi/pkg/repository"
+	"github.com/Threqt1/HACApi/pkg/utils"
+	"github.com/gocolly/colly"
+	"github.com/gofiber/fiber/v2"
+)
+
+// PostClassworkStream handles POST requests to the /classwork/stream endpoint.
+//
+//	@Description	Works like the "/classwork" endpoint, except the response is a stream of Server-Sent Events.
+//	@Description	A "classwork" event is sent with each marking period as soon as it is parsed, in the order they finish in.
+//	@Description	The stream ends with a "done" event, listing the items which failed if partial results were requested, or an "error" event.
+//	@Description	Errors before the stream starts are sent back as JSON, like the "/classwork" endpoint.
+//	@Tags			classwork
+//	@Param			request	body	models.ClassworkRequestBody	false	"Body Params"
+//	@Param			Authorization	header	string	false	"Bearer session token from /login, used instead of the credentials in the body"
+//	@Accept			json
+//	@Produce		text/event-stream
+//	@Success		200	{object}	models.StreamDone
+//	@Router			/classwork/stream [post]
+func PostClassworkStream(server *repository.Server, ctx *fiber.Ctx) error {
+	// Parse body.
+	params := new(models.ClassworkRequestBody)
+
+	// Check if parsing body parameters succeeded.
+	if err := utils.ParseBody(ctx, params); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(models.ClassworkResponse{
+			HTTPError: utils.NewHTTPError(ctx, repository.ErrorBadBodyParams),
+		})
+	}
+
+	// Fill in the credentials bound to the session token, if one was passed.
+	if token := utils.GetBearerToken(ctx); token != "" {
+		credentials, err := server.Cache.GetSession(token)
+
+		// Error out if the session is invalid or expired.
+		if err != nil {
+			return ctx.Status(fiber.StatusUnauthorized).JSON(models.ClassworkResponse{
+				HTTPError: utils.NewHTTPError(ctx, repository.ErrorInvalidSession),
+			})
+		}
+
+		params.BaseRequestBody = credentials
+	}
+
+	// Verify the validity of the body params.
+	if err := server.Validator.Struct(params); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(models.ClassworkResponse{
+			HTTPError: utils.NewHTTPError(ctx, repository.ErrorBadBodyParams, utils.ValidationFields(err)...),
+		})
+	}
+
+	// Verify the base is an allowed HAC URL, and normalize it.
+	base, err := server.BaseURLs.Validate(params.Base)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(models.ClassworkResponse{
+			HTTPError: utils.NewHTTPError(ctx, fmt.Errorf("%w: %s", repository.ErrorInvalidBase, err), "base"),
+		})
+	}
+	params.Base = base
+
+	// Tag the logs with the district and a hash of the user.
+	utils.SetLogIdentity(ctx, params.Username, params.Base)
+
+	// Form a cache key.
+	cacheKey := fmt.Sprintf("%s\n%s\n%s", params.Username, params.Password, params.Base)
+
+	// Try logging in, or grab the cached collector.
+	collector, err := server.Cache.GetOrLogin(ctx.UserContext(), cacheKey)
+
+	// Error out if the login fails.
+	if err != nil {
+		status, loginErr := utils.LoginErrorResponse(err)
+		return ctx.Status(status).JSON(models.ClassworkResponse{
+			HTTPError: utils.NewHTTPError(ctx, loginErr),
+		})
+	}
+
+	// Stream the classwork, once the handler returns.
+	utils.StreamEvents(ctx, server.Cache, cacheKey, collector, "classwork", func(streamCtx context.Context, collector *colly.Collector, emit func(int, models.Classwork)) ([]models.ItemError, error) {
+		return server.Querier.StreamClasswork(streamCtx, collector, *params, emit)
+	})
+
+	return nil
+}
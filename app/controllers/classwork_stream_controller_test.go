@@ -0,0 +1,253 @@
+package controllers
+
+import (
+	"bytes"
+	"io"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Threqt1/HACApi/app/models"
+	"github.com/Threqt1/HACApi/app/queries"
+	"github.com/Threqt1/HACApi/pkg/repository"
+	"github.com/Threqt1/HACApi/pkg/utils"
+	"github.com/Threqt1/HACApi/platform/cache"
+	"github.com/bytedance/sonic"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/go-cmp/cmp"
+)
+
+// Test if PostClassworkStream() streams classwork for
+// each marking period, then completes.
+func TestPostClassworkStream_AllValidInputs(t *testing.T) {
+	// Set up testing server.
+	server := &repository.Server{
+		App: fiber.New(fiber.Config{
+			JSONEncoder: sonic.Marshal,
+			JSONDecoder: sonic.Unmarshal,
+		}),
+		Querier:   queries.NewTestQuerier(),
+		Validator: utils.NewValidator(),
+		Cache:     cache.NewTestCache(),
+		BaseURLs:  utils.NewTestBaseURLPolicy(),
+	}
+
+	// Register PostClassworkStream() as the handler
+	// for the default route.
+	server.App.Post("/", utils.WrapController(server, PostClassworkStream))
+
+	// Create request data.
+	bodyData := models.ClassworkRequestBody{
+		BaseRequestBody: models.BaseRequestBody{
+			Username: repository.FakeUsername,
+			Password: repository.FakePassword,
+			Base:     repository.FakeBase,
+		},
+		MarkingPeriods: []int{1, 2},
+	}
+	body, _ := sonic.Marshal(bodyData)
+
+	// Create a test request.
+	req := httptest.NewRequest("POST", "http://fake.url/", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	// Test the request.
+	resp, _ := server.App.Test(req)
+
+	// Read the body.
+	resBody, _ := io.ReadAll(resp.Body)
+
+	// Make expected body.
+	expected := utils.ExpectedServerResponse[string]{
+		Status: fiber.StatusOK,
+		Body: "event: classwork\ndata: {\"sixWeeks\":0,\"entries\":null}\n\n" +
+			"event: classwork\ndata: {\"sixWeeks\":0,\"entries\":null}\n\n" +
+			"event: done\ndata: {}\n\n",
+	}
+
+	// Convert response to a comparable struct.
+	got := utils.ExpectedServerResponse[string]{
+		Status: resp.StatusCode,
+		Body:   string(resBody),
+	}
+
+	// Test.
+	if diff := cmp.Diff(expected, got); diff != "" {
+		t.Fatalf("Failed for PostClassworkStream() All Valid Inputs (-want, +got)\n%s", diff)
+	}
+}
+
+// Test if PostClassworkStream() lists the marking periods
+// which failed in the completion event, when partial results are requested.
+func TestPostClassworkStream_Partial(t *testing.T) {
+	// Set up testing server.
+	server := &repository.Server{
+		App: fiber.New(fiber.Config{
+			JSONEncoder: sonic.Marshal,
+			JSONDecoder: sonic.Unmarshal,
+		}),
+		Querier:   queries.NewTestQuerier(),
+		Validator: utils.NewValidator(),
+		Cache:     cache.NewTestCache(),
+		BaseURLs:  utils.NewTestBaseURLPolicy(),
+	}
+
+	// Register PostClassworkStream() as the handler
+	// for the default route.
+	server.App.Post("/", utils.WrapController(server, PostClassworkStream))
+
+	// Create request data.
+	bodyData := models.ClassworkRequestBody{
+		BaseRequestBody: models.BaseRequestBody{
+			Username: repository.FakeUsername,
+			Password: repository.FakePassword,
+			Base:     repository.FakeBase,
+		},
+		PartialRequestBody: models.PartialRequestBody{
+			Partial: true,
+		},
+		MarkingPeriods: []int{1, 2},
+	}
+	body, _ := sonic.Marshal(bodyData)
+
+	// Create a test request.
+	req := httptest.NewRequest("POST", "http://fake.url/", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	// Test the request.
+	resp, _ := server.App.Test(req)
+
+	// Read the body.
+	resBody, _ := io.ReadAll(resp.Body)
+
+	// Make expected body.
+	expected := utils.ExpectedServerResponse[string]{
+		Status: fiber.StatusOK,
+		Body: "event: classwork\ndata: {\"sixWeeks\":0,\"entries\":null}\n\n" +
+			"event: done\ndata: {\"errors\":[{\"item\":\"2\",\"message\":\"" + repository.ErrorServerUnreachable.Error() + "\",\"code\":\"HAC_UNAVAILABLE\"}]}\n\n",
+	}
+
+	// Convert response to a comparable struct.
+	got := utils.ExpectedServerResponse[string]{
+		Status: resp.StatusCode,
+		Body:   string(resBody),
+	}
+
+	// Test.
+	if diff := cmp.Diff(expected, got); diff != "" {
+		t.Fatalf("Failed for PostClassworkStream() Partial (-want, +got)\n%s", diff)
+	}
+}
+
+// Test if PostClassworkStream() ends the stream with an
+// error event due to an internal error.
+func TestPostClassworkStream_InternalError(t *testing.T) {
+	// Set up testing server.
+	server := &repository.Server{
+		App: fiber.New(fiber.Config{
+			JSONEncoder: sonic.Marshal,
+			JSONDecoder: sonic.Unmarshal,
+		}),
+		Querier:   queries.NewTestErrorQuerier(),
+		Validator: utils.NewValidator(),
+		Cache:     cache.NewTestCache(),
+		BaseURLs:  utils.NewTestBaseURLPolicy(),
+	}
+
+	// Register PostClassworkStream() as the handler
+	// for the default route.
+	server.App.Post("/", utils.WrapController(server, PostClassworkStream))
+
+	// Create request data.
+	bodyData := models.ClassworkRequestBody{
+		BaseRequestBody: models.BaseRequestBody{
+			Username: repository.FakeUsername,
+			Password: repository.FakePassword,
+			Base:     repository.FakeBase,
+		},
+	}
+	body, _ := sonic.Marshal(bodyData)
+
+	// Create a test request.
+	req := httptest.NewRequest("POST", "http://fake.url/", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	// Test the request.
+	resp, _ := server.App.Test(req)
+
+	// Read the body.
+	resBody, _ := io.ReadAll(resp.Body)
+
+	// Make expected body.
+	expected := utils.ExpectedServerResponse[string]{
+		Status: fiber.StatusOK,
+		Body:   "event: error\ndata: {\"err\":true,\"msg\":\"" + repository.ErrorInternalError.Error() + "\",\"code\":\"INTERNAL_ERROR\"}\n\n",
+	}
+
+	// Convert response to a comparable struct.
+	got := utils.ExpectedServerResponse[string]{
+		Status: resp.StatusCode,
+		Body:   string(resBody),
+	}
+
+	// Test.
+	if diff := cmp.Diff(expected, got); diff != "" {
+		t.Fatalf("Failed for PostClassworkStream() Internal Error (-want, +got)\n%s", diff)
+	}
+}
+
+// Test if PostClassworkStream() errors out as JSON
+// before streaming due to invalid credentials.
+func TestPostClassworkStream_InvalidCredentials(t *testing.T) {
+	// Set up testing server.
+	server := &repository.Server{
+		App: fiber.New(fiber.Config{
+			JSONEncoder: sonic.Marshal,
+			JSONDecoder: sonic.Unmarshal,
+		}),
+		Querier:   queries.NewTestQuerier(),
+		Validator: utils.NewValidator(),
+		Cache:     cache.NewTestCache(),
+		BaseURLs:  utils.NewTestBaseURLPolicy(),
+	}
+
+	// Register PostClassworkStream() as the handler
+	// for the default route.
+	server.App.Post("/", utils.WrapController(server, PostClassworkStream))
+
+	// Create request data.
+	bodyData := models.ClassworkRequestBody{
+		BaseRequestBody: models.BaseRequestBody{
+			Username: "bad username",
+			Password: repository.FakePassword,
+			Base:     repository.FakeBase,
+		},
+	}
+	body, _ := sonic.Marshal(bodyData)
+
+	// Create a test request.
+	req := httptest.NewRequest("POST", "http://fake.url/", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	// Test the request.
+	resp, _ := server.App.Test(req)
+
+	// Read the body.
+	resBody, _ := io.ReadAll(resp.Body)
+
+	// Make expected body.
+	expected := utils.ExpectedServerResponse[string]{
+		Status: fiber.StatusBadRequest,
+		Body:   `{"err":true,"msg":"` + repository.ErrorInvalidAuthentication.Error() + `","code":"INVALID_CREDENTIALS","classwork":null}`,
+	}
+
+	// Convert response to a comparable struct.
+	got := utils.ExpectedServerResponse[string]{
+		Status: resp.StatusCode,
+		Body:   string(resBody),
+	}
+
+	// Test.
+	if diff := cmp.Diff(expected, got); diff != "" {
+		t.Fatalf("Failed for PostClassworkStream() Invalid Credentials (-want, +got)\n%s", diff)
+	}
+}
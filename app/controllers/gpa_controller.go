@@ -0,0 +1,132 @@
+package controllers
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Threqt1/HACApi/app/models"
+	"github.com/Threqt1/HACApi/pkg/repository"
+	"github.com/Threqt1/HACApi/pkg/utils"
+	"github.com/Threqt1/HACApi/platform/grades"
+	"github.com/gocolly/colly"
+	"github.com/gofiber/fiber/v2"
+)
+
+// PostGPA handles POST requests to the GPA endpoint.
+//
+//	@Description	Calculates the GPA from the transcript and report card, weighting every semester by its credit.
+//	@Description	"current" counts the completed semesters on the transcript, "projected" adds the semester averages of the current school year's report card, unless the semester is already on the transcript, and "whatIf" replaces or adds the hypothetical semester averages passed.
+//	@Description	Averages are converted into points with the scale passed, and classes whose course code matches a weight rule, like AP or honors classes, earn its bonus.
+//	@Tags			gpa
+//	@Param			request	body	models.GPARequestBody	false	"Body Params"
+//	@Param			Authorization	header	string	false	"Bearer session token from /login, used instead of the credentials in the body"
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{object}	models.GPAResponse
+//	@Router			/gpa [post]
+func PostGPA(server *repository.Server, ctx *fiber.Ctx) error {
+	// Parse body.
+	params := new(models.GPARequestBody)
+
+	// Check if parsing body parameters succeeded.
+	if err := utils.ParseBody(ctx, params); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(models.GPAResponse{
+			HTTPError: utils.NewHTTPError(ctx, repository.ErrorBadBodyParams),
+		})
+	}
+
+	// Fill in the credentials bound to the session token, if one was passed.
+	if token := utils.GetBearerToken(ctx); token != "" {
+		credentials, err := server.Cache.GetSession(token)
+
+		// Error out if the session is invalid or expired.
+		if err != nil {
+			return ctx.Status(fiber.StatusUnauthorized).JSON(models.GPAResponse{
+				HTTPError: utils.NewHTTPError(ctx, repository.ErrorInvalidSession),
+			})
+		}
+
+		params.BaseRequestBody = credentials
+	}
+
+	// Verify the validity of the body params.
+	if err := server.Validator.Struct(params); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(models.GPAResponse{
+			HTTPError: utils.NewHTTPError(ctx, repository.ErrorBadBodyParams, utils.ValidationFields(err)...),
+		})
+	}
+
+	// Compile the weight rules.
+	scale, err := grades.NewScale(params.Scale, params.Rules)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(models.GPAResponse{
+			HTTPError: utils.NewHTTPError(ctx, err, "rules"),
+		})
+	}
+
+	// Verify the base is an allowed HAC URL, and normalize it.
+	base, err := server.BaseURLs.Validate(params.Base)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(models.GPAResponse{
+			HTTPError: utils.NewHTTPError(ctx, fmt.Errorf("%w: %s", repository.ErrorInvalidBase, err), "base"),
+		})
+	}
+	params.Base = base
+
+	// Tag the logs with the district and a hash of the user.
+	utils.SetLogIdentity(ctx, params.Username, params.Base)
+
+	// Form a cache key.
+	cacheKey := fmt.Sprintf("%s\n%s\n%s", params.Username, params.Password, params.Base)
+
+	// Try logging in, or grab the cached collector.
+	collector, err := server.Cache.GetOrLogin(ctx.UserContext(), cacheKey)
+
+	// Error out if the login fails.
+	if err != nil {
+		status, loginErr := utils.LoginErrorResponse(err)
+		return ctx.Status(status).JSON(models.GPAResponse{
+			HTTPError: utils.NewHTTPError(ctx, loginErr),
+		})
+	}
+
+	// Get the normalized transcript.
+	transcript, err := utils.RetryOnExpiredSession(ctx.UserContext(), server.Cache, cacheKey, collector, func(collector *colly.Collector) ([]models.Transcript, error) {
+		return server.Querier.GetTranscript(ctx.UserContext(), collector, models.TranscriptRequestBody{
+			BaseRequestBody:      params.BaseRequestBody,
+			NormalizeRequestBody: models.NormalizeRequestBody{Normalize: true},
+		})
+	})
+
+	// Check if getting the transcript succeeded.
+	if err != nil {
+		status, queryErr := utils.QueryErrorResponse(err)
+		return ctx.Status(status).JSON(models.GPAResponse{
+			HTTPError: utils.NewHTTPError(ctx, queryErr),
+		})
+	}
+
+	// Get the normalized report card.
+	reportCard, err := utils.RetryOnExpiredSession(ctx.UserContext(), server.Cache, cacheKey, collector, func(collector *colly.Collector) ([]models.ReportCard, error) {
+		return server.Querier.GetReportCard(ctx.UserContext(), collector, models.ReportCardRequestBody{
+			BaseRequestBody:      params.BaseRequestBody,
+			NormalizeRequestBody: models.NormalizeRequestBody{Normalize: true},
+		})
+	})
+
+	// Check if getting the report card succeeded.
+	if err != nil {
+		status, queryErr := utils.QueryErrorResponse(err)
+		return ctx.Status(status).JSON(models.GPAResponse{
+			HTTPError: utils.NewHTTPError(ctx, queryErr),
+		})
+	}
+
+	// Calculate the GPA.
+	gpa := grades.CalculateGPA(transcript, reportCard, grades.SchoolYear(time.Now()), params.WhatIf, scale)
+
+	// Return the GPA.
+	return ctx.Status(fiber.StatusOK).JSON(models.GPAResponse{
+		GPA: &gpa,
+	})
+}
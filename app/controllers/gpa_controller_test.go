@@ -0,0 +1,200 @@
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/Threqt1/HACApi/app/models"
+	"github.com/Threqt1/HACApi/app/queries"
+	"github.com/Threqt1/HACApi/app/queries/parsers"
+	"github.com/Threqt1/HACApi/pkg/repository"
+	"github.com/Threqt1/HACApi/pkg/utils"
+	"github.com/Threqt1/HACApi/platform/cache"
+	"github.com/Threqt1/HACApi/platform/grades"
+	"github.com/bytedance/sonic"
+	"github.com/gocolly/colly"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/go-cmp/cmp"
+)
+
+// testGPA_Querier is a test querier sending back a transcript
+// with a completed semester, and a report card with a class in progress,
+// normalized if requested.
+type testGPA_Querier struct {
+	queries.TestQuerier
+}
+
+func (testGPA_Querier) GetTranscript(ctx context.Context, collector *colly.Collector, params models.TranscriptRequestBody) ([]models.Transcript, error) {
+	transcript := models.Transcript{
+		Entries: []models.TranscriptGroup{{
+			Year:     "2022-2023",
+			Semester: "1",
+			Entries:  []models.TranscriptGroupEntry{{Class: models.Class{Name: "AP Biology", Course: "AP0101 - 1"}, Average: "85", Credit: "0.5"}},
+		}},
+		Weighted:   models.TranscriptGPA{Type: "Weighted", GPA: "4.0000"},
+		Unweighted: models.TranscriptGPA{Type: "Unweighted", GPA: "3.0000"},
+	}
+	if params.Normalize {
+		transcript = parsers.NewParser().NormalizeTranscript(transcript)
+	}
+	return []models.Transcript{transcript}, nil
+}
+
+func (testGPA_Querier) GetReportCard(ctx context.Context, collector *colly.Collector, params models.ReportCardRequestBody) ([]models.ReportCard, error) {
+	reportCard := models.ReportCard{
+		Entries: []models.ReportCardEntry{{
+			Class:           models.Class{Name: "Algebra", Course: "0401A - 1"},
+			AttemptedCredit: "1.00",
+			Averages:        models.SixWeeksGrades{Sem1: "95"},
+		}},
+	}
+	if params.Normalize {
+		reportCard = parsers.NewParser().NormalizeReportCard(reportCard)
+	}
+	return []models.ReportCard{reportCard}, nil
+}
+
+// newTestGPAServer sets up a testing server with
+// the GPA endpoint registered.
+func newTestGPAServer() *repository.Server {
+	server := &repository.Server{
+		App: fiber.New(fiber.Config{
+			JSONEncoder: sonic.Marshal,
+			JSONDecoder: sonic.Unmarshal,
+		}),
+		Querier:   testGPA_Querier{},
+		Validator: utils.NewValidator(),
+		Cache:     cache.NewTestCache(),
+		BaseURLs:  utils.NewTestBaseURLPolicy(),
+	}
+
+	server.App.Post("/", utils.WrapController(server, PostGPA))
+
+	return server
+}
+
+// postTestGPA posts weight rules and hypothetical averages to a testing server.
+func postTestGPA(server *repository.Server, rules []models.GPAWeightRule, whatIf []models.GPAWhatIf) utils.ExpectedServerResponse[models.GPAResponse] {
+	bodyData := models.GPARequestBody{
+		BaseRequestBody: models.BaseRequestBody{
+			Username: repository.FakeUsername,
+			Password: repository.FakePassword,
+			Base:     repository.FakeBase,
+		},
+		Rules:  rules,
+		WhatIf: whatIf,
+	}
+	body, _ := sonic.Marshal(bodyData)
+
+	// Create a test request.
+	req := httptest.NewRequest("POST", "http://fake.url/", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	// Test the request.
+	resp, _ := server.App.Test(req)
+
+	// Parse the body.
+	resBody, _ := io.ReadAll(resp.Body)
+	res := models.GPAResponse{}
+
+	sonic.Unmarshal(resBody, &res)
+
+	return utils.ExpectedServerResponse[models.GPAResponse]{
+		Status: resp.StatusCode,
+		Body:   res,
+	}
+}
+
+// Test if PostGPA() calculates the GPA
+// given all valid inputs.
+func TestPostGPA_AllValidInputs(t *testing.T) {
+	server := newTestGPAServer()
+
+	average := 75.0
+	got := postTestGPA(server, []models.GPAWeightRule{{Pattern: "^AP", Bonus: 1}}, []models.GPAWhatIf{{Course: "0401A - 1", Semester: 1, Average: &average}})
+
+	// Make expected body.
+	current, projected, whatIf := 4.0, 4.0, 3.0
+	expected := utils.ExpectedServerResponse[models.GPAResponse]{
+		Status: fiber.StatusOK,
+		Body: models.GPAResponse{
+			GPA: &models.GPA{
+				Current:   models.GPAResult{GPA: &current, Credits: 0.5},
+				Projected: models.GPAResult{GPA: &projected, Credits: 1},
+				WhatIf:    &models.GPAResult{GPA: &whatIf, Credits: 1},
+				District: []models.TranscriptGPA{
+					{Type: "Weighted", GPA: "4.0000"},
+					{Type: "Unweighted", GPA: "3.0000"},
+				},
+				Classes: []models.GPAClass{
+					{Class: models.Class{Name: "AP Biology", Course: "AP0101 - 1"}, Year: "2022-2023", Semester: "1", Average: 85, Credit: 0.5, Points: 4, Source: models.GPASourceTranscript},
+					{Class: models.Class{Name: "Algebra", Course: "0401A - 1"}, Year: grades.SchoolYear(time.Now()), Semester: "1", Average: 75, Credit: 0.5, Points: 2, Source: models.GPASourceWhatIf},
+				},
+			},
+		},
+	}
+
+	// Test.
+	if diff := cmp.Diff(expected, got); diff != "" {
+		t.Fatalf("Failed for PostGPA() All Valid Inputs (-want, +got)\n%s", diff)
+	}
+}
+
+// Test if PostGPA() errors out if a weight
+// rule isn't a valid regular expression.
+func TestPostGPA_InvalidRule(t *testing.T) {
+	server := newTestGPAServer()
+
+	got := postTestGPA(server, []models.GPAWeightRule{{Pattern: "(AP", Bonus: 1}}, nil)
+
+	// Make expected body.
+	_, compileErr := regexp.Compile("(AP")
+	expected := utils.ExpectedServerResponse[models.GPAResponse]{
+		Status: fiber.StatusBadRequest,
+		Body: models.GPAResponse{
+			HTTPError: models.HTTPError{
+				Error:   true,
+				Message: repository.ErrorBadBodyParams.Error() + ": rules[0]: " + compileErr.Error(),
+				Code:    models.ErrorCodeBadRequestField,
+				Fields:  []string{"rules"},
+			},
+		},
+	}
+
+	// Test.
+	if diff := cmp.Diff(expected, got); diff != "" {
+		t.Fatalf("Failed for PostGPA() Invalid Rule (-want, +got)\n%s", diff)
+	}
+}
+
+// Test if PostGPA() errors out if a hypothetical
+// average is missing its semester.
+func TestPostGPA_BadBodyParams_InvalidModel(t *testing.T) {
+	server := newTestGPAServer()
+
+	average := 90.0
+	got := postTestGPA(server, nil, []models.GPAWhatIf{{Course: "0401A - 1", Average: &average}})
+
+	// Make expected body.
+	expected := utils.ExpectedServerResponse[models.GPAResponse]{
+		Status: fiber.StatusBadRequest,
+		Body: models.GPAResponse{
+			HTTPError: models.HTTPError{
+				Error:   true,
+				Message: repository.ErrorBadBodyParams.Error(),
+				Code:    models.ErrorCodeBadRequestField,
+				Fields:  []string{"whatIf[0].semester"},
+			},
+		},
+	}
+
+	// Test.
+	if diff := cmp.Diff(expected, got); diff != "" {
+		t.Fatalf("Failed for PostGPA() Bad Body Params, Invalid Request Model (-want, +got)\n%s", diff)
+	}
+}
@@ -0,0 +1,29 @@
+package controllers
+
+import (
+	"github.com/Threqt1/HACApi/app/models"
+	"github.com/Threqt1/HACApi/pkg/repository"
+	"github.com/gofiber/fiber/v2"
+)
+
+// GetHealthz handles GET requests to the liveness endpoint, reporting
+// that the API is up without checking HAC. It lives outside of the
+// API's base path, so it isn't part of the swagger docs.
+func GetHealthz(server *repository.Server, ctx *fiber.Ctx) error {
+	return ctx.Status(fiber.StatusOK).JSON(models.HealthResponse{Status: models.HealthStatusUp})
+}
+
+// GetReadyz handles GET requests to the readiness endpoint, reporting
+// whether the LogOn page of each configured district is reachable and
+// still has a request verification token.
+func GetReadyz(server *repository.Server, ctx *fiber.Ctx) error {
+	readiness := server.Health.Readiness(ctx.UserContext())
+
+	// Only take the API out of rotation if no district can be served.
+	status := fiber.StatusOK
+	if readiness.Status == models.HealthStatusDown {
+		status = fiber.StatusServiceUnavailable
+	}
+
+	return ctx.Status(status).JSON(readiness)
+}
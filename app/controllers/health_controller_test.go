@@ -0,0 +1,129 @@
+package controllers
+
+import (
+	"io"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Threqt1/HACApi/app/models"
+	"github.com/Threqt1/HACApi/pkg/repository"
+	"github.com/Threqt1/HACApi/pkg/utils"
+	"github.com/Threqt1/HACApi/platform/health"
+	"github.com/bytedance/sonic"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/go-cmp/cmp"
+)
+
+// Test if GetHealthz() reports the API as up.
+func TestGetHealthz(t *testing.T) {
+	// Set up testing server.
+	server := &repository.Server{
+		App: fiber.New(fiber.Config{
+			JSONEncoder: sonic.Marshal,
+			JSONDecoder: sonic.Unmarshal,
+		}),
+	}
+
+	// Register GetHealthz() as the handler
+	// for the default route.
+	server.App.Get("/", utils.WrapController(server, GetHealthz))
+
+	// Test the request.
+	resp, _ := server.App.Test(httptest.NewRequest("GET", "http://fake.url/", nil))
+
+	// Parse the body.
+	resBody, _ := io.ReadAll(resp.Body)
+	res := models.HealthResponse{}
+
+	sonic.Unmarshal(resBody, &res)
+
+	// Make expected body.
+	expected := utils.ExpectedServerResponse[models.HealthResponse]{
+		Status: fiber.StatusOK,
+		Body:   models.HealthResponse{Status: models.HealthStatusUp},
+	}
+
+	// Convert response to a comparable struct.
+	got := utils.ExpectedServerResponse[models.HealthResponse]{
+		Status: resp.StatusCode,
+		Body:   res,
+	}
+
+	// Test.
+	if diff := cmp.Diff(expected, got); diff != "" {
+		t.Fatalf("Failed for GetHealthz() (-want, +got)\n%s", diff)
+	}
+}
+
+// Test if GetReadyz() reports the status of each district,
+// and is only unready if every district is down.
+func TestGetReadyz(t *testing.T) {
+	tests := []struct {
+		Name     string
+		Down     bool
+		Expected utils.ExpectedServerResponse[models.ReadinessResponse]
+	}{
+		{
+			Name: "Up",
+			Down: false,
+			Expected: utils.ExpectedServerResponse[models.ReadinessResponse]{
+				Status: fiber.StatusOK,
+				Body: models.ReadinessResponse{
+					Status: models.HealthStatusUp,
+					Districts: []models.DistrictHealth{
+						{Base: repository.FakeBase, Status: models.HealthStatusUp, CheckedAt: time.Time{}},
+					},
+				},
+			},
+		},
+		{
+			Name: "Down",
+			Down: true,
+			Expected: utils.ExpectedServerResponse[models.ReadinessResponse]{
+				Status: fiber.StatusServiceUnavailable,
+				Body: models.ReadinessResponse{
+					Status: models.HealthStatusDown,
+					Districts: []models.DistrictHealth{
+						{Base: repository.FakeBase, Status: models.HealthStatusDown, Error: "server unreachable", CheckedAt: time.Time{}},
+					},
+				},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		// Set up testing server.
+		server := &repository.Server{
+			App: fiber.New(fiber.Config{
+				JSONEncoder: sonic.Marshal,
+				JSONDecoder: sonic.Unmarshal,
+			}),
+			Health: health.NewTestHealth(test.Down),
+		}
+
+		// Register GetReadyz() as the handler
+		// for the default route.
+		server.App.Get("/", utils.WrapController(server, GetReadyz))
+
+		// Test the request.
+		resp, _ := server.App.Test(httptest.NewRequest("GET", "http://fake.url/", nil))
+
+		// Parse the body.
+		resBody, _ := io.ReadAll(resp.Body)
+		res := models.ReadinessResponse{}
+
+		sonic.Unmarshal(resBody, &res)
+
+		// Convert response to a comparable struct.
+		got := utils.ExpectedServerResponse[models.ReadinessResponse]{
+			Status: resp.StatusCode,
+			Body:   res,
+		}
+
+		// Test.
+		if diff := cmp.Diff(test.Expected, got); diff != "" {
+			t.Fatalf("Failed for GetReadyz() %s (-want, +got)\n%s", test.Name, diff)
+		}
+	}
+}
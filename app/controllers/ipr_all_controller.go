@@ -5,14 +5,19 @@ import (
 
 	"github.com/Threqt1/HACApi/app/models"
 	"github.com/Threqt1/HACApi/pkg/repository"
+	"github.com/Threqt1/HACApi/pkg/utils"
+	"github.com/gocolly/colly"
 	"github.com/gofiber/fiber/v2"
 )
 
 // PostIPRAll handles POST requests to the IPR/All endpoint.
 //
 //	@Description	Returns all the IPRs for the user, or just the dates depending on the DatesOnly parameter's value in the body.
+//	@Description	If the normalize parameter is true, typed grade and ISO-8601 date fields are added under "normalized" alongside the raw strings.
+//	@Description	If the partial parameter is true, IPR dates which fail to load are left out and listed under "errors", instead of failing the request.
 //	@Tags			ipr
 //	@Param			request	body	models.IprAllRequestBody	false	"Body Params"
+//	@Param			Authorization	header	string	false	"Bearer session token from /login, used instead of the credentials in the body"
 //	@Accept			json
 //	@Produce		json
 //	@Success		200	{object}	models.IPRResponse
@@ -22,56 +27,78 @@ func PostIPRAll(server *repository.Server, ctx *fiber.Ctx) error {
 	params := new(models.IprAllRequestBody)
 
 	// Check if parsing body was successful.
-	if err := ctx.BodyParser(params); err != nil {
+	if err := utils.ParseBody(ctx, params); err != nil {
 		return ctx.Status(fiber.StatusBadRequest).JSON(models.IPRResponse{
-			HTTPError: models.HTTPError{
-				Error:   true,
-				Message: repository.ErrorBadBodyParams.Error(),
-			},
+			HTTPError: utils.NewHTTPError(ctx, repository.ErrorBadBodyParams),
 		})
 	}
 
+	// Fill in the credentials bound to the session token, if one was passed.
+	if token := utils.GetBearerToken(ctx); token != "" {
+		credentials, err := server.Cache.GetSession(token)
+
+		// Error out if the session is invalid or expired.
+		if err != nil {
+			return ctx.Status(fiber.StatusUnauthorized).JSON(models.IPRResponse{
+				HTTPError: utils.NewHTTPError(ctx, repository.ErrorInvalidSession),
+			})
+		}
+
+		params.BaseRequestBody = credentials
+	}
+
 	// Check if the body parameters are valid.
 	if err := server.Validator.Struct(params); err != nil {
 		return ctx.Status(fiber.StatusBadRequest).JSON(models.IPRResponse{
-			HTTPError: models.HTTPError{
-				Error:   true,
-				Message: repository.ErrorBadBodyParams.Error(),
-			},
+			HTTPError: utils.NewHTTPError(ctx, repository.ErrorBadBodyParams, utils.ValidationFields(err)...),
+		})
+	}
+
+	// Verify the base is an allowed HAC URL, and normalize it.
+	base, err := server.BaseURLs.Validate(params.Base)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(models.IPRResponse{
+			HTTPError: utils.NewHTTPError(ctx, fmt.Errorf("%w: %s", repository.ErrorInvalidBase, err), "base"),
 		})
 	}
+	params.Base = base
+
+	// Tag the logs with the district and a hash of the user.
+	utils.SetLogIdentity(ctx, params.Username, params.Base)
 
 	// Form a cache key.
 	cacheKey := fmt.Sprintf("%s\n%s\n%s", params.Username, params.Password, params.Base)
 
 	// Try logging in, or grab the cached collector
-	collector, err := server.Cache.GetOrLogin(cacheKey)
+	collector, err := server.Cache.GetOrLogin(ctx.UserContext(), cacheKey)
 
 	// Check if the login failed.
 	if err != nil {
-		return ctx.Status(fiber.StatusBadRequest).JSON(models.IPRResponse{
-			HTTPError: models.HTTPError{
-				Error:   true,
-				Message: repository.ErrorInvalidAuthentication.Error(),
-			},
+		status, loginErr := utils.LoginErrorResponse(err)
+		return ctx.Status(status).JSON(models.IPRResponse{
+			HTTPError: utils.NewHTTPError(ctx, loginErr),
 		})
 	}
 
-	// Get IPRs.
-	iprs, err := server.Querier.GetIPRAll(collector, *params)
+	// Get IPRs, and the dates which failed if partial results were requested.
+	var itemErrors []models.ItemError
+	iprs, err := utils.RetryOnExpiredSession(ctx.UserContext(), server.Cache, cacheKey, collector, func(collector *colly.Collector) ([]models.IPR, error) {
+		iprs, errs, err := server.Querier.GetIPRAll(ctx.UserContext(), collector, *params)
+		itemErrors = errs
+		return iprs, err
+	})
 
 	// Check if getting IPRs succeeded.
 	if err != nil {
-		return ctx.Status(fiber.StatusInternalServerError).JSON(models.IPRResponse{
-			HTTPError: models.HTTPError{
-				Error:   true,
-				Message: repository.ErrorInternalError.Error(),
-			},
+		status, queryErr := utils.QueryErrorResponse(err)
+		return ctx.Status(status).JSON(models.IPRResponse{
+			HTTPError: utils.NewHTTPError(ctx, queryErr),
 		})
 	}
 
 	// Return the grabbed IPRs.
 	return ctx.Status(fiber.StatusOK).JSON(models.IPRResponse{
-		IPR: iprs,
+		IPR:    iprs,
+		Errors: itemErrors,
 	})
 }
@@ -12,7 +12,6 @@ import (
 	"github.com/Threqt1/HACApi/pkg/utils"
 	"github.com/Threqt1/HACApi/platform/cache"
 	"github.com/bytedance/sonic"
-	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/go-cmp/cmp"
 )
@@ -27,8 +26,9 @@ func TestPostIPRAll_AllValidInputs(t *testing.T) {
 			JSONDecoder: sonic.Unmarshal,
 		}),
 		Querier:   queries.NewTestQuerier(),
-		Validator: validator.New(),
+		Validator: utils.NewValidator(),
 		Cache:     cache.NewTestCache(),
+		BaseURLs:  utils.NewTestBaseURLPolicy(),
 	}
 
 	// Register PostIPRAll() as the handler
@@ -88,8 +88,9 @@ func TestPostIPRAll_BadBodyParams(t *testing.T) {
 			JSONDecoder: sonic.Unmarshal,
 		}),
 		Querier:   queries.NewTestQuerier(),
-		Validator: validator.New(),
+		Validator: utils.NewValidator(),
 		Cache:     cache.NewTestCache(),
+		BaseURLs:  utils.NewTestBaseURLPolicy(),
 	}
 
 	// Register PostIPRAll() as the handler
@@ -125,6 +126,7 @@ func TestPostIPRAll_BadBodyParams(t *testing.T) {
 			HTTPError: models.HTTPError{
 				Error:   true,
 				Message: repository.ErrorBadBodyParams.Error(),
+				Code:    models.ErrorCodeBadRequestField,
 			},
 		},
 	}
@@ -151,8 +153,9 @@ func TestPostIPRAll_BadBodyParams_InvalidModel(t *testing.T) {
 			JSONDecoder: sonic.Unmarshal,
 		}),
 		Querier:   queries.NewTestQuerier(),
-		Validator: validator.New(),
+		Validator: utils.NewValidator(),
 		Cache:     cache.NewTestCache(),
+		BaseURLs:  utils.NewTestBaseURLPolicy(),
 	}
 
 	// Register PostIPRAll() as the handler
@@ -189,6 +192,8 @@ func TestPostIPRAll_BadBodyParams_InvalidModel(t *testing.T) {
 			HTTPError: models.HTTPError{
 				Error:   true,
 				Message: repository.ErrorBadBodyParams.Error(),
+				Code:    models.ErrorCodeBadRequestField,
+				Fields:  []string{"username", "password", "base"},
 			},
 		},
 	}
@@ -215,8 +220,9 @@ func TestPostIPRAll_InvalidCredentials(t *testing.T) {
 			JSONDecoder: sonic.Unmarshal,
 		}),
 		Querier:   queries.NewTestQuerier(),
-		Validator: validator.New(),
+		Validator: utils.NewValidator(),
 		Cache:     cache.NewTestCache(),
+		BaseURLs:  utils.NewTestBaseURLPolicy(),
 	}
 
 	// Register PostIPRAll() as the handler
@@ -228,7 +234,7 @@ func TestPostIPRAll_InvalidCredentials(t *testing.T) {
 		BaseRequestBody: models.BaseRequestBody{
 			Username: "bad username",
 			Password: "bad password",
-			Base:     "bad base",
+			Base:     repository.FakeBase,
 		},
 	}
 	body, _ := sonic.Marshal(bodyData)
@@ -253,6 +259,7 @@ func TestPostIPRAll_InvalidCredentials(t *testing.T) {
 			HTTPError: models.HTTPError{
 				Error:   true,
 				Message: repository.ErrorInvalidAuthentication.Error(),
+				Code:    models.ErrorCodeInvalidCredentials,
 			},
 		},
 	}
@@ -279,8 +286,9 @@ func TestPostIPRAll_InternalError(t *testing.T) {
 			JSONDecoder: sonic.Unmarshal,
 		}),
 		Querier:   queries.NewTestErrorQuerier(),
-		Validator: validator.New(),
+		Validator: utils.NewValidator(),
 		Cache:     cache.NewTestCache(),
+		BaseURLs:  utils.NewTestBaseURLPolicy(),
 	}
 
 	// Register PostIPRAll() as the handler
@@ -317,6 +325,7 @@ func TestPostIPRAll_InternalError(t *testing.T) {
 			HTTPError: models.HTTPError{
 				Error:   true,
 				Message: repository.ErrorInternalError.Error(),
+				Code:    models.ErrorCodeInternal,
 			},
 		},
 	}
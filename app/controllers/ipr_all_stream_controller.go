@@ -0,0 +1,91 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Threqt1/HACApi/app/models"
+	"github.com/Threqt1/HACApi/pkg/repository"
+	"github.com/Threqt1/HACApi/pkg/utils"
+	"github.com/gocolly/colly"
+	"github.com/gofiber/fiber/v2"
+)
+
+// PostIPRAllStream handles POST requests to the /ipr/all/stream endpoint.
+//
+//	@Description	Works like the "/ipr/all" endpoint, except the response is a stream of Server-Sent Events.
+//	@Description	An "ipr" event is sent with each IPR as soon as it is parsed, in the order they finish in.
+//	@Description	The stream ends with a "done" event, listing the items which failed if partial results were requested, or an "error" event.
+//	@Description	Errors before the stream starts are sent back as JSON, like the "/ipr/all" endpoint.
+//	@Tags			ipr
+//	@Param			request	body	models.IprAllRequestBody	false	"Body Params"
+//	@Param			Authorization	header	string	false	"Bearer session token from /login, used instead of the credentials in the body"
+//	@Accept			json
+//	@Produce		text/event-stream
+//	@Success		200	{object}	models.StreamDone
+//	@Router			/ipr/all/stream [post]
+func PostIPRAllStream(server *repository.Server, ctx *fiber.Ctx) error {
+	// Parse body.
+	params := new(models.IprAllRequestBody)
+
+	// Check if parsing body was successful.
+	if err := utils.ParseBody(ctx, params); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(models.IPRResponse{
+			HTTPError: utils.NewHTTPError(ctx, repository.ErrorBadBodyParams),
+		})
+	}
+
+	// Fill in the credentials bound to the session token, if one was passed.
+	if token := utils.GetBearerToken(ctx); token != "" {
+		credentials, err := server.Cache.GetSession(token)
+
+		// Error out if the session is invalid or expired.
+		if err != nil {
+			return ctx.Status(fiber.StatusUnauthorized).JSON(models.IPRResponse{
+				HTTPError: utils.NewHTTPError(ctx, repository.ErrorInvalidSession),
+			})
+		}
+
+		params.BaseRequestBody = credentials
+	}
+
+	// Check if the body parameters are valid.
+	if err := server.Validator.Struct(params); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(models.IPRResponse{
+			HTTPError: utils.NewHTTPError(ctx, repository.ErrorBadBodyParams, utils.ValidationFields(err)...),
+		})
+	}
+
+	// Verify the base is an allowed HAC URL, and normalize it.
+	base, err := server.BaseURLs.Validate(params.Base)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(models.IPRResponse{
+			HTTPError: utils.NewHTTPError(ctx, fmt.Errorf("%w: %s", repository.ErrorInvalidBase, err), "base"),
+		})
+	}
+	params.Base = base
+
+	// Tag the logs with the district and a hash of the user.
+	utils.SetLogIdentity(ctx, params.Username, params.Base)
+
+	// Form a cache key.
+	cacheKey := fmt.Sprintf("%s\n%s\n%s", params.Username, params.Password, params.Base)
+
+	// Try logging in, or grab the cached collector
+	collector, err := server.Cache.GetOrLogin(ctx.UserContext(), cacheKey)
+
+	// Check if the login failed.
+	if err != nil {
+		status, loginErr := utils.LoginErrorResponse(err)
+		return ctx.Status(status).JSON(models.IPRResponse{
+			HTTPError: utils.NewHTTPError(ctx, loginErr),
+		})
+	}
+
+	// Stream the IPRs, once the handler returns.
+	utils.StreamEvents(ctx, server.Cache, cacheKey, collector, "ipr", func(streamCtx context.Context, collector *colly.Collector, emit func(int, models.IPR)) ([]models.ItemError, error) {
+		return server.Querier.StreamIPRAll(streamCtx, collector, *params, emit)
+	})
+
+	return nil
+}
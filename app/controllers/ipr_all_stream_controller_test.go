@@ -0,0 +1,132 @@
+package controllers
+
+import (
+	"bytes"
+	"io"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Threqt1/HACApi/app/models"
+	"github.com/Threqt1/HACApi/app/queries"
+	"github.com/Threqt1/HACApi/pkg/repository"
+	"github.com/Threqt1/HACApi/pkg/utils"
+	"github.com/Threqt1/HACApi/platform/cache"
+	"github.com/bytedance/sonic"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/go-cmp/cmp"
+)
+
+// Test if PostIPRAllStream() streams each IPR,
+// then completes.
+func TestPostIPRAllStream_AllValidInputs(t *testing.T) {
+	// Set up testing server.
+	server := &repository.Server{
+		App: fiber.New(fiber.Config{
+			JSONEncoder: sonic.Marshal,
+			JSONDecoder: sonic.Unmarshal,
+		}),
+		Querier:   queries.NewTestQuerier(),
+		Validator: utils.NewValidator(),
+		Cache:     cache.NewTestCache(),
+		BaseURLs:  utils.NewTestBaseURLPolicy(),
+	}
+
+	// Register PostIPRAllStream() as the handler
+	// for the default route.
+	server.App.Post("/", utils.WrapController(server, PostIPRAllStream))
+
+	// Create request data.
+	bodyData := models.IprAllRequestBody{
+		BaseRequestBody: models.BaseRequestBody{
+			Username: repository.FakeUsername,
+			Password: repository.FakePassword,
+			Base:     repository.FakeBase,
+		},
+	}
+	body, _ := sonic.Marshal(bodyData)
+
+	// Create a test request.
+	req := httptest.NewRequest("POST", "http://fake.url/", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	// Test the request.
+	resp, _ := server.App.Test(req)
+
+	// Read the body.
+	resBody, _ := io.ReadAll(resp.Body)
+
+	// Make expected body.
+	expected := utils.ExpectedServerResponse[string]{
+		Status: fiber.StatusOK,
+		Body: "event: ipr\ndata: {\"date\":\"\",\"entries\":null}\n\n" +
+			"event: done\ndata: {}\n\n",
+	}
+
+	// Convert response to a comparable struct.
+	got := utils.ExpectedServerResponse[string]{
+		Status: resp.StatusCode,
+		Body:   string(resBody),
+	}
+
+	// Test.
+	if diff := cmp.Diff(expected, got); diff != "" {
+		t.Fatalf("Failed for PostIPRAllStream() All Valid Inputs (-want, +got)\n%s", diff)
+	}
+}
+
+// Test if PostIPRAllStream() ends the stream with an
+// error event due to an internal error.
+func TestPostIPRAllStream_InternalError(t *testing.T) {
+	// Set up testing server.
+	server := &repository.Server{
+		App: fiber.New(fiber.Config{
+			JSONEncoder: sonic.Marshal,
+			JSONDecoder: sonic.Unmarshal,
+		}),
+		Querier:   queries.NewTestErrorQuerier(),
+		Validator: utils.NewValidator(),
+		Cache:     cache.NewTestCache(),
+		BaseURLs:  utils.NewTestBaseURLPolicy(),
+	}
+
+	// Register PostIPRAllStream() as the handler
+	// for the default route.
+	server.App.Post("/", utils.WrapController(server, PostIPRAllStream))
+
+	// Create request data.
+	bodyData := models.IprAllRequestBody{
+		BaseRequestBody: models.BaseRequestBody{
+			Username: repository.FakeUsername,
+			Password: repository.FakePassword,
+			Base:     repository.FakeBase,
+		},
+	}
+	body, _ := sonic.Marshal(bodyData)
+
+	// Create a test request.
+	req := httptest.NewRequest("POST", "http://fake.url/", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	// Test the request.
+	resp, _ := server.App.Test(req)
+
+	// Read the body.
+	resBody, _ := io.ReadAll(resp.Body)
+
+	// Make expected body.
+	expected := utils.ExpectedServerResponse[string]{
+		Status: fiber.StatusOK,
+		Body:   "event: error\ndata: {\"err\":true,\"msg\":\"" + repository.ErrorInternalError.Error() + "\",\"code\":\"INTERNAL_ERROR\"}\n\n",
+	}
+
+	// Convert response to a comparable struct.
+	got := utils.ExpectedServerResponse[string]{
+		Status: resp.StatusCode,
+		Body:   string(resBody),
+	}
+
+	// Test.
+	if diff := cmp.Diff(expected, got); diff != "" {
+		t.Fatalf("Failed for PostIPRAllStream() Internal Error (-want, +got)\n%s", diff)
+	}
+}
@@ -6,6 +6,8 @@ import (
 
 	"github.com/Threqt1/HACApi/app/models"
 	"github.com/Threqt1/HACApi/pkg/repository"
+	"github.com/Threqt1/HACApi/pkg/utils"
+	"github.com/gocolly/colly"
 	"github.com/gofiber/fiber/v2"
 )
 
@@ -14,8 +16,11 @@ import (
 //	@Description	Returns the IPR(s) for the user. If the date parameter is not passed into the body or is invalid, the most recent IPR is returned.
 //	@Description	It is important the format of the date follows the format "01/02/2006" (01 = month, 02 = day, 2006 = year), with leading zeros like shown in the format.
 //	@Description	For all possible dates, refer to the "/ipr/all" endpoint.
+//	@Description	If the normalize parameter is true, typed grade and ISO-8601 date fields are added under "normalized" alongside the raw strings.
+//	@Description	If the partial parameter is true, IPR dates which fail to load are left out and listed under "errors", instead of failing the request.
 //	@Tags			ipr
 //	@Param			request	body	models.IprRequestBody	false	"Body Params"
+//	@Param			Authorization	header	string	false	"Bearer session token from /login, used instead of the credentials in the body"
 //	@Accept			json
 //	@Produce		json
 //	@Success		200	{object}	models.IPRResponse
@@ -25,68 +30,93 @@ func PostIPR(server *repository.Server, ctx *fiber.Ctx) error {
 	params := new(models.IprRequestBody)
 
 	// Check if parsing succeeded.
-	if err := ctx.BodyParser(params); err != nil {
+	if err := utils.ParseBody(ctx, params); err != nil {
 		return ctx.Status(fiber.StatusBadRequest).JSON(models.IPRResponse{
-			HTTPError: models.HTTPError{
-				Error:   true,
-				Message: repository.ErrorBadBodyParams.Error(),
-			},
+			HTTPError: utils.NewHTTPError(ctx, repository.ErrorBadBodyParams),
 		})
 	}
 
+	// Fill in the credentials bound to the session token, if one was passed.
+	if token := utils.GetBearerToken(ctx); token != "" {
+		credentials, err := server.Cache.GetSession(token)
+
+		// Error out if the session is invalid or expired.
+		if err != nil {
+			return ctx.Status(fiber.StatusUnauthorized).JSON(models.IPRResponse{
+				HTTPError: utils.NewHTTPError(ctx, repository.ErrorInvalidSession),
+			})
+		}
+
+		params.BaseRequestBody = credentials
+	}
+
 	// Verify the validity of the body parameters.
 	valid := true
+	var fields []string
 
 	if err := server.Validator.Struct(params); err != nil {
 		valid = false
+		fields = utils.ValidationFields(err)
 	}
 
 	// Confirm the date is valid.
 	if _, err := time.Parse("01/02/2006", params.Date); len(params.Date) > 0 && err != nil {
 		valid = false
+		fields = append(fields, "date")
 	}
 
 	// If they aren't valid, send back an error.
 	if !valid {
 		return ctx.Status(fiber.StatusBadRequest).JSON(models.IPRResponse{
-			HTTPError: models.HTTPError{
-				Error:   true,
-				Message: repository.ErrorBadBodyParams.Error(),
-			},
+			HTTPError: utils.NewHTTPError(ctx, repository.ErrorBadBodyParams, fields...),
+		})
+	}
+
+	// Verify the base is an allowed HAC URL, and normalize it.
+	base, err := server.BaseURLs.Validate(params.Base)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(models.IPRResponse{
+			HTTPError: utils.NewHTTPError(ctx, fmt.Errorf("%w: %s", repository.ErrorInvalidBase, err), "base"),
 		})
 	}
+	params.Base = base
+
+	// Tag the logs with the district and a hash of the user.
+	utils.SetLogIdentity(ctx, params.Username, params.Base)
 
 	// Form a cache key.
 	cacheKey := fmt.Sprintf("%s\n%s\n%s", params.Username, params.Password, params.Base)
 
 	// Try logging in, or grab the cached collector.
-	collector, err := server.Cache.GetOrLogin(cacheKey)
+	collector, err := server.Cache.GetOrLogin(ctx.UserContext(), cacheKey)
 
 	// Check if login succeeded.
 	if err != nil {
-		return ctx.Status(fiber.StatusBadRequest).JSON(models.IPRResponse{
-			HTTPError: models.HTTPError{
-				Error:   true,
-				Message: repository.ErrorInvalidAuthentication.Error(),
-			},
+		status, loginErr := utils.LoginErrorResponse(err)
+		return ctx.Status(status).JSON(models.IPRResponse{
+			HTTPError: utils.NewHTTPError(ctx, loginErr),
 		})
 	}
 
-	// Get IPR.
-	ipr, err := server.Querier.GetIPR(collector, *params)
+	// Get IPR, and the dates which failed if partial results were requested.
+	var itemErrors []models.ItemError
+	ipr, err := utils.RetryOnExpiredSession(ctx.UserContext(), server.Cache, cacheKey, collector, func(collector *colly.Collector) ([]models.IPR, error) {
+		ipr, errs, err := server.Querier.GetIPR(ctx.UserContext(), collector, *params)
+		itemErrors = errs
+		return ipr, err
+	})
 
 	// Check if getting IPR succeeded.
 	if err != nil {
-		return ctx.Status(fiber.StatusInternalServerError).JSON(models.IPRResponse{
-			HTTPError: models.HTTPError{
-				Error:   true,
-				Message: repository.ErrorInternalError.Error(),
-			},
+		status, queryErr := utils.QueryErrorResponse(err)
+		return ctx.Status(status).JSON(models.IPRResponse{
+			HTTPError: utils.NewHTTPError(ctx, queryErr),
 		})
 	}
 
 	// Return the IPR.
 	return ctx.Status(fiber.StatusOK).JSON(models.IPRResponse{
-		IPR: ipr,
+		IPR:    ipr,
+		Errors: itemErrors,
 	})
 }
@@ -12,7 +12,6 @@ import (
 	"github.com/Threqt1/HACApi/pkg/utils"
 	"github.com/Threqt1/HACApi/platform/cache"
 	"github.com/bytedance/sonic"
-	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/go-cmp/cmp"
 )
@@ -27,8 +26,9 @@ func TestPostIPR_AllValidInputs_NoDate(t *testing.T) {
 			JSONDecoder: sonic.Unmarshal,
 		}),
 		Querier:   queries.NewTestQuerier(),
-		Validator: validator.New(),
+		Validator: utils.NewValidator(),
 		Cache:     cache.NewTestCache(),
+		BaseURLs:  utils.NewTestBaseURLPolicy(),
 	}
 
 	// Register PostIPR() as the handler
@@ -88,8 +88,9 @@ func TestPostIPR_AllValidInputs_WithDate(t *testing.T) {
 			JSONDecoder: sonic.Unmarshal,
 		}),
 		Querier:   queries.NewTestQuerier(),
-		Validator: validator.New(),
+		Validator: utils.NewValidator(),
 		Cache:     cache.NewTestCache(),
+		BaseURLs:  utils.NewTestBaseURLPolicy(),
 	}
 
 	// Register PostIPR() as the handler
@@ -150,8 +151,9 @@ func TestPostIPR_BadBodyParams(t *testing.T) {
 			JSONDecoder: sonic.Unmarshal,
 		}),
 		Querier:   queries.NewTestQuerier(),
-		Validator: validator.New(),
+		Validator: utils.NewValidator(),
 		Cache:     cache.NewTestCache(),
+		BaseURLs:  utils.NewTestBaseURLPolicy(),
 	}
 
 	// Register PostIPR() as the handler
@@ -187,6 +189,7 @@ func TestPostIPR_BadBodyParams(t *testing.T) {
 			HTTPError: models.HTTPError{
 				Error:   true,
 				Message: repository.ErrorBadBodyParams.Error(),
+				Code:    models.ErrorCodeBadRequestField,
 			},
 		},
 	}
@@ -213,8 +216,9 @@ func TestPostIPR_BadBodyParams_InvalidModel(t *testing.T) {
 			JSONDecoder: sonic.Unmarshal,
 		}),
 		Querier:   queries.NewTestQuerier(),
-		Validator: validator.New(),
+		Validator: utils.NewValidator(),
 		Cache:     cache.NewTestCache(),
+		BaseURLs:  utils.NewTestBaseURLPolicy(),
 	}
 
 	// Register PostIPR() as the handler
@@ -251,6 +255,8 @@ func TestPostIPR_BadBodyParams_InvalidModel(t *testing.T) {
 			HTTPError: models.HTTPError{
 				Error:   true,
 				Message: repository.ErrorBadBodyParams.Error(),
+				Code:    models.ErrorCodeBadRequestField,
+				Fields:  []string{"username", "password", "base"},
 			},
 		},
 	}
@@ -277,8 +283,9 @@ func TestPostIPR_InvalidBodyParams_InvalidDate(t *testing.T) {
 			JSONDecoder: sonic.Unmarshal,
 		}),
 		Querier:   queries.NewTestQuerier(),
-		Validator: validator.New(),
+		Validator: utils.NewValidator(),
 		Cache:     cache.NewTestCache(),
+		BaseURLs:  utils.NewTestBaseURLPolicy(),
 	}
 
 	// Register PostIPR() as the handler
@@ -316,6 +323,8 @@ func TestPostIPR_InvalidBodyParams_InvalidDate(t *testing.T) {
 			HTTPError: models.HTTPError{
 				Error:   true,
 				Message: repository.ErrorBadBodyParams.Error(),
+				Code:    models.ErrorCodeBadRequestField,
+				Fields:  []string{"date"},
 			},
 		},
 	}
@@ -342,8 +351,9 @@ func TestPostIPR_InvalidCredentials(t *testing.T) {
 			JSONDecoder: sonic.Unmarshal,
 		}),
 		Querier:   queries.NewTestQuerier(),
-		Validator: validator.New(),
+		Validator: utils.NewValidator(),
 		Cache:     cache.NewTestCache(),
+		BaseURLs:  utils.NewTestBaseURLPolicy(),
 	}
 
 	// Register PostIPR() as the handler
@@ -355,7 +365,7 @@ func TestPostIPR_InvalidCredentials(t *testing.T) {
 		BaseRequestBody: models.BaseRequestBody{
 			Username: "bad username",
 			Password: "bad password",
-			Base:     "bad base",
+			Base:     repository.FakeBase,
 		},
 	}
 	body, _ := sonic.Marshal(bodyData)
@@ -380,6 +390,7 @@ func TestPostIPR_InvalidCredentials(t *testing.T) {
 			HTTPError: models.HTTPError{
 				Error:   true,
 				Message: repository.ErrorInvalidAuthentication.Error(),
+				Code:    models.ErrorCodeInvalidCredentials,
 			},
 		},
 	}
@@ -406,8 +417,9 @@ func TestPostIPR_InternalError(t *testing.T) {
 			JSONDecoder: sonic.Unmarshal,
 		}),
 		Querier:   queries.NewTestErrorQuerier(),
-		Validator: validator.New(),
+		Validator: utils.NewValidator(),
 		Cache:     cache.NewTestCache(),
+		BaseURLs:  utils.NewTestBaseURLPolicy(),
 	}
 
 	// Register PostIPR() as the handler
@@ -444,6 +456,7 @@ func TestPostIPR_InternalError(t *testing.T) {
 			HTTPError: models.HTTPError{
 				Error:   true,
 				Message: repository.ErrorInternalError.Error(),
+				Code:    models.ErrorCodeInternal,
 			},
 		},
 	}
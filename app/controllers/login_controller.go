@@ -5,6 +5,7 @@ import (
 
 	"github.com/Threqt1/HACApi/app/models"
 	"github.com/Threqt1/HACApi/pkg/repository"
+	"github.com/Threqt1/HACApi/pkg/utils"
 	"github.com/gofiber/fiber/v2"
 )
 
@@ -12,6 +13,7 @@ import (
 //
 //	@Description	Pre-registers the user with the API by logging them into HAC early, and caching the cookies.
 //	@Description	Subsequent requests using the same credentials will use these stored cookies, leading to faster response times for other endpoints.
+//	@Description	The response contains an opaque session token, which can be passed to other endpoints as an "Authorization: Bearer" header instead of the credentials.
 //	@Tags			auth
 //	@Param			request	body	models.LoginRequestBody	false	"Body Params"
 //	@Accept			json
@@ -25,51 +27,67 @@ func PostLogin(server *repository.Server, ctx *fiber.Ctx) error {
 	// Check if parsing was successful.
 	if err := ctx.BodyParser(params); err != nil {
 		return ctx.Status(fiber.StatusBadRequest).JSON(models.LoginResponse{
-			HTTPError: models.HTTPError{
-				Error:   true,
-				Message: repository.ErrorBadBodyParams.Error(),
-			},
+			HTTPError: utils.NewHTTPError(ctx, repository.ErrorBadBodyParams),
 		})
 	}
 
 	// Verify the validity of the body parameters.
 	if err := server.Validator.Struct(params); err != nil {
 		return ctx.Status(fiber.StatusBadRequest).JSON(models.LoginResponse{
-			HTTPError: models.HTTPError{
-				Error:   true,
-				Message: repository.ErrorBadBodyParams.Error(),
-			},
+			HTTPError: utils.NewHTTPError(ctx, repository.ErrorBadBodyParams, utils.ValidationFields(err)...),
 		})
 	}
 
+	// Verify the base is an allowed HAC URL, and normalize it.
+	base, err := server.BaseURLs.Validate(params.Base)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(models.LoginResponse{
+			HTTPError: utils.NewHTTPError(ctx, fmt.Errorf("%w: %s", repository.ErrorInvalidBase, err), "base"),
+		})
+	}
+	params.Base = base
+
+	// Tag the logs with the district and a hash of the user.
+	utils.SetLogIdentity(ctx, params.Username, params.Base)
+
 	// Form a cache key.
 	cacheKey := fmt.Sprintf("%s\n%s\n%s", params.Username, params.Password, params.Base)
 
 	// Cache the user, if not cached already.
-	collector, err := server.Cache.GetOrLogin(cacheKey)
+	collector, err := server.Cache.GetOrLogin(ctx.UserContext(), cacheKey)
 
 	// Check if caching succeeded.
 	if err != nil {
-		return ctx.Status(fiber.StatusBadRequest).JSON(models.LoginResponse{
-			HTTPError: models.HTTPError{
-				Error:   true,
-				Message: repository.ErrorInvalidAuthentication.Error(),
-			},
+		status, loginErr := utils.LoginErrorResponse(err)
+		return ctx.Status(status).JSON(models.LoginResponse{
+			HTTPError: utils.NewHTTPError(ctx, loginErr),
+		})
+	}
+
+	// Mint a session token for the credentials.
+	session, err := server.Cache.NewSession(params.BaseRequestBody)
+
+	if err != nil {
+		return ctx.Status(fiber.StatusInternalServerError).JSON(models.LoginResponse{
+			HTTPError: utils.NewHTTPError(ctx, repository.ErrorInternalError),
 		})
 	}
 
 	// Get response from the querier.
-	login, err := server.Querier.GetLogin(collector, *params)
+	login, err := server.Querier.GetLogin(ctx.UserContext(), collector, *params)
 
 	if err != nil {
 		return ctx.Status(fiber.StatusInternalServerError).JSON(models.LoginResponse{
-			HTTPError: models.HTTPError{
-				Error:   true,
-				Message: repository.ErrorInternalError.Error(),
-			},
+			HTTPError: utils.NewHTTPError(ctx, repository.ErrorInternalError),
 		})
 	}
 
+	// Attach the session token to the login.
+	for i := range login {
+		login[i].Token = session.Token
+		login[i].Expires = session.Expires
+	}
+
 	// Send back information about the login.
 	return ctx.Status(fiber.StatusOK).JSON(models.LoginResponse{
 		Login: login,
@@ -2,6 +2,7 @@ package controllers
 
 import (
 	"bytes"
+	"fmt"
 	"io"
 	"net/http/httptest"
 	"testing"
@@ -12,7 +13,6 @@ import (
 	"github.com/Threqt1/HACApi/pkg/utils"
 	"github.com/Threqt1/HACApi/platform/cache"
 	"github.com/bytedance/sonic"
-	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/go-cmp/cmp"
 )
@@ -27,8 +27,9 @@ func TestPostLogin_AllValidInputs(t *testing.T) {
 			JSONDecoder: sonic.Unmarshal,
 		}),
 		Querier:   queries.NewTestQuerier(),
-		Validator: validator.New(),
+		Validator: utils.NewValidator(),
 		Cache:     cache.NewTestCache(),
+		BaseURLs:  utils.NewTestBaseURLPolicy(),
 	}
 
 	// Register PostLogin() as the handler
@@ -64,8 +65,8 @@ func TestPostLogin_AllValidInputs(t *testing.T) {
 		Body: models.LoginResponse{
 			Login: []models.Login{{
 				Username: repository.FakeUsername,
-				Password: repository.FakePassword,
 				Base:     repository.FakeBase,
+				Token:    repository.FakeToken,
 			}},
 		},
 	}
@@ -92,8 +93,9 @@ func TestPostLogin_BadBodyParams(t *testing.T) {
 			JSONDecoder: sonic.Unmarshal,
 		}),
 		Querier:   queries.NewTestQuerier(),
-		Validator: validator.New(),
+		Validator: utils.NewValidator(),
 		Cache:     cache.NewTestCache(),
+		BaseURLs:  utils.NewTestBaseURLPolicy(),
 	}
 
 	// Register PostLogin() as the handler
@@ -129,6 +131,7 @@ func TestPostLogin_BadBodyParams(t *testing.T) {
 			HTTPError: models.HTTPError{
 				Error:   true,
 				Message: repository.ErrorBadBodyParams.Error(),
+				Code:    models.ErrorCodeBadRequestField,
 			},
 		},
 	}
@@ -155,8 +158,9 @@ func TestPostLogin_BadBodyParams_InvalidModel(t *testing.T) {
 			JSONDecoder: sonic.Unmarshal,
 		}),
 		Querier:   queries.NewTestQuerier(),
-		Validator: validator.New(),
+		Validator: utils.NewValidator(),
 		Cache:     cache.NewTestCache(),
+		BaseURLs:  utils.NewTestBaseURLPolicy(),
 	}
 
 	// Register PostLogin() as the handler
@@ -193,6 +197,8 @@ func TestPostLogin_BadBodyParams_InvalidModel(t *testing.T) {
 			HTTPError: models.HTTPError{
 				Error:   true,
 				Message: repository.ErrorBadBodyParams.Error(),
+				Code:    models.ErrorCodeBadRequestField,
+				Fields:  []string{"username", "password", "base"},
 			},
 		},
 	}
@@ -219,8 +225,9 @@ func TestPostLogin_InvalidCredentials(t *testing.T) {
 			JSONDecoder: sonic.Unmarshal,
 		}),
 		Querier:   queries.NewTestQuerier(),
-		Validator: validator.New(),
+		Validator: utils.NewValidator(),
 		Cache:     cache.NewTestCache(),
+		BaseURLs:  utils.NewTestBaseURLPolicy(),
 	}
 
 	// Register PostLogin() as the handler
@@ -232,7 +239,7 @@ func TestPostLogin_InvalidCredentials(t *testing.T) {
 		BaseRequestBody: models.BaseRequestBody{
 			Username: "bad username",
 			Password: "bad password",
-			Base:     "bad base",
+			Base:     repository.FakeBase,
 		},
 	}
 	body, _ := sonic.Marshal(bodyData)
@@ -257,6 +264,7 @@ func TestPostLogin_InvalidCredentials(t *testing.T) {
 			HTTPError: models.HTTPError{
 				Error:   true,
 				Message: repository.ErrorInvalidAuthentication.Error(),
+				Code:    models.ErrorCodeInvalidCredentials,
 			},
 		},
 	}
@@ -273,6 +281,73 @@ func TestPostLogin_InvalidCredentials(t *testing.T) {
 	}
 }
 
+// Test if PostLogin() errors out
+// for bases rejected by the base URL policy.
+func TestPostLogin_InvalidBase(t *testing.T) {
+	// Set up testing server.
+	server := &repository.Server{
+		App: fiber.New(fiber.Config{
+			JSONEncoder: sonic.Marshal,
+			JSONDecoder: sonic.Unmarshal,
+		}),
+		Querier:   queries.NewTestQuerier(),
+		Validator: utils.NewValidator(),
+		Cache:     cache.NewTestCache(),
+		BaseURLs:  utils.NewTestBaseURLPolicy(),
+	}
+
+	// Register PostLogin() as the handler
+	// for the default route.
+	server.App.Post("/", utils.WrapController(server, PostLogin))
+
+	// Create request data.
+	bodyData := models.LoginRequestBody{
+		BaseRequestBody: models.BaseRequestBody{
+			Username: repository.FakeUsername,
+			Password: repository.FakePassword,
+			Base:     "http://fake.url",
+		},
+	}
+	body, _ := sonic.Marshal(bodyData)
+
+	// Create a test request.
+	req := httptest.NewRequest("POST", "http://fake.url/", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	// Test the request.
+	resp, _ := server.App.Test(req)
+
+	// Parse the body.
+	resBody, _ := io.ReadAll(resp.Body)
+	res := models.LoginResponse{}
+
+	sonic.Unmarshal(resBody, &res)
+
+	// Make expected body.
+	expected := utils.ExpectedServerResponse[models.LoginResponse]{
+		Status: fiber.StatusBadRequest,
+		Body: models.LoginResponse{
+			HTTPError: models.HTTPError{
+				Error:   true,
+				Message: fmt.Sprintf("%s: %s", repository.ErrorInvalidBase, utils.ErrorBaseInsecure),
+				Code:    models.ErrorCodeBadRequestField,
+				Fields:  []string{"base"},
+			},
+		},
+	}
+
+	// Convert response to a comparable struct.
+	got := utils.ExpectedServerResponse[models.LoginResponse]{
+		Status: resp.StatusCode,
+		Body:   res,
+	}
+
+	// Test.
+	if diff := cmp.Diff(expected, got); diff != "" {
+		t.Fatalf("Failed for PostLogin() Invalid Base (-want, +got)\n%s", diff)
+	}
+}
+
 // Test if PostLogin() errors out
 // for internal errors.
 func TestPostLogin_InternalError(t *testing.T) {
@@ -283,8 +358,9 @@ func TestPostLogin_InternalError(t *testing.T) {
 			JSONDecoder: sonic.Unmarshal,
 		}),
 		Querier:   queries.NewTestErrorQuerier(),
-		Validator: validator.New(),
+		Validator: utils.NewValidator(),
 		Cache:     cache.NewTestCache(),
+		BaseURLs:  utils.NewTestBaseURLPolicy(),
 	}
 
 	// Register PostLogin() as the handler
@@ -321,6 +397,7 @@ func TestPostLogin_InternalError(t *testing.T) {
 			HTTPError: models.HTTPError{
 				Error:   true,
 				Message: repository.ErrorInternalError.Error(),
+				Code:    models.ErrorCodeInternal,
 			},
 		},
 	}
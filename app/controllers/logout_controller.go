@@ -0,0 +1,38 @@
+package controllers
+
+import (
+	"github.com/Threqt1/HACApi/app/models"
+	"github.com/Threqt1/HACApi/pkg/repository"
+	"github.com/Threqt1/HACApi/pkg/utils"
+	"github.com/gofiber/fiber/v2"
+)
+
+// PostLogout handles POST requests to the logout endpoint.
+//
+//	@Description	Revokes the session token passed in the "Authorization: Bearer" header, and evicts the cached login for it.
+//	@Tags			auth
+//	@Param			Authorization	header	string	true	"Bearer session token from /login"
+//	@Produce		json
+//	@Success		200	{object}	models.LogoutResponse
+//	@Router			/logout [post]
+func PostLogout(server *repository.Server, ctx *fiber.Ctx) error {
+	// Get the session token.
+	token := utils.GetBearerToken(ctx)
+
+	// Check if a token was passed.
+	if token == "" {
+		return ctx.Status(fiber.StatusUnauthorized).JSON(models.LogoutResponse{
+			HTTPError: utils.NewHTTPError(ctx, repository.ErrorInvalidSession),
+		})
+	}
+
+	// Evict the session.
+	if err := server.Cache.DeleteSession(token); err != nil {
+		return ctx.Status(fiber.StatusUnauthorized).JSON(models.LogoutResponse{
+			HTTPError: utils.NewHTTPError(ctx, repository.ErrorInvalidSession),
+		})
+	}
+
+	// Confirm the logout.
+	return ctx.Status(fiber.StatusOK).JSON(models.LogoutResponse{})
+}
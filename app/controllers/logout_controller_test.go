@@ -0,0 +1,174 @@
+package controllers
+
+import (
+	"io"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Threqt1/HACApi/app/models"
+	"github.com/Threqt1/HACApi/app/queries"
+	"github.com/Threqt1/HACApi/pkg/repository"
+	"github.com/Threqt1/HACApi/pkg/utils"
+	"github.com/Threqt1/HACApi/platform/cache"
+	"github.com/bytedance/sonic"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/go-cmp/cmp"
+)
+
+// Test if PostLogout() functions correctly
+// given a valid session token.
+func TestPostLogout_ValidToken(t *testing.T) {
+	// Set up testing server.
+	server := &repository.Server{
+		App: fiber.New(fiber.Config{
+			JSONEncoder: sonic.Marshal,
+			JSONDecoder: sonic.Unmarshal,
+		}),
+		Querier:   queries.NewTestQuerier(),
+		Validator: utils.NewValidator(),
+		Cache:     cache.NewTestCache(),
+	}
+
+	// Register PostLogout() as the handler
+	// for the default route.
+	server.App.Post("/", utils.WrapController(server, PostLogout))
+
+	// Create a test request.
+	req := httptest.NewRequest("POST", "http://fake.url/", nil)
+	req.Header.Set("Authorization", "Bearer "+repository.FakeToken)
+
+	// Test the request.
+	resp, _ := server.App.Test(req)
+
+	// Parse the body.
+	resBody, _ := io.ReadAll(resp.Body)
+	res := models.LogoutResponse{}
+
+	sonic.Unmarshal(resBody, &res)
+
+	// Make expected body.
+	expected := utils.ExpectedServerResponse[models.LogoutResponse]{
+		Status: fiber.StatusOK,
+		Body:   models.LogoutResponse{},
+	}
+
+	// Convert response to a comparable struct.
+	got := utils.ExpectedServerResponse[models.LogoutResponse]{
+		Status: resp.StatusCode,
+		Body:   res,
+	}
+
+	// Test.
+	if diff := cmp.Diff(expected, got); diff != "" {
+		t.Fatalf("Failed for PostLogout() Valid Token (-want, +got)\n%s", diff)
+	}
+}
+
+// Test if PostLogout() errors out
+// without a session token.
+func TestPostLogout_NoToken(t *testing.T) {
+	// Set up testing server.
+	server := &repository.Server{
+		App: fiber.New(fiber.Config{
+			JSONEncoder: sonic.Marshal,
+			JSONDecoder: sonic.Unmarshal,
+		}),
+		Querier:   queries.NewTestQuerier(),
+		Validator: utils.NewValidator(),
+		Cache:     cache.NewTestCache(),
+	}
+
+	// Register PostLogout() as the handler
+	// for the default route.
+	server.App.Post("/", utils.WrapController(server, PostLogout))
+
+	// Create a test request.
+	req := httptest.NewRequest("POST", "http://fake.url/", nil)
+
+	// Test the request.
+	resp, _ := server.App.Test(req)
+
+	// Parse the body.
+	resBody, _ := io.ReadAll(resp.Body)
+	res := models.LogoutResponse{}
+
+	sonic.Unmarshal(resBody, &res)
+
+	// Make expected body.
+	expected := utils.ExpectedServerResponse[models.LogoutResponse]{
+		Status: fiber.StatusUnauthorized,
+		Body: models.LogoutResponse{
+			HTTPError: models.HTTPError{
+				Error:   true,
+				Message: repository.ErrorInvalidSession.Error(),
+				Code:    models.ErrorCodeSessionExpired,
+			},
+		},
+	}
+
+	// Convert response to a comparable struct.
+	got := utils.ExpectedServerResponse[models.LogoutResponse]{
+		Status: resp.StatusCode,
+		Body:   res,
+	}
+
+	// Test.
+	if diff := cmp.Diff(expected, got); diff != "" {
+		t.Fatalf("Failed for PostLogout() No Token (-want, +got)\n%s", diff)
+	}
+}
+
+// Test if PostLogout() errors out
+// given an invalid session token.
+func TestPostLogout_InvalidToken(t *testing.T) {
+	// Set up testing server.
+	server := &repository.Server{
+		App: fiber.New(fiber.Config{
+			JSONEncoder: sonic.Marshal,
+			JSONDecoder: sonic.Unmarshal,
+		}),
+		Querier:   queries.NewTestQuerier(),
+		Validator: utils.NewValidator(),
+		Cache:     cache.NewTestCache(),
+	}
+
+	// Register PostLogout() as the handler
+	// for the default route.
+	server.App.Post("/", utils.WrapController(server, PostLogout))
+
+	// Create a test request.
+	req := httptest.NewRequest("POST", "http://fake.url/", nil)
+	req.Header.Set("Authorization", "Bearer bad-token")
+
+	// Test the request.
+	resp, _ := server.App.Test(req)
+
+	// Parse the body.
+	resBody, _ := io.ReadAll(resp.Body)
+	res := models.LogoutResponse{}
+
+	sonic.Unmarshal(resBody, &res)
+
+	// Make expected body.
+	expected := utils.ExpectedServerResponse[models.LogoutResponse]{
+		Status: fiber.StatusUnauthorized,
+		Body: models.LogoutResponse{
+			HTTPError: models.HTTPError{
+				Error:   true,
+				Message: repository.ErrorInvalidSession.Error(),
+				Code:    models.ErrorCodeSessionExpired,
+			},
+		},
+	}
+
+	// Convert response to a comparable struct.
+	got := utils.ExpectedServerResponse[models.LogoutResponse]{
+		Status: resp.StatusCode,
+		Body:   res,
+	}
+
+	// Test.
+	if diff := cmp.Diff(expected, got); diff != "" {
+		t.Fatalf("Failed for PostLogout() Invalid Token (-want, +got)\n%s", diff)
+	}
+}
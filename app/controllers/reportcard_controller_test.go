@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"io"
 	"net/http/httptest"
+	"strconv"
 	"testing"
 
 	"github.com/Threqt1/HACApi/app/models"
@@ -12,7 +13,6 @@ import (
 	"github.com/Threqt1/HACApi/pkg/utils"
 	"github.com/Threqt1/HACApi/platform/cache"
 	"github.com/bytedance/sonic"
-	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/go-cmp/cmp"
 )
@@ -27,8 +27,9 @@ func TestPostReportCard(t *testing.T) {
 			JSONDecoder: sonic.Unmarshal,
 		}),
 		Querier:   queries.NewTestQuerier(),
-		Validator: validator.New(),
+		Validator: utils.NewValidator(),
 		Cache:     cache.NewTestCache(),
+		BaseURLs:  utils.NewTestBaseURLPolicy(),
 	}
 
 	// Register PostReportCard() as the handler
@@ -88,8 +89,9 @@ func TestPostReportCard_BadBodyParams(t *testing.T) {
 			JSONDecoder: sonic.Unmarshal,
 		}),
 		Querier:   queries.NewTestQuerier(),
-		Validator: validator.New(),
+		Validator: utils.NewValidator(),
 		Cache:     cache.NewTestCache(),
+		BaseURLs:  utils.NewTestBaseURLPolicy(),
 	}
 
 	// Register PostReportCard() as the handler
@@ -125,6 +127,7 @@ func TestPostReportCard_BadBodyParams(t *testing.T) {
 			HTTPError: models.HTTPError{
 				Error:   true,
 				Message: repository.ErrorBadBodyParams.Error(),
+				Code:    models.ErrorCodeBadRequestField,
 			},
 		},
 	}
@@ -151,8 +154,9 @@ func TestPostReportCard_BadBodyParams_InvalidModel(t *testing.T) {
 			JSONDecoder: sonic.Unmarshal,
 		}),
 		Querier:   queries.NewTestQuerier(),
-		Validator: validator.New(),
+		Validator: utils.NewValidator(),
 		Cache:     cache.NewTestCache(),
+		BaseURLs:  utils.NewTestBaseURLPolicy(),
 	}
 
 	// Register PostReportCard() as the handler
@@ -189,6 +193,8 @@ func TestPostReportCard_BadBodyParams_InvalidModel(t *testing.T) {
 			HTTPError: models.HTTPError{
 				Error:   true,
 				Message: repository.ErrorBadBodyParams.Error(),
+				Code:    models.ErrorCodeBadRequestField,
+				Fields:  []string{"username", "password", "base"},
 			},
 		},
 	}
@@ -215,8 +221,9 @@ func TestPostReportCard_InvalidCredentials(t *testing.T) {
 			JSONDecoder: sonic.Unmarshal,
 		}),
 		Querier:   queries.NewTestQuerier(),
-		Validator: validator.New(),
+		Validator: utils.NewValidator(),
 		Cache:     cache.NewTestCache(),
+		BaseURLs:  utils.NewTestBaseURLPolicy(),
 	}
 
 	// Register PostReportCard() as the handler
@@ -228,7 +235,7 @@ func TestPostReportCard_InvalidCredentials(t *testing.T) {
 		BaseRequestBody: models.BaseRequestBody{
 			Username: "bad username",
 			Password: "bad password",
-			Base:     "bad base",
+			Base:     repository.FakeBase,
 		},
 	}
 	body, _ := sonic.Marshal(bodyData)
@@ -253,6 +260,7 @@ func TestPostReportCard_InvalidCredentials(t *testing.T) {
 			HTTPError: models.HTTPError{
 				Error:   true,
 				Message: repository.ErrorInvalidAuthentication.Error(),
+				Code:    models.ErrorCodeInvalidCredentials,
 			},
 		},
 	}
@@ -279,8 +287,9 @@ func TestPostReportCard_InternalError(t *testing.T) {
 			JSONDecoder: sonic.Unmarshal,
 		}),
 		Querier:   queries.NewTestErrorQuerier(),
-		Validator: validator.New(),
+		Validator: utils.NewValidator(),
 		Cache:     cache.NewTestCache(),
+		BaseURLs:  utils.NewTestBaseURLPolicy(),
 	}
 
 	// Register PostReportCard() as the handler
@@ -317,6 +326,7 @@ func TestPostReportCard_InternalError(t *testing.T) {
 			HTTPError: models.HTTPError{
 				Error:   true,
 				Message: repository.ErrorInternalError.Error(),
+				Code:    models.ErrorCodeInternal,
 			},
 		},
 	}
@@ -332,3 +342,56 @@ func TestPostReportCard_InternalError(t *testing.T) {
 		t.Fatalf("Failed for PostReportCard() Internal Error (-want, +got)\n%s", diff)
 	}
 }
+
+// Test if PostReportCard() sends back a spreadsheet
+// if the Accept header prefers one.
+func TestPostReportCard_AcceptXLSX(t *testing.T) {
+	// Set up testing server.
+	server := &repository.Server{
+		App: fiber.New(fiber.Config{
+			JSONEncoder: sonic.Marshal,
+			JSONDecoder: sonic.Unmarshal,
+		}),
+		Querier:   queries.NewTestQuerier(),
+		Validator: utils.NewValidator(),
+		Cache:     cache.NewTestCache(),
+		BaseURLs:  utils.NewTestBaseURLPolicy(),
+	}
+
+	// Register PostReportCard() as the handler
+	// for the default route.
+	server.App.Post("/", utils.WrapController(server, PostReportCard))
+
+	// Create request data.
+	bodyData := models.ReportCardRequestBody{
+		BaseRequestBody: models.BaseRequestBody{
+			Username: repository.FakeUsername,
+			Password: repository.FakePassword,
+			Base:     repository.FakeBase,
+		},
+	}
+	body, _ := sonic.Marshal(bodyData)
+
+	// Create a test request, preferring a spreadsheet over JSON.
+	req := httptest.NewRequest("POST", "http://fake.url/", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet, application/json;q=0.5")
+
+	// Test the request.
+	resp, _ := server.App.Test(req)
+	resBody, _ := io.ReadAll(resp.Body)
+
+	// Make expected response.
+	expected := []string{
+		"200",
+		"application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+		`attachment; filename="reportcard.xlsx"`,
+		"PK",
+	}
+	got := []string{strconv.Itoa(resp.StatusCode), resp.Header.Get("Content-Type"), resp.Header.Get("Content-Disposition"), string(resBody[:2])}
+
+	// Test.
+	if diff := cmp.Diff(expected, got); diff != "" {
+		t.Fatalf("Failed for PostReportCard() Accept XLSX (-want, +got)\n%s", diff)
+	}
+}
@@ -0,0 +1,147 @@
+package controllers
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Threqt1/HACApi/app/models"
+	"github.com/Threqt1/HACApi/pkg/repository"
+	"github.com/Threqt1/HACApi/pkg/utils"
+	"github.com/Threqt1/HACApi/platform/calendar"
+	"github.com/gocolly/colly"
+	"github.com/gofiber/fiber/v2"
+)
+
+// GetScheduleCalendar handles GET and POST requests to the schedule calendar endpoint.
+//
+//	@Description	Returns an iCalendar file with an event for every active class, repeating weekly on the days the class meets, from startDate until endDate.
+//	@Description	It is important the format of the dates follows the format "01/02/2006" (01 = month, 02 = day, 2006 = year), and period times the format "15:04".
+//	@Description	HAC doesn't list bell times, so classes in periods without times, or meeting on days which aren't weekdays (like A/B days), are left out.
+//	@Description	Calendar apps can subscribe with a GET request, passing the params and a calendar token from POST /calendar/token as query parameters, like ?token=...&startDate=08/15/2022&endDate=12/16/2022&periods[0][period]=1&periods[0][start]=08:45&periods[0][end]=09:35. Credentials are only accepted in POST bodies.
+//	@Description	Calendar tokens last a year, unless revoked with DELETE /calendar/token. Session tokens from /login are also accepted, but expire 24 hours after logging in, so subscriptions made with them stop working the next day.
+//	@Tags			calendar
+//	@Param			request	body	models.ScheduleCalendarRequestBody	false	"Body Params"
+//	@Param			Authorization	header	string	false	"Bearer calendar token from /calendar/token, or session token from /login, used instead of the credentials in the body"
+//	@Accept			json
+//	@Produce		text/calendar
+//	@Success		200	{string}	string
+//	@Failure		400	{object}	models.CalendarResponse
+//	@Router			/calendar/schedule.ics [get]
+//	@Router			/calendar/schedule.ics [post]
+func GetScheduleCalendar(server *repository.Server, ctx *fiber.Ctx) error {
+	// Parse params.
+	params := new(models.ScheduleCalendarRequestBody)
+
+	// Check if parsing the params succeeded.
+	if err := utils.ParseCalendarParams(ctx, params); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(models.CalendarResponse{
+			HTTPError: utils.NewHTTPError(ctx, repository.ErrorBadBodyParams),
+		})
+	}
+
+	// Credentials are never taken from the query string, so GET requests need a token.
+	token := utils.GetCalendarToken(ctx)
+	if ctx.Method() == fiber.MethodGet {
+		params.BaseRequestBody = models.BaseRequestBody{}
+
+		if token == "" {
+			return ctx.Status(fiber.StatusUnauthorized).JSON(models.CalendarResponse{
+				HTTPError: utils.NewHTTPError(ctx, repository.ErrorInvalidSession),
+			})
+		}
+	}
+
+	// Fill in the credentials bound to the calendar or session token, if one was passed.
+	if token != "" {
+		credentials, err := utils.GetCalendarCredentials(server.Cache, token)
+
+		// Error out if the token is invalid or expired.
+		if err != nil {
+			return ctx.Status(fiber.StatusUnauthorized).JSON(models.CalendarResponse{
+				HTTPError: utils.NewHTTPError(ctx, repository.ErrorInvalidSession),
+			})
+		}
+
+		params.BaseRequestBody = credentials
+	}
+
+	// Verify the validity of the params.
+	valid := true
+	var fields []string
+
+	if err := server.Validator.Struct(params); err != nil {
+		valid = false
+		fields = utils.ValidationFields(err)
+	}
+
+	// Confirm the dates are valid, and in order.
+	startDate, startErr := time.Parse("01/02/2006", params.StartDate)
+	if len(params.StartDate) > 0 && startErr != nil {
+		valid = false
+		fields = append(fields, "startDate")
+	}
+	endDate, endErr := time.Parse("01/02/2006", params.EndDate)
+	if len(params.EndDate) > 0 && (endErr != nil || (startErr == nil && endDate.Before(startDate))) {
+		valid = false
+		fields = append(fields, "endDate")
+	}
+
+	// Confirm the period times are valid.
+	periods, err := calendar.ParsePeriods(params.Periods)
+	if len(params.Periods) > 0 && err != nil {
+		valid = false
+		fields = append(fields, "periods")
+	}
+
+	// If they aren't valid, send back an error.
+	if !valid {
+		return ctx.Status(fiber.StatusBadRequest).JSON(models.CalendarResponse{
+			HTTPError: utils.NewHTTPError(ctx, repository.ErrorBadBodyParams, fields...),
+		})
+	}
+
+	// Verify the base is an allowed HAC URL, and normalize it.
+	base, err := server.BaseURLs.Validate(params.Base)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(models.CalendarResponse{
+			HTTPError: utils.NewHTTPError(ctx, fmt.Errorf("%w: %s", repository.ErrorInvalidBase, err), "base"),
+		})
+	}
+	params.Base = base
+
+	// Tag the logs with the district and a hash of the user.
+	utils.SetLogIdentity(ctx, params.Username, params.Base)
+
+	// Form a cache key.
+	cacheKey := fmt.Sprintf("%s\n%s\n%s", params.Username, params.Password, params.Base)
+
+	// Try logging in, or grab the cached collector.
+	collector, err := server.Cache.GetOrLogin(ctx.UserContext(), cacheKey)
+
+	// Check if the login was successful.
+	if err != nil {
+		status, loginErr := utils.LoginErrorResponse(err)
+		return ctx.Status(status).JSON(models.CalendarResponse{
+			HTTPError: utils.NewHTTPError(ctx, loginErr),
+		})
+	}
+
+	// Get the schedule.
+	scheduleParams := models.ScheduleRequestBody{BaseRequestBody: params.BaseRequestBody}
+	schedule, err := utils.RetryOnExpiredSession(ctx.UserContext(), server.Cache, cacheKey, collector, func(collector *colly.Collector) ([]models.Schedule, error) {
+		return server.Querier.GetSchedule(ctx.UserContext(), collector, scheduleParams)
+	})
+
+	// Check if getting the schedule succeeded.
+	if err != nil {
+		status, queryErr := utils.QueryErrorResponse(err)
+		return ctx.Status(status).JSON(models.CalendarResponse{
+			HTTPError: utils.NewHTTPError(ctx, queryErr),
+		})
+	}
+
+	// Return the schedule as a calendar.
+	utils.SetCalendarHeaders(ctx, "schedule.ics")
+	ctx.Status(fiber.StatusOK)
+	return calendar.Schedule(schedule, periods, startDate, endDate, params.Username+"\n"+params.Base, time.Now()).Write(ctx)
+}
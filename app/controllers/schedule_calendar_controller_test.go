@@ -0,0 +1,104 @@
+package controllers
+
+import (
+	"bytes"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/Threqt1/HACApi/app/models"
+	"github.com/Threqt1/HACApi/pkg/repository"
+	"github.com/Threqt1/HACApi/pkg/utils"
+	"github.com/bytedance/sonic"
+	"github.com/gofiber/fiber/v2"
+)
+
+// Test if GetScheduleCalendar() sends back a calendar
+// for a POST request with valid inputs.
+func TestGetScheduleCalendar_Post(t *testing.T) {
+	server := newTestCalendarServer(GetScheduleCalendar)
+
+	// Create request data.
+	bodyData := models.ScheduleCalendarRequestBody{
+		BaseRequestBody: models.BaseRequestBody{
+			Username: repository.FakeUsername,
+			Password: repository.FakePassword,
+			Base:     repository.FakeBase,
+		},
+		StartDate: "08/15/2022",
+		EndDate:   "12/16/2022",
+		Periods:   []models.PeriodTime{{Period: "1", Start: "08:45", End: "09:35"}},
+	}
+	body, _ := sonic.Marshal(bodyData)
+
+	// Create a test request.
+	req := httptest.NewRequest("POST", "http://fake.url/", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	// Test the request.
+	resp, _ := server.App.Test(req)
+
+	testCalendar_ExpectCalendar(t, "GetScheduleCalendar() Post", resp)
+}
+
+// Test if GetScheduleCalendar() sends back a calendar for a GET
+// request with the params in the query string.
+func TestGetScheduleCalendar_GetWithToken(t *testing.T) {
+	server := newTestCalendarServer(GetScheduleCalendar)
+
+	// Create the query string.
+	query := url.Values{
+		"token":              {repository.FakeToken},
+		"startDate":          {"08/15/2022"},
+		"endDate":            {"12/16/2022"},
+		"periods[0][period]": {"1"},
+		"periods[0][start]":  {"08:45"},
+		"periods[0][end]":    {"09:35"},
+	}
+
+	// Create a test request.
+	req := httptest.NewRequest("GET", "http://fake.url/?"+query.Encode(), nil)
+
+	// Test the request.
+	resp, _ := server.App.Test(req)
+
+	testCalendar_ExpectCalendar(t, "GetScheduleCalendar() Get With Token", resp)
+}
+
+// Test if GetScheduleCalendar() errors out if the dates
+// are out of order, and the period times are invalid.
+func TestGetScheduleCalendar_InvalidDatesAndPeriods(t *testing.T) {
+	server := newTestCalendarServer(GetScheduleCalendar)
+
+	// Create request data.
+	bodyData := models.ScheduleCalendarRequestBody{
+		BaseRequestBody: models.BaseRequestBody{
+			Username: repository.FakeUsername,
+			Password: repository.FakePassword,
+			Base:     repository.FakeBase,
+		},
+		StartDate: "12/16/2022",
+		EndDate:   "08/15/2022",
+		Periods:   []models.PeriodTime{{Period: "1", Start: "09:35", End: "08:45"}},
+	}
+	body, _ := sonic.Marshal(bodyData)
+
+	// Create a test request.
+	req := httptest.NewRequest("POST", "http://fake.url/", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	// Test the request.
+	resp, _ := server.App.Test(req)
+
+	testCalendar_ExpectError(t, "GetScheduleCalendar() Invalid Dates And Periods", resp, utils.ExpectedServerResponse[models.CalendarResponse]{
+		Status: fiber.StatusBadRequest,
+		Body: models.CalendarResponse{
+			HTTPError: models.HTTPError{
+				Error:   true,
+				Message: repository.ErrorBadBodyParams.Error(),
+				Code:    models.ErrorCodeBadRequestField,
+				Fields:  []string{"endDate", "periods"},
+			},
+		},
+	})
+}
@@ -5,6 +5,8 @@ import (
 
 	"github.com/Threqt1/HACApi/app/models"
 	"github.com/Threqt1/HACApi/pkg/repository"
+	"github.com/Threqt1/HACApi/pkg/utils"
+	"github.com/gocolly/colly"
 	"github.com/gofiber/fiber/v2"
 )
 
@@ -13,6 +15,7 @@ import (
 //	@Description	Returns the schedule for the user.
 //	@Tags			schedule
 //	@Param			request	body	models.ScheduleRequestBody	false	"Body params"
+//	@Param			Authorization	header	string	false	"Bearer session token from /login, used instead of the credentials in the body"
 //	@Accept			json
 //	@Produce		json
 //	@Success		200	{object}	models.ScheduleResponse
@@ -22,51 +25,69 @@ func PostSchedule(server *repository.Server, ctx *fiber.Ctx) error {
 	params := new(models.ScheduleRequestBody)
 
 	// Check if parsing was successful.
-	if err := ctx.BodyParser(params); err != nil {
+	if err := utils.ParseBody(ctx, params); err != nil {
 		return ctx.Status(fiber.StatusBadRequest).JSON(models.ScheduleResponse{
-			HTTPError: models.HTTPError{
-				Error:   true,
-				Message: repository.ErrorBadBodyParams.Error(),
-			},
+			HTTPError: utils.NewHTTPError(ctx, repository.ErrorBadBodyParams),
 		})
 	}
 
+	// Fill in the credentials bound to the session token, if one was passed.
+	if token := utils.GetBearerToken(ctx); token != "" {
+		credentials, err := server.Cache.GetSession(token)
+
+		// Error out if the session is invalid or expired.
+		if err != nil {
+			return ctx.Status(fiber.StatusUnauthorized).JSON(models.ScheduleResponse{
+				HTTPError: utils.NewHTTPError(ctx, repository.ErrorInvalidSession),
+			})
+		}
+
+		params.BaseRequestBody = credentials
+	}
+
 	// Check for body parameter validity.
 	if err := server.Validator.Struct(params); err != nil {
 		return ctx.Status(fiber.StatusBadRequest).JSON(models.ScheduleResponse{
-			HTTPError: models.HTTPError{
-				Error:   true,
-				Message: repository.ErrorBadBodyParams.Error(),
-			},
+			HTTPError: utils.NewHTTPError(ctx, repository.ErrorBadBodyParams, utils.ValidationFields(err)...),
+		})
+	}
+
+	// Verify the base is an allowed HAC URL, and normalize it.
+	base, err := server.BaseURLs.Validate(params.Base)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(models.ScheduleResponse{
+			HTTPError: utils.NewHTTPError(ctx, fmt.Errorf("%w: %s", repository.ErrorInvalidBase, err), "base"),
 		})
 	}
+	params.Base = base
+
+	// Tag the logs with the district and a hash of the user.
+	utils.SetLogIdentity(ctx, params.Username, params.Base)
 
 	// Form a cache key.
 	cacheKey := fmt.Sprintf("%s\n%s\n%s", params.Username, params.Password, params.Base)
 
 	// Try logging in, or grab the cached collector.
-	collector, err := server.Cache.GetOrLogin(cacheKey)
+	collector, err := server.Cache.GetOrLogin(ctx.UserContext(), cacheKey)
 
 	// Check if the login was successful.
 	if err != nil {
-		return ctx.Status(fiber.StatusBadRequest).JSON(models.ScheduleResponse{
-			HTTPError: models.HTTPError{
-				Error:   true,
-				Message: repository.ErrorInvalidAuthentication.Error(),
-			},
+		status, loginErr := utils.LoginErrorResponse(err)
+		return ctx.Status(status).JSON(models.ScheduleResponse{
+			HTTPError: utils.NewHTTPError(ctx, loginErr),
 		})
 	}
 
 	// Get the schedule.
-	schedule, err := server.Querier.GetSchedule(collector, *params)
+	schedule, err := utils.RetryOnExpiredSession(ctx.UserContext(), server.Cache, cacheKey, collector, func(collector *colly.Collector) ([]models.Schedule, error) {
+		return server.Querier.GetSchedule(ctx.UserContext(), collector, *params)
+	})
 
 	// Check if getting the schedule succeeded.
 	if err != nil {
-		return ctx.Status(fiber.StatusInternalServerError).JSON(models.ScheduleResponse{
-			HTTPError: models.HTTPError{
-				Error:   true,
-				Message: repository.ErrorInternalError.Error(),
-			},
+		status, queryErr := utils.QueryErrorResponse(err)
+		return ctx.Status(status).JSON(models.ScheduleResponse{
+			HTTPError: utils.NewHTTPError(ctx, queryErr),
 		})
 	}
 
@@ -0,0 +1,129 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/Threqt1/HACApi/app/models"
+	"github.com/Threqt1/HACApi/pkg/repository"
+	"github.com/Threqt1/HACApi/pkg/utils"
+	"github.com/gofiber/fiber/v2"
+)
+
+// PostSubscription handles POST requests to the subscriptions endpoint.
+//
+//	@Description	Registers a webhook, which is posted the changes to the user's classwork and IPRs every interval.
+//	@Description	Changes are assignments added, grades changed, averages changed and IPRs posted, found by comparing against the previous check.
+//	@Description	Payloads are signed in the X-HACApi-Signature header as "t=<unix timestamp>,v1=<hex HMAC-SHA256 of "<timestamp>.<body>">", keyed with the secret in the response.
+//	@Description	A subscription made with a session token ends along with the session.
+//	@Description	Each account can have a limited amount of subscriptions, 5 by default, and the API as a whole 1000; past that, a 429 with the LIMIT_REACHED code is sent back.
+//	@Tags			subscriptions
+//	@Param			request	body	models.SubscriptionRequestBody	false	"Body params"
+//	@Param			Authorization	header	string	false	"Bearer session token from /login, used instead of the credentials in the body"
+//	@Accept			json
+//	@Produce		json
+//	@Success		201	{object}	models.SubscriptionResponse
+//	@Router			/subscriptions [post]
+func PostSubscription(server *repository.Server, ctx *fiber.Ctx) error {
+	// Parse body.
+	params := new(models.SubscriptionRequestBody)
+
+	// Check if parsing was successful.
+	if err := utils.ParseBody(ctx, params); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(models.SubscriptionResponse{
+			HTTPError: utils.NewHTTPError(ctx, repository.ErrorBadBodyParams),
+		})
+	}
+
+	// Fill in the credentials bound to the session token, if one was passed.
+	token := utils.GetBearerToken(ctx)
+	if token != "" {
+		credentials, err := server.Cache.GetSession(token)
+
+		// Error out if the session is invalid or expired.
+		if err != nil {
+			return ctx.Status(fiber.StatusUnauthorized).JSON(models.SubscriptionResponse{
+				HTTPError: utils.NewHTTPError(ctx, repository.ErrorInvalidSession),
+			})
+		}
+
+		params.BaseRequestBody = credentials
+	}
+
+	// Check for body parameter validity.
+	if err := server.Validator.Struct(params); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(models.SubscriptionResponse{
+			HTTPError: utils.NewHTTPError(ctx, repository.ErrorBadBodyParams, utils.ValidationFields(err)...),
+		})
+	}
+
+	// Verify the base is an allowed HAC URL, and normalize it.
+	base, err := server.BaseURLs.Validate(params.Base)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(models.SubscriptionResponse{
+			HTTPError: utils.NewHTTPError(ctx, fmt.Errorf("%w: %s", repository.ErrorInvalidBase, err), "base"),
+		})
+	}
+	params.Base = base
+
+	// Verify the webhook URL is allowed, so subscriptions can't post to internal hosts.
+	webhookURL, err := server.WebhookURLs.ValidateURL(params.WebhookURL)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(models.SubscriptionResponse{
+			HTTPError: utils.NewHTTPError(ctx, fmt.Errorf("%w: %s", repository.ErrorInvalidWebhookURL, err), "webhookUrl"),
+		})
+	}
+	params.WebhookURL = webhookURL
+
+	// Tag the logs with the district and a hash of the user.
+	utils.SetLogIdentity(ctx, params.Username, params.Base)
+
+	// Form a cache key.
+	cacheKey := fmt.Sprintf("%s\n%s\n%s", params.Username, params.Password, params.Base)
+
+	// Log in, so subscriptions can't be made with credentials which don't work.
+	if _, err := server.Cache.GetOrLogin(ctx.UserContext(), cacheKey); err != nil {
+		status, loginErr := utils.LoginErrorResponse(err)
+		return ctx.Status(status).JSON(models.SubscriptionResponse{
+			HTTPError: utils.NewHTTPError(ctx, loginErr),
+		})
+	}
+
+	// Register the subscription.
+	subscription, err := server.Subscriptions.Subscribe(*params, token)
+	if errors.Is(err, repository.ErrorSubscriptionLimit) {
+		return ctx.Status(fiber.StatusTooManyRequests).JSON(models.SubscriptionResponse{
+			HTTPError: utils.NewHTTPError(ctx, err),
+		})
+	}
+	if err != nil {
+		return ctx.Status(fiber.StatusInternalServerError).JSON(models.SubscriptionResponse{
+			HTTPError: utils.NewHTTPError(ctx, repository.ErrorInternalError),
+		})
+	}
+
+	// Return the subscription.
+	return ctx.Status(fiber.StatusCreated).JSON(models.SubscriptionResponse{
+		Subscription: &subscription,
+	})
+}
+
+// DeleteSubscription handles DELETE requests to the subscriptions endpoint.
+//
+//	@Description	Removes a subscription, so its webhook isn't posted to anymore.
+//	@Tags			subscriptions
+//	@Param			id	path	string	true	"The ID of the subscription"
+//	@Produce		json
+//	@Success		200	{object}	models.SubscriptionResponse
+//	@Router			/subscriptions/{id} [delete]
+func DeleteSubscription(server *repository.Server, ctx *fiber.Ctx) error {
+	// Remove the subscription.
+	if err := server.Subscriptions.Unsubscribe(ctx.Params("id")); err != nil {
+		return ctx.Status(fiber.StatusNotFound).JSON(models.SubscriptionResponse{
+			HTTPError: utils.NewHTTPError(ctx, repository.ErrorSubscriptionNotFound),
+		})
+	}
+
+	// Confirm the removal.
+	return ctx.Status(fiber.StatusOK).JSON(models.SubscriptionResponse{})
+}
@@ -0,0 +1,305 @@
+package controllers
+
+import (
+	"bytes"
+	"io"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Threqt1/HACApi/app/models"
+	"github.com/Threqt1/HACApi/app/queries"
+	"github.com/Threqt1/HACApi/pkg/repository"
+	"github.com/Threqt1/HACApi/pkg/utils"
+	"github.com/Threqt1/HACApi/platform/cache"
+	"github.com/Threqt1/HACApi/platform/subscriptions"
+	"github.com/bytedance/sonic"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+// Filter out the random and time-dependent fields of a subscription.
+var testSubscription_Comparer = cmpopts.IgnoreFields(models.Subscription{}, "ID", "Secret", "Created")
+
+// newTestSubscriptionServer sets up a testing server with
+// the subscription endpoints registered.
+func newTestSubscriptionServer() *repository.Server {
+	server := &repository.Server{
+		App: fiber.New(fiber.Config{
+			JSONEncoder: sonic.Marshal,
+			JSONDecoder: sonic.Unmarshal,
+		}),
+		Querier:     queries.NewTestQuerier(),
+		Validator:   utils.NewValidator(),
+		Cache:       cache.NewTestCache(),
+		BaseURLs:    utils.NewTestBaseURLPolicy(),
+		WebhookURLs: utils.NewTestBaseURLPolicy(),
+	}
+	server.Subscriptions = subscriptions.NewService(server.Cache, server.Querier, server.WebhookURLs)
+
+	server.App.Post("/", utils.WrapController(server, PostSubscription))
+	server.App.Delete("/:id", utils.WrapController(server, DeleteSubscription))
+
+	return server
+}
+
+// postTestSubscription posts a subscription to a testing server.
+func postTestSubscription(server *repository.Server, bodyData models.SubscriptionRequestBody) utils.ExpectedServerResponse[models.SubscriptionResponse] {
+	body, _ := sonic.Marshal(bodyData)
+
+	// Create a test request.
+	req := httptest.NewRequest("POST", "http://fake.url/", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	// Test the request.
+	resp, _ := server.App.Test(req)
+
+	// Parse the body.
+	resBody, _ := io.ReadAll(resp.Body)
+	res := models.SubscriptionResponse{}
+
+	sonic.Unmarshal(resBody, &res)
+
+	return utils.ExpectedServerResponse[models.SubscriptionResponse]{
+		Status: resp.StatusCode,
+		Body:   res,
+	}
+}
+
+// Test if PostSubscription() registers a subscription
+// given all valid inputs, and sends back its secret.
+func TestPostSubscription_AllValidInputs(t *testing.T) {
+	server := newTestSubscriptionServer()
+
+	// Test the request.
+	got := postTestSubscription(server, models.SubscriptionRequestBody{
+		BaseRequestBody: models.BaseRequestBody{
+			Username: repository.FakeUsername,
+			Password: repository.FakePassword,
+			Base:     repository.FakeBase,
+		},
+		Interval:   900,
+		WebhookURL: repository.FakeBase + "/hooks",
+	})
+
+	// Make expected body.
+	expected := utils.ExpectedServerResponse[models.SubscriptionResponse]{
+		Status: fiber.StatusCreated,
+		Body: models.SubscriptionResponse{
+			Subscription: &models.Subscription{
+				Base:       repository.FakeBase,
+				Interval:   900,
+				WebhookURL: repository.FakeBase + "/hooks",
+				Resources:  []string{models.SubscriptionResourceClasswork, models.SubscriptionResourceIPR},
+			},
+		},
+	}
+
+	// Test.
+	if diff := cmp.Diff(expected, got, testSubscription_Comparer); diff != "" {
+		t.Fatalf("Failed for PostSubscription() All Valid Inputs (-want, +got)\n%s", diff)
+	}
+	if got.Body.Subscription.ID == "" || got.Body.Subscription.Secret == "" {
+		t.Fatalf("Failed for PostSubscription() All Valid Inputs, expected an ID and a secret")
+	}
+}
+
+// Test if PostSubscription() errors out
+// with a webhook URL which isn't allowed.
+func TestPostSubscription_InvalidWebhookURL(t *testing.T) {
+	server := newTestSubscriptionServer()
+
+	// Test the request.
+	got := postTestSubscription(server, models.SubscriptionRequestBody{
+		BaseRequestBody: models.BaseRequestBody{
+			Username: repository.FakeUsername,
+			Password: repository.FakePassword,
+			Base:     repository.FakeBase,
+		},
+		Interval:   900,
+		WebhookURL: "https://127.0.0.1/hooks",
+	})
+
+	// Make expected body.
+	expected := utils.ExpectedServerResponse[models.SubscriptionResponse]{
+		Status: fiber.StatusBadRequest,
+		Body: models.SubscriptionResponse{
+			HTTPError: models.HTTPError{
+				Error:   true,
+				Message: repository.ErrorInvalidWebhookURL.Error() + ": " + utils.ErrorBaseNotAllowed.Error(),
+				Code:    models.ErrorCodeBadRequestField,
+				Fields:  []string{"webhookUrl"},
+			},
+		},
+	}
+
+	// Test.
+	if diff := cmp.Diff(expected, got); diff != "" {
+		t.Fatalf("Failed for PostSubscription() Invalid Webhook URL (-want, +got)\n%s", diff)
+	}
+}
+
+// Test if PostSubscription() errors out once
+// the account has as many subscriptions as allowed.
+func TestPostSubscription_LimitReached(t *testing.T) {
+	server := newTestSubscriptionServer()
+	server.Subscriptions.(*subscriptions.Service).MaxAccountSubscriptions = 1
+
+	bodyData := models.SubscriptionRequestBody{
+		BaseRequestBody: models.BaseRequestBody{
+			Username: repository.FakeUsername,
+			Password: repository.FakePassword,
+			Base:     repository.FakeBase,
+		},
+		Interval:   900,
+		WebhookURL: repository.FakeBase + "/hooks",
+	}
+
+	// Test the requests.
+	postTestSubscription(server, bodyData)
+	got := postTestSubscription(server, bodyData)
+
+	// Make expected body.
+	expected := utils.ExpectedServerResponse[models.SubscriptionResponse]{
+		Status: fiber.StatusTooManyRequests,
+		Body: models.SubscriptionResponse{
+			HTTPError: models.HTTPError{
+				Error:   true,
+				Message: repository.ErrorSubscriptionLimit.Error() + ": accounts can have 1 subscriptions",
+				Code:    models.ErrorCodeLimitReached,
+			},
+		},
+	}
+
+	// Test.
+	if diff := cmp.Diff(expected, got); diff != "" {
+		t.Fatalf("Failed for PostSubscription() Limit Reached (-want, +got)\n%s", diff)
+	}
+}
+
+// Test if PostSubscription() errors out
+// with an interval which is too short.
+func TestPostSubscription_BadBodyParams_ShortInterval(t *testing.T) {
+	server := newTestSubscriptionServer()
+
+	// Test the request.
+	got := postTestSubscription(server, models.SubscriptionRequestBody{
+		BaseRequestBody: models.BaseRequestBody{
+			Username: repository.FakeUsername,
+			Password: repository.FakePassword,
+			Base:     repository.FakeBase,
+		},
+		Interval:   10,
+		WebhookURL: repository.FakeBase + "/hooks",
+	})
+
+	// Make expected body.
+	expected := utils.ExpectedServerResponse[models.SubscriptionResponse]{
+		Status: fiber.StatusBadRequest,
+		Body: models.SubscriptionResponse{
+			HTTPError: models.HTTPError{
+				Error:   true,
+				Message: repository.ErrorBadBodyParams.Error(),
+				Code:    models.ErrorCodeBadRequestField,
+				Fields:  []string{"interval"},
+			},
+		},
+	}
+
+	// Test.
+	if diff := cmp.Diff(expected, got); diff != "" {
+		t.Fatalf("Failed for PostSubscription() Short Interval (-want, +got)\n%s", diff)
+	}
+}
+
+// Test if PostSubscription() errors out
+// given invalid credentials.
+func TestPostSubscription_InvalidCredentials(t *testing.T) {
+	server := newTestSubscriptionServer()
+
+	// Test the request.
+	got := postTestSubscription(server, models.SubscriptionRequestBody{
+		BaseRequestBody: models.BaseRequestBody{
+			Username: "bad username",
+			Password: repository.FakePassword,
+			Base:     repository.FakeBase,
+		},
+		Interval:   900,
+		WebhookURL: repository.FakeBase + "/hooks",
+	})
+
+	// Make expected body.
+	expected := utils.ExpectedServerResponse[models.SubscriptionResponse]{
+		Status: fiber.StatusBadRequest,
+		Body: models.SubscriptionResponse{
+			HTTPError: models.HTTPError{
+				Error:   true,
+				Message: repository.ErrorInvalidAuthentication.Error(),
+				Code:    models.ErrorCodeInvalidCredentials,
+			},
+		},
+	}
+
+	// Test.
+	if diff := cmp.Diff(expected, got); diff != "" {
+		t.Fatalf("Failed for PostSubscription() Invalid Credentials (-want, +got)\n%s", diff)
+	}
+}
+
+// Test if DeleteSubscription() removes a subscription,
+// and errors out once it is gone.
+func TestDeleteSubscription(t *testing.T) {
+	server := newTestSubscriptionServer()
+
+	// Register a subscription.
+	subscription, _ := server.Subscriptions.Subscribe(models.SubscriptionRequestBody{
+		BaseRequestBody: models.BaseRequestBody{
+			Username: repository.FakeUsername,
+			Password: repository.FakePassword,
+			Base:     repository.FakeBase,
+		},
+		Interval:   900,
+		WebhookURL: repository.FakeBase + "/hooks",
+	}, "")
+
+	expected := []utils.ExpectedServerResponse[models.SubscriptionResponse]{
+		// Removed.
+		{
+			Status: fiber.StatusOK,
+			Body:   models.SubscriptionResponse{},
+		},
+		// Already gone.
+		{
+			Status: fiber.StatusNotFound,
+			Body: models.SubscriptionResponse{
+				HTTPError: models.HTTPError{
+					Error:   true,
+					Message: repository.ErrorSubscriptionNotFound.Error(),
+					Code:    models.ErrorCodeNotFound,
+				},
+			},
+		},
+	}
+
+	for i, expected := range expected {
+		// Test the request.
+		resp, _ := server.App.Test(httptest.NewRequest("DELETE", "http://fake.url/"+subscription.ID, nil))
+
+		// Parse the body.
+		resBody, _ := io.ReadAll(resp.Body)
+		res := models.SubscriptionResponse{}
+
+		sonic.Unmarshal(resBody, &res)
+
+		// Convert response to a comparable struct.
+		got := utils.ExpectedServerResponse[models.SubscriptionResponse]{
+			Status: resp.StatusCode,
+			Body:   res,
+		}
+
+		// Test.
+		if diff := cmp.Diff(expected, got); diff != "" {
+			t.Fatalf("Failed for DeleteSubscription() request %d (-want, +got)\n%s", i, diff)
+		}
+	}
+}
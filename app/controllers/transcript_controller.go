@@ -5,16 +5,22 @@ import (
 
 	"github.com/Threqt1/HACApi/app/models"
 	"github.com/Threqt1/HACApi/pkg/repository"
+	"github.com/Threqt1/HACApi/pkg/utils"
+	"github.com/Threqt1/HACApi/platform/export"
+	"github.com/gocolly/colly"
 	"github.com/gofiber/fiber/v2"
 )
 
 // PostTranscript handles POST request to the transcript endpoint.
 //
 //	@Description	Returns the transcript for the user.
+//	@Description	If the normalize parameter is true, typed average and credit fields are added under "normalized" alongside the raw strings.
+//	@Description	If the format parameter is "csv" or "xlsx", or the Accept header prefers text/csv or the XLSX MIME type, the transcript is sent back as a CSV file or spreadsheet instead, with a sheet per school year.
 //	@Tags			transcript
 //	@Param			request	body	models.TranscriptRequestBody	false	"Body params"
+//	@Param			Authorization	header	string	false	"Bearer session token from /login, used instead of the credentials in the body"
 //	@Accept			json
-//	@Produce		json
+//	@Produce		json,text/csv,application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
 //	@Success		200	{object}	models.TranscriptResponse
 //	@Router			/transcript [post]
 func PostTranscript(server *repository.Server, ctx *fiber.Ctx) error {
@@ -22,54 +28,77 @@ func PostTranscript(server *repository.Server, ctx *fiber.Ctx) error {
 	params := new(models.TranscriptRequestBody)
 
 	// Check if the parsing was successful.
-	if err := ctx.BodyParser(params); err != nil {
+	if err := utils.ParseBody(ctx, params); err != nil {
 		return ctx.Status(fiber.StatusBadRequest).JSON(models.TranscriptResponse{
-			HTTPError: models.HTTPError{
-				Error:   true,
-				Message: repository.ErrorBadBodyParams.Error(),
-			},
+			HTTPError: utils.NewHTTPError(ctx, repository.ErrorBadBodyParams),
 		})
 	}
 
+	// Fill in the credentials bound to the session token, if one was passed.
+	if token := utils.GetBearerToken(ctx); token != "" {
+		credentials, err := server.Cache.GetSession(token)
+
+		// Error out if the session is invalid or expired.
+		if err != nil {
+			return ctx.Status(fiber.StatusUnauthorized).JSON(models.TranscriptResponse{
+				HTTPError: utils.NewHTTPError(ctx, repository.ErrorInvalidSession),
+			})
+		}
+
+		params.BaseRequestBody = credentials
+	}
+
 	// Verify the validity of the body params.
 	if err := server.Validator.Struct(params); err != nil {
 		return ctx.Status(fiber.StatusBadRequest).JSON(models.TranscriptResponse{
-			HTTPError: models.HTTPError{
-				Error:   true,
-				Message: repository.ErrorBadBodyParams.Error(),
-			},
+			HTTPError: utils.NewHTTPError(ctx, repository.ErrorBadBodyParams, utils.ValidationFields(err)...),
+		})
+	}
+
+	// Verify the base is an allowed HAC URL, and normalize it.
+	base, err := server.BaseURLs.Validate(params.Base)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(models.TranscriptResponse{
+			HTTPError: utils.NewHTTPError(ctx, fmt.Errorf("%w: %s", repository.ErrorInvalidBase, err), "base"),
 		})
 	}
+	params.Base = base
+
+	// Tag the logs with the district and a hash of the user.
+	utils.SetLogIdentity(ctx, params.Username, params.Base)
 
 	// Form a cache key.
 	cacheKey := fmt.Sprintf("%s\n%s\n%s", params.Username, params.Password, params.Base)
 
 	// Try logging in, or grab the cached collector.
-	collector, err := server.Cache.GetOrLogin(cacheKey)
+	collector, err := server.Cache.GetOrLogin(ctx.UserContext(), cacheKey)
 
 	// Check if the login went through.
 	if err != nil {
-		return ctx.Status(fiber.StatusBadRequest).JSON(models.TranscriptResponse{
-			HTTPError: models.HTTPError{
-				Error:   true,
-				Message: repository.ErrorInvalidAuthentication.Error(),
-			},
+		status, loginErr := utils.LoginErrorResponse(err)
+		return ctx.Status(status).JSON(models.TranscriptResponse{
+			HTTPError: utils.NewHTTPError(ctx, loginErr),
 		})
 	}
 
 	// Get the transcript.
-	transcript, err := server.Querier.GetTranscript(collector, *params)
+	transcript, err := utils.RetryOnExpiredSession(ctx.UserContext(), server.Cache, cacheKey, collector, func(collector *colly.Collector) ([]models.Transcript, error) {
+		return server.Querier.GetTranscript(ctx.UserContext(), collector, *params)
+	})
 
 	// Check if getting the transcript was successful.
 	if err != nil {
-		return ctx.Status(fiber.StatusInternalServerError).JSON(models.TranscriptResponse{
-			HTTPError: models.HTTPError{
-				Error:   true,
-				Message: repository.ErrorInternalError.Error(),
-			},
+		status, queryErr := utils.QueryErrorResponse(err)
+		return ctx.Status(status).JSON(models.TranscriptResponse{
+			HTTPError: utils.NewHTTPError(ctx, queryErr),
 		})
 	}
 
+	// Send back a CSV file or spreadsheet, if one was asked for.
+	if format := utils.ResponseFormat(ctx, params.Format); format != models.FormatJSON {
+		return utils.SendWorkbook(ctx.Status(fiber.StatusOK), export.Transcript(transcript), format, "transcript")
+	}
+
 	// Return the transcript.
 	return ctx.Status(fiber.StatusOK).JSON(models.TranscriptResponse{
 		Transcript: transcript,
@@ -0,0 +1,116 @@
+package controllers
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Threqt1/HACApi/app/models"
+	"github.com/Threqt1/HACApi/pkg/repository"
+	"github.com/Threqt1/HACApi/pkg/utils"
+	"github.com/gocolly/colly"
+	"github.com/gofiber/fiber/v2"
+)
+
+// PostWeekView handles POST requests to the week view endpoint.
+//
+//	@Description	Returns the assignments due on each day of a week, along with the average for each class.
+//	@Description	If the date parameter is passed into the body, the week containing that date is returned, otherwise the current week is returned.
+//	@Description	It is important the format of the date follows the format "01/02/2006" (01 = month, 02 = day, 2006 = year), with leading zeros like shown in the format.
+//	@Tags			weekview
+//	@Param			request	body	models.WeekViewRequestBody	false	"Body Params"
+//	@Param			Authorization	header	string	false	"Bearer session token from /login, used instead of the credentials in the body"
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{object}	models.WeekViewResponse
+//	@Router			/weekview [post]
+func PostWeekView(server *repository.Server, ctx *fiber.Ctx) error {
+	// Parse body.
+	params := new(models.WeekViewRequestBody)
+
+	// Check if parsing succeeded.
+	if err := utils.ParseBody(ctx, params); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(models.WeekViewResponse{
+			HTTPError: utils.NewHTTPError(ctx, repository.ErrorBadBodyParams),
+		})
+	}
+
+	// Fill in the credentials bound to the session token, if one was passed.
+	if token := utils.GetBearerToken(ctx); token != "" {
+		credentials, err := server.Cache.GetSession(token)
+
+		// Error out if the session is invalid or expired.
+		if err != nil {
+			return ctx.Status(fiber.StatusUnauthorized).JSON(models.WeekViewResponse{
+				HTTPError: utils.NewHTTPError(ctx, repository.ErrorInvalidSession),
+			})
+		}
+
+		params.BaseRequestBody = credentials
+	}
+
+	// Verify the validity of the body parameters.
+	valid := true
+	var fields []string
+
+	if err := server.Validator.Struct(params); err != nil {
+		valid = false
+		fields = utils.ValidationFields(err)
+	}
+
+	// Confirm the date is valid.
+	if _, err := time.Parse("01/02/2006", params.Date); len(params.Date) > 0 && err != nil {
+		valid = false
+		fields = append(fields, "date")
+	}
+
+	// If they aren't valid, send back an error.
+	if !valid {
+		return ctx.Status(fiber.StatusBadRequest).JSON(models.WeekViewResponse{
+			HTTPError: utils.NewHTTPError(ctx, repository.ErrorBadBodyParams, fields...),
+		})
+	}
+
+	// Verify the base is an allowed HAC URL, and normalize it.
+	base, err := server.BaseURLs.Validate(params.Base)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(models.WeekViewResponse{
+			HTTPError: utils.NewHTTPError(ctx, fmt.Errorf("%w: %s", repository.ErrorInvalidBase, err), "base"),
+		})
+	}
+	params.Base = base
+
+	// Tag the logs with the district and a hash of the user.
+	utils.SetLogIdentity(ctx, params.Username, params.Base)
+
+	// Form a cache key.
+	cacheKey := fmt.Sprintf("%s\n%s\n%s", params.Username, params.Password, params.Base)
+
+	// Try logging in, or grab the cached collector.
+	collector, err := server.Cache.GetOrLogin(ctx.UserContext(), cacheKey)
+
+	// Check if login succeeded.
+	if err != nil {
+		status, loginErr := utils.LoginErrorResponse(err)
+		return ctx.Status(status).JSON(models.WeekViewResponse{
+			HTTPError: utils.NewHTTPError(ctx, loginErr),
+		})
+	}
+
+	// Get the week view.
+	weekView, err := utils.RetryOnExpiredSession(ctx.UserContext(), server.Cache, cacheKey, collector, func(collector *colly.Collector) ([]models.WeekView, error) {
+		return server.Querier.GetWeekView(ctx.UserContext(), collector, *params)
+	})
+
+	// Check if getting the week view succeeded.
+	if err != nil {
+		status, queryErr := utils.QueryErrorResponse(err)
+		return ctx.Status(status).JSON(models.WeekViewResponse{
+			HTTPError: utils.NewHTTPError(ctx, queryErr),
+		})
+	}
+
+	// Return the week view.
+	return ctx.Status(fiber.StatusOK).JSON(models.WeekViewResponse{
+		WeekView: weekView,
+	})
+}
@@ -0,0 +1,122 @@
+package controllers
+
+import (
+	"fmt"
+
+	"github.com/Threqt1/HACApi/app/models"
+	"github.com/Threqt1/HACApi/pkg/repository"
+	"github.com/Threqt1/HACApi/pkg/utils"
+	"github.com/Threqt1/HACApi/platform/grades"
+	"github.com/gocolly/colly"
+	"github.com/gofiber/fiber/v2"
+)
+
+// PostWhatIf handles POST requests to the what-if endpoint.
+//
+//	@Description	Recalculates the averages of classes with hypothetical assignments added, or existing assignments edited, in the marking period specified.
+//	@Description	If no marking period is specified, the current marking period is used.
+//	@Description	Classes are averaged with the category weights HAC lists, leaving out dropped, excused and ungraded assignments, and counting missing ones as zero.
+//	@Description	"current" is the average recalculated from the entered assignments, to compare with the average HAC lists.
+//	@Tags			classwork
+//	@Param			request	body	models.WhatIfRequestBody	false	"Body Params"
+//	@Param			Authorization	header	string	false	"Bearer session token from /login, used instead of the credentials in the body"
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{object}	models.WhatIfResponse
+//	@Router			/classwork/whatif [post]
+func PostWhatIf(server *repository.Server, ctx *fiber.Ctx) error {
+	// Parse body.
+	params := new(models.WhatIfRequestBody)
+
+	// Check if parsing body parameters succeeded.
+	if err := utils.ParseBody(ctx, params); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(models.WhatIfResponse{
+			HTTPError: utils.NewHTTPError(ctx, repository.ErrorBadBodyParams),
+		})
+	}
+
+	// Fill in the credentials bound to the session token, if one was passed.
+	if token := utils.GetBearerToken(ctx); token != "" {
+		credentials, err := server.Cache.GetSession(token)
+
+		// Error out if the session is invalid or expired.
+		if err != nil {
+			return ctx.Status(fiber.StatusUnauthorized).JSON(models.WhatIfResponse{
+				HTTPError: utils.NewHTTPError(ctx, repository.ErrorInvalidSession),
+			})
+		}
+
+		params.BaseRequestBody = credentials
+	}
+
+	// Verify the validity of the body params.
+	if err := server.Validator.Struct(params); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(models.WhatIfResponse{
+			HTTPError: utils.NewHTTPError(ctx, repository.ErrorBadBodyParams, utils.ValidationFields(err)...),
+		})
+	}
+
+	// Verify the base is an allowed HAC URL, and normalize it.
+	base, err := server.BaseURLs.Validate(params.Base)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(models.WhatIfResponse{
+			HTTPError: utils.NewHTTPError(ctx, fmt.Errorf("%w: %s", repository.ErrorInvalidBase, err), "base"),
+		})
+	}
+	params.Base = base
+
+	// Tag the logs with the district and a hash of the user.
+	utils.SetLogIdentity(ctx, params.Username, params.Base)
+
+	// Form a cache key.
+	cacheKey := fmt.Sprintf("%s\n%s\n%s", params.Username, params.Password, params.Base)
+
+	// Try logging in, or grab the cached collector.
+	collector, err := server.Cache.GetOrLogin(ctx.UserContext(), cacheKey)
+
+	// Error out if the login fails.
+	if err != nil {
+		status, loginErr := utils.LoginErrorResponse(err)
+		return ctx.Status(status).JSON(models.WhatIfResponse{
+			HTTPError: utils.NewHTTPError(ctx, loginErr),
+		})
+	}
+
+	// Get the normalized classwork of the marking period.
+	classworkParams := models.ClassworkRequestBody{
+		BaseRequestBody:      params.BaseRequestBody,
+		NormalizeRequestBody: models.NormalizeRequestBody{Normalize: true},
+	}
+	if params.MarkingPeriod > 0 {
+		classworkParams.MarkingPeriods = []int{params.MarkingPeriod}
+	}
+	classwork, err := utils.RetryOnExpiredSession(ctx.UserContext(), server.Cache, cacheKey, collector, func(collector *colly.Collector) ([]models.Classwork, error) {
+		classwork, _, err := server.Querier.GetClasswork(ctx.UserContext(), collector, classworkParams)
+		return classwork, err
+	})
+
+	// Check if getting the classwork succeeded.
+	if err == nil && len(classwork) == 0 {
+		err = repository.ErrorPageLayoutChanged
+	}
+	if err != nil {
+		status, queryErr := utils.QueryErrorResponse(err)
+		return ctx.Status(status).JSON(models.WhatIfResponse{
+			HTTPError: utils.NewHTTPError(ctx, queryErr),
+		})
+	}
+
+	// Recalculate the averages with the hypothetical assignments.
+	classes, err := grades.WhatIf(classwork[0], params.Assignments)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(models.WhatIfResponse{
+			HTTPError: utils.NewHTTPError(ctx, err, "assignments"),
+		})
+	}
+
+	// Return the recalculated averages.
+	return ctx.Status(fiber.StatusOK).JSON(models.WhatIfResponse{
+		MarkingPeriod: classwork[0].MarkingPeriod,
+		Classes:       classes,
+	})
+}
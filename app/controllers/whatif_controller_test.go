@@ -0,0 +1,173 @@
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Threqt1/HACApi/app/models"
+	"github.com/Threqt1/HACApi/app/queries"
+	"github.com/Threqt1/HACApi/pkg/repository"
+	"github.com/Threqt1/HACApi/pkg/utils"
+	"github.com/Threqt1/HACApi/platform/cache"
+	"github.com/bytedance/sonic"
+	"github.com/gocolly/colly"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/go-cmp/cmp"
+)
+
+// testWhatIf_Querier is a test querier sending back
+// normalized classwork for a single class.
+type testWhatIf_Querier struct {
+	queries.TestQuerier
+}
+
+func (testWhatIf_Querier) GetClasswork(ctx context.Context, collector *colly.Collector, params models.ClassworkRequestBody) ([]models.Classwork, []models.ItemError, error) {
+	score, maxPoints := 80.0, 100.0
+	return []models.Classwork{{
+		MarkingPeriod: 1,
+		Entries: []models.ClassworkEntry{{
+			Class:   models.Class{Name: "Algebra", Course: "0401A - 1"},
+			Average: "80.00",
+			Assignments: []models.Assignment{{
+				Name:       "Test 1",
+				Normalized: &models.NormalizedAssignment{Grade: models.NormalizedGrade{Score: &score, MaxPoints: &maxPoints}},
+			}},
+		}},
+	}}, nil, nil
+}
+
+// newTestWhatIfServer sets up a testing server with
+// the what-if endpoint registered.
+func newTestWhatIfServer() *repository.Server {
+	server := &repository.Server{
+		App: fiber.New(fiber.Config{
+			JSONEncoder: sonic.Marshal,
+			JSONDecoder: sonic.Unmarshal,
+		}),
+		Querier:   testWhatIf_Querier{},
+		Validator: utils.NewValidator(),
+		Cache:     cache.NewTestCache(),
+		BaseURLs:  utils.NewTestBaseURLPolicy(),
+	}
+
+	server.App.Post("/", utils.WrapController(server, PostWhatIf))
+
+	return server
+}
+
+// postTestWhatIf posts hypothetical assignments to a testing server.
+func postTestWhatIf(server *repository.Server, assignments []models.WhatIfAssignment) utils.ExpectedServerResponse[models.WhatIfResponse] {
+	bodyData := models.WhatIfRequestBody{
+		BaseRequestBody: models.BaseRequestBody{
+			Username: repository.FakeUsername,
+			Password: repository.FakePassword,
+			Base:     repository.FakeBase,
+		},
+		Assignments: assignments,
+	}
+	body, _ := sonic.Marshal(bodyData)
+
+	// Create a test request.
+	req := httptest.NewRequest("POST", "http://fake.url/", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	// Test the request.
+	resp, _ := server.App.Test(req)
+
+	// Parse the body.
+	resBody, _ := io.ReadAll(resp.Body)
+	res := models.WhatIfResponse{}
+
+	sonic.Unmarshal(resBody, &res)
+
+	return utils.ExpectedServerResponse[models.WhatIfResponse]{
+		Status: resp.StatusCode,
+		Body:   res,
+	}
+}
+
+// Test if PostWhatIf() recalculates the average
+// given all valid inputs.
+func TestPostWhatIf_AllValidInputs(t *testing.T) {
+	server := newTestWhatIfServer()
+
+	score := 100.0
+	got := postTestWhatIf(server, []models.WhatIfAssignment{{Course: "0401A - 1", Name: "Test 2", New: true, Score: &score}})
+
+	// Make expected body.
+	current, whatIf, points, maxPoints := 80.0, 90.0, 180.0, 200.0
+	expected := utils.ExpectedServerResponse[models.WhatIfResponse]{
+		Status: fiber.StatusOK,
+		Body: models.WhatIfResponse{
+			MarkingPeriod: 1,
+			Classes: []models.WhatIfClass{{
+				Class:      models.Class{Name: "Algebra", Course: "0401A - 1"},
+				Average:    "80.00",
+				Current:    &current,
+				WhatIf:     &whatIf,
+				Categories: []models.WhatIfCategory{{Name: "Total", Weight: 100, StudentPoints: points, MaximumPoints: maxPoints, Percent: &whatIf}},
+			}},
+		},
+	}
+
+	// Test.
+	if diff := cmp.Diff(expected, got); diff != "" {
+		t.Fatalf("Failed for PostWhatIf() All Valid Inputs (-want, +got)\n%s", diff)
+	}
+}
+
+// Test if PostWhatIf() errors out if an edited
+// assignment isn't in the class.
+func TestPostWhatIf_UnknownAssignment(t *testing.T) {
+	server := newTestWhatIfServer()
+
+	score := 100.0
+	got := postTestWhatIf(server, []models.WhatIfAssignment{{Course: "0401A - 1", Name: "Test 9", Score: &score}})
+
+	// Make expected body.
+	expected := utils.ExpectedServerResponse[models.WhatIfResponse]{
+		Status: fiber.StatusBadRequest,
+		Body: models.WhatIfResponse{
+			HTTPError: models.HTTPError{
+				Error:   true,
+				Message: repository.ErrorInvalidWhatIf.Error() + `: "Algebra" has no assignment "Test 9"`,
+				Code:    models.ErrorCodeBadRequestField,
+				Fields:  []string{"assignments"},
+			},
+		},
+	}
+
+	// Test.
+	if diff := cmp.Diff(expected, got); diff != "" {
+		t.Fatalf("Failed for PostWhatIf() Unknown Assignment (-want, +got)\n%s", diff)
+	}
+}
+
+// Test if PostWhatIf() errors out if a hypothetical
+// assignment has neither a score nor is dropped.
+func TestPostWhatIf_BadBodyParams_InvalidModel(t *testing.T) {
+	server := newTestWhatIfServer()
+
+	got := postTestWhatIf(server, []models.WhatIfAssignment{{Course: "0401A - 1", Name: "Test 1"}})
+
+	// Make expected body.
+	expected := utils.ExpectedServerResponse[models.WhatIfResponse]{
+		Status: fiber.StatusBadRequest,
+		Body: models.WhatIfResponse{
+			HTTPError: models.HTTPError{
+				Error:   true,
+				Message: repository.ErrorBadBodyParams.Error(),
+				Code:    models.ErrorCodeBadRequestField,
+				Fields:  []string{"assignments[0].score"},
+			},
+		},
+	}
+
+	// Test.
+	if diff := cmp.Diff(expected, got); diff != "" {
+		t.Fatalf("Failed for PostWhatIf() Bad Body Params, Invalid Request Model (-want, +got)\n%s", diff)
+	}
+}
@@ -9,4 +9,6 @@ type Assignment struct {
 	Grade        string `json:"grade"`        // What grade the user got on the assignment
 	TotalPoints  string `json:"totalPoints"`  // The total points that could be earned on the assignment
 	Dropped      bool   `json:"dropped"`      // Whether the assignment was dropped or not
+
+	Normalized *NormalizedAssignment `json:"normalized,omitempty"` // Normalized fields, if requested
 }
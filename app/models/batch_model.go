@@ -0,0 +1,51 @@
+package models
+
+// The resources which can be requested in a batch.
+const (
+	BatchResourceClasswork  = "classwork"
+	BatchResourceIPR        = "ipr"
+	BatchResourceSchedule   = "schedule"
+	BatchResourceReportCard = "reportcard"
+	BatchResourceTranscript = "transcript"
+)
+
+// BatchRequestBody represents the body that is to be passed with
+// the POST request to the batch endpoint.
+type BatchRequestBody struct {
+	BaseRequestBody
+	NormalizeRequestBody
+	// The resources to fetch
+	Requests []BatchResourceRequest `json:"requests" validate:"required,min=1,max=10,dive"`
+}
+
+// BatchResourceRequest represents a single resource
+// requested in a batch.
+type BatchResourceRequest struct {
+	// The resource to fetch
+	Resource string `json:"resource" validate:"required,oneof=classwork ipr schedule reportcard transcript" example:"classwork"`
+	// The marking periods to pull classwork from
+	MarkingPeriods []int `json:"markingPeriods,omitempty" validate:"max=6,dive,min=1,max=6" example:"1,2"`
+	// The date of the IPR to return
+	Date string `json:"date,omitempty" example:"09/06/2022"`
+}
+
+// BatchResult represents the result of a single
+// resource requested in a batch. Only the field
+// for the requested resource is set.
+type BatchResult struct {
+	HTTPError               // Error, if fetching the resource failed
+	Resource   string       `json:"resource"`             // The resource which was requested
+	Status     int          `json:"status"`               // The status code the resource's own endpoint would have responded with
+	Classwork  []Classwork  `json:"classwork,omitempty"`  // The resulting classwork
+	IPR        []IPR        `json:"ipr,omitempty"`        // The resulting IPR(s)
+	Schedule   []Schedule   `json:"schedule,omitempty"`   // The resulting schedule
+	ReportCard []ReportCard `json:"reportCard,omitempty"` // The resulting report card
+	Transcript []Transcript `json:"transcript,omitempty"` // The resulting transcript
+}
+
+// BatchResponse represents a JSON response
+// to the batch POST request.
+type BatchResponse struct {
+	HTTPError               // Error, if one is attached to the response
+	Results   []BatchResult `json:"results"` // The result of each requested resource, in the order requested
+}
@@ -0,0 +1,64 @@
+package models
+
+import "time"
+
+// The kinds of entries assignments can be exported as.
+const (
+	CalendarKindEvent = "event" // An all-day event on the due date
+	CalendarKindTodo  = "todo"  // A to-do due on the due date
+)
+
+// AssignmentCalendarRequestBody represents the body that is to be passed with
+// the request to the assignment calendar endpoint.
+type AssignmentCalendarRequestBody struct {
+	BaseRequestBody
+	// The marking periods to export assignments from
+	MarkingPeriods []int `json:"markingPeriods" query:"markingPeriods" validate:"max=6,dive,min=1,max=6" example:"1,2"`
+	// Whether assignments are exported as events or to-dos, defaults to events
+	Kind string `json:"kind" query:"kind" validate:"omitempty,oneof=event todo" example:"event"`
+}
+
+// PeriodTime represents when a period starts and ends.
+type PeriodTime struct {
+	Period string `json:"period" query:"period" validate:"required" example:"1"`   // The period, as HAC numbers it
+	Start  string `json:"start" query:"start" validate:"required" example:"08:45"` // When the period starts, as HH:MM
+	End    string `json:"end" query:"end" validate:"required" example:"09:35"`     // When the period ends, as HH:MM
+}
+
+// ScheduleCalendarRequestBody represents the body that is to be passed with
+// the request to the schedule calendar endpoint.
+type ScheduleCalendarRequestBody struct {
+	BaseRequestBody
+	// The first day classes repeat on
+	StartDate string `json:"startDate" query:"startDate" validate:"required" example:"08/15/2022"`
+	// The last day classes repeat on
+	EndDate string `json:"endDate" query:"endDate" validate:"required" example:"12/16/2022"`
+	// When each period starts and ends, since HAC doesn't list bell times
+	Periods []PeriodTime `json:"periods" query:"periods" validate:"required,min=1,max=20,dive"`
+}
+
+// CalendarResponse represents the JSON response sent
+// instead of a calendar, if the request fails.
+type CalendarResponse struct {
+	HTTPError // Error, if one is attached to the response
+}
+
+// CalendarTokenRequestBody represents the body that is to be passed with
+// the POST request to the calendar token endpoint.
+type CalendarTokenRequestBody struct {
+	BaseRequestBody
+}
+
+// CalendarToken represents a long-lived token calendar apps
+// can subscribe to the calendar endpoints with.
+type CalendarToken struct {
+	Token   string    `json:"token"`   // The token to pass as the token query parameter of the calendar endpoints
+	Expires time.Time `json:"expires"` // When the token expires, unless it is revoked before
+}
+
+// CalendarTokenResponse represents a JSON response
+// to the calendar token requests.
+type CalendarTokenResponse struct {
+	HTTPError                    // Error, if one is attached to the response
+	CalendarToken *CalendarToken `json:"calendarToken,omitempty"` // The calendar token, if one was minted
+}
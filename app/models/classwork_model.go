@@ -4,17 +4,36 @@ package models
 // the POST request to the classwork endpoint.
 type ClassworkRequestBody struct {
 	BaseRequestBody
+	NormalizeRequestBody
+	PartialRequestBody
+	FormatRequestBody
 	// The marking period to pull data from
 	MarkingPeriods []int `json:"markingPeriods" validate:"max=6,dive,min=1,max=6" example:"1,2"`
 }
 
+// ClassworkCategory represents a row of the "Average Details" of a class,
+// which breaks the average down into its categories.
+type ClassworkCategory struct {
+	Name           string `json:"name"`           // The name of the category (major, minor, other, etc)
+	StudentPoints  string `json:"studentPoints"`  // The points the user scored in the category
+	MaximumPoints  string `json:"maximumPoints"`  // The points that could be scored in the category
+	Percent        string `json:"percent"`        // The points scored as a percentage of the maximum points
+	Weight         string `json:"weight"`         // The weight of the category in the average
+	CategoryPoints string `json:"categoryPoints"` // The points the category adds to the average
+
+	Normalized *NormalizedCategory `json:"normalized,omitempty"` // Normalized fields, if requested
+}
+
 // ClassworkEntry represents all classswork for a single class
 // during a given six weeks.
 type ClassworkEntry struct {
-	Position    int          `json:"position"`    // The position of the class, used for ordering
-	Class       Class        `json:"class"`       // Class information about the entry
-	Average     string       `json:"average"`     // The average grade for that class
-	Assignments []Assignment `json:"assignments"` // All the assignments currently entered for the class
+	Position    int                 `json:"position"`             // The position of the class, used for ordering
+	Class       Class               `json:"class"`                // Class information about the entry
+	Average     string              `json:"average"`              // The average grade for that class
+	Assignments []Assignment        `json:"assignments"`          // All the assignments currently entered for the class
+	Categories  []ClassworkCategory `json:"categories,omitempty"` // The category breakdown of the average, if HAC lists one
+
+	Normalized *NormalizedGrade `json:"normalized,omitempty"` // The normalized average, if requested
 }
 
 // Classwork represents all classwork
@@ -28,5 +47,6 @@ type Classwork struct {
 // to the Classwork POST request.
 type ClassworkResponse struct {
 	HTTPError             // Error, if one is attached to the response
-	Classwork []Classwork `json:"classwork"` // The resulting classwork
+	Classwork []Classwork `json:"classwork"`        // The resulting classwork
+	Errors    []ItemError `json:"errors,omitempty"` // The marking periods which failed, if partial results were requested
 }
@@ -0,0 +1,15 @@
+package models
+
+// The formats responses can be sent back in.
+const (
+	FormatJSON = "json" // The JSON response
+	FormatCSV  = "csv"  // A CSV file, with a row per assignment, class or transcript entry
+	FormatXLSX = "xlsx" // A spreadsheet, with a sheet per marking period or transcript year
+)
+
+// FormatRequestBody describes the option to send back
+// a CSV file or spreadsheet instead of JSON.
+type FormatRequestBody struct {
+	// The format to send back, the Accept header decides if it isn't set
+	Format string `json:"format" validate:"omitempty,oneof=json csv xlsx" example:"csv"`
+}
@@ -0,0 +1,85 @@
+package models
+
+// The ways averages can be converted into grade points.
+const (
+	GPAMethodLetter = "letter" // Full points for an A (90+), one less for each letter below
+	GPAMethodLinear = "linear" // A tenth of a point less for every point below 100
+)
+
+// The sources of the classes counted in a GPA.
+const (
+	GPASourceTranscript = "transcript" // A completed semester on the transcript
+	GPASourceReportCard = "reportCard" // A semester of the current year, from the report card
+	GPASourceWhatIf     = "whatIf"     // A hypothetical semester average
+)
+
+// GPAScale represents how averages are converted into grade points.
+type GPAScale struct {
+	// The points for a 100 in a regular class, defaults to 4
+	Base float64 `json:"base" validate:"omitempty,gt=0,max=10" example:"4"`
+	// How averages are converted into points, defaults to letter
+	Method string `json:"method" validate:"omitempty,oneof=letter linear" example:"letter"`
+	// The lowest passing average, below which no points are earned, defaults to 70
+	Passing float64 `json:"passing" validate:"omitempty,gt=0,max=100" example:"70"`
+}
+
+// GPAWeightRule represents the extra points earned by classes whose course code
+// matches a pattern, like AP or honors classes.
+type GPAWeightRule struct {
+	Pattern string  `json:"pattern" validate:"required,max=200" example:"^AP|A$"` // A regular expression matched against the course code
+	Bonus   float64 `json:"bonus" validate:"min=0,max=10" example:"1"`            // The points added to passing averages, 1 makes a 4.0 scale a 5.0 one
+}
+
+// GPAWhatIf represents a hypothetical semester average, replacing the
+// projected average of a class, or adding a class if none has the course.
+type GPAWhatIf struct {
+	Course   string   `json:"course" validate:"required" example:"0401A - 1"`         // The course code of the class
+	Semester int      `json:"semester" validate:"required,min=1,max=2" example:"2"`   // The semester of the current year
+	Average  *float64 `json:"average" validate:"required,min=0,max=110" example:"93"` // The hypothetical semester average
+	Credit   *float64 `json:"credit" validate:"omitempty,gt=0,max=10" example:"0.5"`  // The credit of the semester, defaults to that of the class, or 0.5
+}
+
+// GPARequestBody represents the body that is to be passed with
+// the POST request to the GPA endpoint.
+type GPARequestBody struct {
+	BaseRequestBody
+	// How averages are converted into grade points
+	Scale GPAScale `json:"scale"`
+	// The extra points earned by AP, honors and other weighted classes
+	Rules []GPAWeightRule `json:"rules" validate:"max=20,dive"`
+	// Hypothetical semester averages for the what-if GPA
+	WhatIf []GPAWhatIf `json:"whatIf" validate:"max=50,dive"`
+}
+
+// GPAClass represents a semester of a class counted in a GPA.
+type GPAClass struct {
+	Class    Class   `json:"class"`    // Information about the class
+	Year     string  `json:"year"`     // The school year, empty for the current year
+	Semester string  `json:"semester"` // The semester
+	Average  float64 `json:"average"`  // The semester average
+	Credit   float64 `json:"credit"`   // The credit the semester is worth
+	Points   float64 `json:"points"`   // The grade points the average earned
+	Source   string  `json:"source"`   // Where the average came from
+}
+
+// GPAResult represents a GPA, calculated over some classes.
+type GPAResult struct {
+	GPA     *float64 `json:"gpa"`     // The credit-weighted GPA, if any credit was counted
+	Credits float64  `json:"credits"` // The credit counted
+}
+
+// GPA represents the current, projected and what-if GPA.
+type GPA struct {
+	Current   GPAResult       `json:"current"`          // The GPA of the completed semesters on the transcript
+	Projected GPAResult       `json:"projected"`        // The GPA including the current year's report card averages
+	WhatIf    *GPAResult      `json:"whatIf,omitempty"` // The projected GPA with the hypothetical averages, if any were passed
+	District  []TranscriptGPA `json:"district"`         // The GPAs the district calculated, for comparison
+	Classes   []GPAClass      `json:"classes"`          // The semesters counted, with the hypothetical averages applied
+}
+
+// GPAResponse represents a JSON response
+// to the GPA POST request.
+type GPAResponse struct {
+	HTTPError      // Error, if one is attached to the response
+	GPA       *GPA `json:"gpa,omitempty"` // The resulting GPA
+}
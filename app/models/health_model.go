@@ -0,0 +1,32 @@
+package models
+
+import "time"
+
+// The statuses of the API and of each district's HAC.
+const (
+	HealthStatusUp       = "up"       // Everything is reachable
+	HealthStatusDegraded = "degraded" // Some districts are unreachable
+	HealthStatusDown     = "down"     // Nothing is reachable
+)
+
+// HealthResponse represents a JSON response
+// to the healthz GET request.
+type HealthResponse struct {
+	Status string `json:"status"` // Whether the API is up
+}
+
+// DistrictHealth represents the result of probing
+// a district's HAC.
+type DistrictHealth struct {
+	Base      string    `json:"base"`            // The base URL of the district's HAC
+	Status    string    `json:"status"`          // Whether the district's LogOn page is up
+	Error     string    `json:"error,omitempty"` // Why the district is down, if it is
+	CheckedAt time.Time `json:"checkedAt"`       // When the district was last probed
+}
+
+// ReadinessResponse represents a JSON response
+// to the readyz GET request.
+type ReadinessResponse struct {
+	Status    string           `json:"status"`    // Whether the configured districts are up
+	Districts []DistrictHealth `json:"districts"` // The status of each configured district
+}
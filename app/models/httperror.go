@@ -1,7 +1,29 @@
 package models
 
+// ErrorCode represents a stable, machine-readable
+// code for an error.
+type ErrorCode string
+
+const (
+	ErrorCodeBadRequestField    ErrorCode = "BAD_REQUEST_FIELD"   // A body parameter is missing or invalid
+	ErrorCodeInvalidCredentials ErrorCode = "INVALID_CREDENTIALS" // HAC rejected the username/password
+	ErrorCodeSessionExpired     ErrorCode = "SESSION_EXPIRED"     // The session token is invalid or expired
+	ErrorCodeAccountLocked      ErrorCode = "ACCOUNT_LOCKED"      // HAC locked the account
+	ErrorCodeHACUnavailable     ErrorCode = "HAC_UNAVAILABLE"     // The district's HAC is down or unreachable
+	ErrorCodePageLayoutChanged  ErrorCode = "PAGE_LAYOUT_CHANGED" // A HAC page doesn't look as expected
+	ErrorCodeRateLimited        ErrorCode = "RATE_LIMITED"        // The district's HAC is rate limiting requests
+	ErrorCodeTimeout            ErrorCode = "TIMEOUT"             // HAC took longer to respond than the request allows
+	ErrorCodeCanceled           ErrorCode = "CANCELED"            // The request was canceled before HAC responded
+	ErrorCodeNotFound           ErrorCode = "NOT_FOUND"           // The endpoint doesn't exist
+	ErrorCodeLimitReached       ErrorCode = "LIMIT_REACHED"       // The account or API has as many subscriptions as allowed
+	ErrorCodeInternal           ErrorCode = "INTERNAL_ERROR"      // Anything else
+)
+
 // HTTPError represents a HTTP error.
 type HTTPError struct {
-	Error   bool   `json:"err"` // If there was an error
-	Message string `json:"msg"` // The associated message
+	Error     bool      `json:"err"`                 // If there was an error
+	Message   string    `json:"msg"`                 // The associated message
+	Code      ErrorCode `json:"code,omitempty"`      // The machine-readable code for the error
+	Fields    []string  `json:"fields,omitempty"`    // The body parameters which are invalid, if any
+	RequestID string    `json:"requestId,omitempty"` // The ID of the request, for reporting the error
 }
@@ -5,6 +5,8 @@ package models
 // endpoint.
 type IprAllRequestBody struct {
 	BaseRequestBody
+	NormalizeRequestBody
+	PartialRequestBody
 	// Whether to return only dates or all the IPRs
 	DatesOnly bool `json:"datesOnly" example:"true" default:"false"`
 }
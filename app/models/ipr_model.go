@@ -5,6 +5,8 @@ package models
 // endpoint.
 type IprRequestBody struct {
 	BaseRequestBody
+	NormalizeRequestBody
+	PartialRequestBody
 	// The date of the IPR to return
 	Date string `json:"date" example:"09/06/2022"`
 }
@@ -14,6 +16,8 @@ type IprRequestBody struct {
 type IPREntry struct {
 	Class Class  `json:"class"` // Information about the class related to the IPREntry
 	Grade string `json:"grade"` // The average at the moment the progress report was submitted
+
+	Normalized *NormalizedGrade `json:"normalized,omitempty"` // The normalized grade, if requested
 }
 
 // IPR represents the overall progress report. It contains
@@ -21,11 +25,14 @@ type IPREntry struct {
 type IPR struct {
 	Date    string     `json:"date"`    // The date the IPR was submitted
 	Entries []IPREntry `json:"entries"` // An array representing all the IPR entries
+
+	Normalized *NormalizedIPR `json:"normalized,omitempty"` // Normalized fields, if requested
 }
 
 // IPRResponse represents a JSON response
 // to the IPR POST request.
 type IPRResponse struct {
-	HTTPError       // Error, if one is attached to the response
-	IPR       []IPR `json:"ipr"` // The resulting IPR(s)
+	HTTPError             // Error, if one is attached to the response
+	IPR       []IPR       `json:"ipr"`              // The resulting IPR(s)
+	Errors    []ItemError `json:"errors,omitempty"` // The IPR dates which failed, if partial results were requested
 }
@@ -1,5 +1,7 @@
 package models
 
+import "time"
+
 // LoginRequestBody represents the body that is to be
 // passed along with the POST request to the login
 // endpoint.
@@ -10,9 +12,10 @@ type LoginRequestBody struct {
 // Login represents the response to the POST request to
 // the login endpoint.
 type Login struct {
-	Username string `json:"username"` // The username used to sign in with
-	Password string `json:"password"` // The password used to sign in with
-	Base     string `json:"base"`     // The base URL signed in to
+	Username string    `json:"username"` // The username used to sign in with
+	Base     string    `json:"base"`     // The base URL signed in to
+	Token    string    `json:"token"`    // The session token to pass as a Bearer token in subsequent requests
+	Expires  time.Time `json:"expires"`  // When the session token expires
 }
 
 // LoginResponse represents a JSON response
@@ -0,0 +1,7 @@
+package models
+
+// LogoutResponse represents a JSON response
+// to the Logout POST request.
+type LogoutResponse struct {
+	HTTPError // Error, if one is attached to the response
+}
@@ -0,0 +1,82 @@
+package models
+
+// NormalizeRequestBody describes the option to add typed,
+// normalized fields alongside the raw strings in a response.
+type NormalizeRequestBody struct {
+	// Whether to add normalized grade and date fields
+	Normalize bool `json:"normalize" example:"true" default:"false"`
+}
+
+// SpecialMark represents a non-numeric mark HAC
+// can put in place of, or next to, a grade.
+type SpecialMark string
+
+const (
+	SpecialMarkNone      SpecialMark = ""          // No special mark
+	SpecialMarkMissing   SpecialMark = "missing"   // The assignment is missing
+	SpecialMarkExcused   SpecialMark = "excused"   // The assignment was excused
+	SpecialMarkDropped   SpecialMark = "dropped"   // The assignment was dropped
+	SpecialMarkNotGraded SpecialMark = "notGraded" // The assignment has not been graded yet
+)
+
+// NormalizedGrade represents a grade parsed
+// into numbers.
+type NormalizedGrade struct {
+	Score      *float64    `json:"score"`          // The numeric score, if there is one
+	MaxPoints  *float64    `json:"maxPoints"`      // The maximum points that could be scored, if known
+	Percentage *float64    `json:"percentage"`     // The score as a percentage of the maximum points, if known
+	Mark       SpecialMark `json:"mark,omitempty"` // The special mark attached to the grade, if any
+}
+
+// NormalizedAssignment represents the normalized
+// fields of an assignment.
+type NormalizedAssignment struct {
+	DueDate      string          `json:"dueDate"`      // The date the assignment is due, in ISO-8601
+	AssignedDate string          `json:"assignedDate"` // The date the assignment was assigned, in ISO-8601
+	Grade        NormalizedGrade `json:"grade"`        // The grade the user got on the assignment
+}
+
+// NormalizedCategory represents the normalized
+// fields of a classwork category.
+type NormalizedCategory struct {
+	StudentPoints  *float64 `json:"studentPoints"`  // The points the user scored in the category, if known
+	MaximumPoints  *float64 `json:"maximumPoints"`  // The points that could be scored in the category, if known
+	Percent        *float64 `json:"percent"`        // The points scored as a percentage, if known
+	Weight         *float64 `json:"weight"`         // The weight of the category, if known
+	CategoryPoints *float64 `json:"categoryPoints"` // The points the category adds to the average, if known
+}
+
+// NormalizedIPR represents the normalized
+// fields of an IPR.
+type NormalizedIPR struct {
+	Date string `json:"date"` // The date the IPR was submitted, in ISO-8601
+}
+
+// NormalizedSixWeeksGrades represents the normalized
+// fields of every six weeks plus exams/semesters.
+type NormalizedSixWeeksGrades struct {
+	First  NormalizedGrade `json:"first"`
+	Second NormalizedGrade `json:"second"`
+	Third  NormalizedGrade `json:"third"`
+	Fourth NormalizedGrade `json:"fourth"`
+	Fifth  NormalizedGrade `json:"fifth"`
+	Sixth  NormalizedGrade `json:"sixth"`
+	Exam1  NormalizedGrade `json:"exam1"`
+	Sem1   NormalizedGrade `json:"sem1"`
+	Exam2  NormalizedGrade `json:"exam2"`
+	Sem2   NormalizedGrade `json:"sem2"`
+}
+
+// NormalizedReportCardEntry represents the normalized
+// fields of a report card entry.
+type NormalizedReportCardEntry struct {
+	AttemptedCredit *float64 `json:"attemptedCredit"` // The amount of credit attempted, if known
+	EarnedCredit    *float64 `json:"earnedCredit"`    // The amount of credit earned, if known
+}
+
+// NormalizedTranscriptEntry represents the normalized
+// fields of a transcript entry.
+type NormalizedTranscriptEntry struct {
+	Average NormalizedGrade `json:"average"` // The average grade for the class
+	Credit  *float64        `json:"credit"`  // The credit earned for the class, if known
+}
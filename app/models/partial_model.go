@@ -0,0 +1,16 @@
+package models
+
+// PartialRequestBody describes the option to return the pages
+// which succeeded when others fail, instead of failing the request.
+type PartialRequestBody struct {
+	// Whether to return partial results if some pages fail
+	Partial bool `json:"partial" example:"true" default:"false"`
+}
+
+// ItemError represents a page which failed
+// to load in a partial response.
+type ItemError struct {
+	Item    string    `json:"item"`    // The marking period or IPR date which failed
+	Message string    `json:"message"` // Why it failed
+	Code    ErrorCode `json:"code"`    // The machine-readable code for why it failed
+}
@@ -5,6 +5,8 @@ package models
 // with the POST request to this endpoint.
 type ReportCardRequestBody struct {
 	BaseRequestBody
+	NormalizeRequestBody
+	FormatRequestBody
 }
 
 // SixWeeksOther contains fields for
@@ -29,6 +31,8 @@ type SixWeeksGrades struct {
 	Sem1  string `json:"sem1"`
 	Exam2 string `json:"exam2"`
 	Sem2  string `json:"sem2"`
+
+	Normalized *NormalizedSixWeeksGrades `json:"normalized,omitempty"` // Normalized grades, if requested
 }
 
 // Absences represents the struct
@@ -51,6 +55,8 @@ type ReportCardEntry struct {
 	Comments        SixWeeksOther  `json:"comments"`        // Data about comments
 	Conduct         SixWeeksOther  `json:"conduct"`         // Data about conduct
 	Absences        Absences       `json:"absences"`        // Data about absences
+
+	Normalized *NormalizedReportCardEntry `json:"normalized,omitempty"` // Normalized fields, if requested
 }
 
 // ReportCard holds the array Entries with
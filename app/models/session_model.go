@@ -0,0 +1,10 @@
+package models
+
+import "time"
+
+// Session represents an opaque session token minted
+// by the login endpoint.
+type Session struct {
+	Token   string    // The opaque token identifying the session
+	Expires time.Time // When the session expires
+}
@@ -0,0 +1,7 @@
+package models
+
+// StreamDone represents the final event of a
+// successful stream.
+type StreamDone struct {
+	Errors []ItemError `json:"errors,omitempty"` // The items which failed, if partial results were requested
+}
@@ -0,0 +1,71 @@
+package models
+
+import "time"
+
+// The resources a subscription can watch.
+const (
+	SubscriptionResourceClasswork = "classwork"
+	SubscriptionResourceIPR       = "ipr"
+)
+
+// The types of changes sent to webhooks.
+const (
+	ChangeAssignmentAdded   = "assignment_added"   // An assignment was entered
+	ChangeGradeChanged      = "grade_changed"      // The grade of an assignment changed
+	ChangeAssignmentMoved   = "assignment_moved"   // The due date of an assignment changed
+	ChangeAverageChanged    = "average_changed"    // The average of a class changed
+	ChangeIPRPosted         = "ipr_posted"         // A new interim progress report was posted
+	ChangeSubscriptionEnded = "subscription_ended" // The subscription was removed, since its credentials or session stopped working
+)
+
+// SubscriptionRequestBody represents the body that is to be passed with
+// the POST request to the subscriptions endpoint.
+type SubscriptionRequestBody struct {
+	BaseRequestBody
+	// How often to check for changes, in seconds
+	Interval int `json:"interval" validate:"required,min=300,max=86400" example:"900"`
+	// The URL changes are posted to
+	WebhookURL string `json:"webhookUrl" validate:"required,url" example:"https://example.org/hooks/grades"`
+	// The resources to watch, all of them if empty
+	Resources []string `json:"resources" validate:"max=2,dive,oneof=classwork ipr" example:"classwork,ipr"`
+}
+
+// Subscription represents a registered webhook, notified
+// whenever the watched resources change.
+type Subscription struct {
+	ID         string    `json:"id"`               // The ID of the subscription, needed to remove it
+	Base       string    `json:"base"`             // The base URL of the district's HAC
+	Interval   int       `json:"interval"`         // How often changes are checked for, in seconds
+	WebhookURL string    `json:"webhookUrl"`       // The URL changes are posted to
+	Resources  []string  `json:"resources"`        // The resources watched
+	Secret     string    `json:"secret,omitempty"` // The key webhook payloads are signed with, only sent back once
+	Created    time.Time `json:"created"`          // When the subscription was registered
+}
+
+// SubscriptionResponse represents a JSON response
+// to the subscription requests.
+type SubscriptionResponse struct {
+	HTTPError                  // Error, if one is attached to the response
+	Subscription *Subscription `json:"subscription,omitempty"` // The subscription, if one was registered
+}
+
+// ChangeEvent represents a single change found between
+// two snapshots of a subscription's resources.
+type ChangeEvent struct {
+	Type          string      `json:"type"`                 // The type of change
+	MarkingPeriod int         `json:"sixWeeks,omitempty"`   // The marking period of a classwork change
+	Class         *Class      `json:"class,omitempty"`      // The class of a classwork change
+	Assignment    *Assignment `json:"assignment,omitempty"` // The assignment which was added, graded or moved
+	Old           string      `json:"old,omitempty"`        // The grade, due date or average before the change
+	New           string      `json:"new,omitempty"`        // The grade, due date or average after the change
+	IPR           *IPR        `json:"ipr,omitempty"`        // The interim progress report which was posted
+	Reason        string      `json:"reason,omitempty"`     // Why the subscription ended
+}
+
+// WebhookPayload represents the body posted to a webhook.
+type WebhookPayload struct {
+	ID             string        `json:"id"`             // The ID of the delivery, the same for every retry
+	SubscriptionID string        `json:"subscriptionId"` // The subscription the changes are for
+	Sent           time.Time     `json:"sent"`           // When the changes were found
+	Changes        []ChangeEvent `json:"changes"`        // The changes found
+}
@@ -4,6 +4,8 @@ package models
 // a POST request to the transcript endpoint
 type TranscriptRequestBody struct {
 	BaseRequestBody
+	NormalizeRequestBody
+	FormatRequestBody
 }
 
 // TranscriptGroupEntry represents a singular entry
@@ -12,6 +14,8 @@ type TranscriptGroupEntry struct {
 	Class   Class  `json:"class"`   // The class related to the entry
 	Average string `json:"average"` // The average grade for the class
 	Credit  string `json:"credit"`  // The credit earned for the class
+
+	Normalized *NormalizedTranscriptEntry `json:"normalized,omitempty"` // Normalized fields, if requested
 }
 
 // TranscriptGroup represents a group of entries,
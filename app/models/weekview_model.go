@@ -0,0 +1,46 @@
+package models
+
+// WeekViewRequestBody represents the body that is to be
+// passed with the POST request to the week view endpoint.
+type WeekViewRequestBody struct {
+	BaseRequestBody
+	// Any date inside the week to return, defaults to the current week
+	Date string `json:"date" example:"10/03/2022"`
+}
+
+// WeekViewAssignment represents an assignment due on
+// a given day of the week view.
+type WeekViewAssignment struct {
+	Class Class  `json:"class"` // Information about the class the assignment is for
+	Name  string `json:"name"`  // The name of the assignment
+	Grade string `json:"grade"` // What grade the user got on the assignment, if graded
+}
+
+// WeekViewDay represents all the assignments
+// due on a single day.
+type WeekViewDay struct {
+	Date        string               `json:"date"`        // The date of the day
+	Assignments []WeekViewAssignment `json:"assignments"` // All the assignments due on the day
+}
+
+// WeekViewAverage represents the current average
+// for a single class in the week view.
+type WeekViewAverage struct {
+	Class   Class  `json:"class"`   // Information about the class
+	Average string `json:"average"` // The current average for the class
+}
+
+// WeekView represents the assignments due
+// every day of a week, along with the
+// average for every class.
+type WeekView struct {
+	Days     []WeekViewDay     `json:"days"`     // An array containing each day of the week
+	Averages []WeekViewAverage `json:"averages"` // An array containing the average for each class
+}
+
+// WeekViewResponse represents a JSON response
+// to the Week View POST request.
+type WeekViewResponse struct {
+	HTTPError            // Error, if one is attached to the response
+	WeekView  []WeekView `json:"weekView"` // The resulting week view
+}
@@ -0,0 +1,51 @@
+package models
+
+// WhatIfRequestBody represents the body that is to be passed with
+// the POST request to the what-if endpoint.
+type WhatIfRequestBody struct {
+	BaseRequestBody
+	// The marking period to calculate with, defaults to the current one
+	MarkingPeriod int `json:"markingPeriod" validate:"omitempty,min=1,max=6" example:"2"`
+	// The hypothetical assignments to add or edit
+	Assignments []WhatIfAssignment `json:"assignments" validate:"required,min=1,max=100,dive"`
+}
+
+// WhatIfAssignment represents a hypothetical assignment,
+// either editing an existing one or added to a class.
+type WhatIfAssignment struct {
+	Course      string   `json:"course" validate:"required" example:"0401A - 1"`                         // The course ID of the class the assignment is in
+	Name        string   `json:"name" validate:"required" example:"Unit 3 Test"`                         // The name of the assignment to edit, or of the assignment to add
+	New         bool     `json:"new" example:"false"`                                                    // Whether to add the assignment, instead of editing an existing one
+	Category    string   `json:"category" example:"Major Grades"`                                        // The category of the assignment, needed when adding to a class with weighted categories
+	Score       *float64 `json:"score" validate:"required_without=Dropped,omitempty,min=0" example:"95"` // The points scored on the assignment
+	TotalPoints *float64 `json:"totalPoints" validate:"omitempty,gt=0" example:"100"`                    // The points that could be scored, defaults to those of the edited assignment, or 100
+	Dropped     bool     `json:"dropped" example:"false"`                                                // Whether the assignment is dropped, leaving it out of the average
+}
+
+// WhatIfCategory represents a category of an average
+// recalculated with hypothetical assignments.
+type WhatIfCategory struct {
+	Name          string   `json:"name"`          // The name of the category
+	Weight        float64  `json:"weight"`        // The weight of the category in the average
+	StudentPoints float64  `json:"studentPoints"` // The points scored in the category
+	MaximumPoints float64  `json:"maximumPoints"` // The points that could be scored in the category
+	Percent       *float64 `json:"percent"`       // The points scored as a percentage, if anything in the category counts
+}
+
+// WhatIfClass represents the average of a class recalculated
+// with hypothetical assignments.
+type WhatIfClass struct {
+	Class      Class            `json:"class"`      // Information about the class
+	Average    string           `json:"average"`    // The average HAC lists for the class
+	Current    *float64         `json:"current"`    // The average recalculated from the entered assignments, to compare with HAC's
+	WhatIf     *float64         `json:"whatIf"`     // The average with the hypothetical assignments
+	Categories []WhatIfCategory `json:"categories"` // The categories of the average with the hypothetical assignments
+}
+
+// WhatIfResponse represents a JSON response
+// to the What-If POST request.
+type WhatIfResponse struct {
+	HTTPError                   // Error, if one is attached to the response
+	MarkingPeriod int           `json:"sixWeeks"` // The marking period the averages were calculated for
+	Classes       []WhatIfClass `json:"classes"`  // The classes with hypothetical assignments
+}
@@ -1,7 +1,7 @@
 package queries
 
 import (
-	"errors"
+	"context"
 	"strconv"
 	"strings"
 
@@ -13,25 +13,26 @@ import (
 )
 
 // getClasswork returns all parsed classwork for the given marking period(s).
-func getClasswork(scraper repository.ScraperProvider, parser repository.ParserProvider, collector *colly.Collector, params models.ClassworkRequestBody) ([]models.Classwork, error) {
+// If emit isn't nil, classwork is passed to it as it is parsed instead.
+func getClasswork(ctx context.Context, scraper repository.ScraperProvider, parser repository.ParserProvider, profile repository.DistrictProfile, collector *colly.Collector, params models.ClassworkRequestBody, emit func(int, models.Classwork)) ([]models.Classwork, []models.ItemError, error) {
 	// Get initial page
-	collector, html, err := scraper.Navigate(collector, params.Base, repository.CLASSWORK_ROUTE)
+	collector, html, err := scraper.Navigate(ctx, collector, params.Base, profile.Routes.Classwork)
 
 	// Check for initial success
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	// Determine the current marking period suffix
-	markingPerOptionAttr, exists := html.Find("#plnMain_ddlReportCardRuns > option[selected='selected']").Attr("value")
+	markingPerOptionAttr, exists := html.Find(profile.Selectors.ReportCardRuns + " > option[selected='selected']").Attr("value")
 	if !exists {
-		return nil, errors.New("invalid page")
+		return nil, nil, repository.ErrorPageLayoutChanged
 	}
 	markingPerOptionText := strings.TrimSpace(markingPerOptionAttr)
 	markingPerSuffix := markingPerOptionText[1:]
 	currMarkingPer, err := strconv.Atoi(markingPerOptionText[0:1])
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	// Get other necessary fields
@@ -40,13 +41,19 @@ func getClasswork(scraper repository.ScraperProvider, parser repository.ParserPr
 	eventvalidation, _ := html.Find("input[name='__EVENTVALIDATION']").Attr("value")
 
 	// Make structs for pipeline generation
-	formData := utils.PartialFormData{ViewState: viewstate, ViewStateGen: viewstategen, EventValidation: eventvalidation, Url: repository.CLASSWORK_ROUTE, Base: params.Base}
+	formData := utils.PartialFormData{ViewState: viewstate, ViewStateGen: viewstategen, EventValidation: eventvalidation, Url: profile.Routes.Classwork, Base: params.Base}
 	recievedInfo := recievedClassworkInfo{HTML: html, Mp: currMarkingPer}
 	functions := utils.PipelineFunctions[models.Classwork, int]{
 		GenFormData: func(mp string, pfd utils.PartialFormData) map[string]string {
 			return utils.MakeClassworkFormData(mp, &pfd)
 		},
-		Parse: parser.ParseClasswork,
+		Parse: func(html *goquery.Selection) models.Classwork {
+			classwork := parser.ParseClasswork(html, profile.Selectors)
+			if params.Normalize {
+				classwork = parser.NormalizeClasswork(classwork)
+			}
+			return classwork
+		},
 		ToFormData: func(mp int) string {
 			return strconv.Itoa(mp) + markingPerSuffix
 		},
@@ -56,14 +63,35 @@ func getClasswork(scraper repository.ScraperProvider, parser repository.ParserPr
 		params.MarkingPeriods = append(params.MarkingPeriods, recievedInfo.Mp)
 	}
 
-	// Generate classwork
-	recievedClasswork, err := utils.GeneratePipeline[models.Classwork, int](scraper, collector, params.MarkingPeriods, recievedInfo, &formData, functions)
+	// Stream classwork as it is parsed, if requested
+	if emit != nil {
+		pipelineErrors, err := utils.StreamPipeline[models.Classwork, int](ctx, scraper, collector, profile.PipelineWorkers, params.MarkingPeriods, recievedInfo, &formData, functions, params.Partial, emit)
+
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return nil, itemErrors(pipelineErrors, params.MarkingPeriods, strconv.Itoa), nil
+	}
+
+	// Generate classwork, leaving out the marking periods which fail if partial results were requested
+	if params.Partial {
+		recievedClasswork, pipelineErrors, err := utils.GeneratePartialPipeline[models.Classwork, int](ctx, scraper, collector, profile.PipelineWorkers, params.MarkingPeriods, recievedInfo, &formData, functions)
+
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return recievedClasswork, itemErrors(pipelineErrors, params.MarkingPeriods, strconv.Itoa), nil
+	}
+
+	recievedClasswork, err := utils.GeneratePipeline[models.Classwork, int](ctx, scraper, collector, profile.PipelineWorkers, params.MarkingPeriods, recievedInfo, &formData, functions)
 
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	return recievedClasswork, nil
+	return recievedClasswork, nil, nil
 }
 
 // recievedClassworkInfo struct representing classwork information
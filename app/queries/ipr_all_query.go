@@ -1,6 +1,7 @@
 package queries
 
 import (
+	"context"
 	"time"
 
 	"github.com/PuerkitoBio/goquery"
@@ -11,24 +12,25 @@ import (
 )
 
 // getIPRAll returns all the IPRs registered for the user, or the dates only if specified.
-func getIPRAll(scraper repository.ScraperProvider, parser repository.ParserProvider, collector *colly.Collector, params models.IprAllRequestBody) ([]models.IPR, error) {
+// If emit isn't nil, IPRs are passed to it as they are parsed instead.
+func getIPRAll(ctx context.Context, scraper repository.ScraperProvider, parser repository.ParserProvider, profile repository.DistrictProfile, collector *colly.Collector, params models.IprAllRequestBody, emit func(int, models.IPR)) ([]models.IPR, []models.ItemError, error) {
 	// Get initial page
-	collector, html, err := scraper.Navigate(collector, params.Base, repository.IPR_ROUTE)
+	collector, html, err := scraper.Navigate(ctx, collector, params.Base, profile.Routes.IPR)
 
 	// Check for initial success
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	// Determine current IPR date
-	currDateOptionAttr := html.Find("#plnMain_ddlIPRDates > option[selected='selected']").Text()
+	currDateOptionAttr := html.Find(profile.Selectors.IPRDates + " > option[selected='selected']").Text()
 	currDate, err := time.Parse("01/02/2006", currDateOptionAttr)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	// Get every single avaliable date
-	dateOptionEles := html.Find("#plnMain_ddlIPRDates > option")
+	dateOptionEles := html.Find(profile.Selectors.IPRDates + " > option")
 	dates := make([]time.Time, 0, dateOptionEles.Length())
 
 	dateOptionEles.Each(func(_ int, dateOptionEle *goquery.Selection) {
@@ -48,9 +50,17 @@ func getIPRAll(scraper repository.ScraperProvider, parser repository.ParserProvi
 	if params.DatesOnly {
 		partialIPRs := make([]models.IPR, 0, len(dates))
 		for _, date := range dates {
-			partialIPRs = append(partialIPRs, models.IPR{Date: date.Format("01/02/2006"), Entries: []models.IPREntry{}})
+			partialIPR := models.IPR{Date: date.Format("01/02/2006"), Entries: []models.IPREntry{}}
+			if params.Normalize {
+				partialIPR = parser.NormalizeIPR(partialIPR)
+			}
+			partialIPRs = append(partialIPRs, partialIPR)
+
+			if emit != nil {
+				emit(len(partialIPRs)-1, partialIPR)
+			}
 		}
-		return partialIPRs, nil
+		return partialIPRs, nil, nil
 	}
 
 	// Get other necessary fields
@@ -59,24 +69,51 @@ func getIPRAll(scraper repository.ScraperProvider, parser repository.ParserProvi
 	eventvalidation, _ := html.Find("input[name='__EVENTVALIDATION']").Attr("value")
 
 	// Make structs for pipeline generation
-	formData := utils.PartialFormData{ViewState: viewstate, ViewStateGen: viewstategen, EventValidation: eventvalidation, Url: repository.IPR_ROUTE, Base: params.Base}
+	formData := utils.PartialFormData{ViewState: viewstate, ViewStateGen: viewstategen, EventValidation: eventvalidation, Url: profile.Routes.IPR, Base: params.Base}
 	recievedInfo := recievedIPRInfo{HTML: html, Date: currDate}
 	functions := utils.PipelineFunctions[models.IPR, time.Time]{
 		GenFormData: func(date string, pfd utils.PartialFormData) map[string]string {
 			return utils.MakeIPRFormData(date, &pfd)
 		},
-		Parse: parser.ParseIPR,
+		Parse: func(html *goquery.Selection) models.IPR {
+			ipr := parser.ParseIPR(html, profile.Selectors)
+			if params.Normalize {
+				ipr = parser.NormalizeIPR(ipr)
+			}
+			return ipr
+		},
 		ToFormData: func(date time.Time) string {
 			return date.Format("1/2/2006 03:04:05 PM")
 		},
 	}
 
-	// Generate IPRs
-	recievedIPRs, err := utils.GeneratePipeline[models.IPR, time.Time](scraper, collector, dates, recievedInfo, &formData, functions)
+	// Stream IPRs as they are parsed, if requested
+	if emit != nil {
+		pipelineErrors, err := utils.StreamPipeline[models.IPR, time.Time](ctx, scraper, collector, profile.PipelineWorkers, dates, recievedInfo, &formData, functions, params.Partial, emit)
+
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return nil, itemErrors(pipelineErrors, dates, formatIPRDate), nil
+	}
+
+	// Generate IPRs, leaving out the dates which fail if partial results were requested
+	if params.Partial {
+		recievedIPRs, pipelineErrors, err := utils.GeneratePartialPipeline[models.IPR, time.Time](ctx, scraper, collector, profile.PipelineWorkers, dates, recievedInfo, &formData, functions)
+
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return recievedIPRs, itemErrors(pipelineErrors, dates, formatIPRDate), nil
+	}
+
+	recievedIPRs, err := utils.GeneratePipeline[models.IPR, time.Time](ctx, scraper, collector, profile.PipelineWorkers, dates, recievedInfo, &formData, functions)
 
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	return recievedIPRs, nil
+	return recievedIPRs, nil, nil
 }
@@ -1,6 +1,7 @@
 package queries
 
 import (
+	"context"
 	"time"
 
 	"github.com/PuerkitoBio/goquery"
@@ -11,26 +12,26 @@ import (
 )
 
 // getIPR returns the latest IPR or the IPR for the date specified.
-func getIPR(scraper repository.ScraperProvider, parser repository.ParserProvider, collector *colly.Collector, params models.IprRequestBody) ([]models.IPR, error) {
+func getIPR(ctx context.Context, scraper repository.ScraperProvider, parser repository.ParserProvider, profile repository.DistrictProfile, collector *colly.Collector, params models.IprRequestBody) ([]models.IPR, []models.ItemError, error) {
 	// Get initial page
-	collector, html, err := scraper.Navigate(collector, params.Base, repository.IPR_ROUTE)
+	collector, html, err := scraper.Navigate(ctx, collector, params.Base, profile.Routes.IPR)
 
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	// Parse date
 	date, err := time.Parse("01/02/2006", params.Date)
 
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	// Determine current IPR date
-	currDateOptionAttr := html.Find("#plnMain_ddlIPRDates > option[selected='selected']").Text()
+	currDateOptionAttr := html.Find(profile.Selectors.IPRDates + " > option[selected='selected']").Text()
 	currDate, err := time.Parse("01/02/2006", currDateOptionAttr)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	// Get other necessary fields
@@ -39,13 +40,19 @@ func getIPR(scraper repository.ScraperProvider, parser repository.ParserProvider
 	eventvalidation, _ := html.Find("input[name='__EVENTVALIDATION']").Attr("value")
 
 	// Make structs for pipeline generation
-	formData := utils.PartialFormData{ViewState: viewstate, ViewStateGen: viewstategen, EventValidation: eventvalidation, Url: repository.IPR_ROUTE, Base: params.Base}
+	formData := utils.PartialFormData{ViewState: viewstate, ViewStateGen: viewstategen, EventValidation: eventvalidation, Url: profile.Routes.IPR, Base: params.Base}
 	recievedInfo := recievedIPRInfo{HTML: html, Date: currDate}
 	functions := utils.PipelineFunctions[models.IPR, time.Time]{
 		GenFormData: func(date string, pfd utils.PartialFormData) map[string]string {
 			return utils.MakeIPRFormData(date, &pfd)
 		},
-		Parse: parser.ParseIPR,
+		Parse: func(html *goquery.Selection) models.IPR {
+			ipr := parser.ParseIPR(html, profile.Selectors)
+			if params.Normalize {
+				ipr = parser.NormalizeIPR(ipr)
+			}
+			return ipr
+		},
 		ToFormData: func(date time.Time) string {
 			return date.Format("1/2/2006 03:04:05 PM")
 		},
@@ -59,14 +66,24 @@ func getIPR(scraper repository.ScraperProvider, parser repository.ParserProvider
 		dates = append(dates, date)
 	}
 
-	// Generate IPR
-	recievedIPRs, err := utils.GeneratePipeline[models.IPR, time.Time](scraper, collector, dates, recievedInfo, &formData, functions)
+	// Generate IPR, leaving out the dates which fail if partial results were requested
+	if params.Partial {
+		recievedIPRs, pipelineErrors, err := utils.GeneratePartialPipeline[models.IPR, time.Time](ctx, scraper, collector, profile.PipelineWorkers, dates, recievedInfo, &formData, functions)
+
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return recievedIPRs, itemErrors(pipelineErrors, dates, formatIPRDate), nil
+	}
+
+	recievedIPRs, err := utils.GeneratePipeline[models.IPR, time.Time](ctx, scraper, collector, profile.PipelineWorkers, dates, recievedInfo, &formData, functions)
 
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	return recievedIPRs, nil
+	return recievedIPRs, nil, nil
 }
 
 // recievedIPRInfo struct representing IPR information
@@ -83,3 +100,8 @@ func (rii recievedIPRInfo) Html() *goquery.Selection {
 func (rii recievedIPRInfo) Equal(other time.Time) bool {
 	return other.Equal(rii.Date)
 }
+
+// formatIPRDate formats an IPR date the way HAC shows it.
+func formatIPRDate(date time.Time) string {
+	return date.Format("01/02/2006")
+}
@@ -7,42 +7,39 @@ import (
 
 	"github.com/PuerkitoBio/goquery"
 	"github.com/Threqt1/HACApi/app/models"
+	"github.com/Threqt1/HACApi/pkg/repository"
 )
 
 // parseClasswork takes in the initial page HTML, and outputs
 // the parsed classwork.
-func parseClasswork(html *goquery.Selection) models.Classwork {
+func parseClasswork(html *goquery.Selection, selectors repository.Selectors) models.Classwork {
 	// Make a struct to store parsed classwork in, allocate if necessary
 	classwork := models.Classwork{}
 
 	// Get all the classes on the page
 	classEles := html.Find(".AssignmentClass")
 
-	// Allocate memory for the slice
-	classwork.Entries = make([]models.ClassworkEntry, 0, classEles.Length())
+	// Allocate memory for the slice, each class is stored at its position on the page
+	classwork.Entries = make([]models.ClassworkEntry, classEles.Length())
 
 	// Find marking period, try to make it into an int
-	MarkingPerStr := strings.TrimSpace(html.Find("#plnMain_ddlReportCardRuns > option[selected='selected']").Text())
+	MarkingPerStr := strings.TrimSpace(html.Find(selectors.ReportCardRuns + " > option[selected='selected']").Text())
 	MarkingPer, err := strconv.Atoi(MarkingPerStr)
 	if err == nil {
 		classwork.MarkingPeriod = MarkingPer
 	}
 
 	var wg sync.WaitGroup
-	var mutex sync.Mutex
 
 	// Go through each class, parsing all assignments for each class and other data into a ClassworkEntry struct and
-	// pushing it to the slice
+	// storing it in the slice
 	classEles.Each(func(classPos int, classEle *goquery.Selection) {
 		wg.Add(1)
 
 		go func() {
 			defer wg.Done()
-			// Get classwork entry, push it to slice
-			classworkEntry := parseClassworkEntry(classEle, classPos)
-			mutex.Lock()
-			defer mutex.Unlock()
-			classwork.Entries = append(classwork.Entries, classworkEntry)
+			// Get classwork entry, store it at its position
+			classwork.Entries[classPos] = parseClassworkEntry(classEle, classPos, selectors)
 		}()
 	})
 
@@ -52,7 +49,7 @@ func parseClasswork(html *goquery.Selection) models.Classwork {
 }
 
 // parseClassworkEntry parses an individual class entry in the page. Meant for concurrency.
-func parseClassworkEntry(classEle *goquery.Selection, classPos int) models.ClassworkEntry {
+func parseClassworkEntry(classEle *goquery.Selection, classPos int, selectors repository.Selectors) models.ClassworkEntry {
 	// Create the entry
 	classworkEntry := models.ClassworkEntry{}
 
@@ -74,30 +71,40 @@ func parseClassworkEntry(classEle *goquery.Selection, classPos int) models.Class
 	splitAverageText := strings.Split(strings.TrimSpace(classEle.Find("span.sg-header-heading").First().Text()), " ")
 	classworkEntry.Average = strings.TrimSpace(splitAverageText[len(splitAverageText)-1])
 
-	// Get all assignments
+	// Get all assignments, leaving out the rows of the category breakdown
 	assignments := classEle.Find("table.sg-asp-table:first-child tr.sg-asp-table-data-row")
+	if selectors.ClassworkCategories != "" {
+		assignments = assignments.Not(selectors.ClassworkCategories + " tr")
+	}
 
 	// Allocate space for assignments array
-	classworkEntry.Assignments = make([]models.Assignment, 0, assignments.Length())
+	classworkEntry.Assignments = make([]models.Assignment, assignments.Length())
 
 	var wg sync.WaitGroup
-	var mutex sync.Mutex
 
 	// Loop through each assignment, parsing them
-	assignments.Each(func(_ int, assignmentEle *goquery.Selection) {
+	assignments.Each(func(i int, assignmentEle *goquery.Selection) {
 		wg.Add(1)
 
 		go func() {
 			defer wg.Done()
-			assignment := parseClassworkAssignment(assignmentEle)
-			mutex.Lock()
-			defer mutex.Unlock()
-			classworkEntry.Assignments = append(classworkEntry.Assignments, assignment)
+			classworkEntry.Assignments[i] = parseClassworkAssignment(assignmentEle)
 		}()
 	})
 
 	wg.Wait()
 
+	// Get the category breakdown of the average, if the district lists one
+	if selectors.ClassworkCategories == "" {
+		return classworkEntry
+	}
+	classEle.Find(selectors.ClassworkCategories + " tr.sg-asp-table-data-row").Each(func(_ int, categoryEle *goquery.Selection) {
+		// Leave out the row totalling the categories, in case it isn't a footer
+		if category := parseClassworkCategory(categoryEle); category.Name != "" && !strings.EqualFold(category.Name, "total") {
+			classworkEntry.Categories = append(classworkEntry.Categories, category)
+		}
+	})
+
 	return classworkEntry
 }
 
@@ -140,3 +147,33 @@ func parseClassworkAssignment(assignmentEle *goquery.Selection) models.Assignmen
 
 	return assignment
 }
+
+// parseClassworkCategory parses a row of the category breakdown of a class.
+func parseClassworkCategory(categoryEle *goquery.Selection) models.ClassworkCategory {
+	// Create the category
+	category := models.ClassworkCategory{}
+
+	// Go through each td in the category's HTML row, using index to figure out
+	// what data it represents
+	categoryEle.Find("td").Each(func(i int, dataEle *goquery.Selection) {
+		text := strings.TrimSpace(dataEle.Text())
+
+		// Fill in data using i
+		switch i {
+		case 0:
+			category.Name = text
+		case 1:
+			category.StudentPoints = text
+		case 2:
+			category.MaximumPoints = text
+		case 3:
+			category.Percent = text
+		case 4:
+			category.Weight = text
+		case 5:
+			category.CategoryPoints = text
+		}
+	})
+
+	return category
+}
@@ -0,0 +1,132 @@
+package parsers
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/Threqt1/HACApi/app/models"
+	"github.com/Threqt1/HACApi/pkg/repository"
+	"github.com/google/go-cmp/cmp"
+)
+
+// testClasswork_HTML creates a classwork page with the given amount of classes and assignments per class.
+func testClasswork_HTML(classes, assignments int) *goquery.Selection {
+	var page strings.Builder
+
+	page.WriteString(`<html><body><select id="plnMain_ddlReportCardRuns"><option value="1-2023" selected="selected">1</option></select>`)
+	for i := 0; i < classes; i++ {
+		page.WriteString(fmt.Sprintf(`<div class="AssignmentClass"><div><a class="sg-header-heading">%04dA - 1 Class %d</a><span class="sg-header-heading">Cycle Average %d.00</span></div><div><table class="sg-asp-table">`, i, i, i))
+		for j := 0; j < assignments; j++ {
+			page.WriteString(fmt.Sprintf(`<tr class="sg-asp-table-data-row"><td>10/03/2022</td><td>10/01/2022</td><td>Assignment %d</td><td>Minor</td><td>%d</td><td>100</td></tr>`, j, j))
+		}
+		page.WriteString(`</table></div></div>`)
+	}
+	page.WriteString(`</body></html>`)
+
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(page.String()))
+	if err != nil {
+		return nil
+	}
+	return doc.Find("body")
+}
+
+// Test if parseClasswork() keeps the classes and assignments in the order of the page.
+func TestParseClasswork_Ordered(t *testing.T) {
+	// Set up the page.
+	classes, assignments := 8, 12
+	html := testClasswork_HTML(classes, assignments)
+
+	// Make expected value.
+	expected := models.Classwork{MarkingPeriod: 1, Entries: make([]models.ClassworkEntry, classes)}
+	for i := range expected.Entries {
+		expected.Entries[i] = models.ClassworkEntry{
+			Position:    i,
+			Class:       models.Class{Name: fmt.Sprintf("Class %d", i), Course: fmt.Sprintf("%04dA - 1", i)},
+			Average:     fmt.Sprintf("%d.00", i),
+			Assignments: make([]models.Assignment, assignments),
+		}
+		for j := range expected.Entries[i].Assignments {
+			expected.Entries[i].Assignments[j] = models.Assignment{
+				DueDate:      "10/03/2022",
+				AssignedDate: "10/01/2022",
+				Name:         fmt.Sprintf("Assignment %d", j),
+				Category:     "Minor",
+				Grade:        fmt.Sprint(j),
+				TotalPoints:  "100",
+			}
+		}
+	}
+
+	// Test repeatedly, as the parsing is concurrent.
+	for run := 0; run < 20; run++ {
+		got := parseClasswork(html, repository.DefaultDistrictProfile().Selectors)
+
+		if diff := cmp.Diff(expected, got); diff != "" {
+			t.Fatalf("Failed for parseClasswork() Ordered (-want, +got):\n%s", diff)
+		}
+	}
+}
+
+// Test if parseClasswork() parses the category breakdown of a class,
+// without mistaking its rows for assignments.
+func TestParseClasswork_Categories(t *testing.T) {
+	page := `<html><body><div class="AssignmentClass">
+		<div><a class="sg-header-heading">0401A - 1 Algebra</a><span class="sg-header-heading">Cycle Average 91.00</span></div>
+		<div class="sg-content-grid"><table class="sg-asp-table" id="plnMain_rptAssigmnetsByCourse_dgCourseAssignments_0">
+			<tr class="sg-asp-table-header-row"><td>Date Due</td></tr>
+			<tr class="sg-asp-table-data-row"><td>10/03/2022</td><td>10/01/2022</td><td>Test</td><td>Major Grades</td><td>90.00</td><td>100.00</td></tr>
+		</table></div>
+		<div class="sg-content-grid"><table class="sg-asp-table" id="plnMain_rptAssigmnetsByCourse_dgCourseCategories_0">
+			<tr class="sg-asp-table-header-row"><td>Category</td><td>Student's Points</td><td>Maximum Points</td><td>Percent</td><td>Category Weight</td><td>Category Points</td></tr>
+			<tr class="sg-asp-table-data-row"><td>Major Grades</td><td>90.00</td><td>100.0000</td><td>90.000%</td><td>60.0000</td><td>54.00</td></tr>
+			<tr class="sg-asp-table-data-row"><td>Minor Grades</td><td></td><td></td><td></td><td>40.0000</td><td></td></tr>
+			<tr class="sg-asp-table-footer-row"><td>Total</td><td></td><td></td><td></td><td>100.0000</td><td>54.00</td></tr>
+		</table></div>
+	</div></body></html>`
+
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(page))
+	if err != nil {
+		t.Fatalf("Failed to parse page: %v", err)
+	}
+
+	got := parseClasswork(doc.Find("body"), repository.DefaultDistrictProfile().Selectors)
+
+	// Make expected value.
+	expected := models.Classwork{Entries: []models.ClassworkEntry{{
+		Class:   models.Class{Name: "Algebra", Course: "0401A - 1"},
+		Average: "91.00",
+		Assignments: []models.Assignment{
+			{DueDate: "10/03/2022", AssignedDate: "10/01/2022", Name: "Test", Category: "Major Grades", Grade: "90.00", TotalPoints: "100.00"},
+		},
+		Categories: []models.ClassworkCategory{
+			{Name: "Major Grades", StudentPoints: "90.00", MaximumPoints: "100.0000", Percent: "90.000%", Weight: "60.0000", CategoryPoints: "54.00"},
+			{Name: "Minor Grades", Weight: "40.0000"},
+		},
+	}}}
+
+	if diff := cmp.Diff(expected, got); diff != "" {
+		t.Fatalf("Failed for parseClasswork() Categories (-want, +got):\n%s", diff)
+	}
+}
+
+// Test if an excused grade ("X") keeps the raw dropped flag, but is
+// marked as excused once normalized.
+func TestParseClassworkAssignment_Excused(t *testing.T) {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(`<table><tr><td>10/03/2022</td><td>10/01/2022</td><td>Lab</td><td>Minor</td><td>X</td><td>100</td></tr></table>`))
+	if err != nil {
+		t.Fatalf("Failed to parse test page: %v", err)
+	}
+
+	// Test.
+	assignment := parseClassworkAssignment(doc.Find("tr"))
+	if !assignment.Dropped {
+		t.Fatalf("Failed for parseClassworkAssignment() with an excused grade, expected it to be dropped")
+	}
+
+	normalized := normalizeClasswork(models.Classwork{Entries: []models.ClassworkEntry{{Assignments: []models.Assignment{assignment}}}})
+	if diff := cmp.Diff(models.SpecialMarkExcused, normalized.Entries[0].Assignments[0].Normalized.Grade.Mark); diff != "" {
+		t.Fatalf("Failed for normalizeClasswork() with an excused grade (-want, +got):\n%s", diff)
+	}
+}
@@ -6,37 +6,34 @@ import (
 
 	"github.com/PuerkitoBio/goquery"
 	"github.com/Threqt1/HACApi/app/models"
+	"github.com/Threqt1/HACApi/pkg/repository"
 )
 
 // parseIPR takes in the initial page HTMl, and
 // returns the parsed IPR.
-func parseIPR(html *goquery.Selection) models.IPR {
+func parseIPR(html *goquery.Selection, selectors repository.Selectors) models.IPR {
 	// Make a struct to store parsed IPR info to
 	ipr := models.IPR{}
 
 	// Get date
-	dateText := html.Find("#plnMain_ddlIPRDates > option[selected='selected']").Text()
+	dateText := html.Find(selectors.IPRDates + " > option[selected='selected']").Text()
 	ipr.Date = strings.TrimSpace(dateText)
 
 	// Get all IPR class HTML rows
 	classEles := html.Find("table.sg-asp-table:first-child tr.sg-asp-table-data-row")
 
 	// Allocate memory for the array
-	ipr.Entries = make([]models.IPREntry, 0, classEles.Length())
+	ipr.Entries = make([]models.IPREntry, classEles.Length())
 
 	var wg sync.WaitGroup
-	var mutex sync.Mutex
 
 	// Parse each row
-	classEles.Each(func(_ int, iprRowEle *goquery.Selection) {
+	classEles.Each(func(i int, iprRowEle *goquery.Selection) {
 		wg.Add(1)
 
 		go func() {
 			defer wg.Done()
-			iprEntry := parseIPREntry(iprRowEle)
-			mutex.Lock()
-			defer mutex.Unlock()
-			ipr.Entries = append(ipr.Entries, iprEntry)
+			ipr.Entries[i] = parseIPREntry(iprRowEle)
 		}()
 	})
 
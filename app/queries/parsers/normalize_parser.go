@@ -0,0 +1,173 @@
+package parsers
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Threqt1/HACApi/app/models"
+)
+
+// The date layouts HAC uses, and the ISO-8601 layout dates are normalized into.
+var hacDateLayouts = []string{"01/02/2006", "1/2/2006"}
+
+const isoDateLayout = "2006-01-02"
+
+// The special marks HAC puts in place of a grade.
+var specialMarks = map[string]models.SpecialMark{
+	"":    models.SpecialMarkNotGraded,
+	"NG":  models.SpecialMarkNotGraded,
+	"M":   models.SpecialMarkMissing,
+	"MSG": models.SpecialMarkMissing,
+	"Z":   models.SpecialMarkMissing,
+	"EX":  models.SpecialMarkExcused,
+	"EXC": models.SpecialMarkExcused,
+	"X":   models.SpecialMarkExcused,
+}
+
+// normalizeDate converts a HAC date into ISO-8601, or returns
+// an empty string if the date can't be parsed.
+func normalizeDate(text string) string {
+	for _, layout := range hacDateLayouts {
+		if date, err := time.Parse(layout, strings.TrimSpace(text)); err == nil {
+			return date.Format(isoDateLayout)
+		}
+	}
+	return ""
+}
+
+// normalizeNumber parses a HAC number such as "95.00" or "95%", returning nil
+// if it isn't a number.
+func normalizeNumber(text string) *float64 {
+	text = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(text), "%"))
+	number, err := strconv.ParseFloat(strings.ReplaceAll(text, ",", ""), 64)
+	if err != nil {
+		return nil
+	}
+	return &number
+}
+
+// normalizeGrade parses a grade out of the points scored and the max points. Dropped grades
+// keep their score, but are marked as dropped, unless the grade itself marks the assignment
+// as excused, which the raw fields also flag as dropped.
+func normalizeGrade(grade, maxPoints string, dropped bool) models.NormalizedGrade {
+	normalizedGrade := models.NormalizedGrade{
+		Score:     normalizeNumber(grade),
+		MaxPoints: normalizeNumber(maxPoints),
+	}
+
+	// Find any special mark
+	if normalizedGrade.Score == nil {
+		normalizedGrade.Mark = specialMarks[strings.ToUpper(strings.TrimSpace(grade))]
+	}
+	if dropped && normalizedGrade.Mark != models.SpecialMarkExcused {
+		normalizedGrade.Mark = models.SpecialMarkDropped
+	}
+
+	// Calculate the percentage if possible
+	if normalizedGrade.Score != nil && normalizedGrade.MaxPoints != nil && *normalizedGrade.MaxPoints > 0 {
+		percentage := *normalizedGrade.Score / *normalizedGrade.MaxPoints * 100
+		normalizedGrade.Percentage = &percentage
+	}
+
+	return normalizedGrade
+}
+
+// normalizeAverage parses an average, which is already a percentage.
+func normalizeAverage(average string) models.NormalizedGrade {
+	normalizedGrade := models.NormalizedGrade{Score: normalizeNumber(average)}
+
+	if normalizedGrade.Score == nil {
+		normalizedGrade.Mark = specialMarks[strings.ToUpper(strings.TrimSpace(average))]
+	} else {
+		normalizedGrade.Percentage = normalizedGrade.Score
+	}
+
+	return normalizedGrade
+}
+
+// normalizeClasswork fills in the normalized fields of classwork.
+func normalizeClasswork(classwork models.Classwork) models.Classwork {
+	for i := range classwork.Entries {
+		entry := &classwork.Entries[i]
+
+		average := normalizeAverage(entry.Average)
+		entry.Normalized = &average
+
+		for j := range entry.Assignments {
+			assignment := &entry.Assignments[j]
+			assignment.Normalized = &models.NormalizedAssignment{
+				DueDate:      normalizeDate(assignment.DueDate),
+				AssignedDate: normalizeDate(assignment.AssignedDate),
+				Grade:        normalizeGrade(assignment.Grade, assignment.TotalPoints, assignment.Dropped),
+			}
+		}
+
+		for j := range entry.Categories {
+			category := &entry.Categories[j]
+			category.Normalized = &models.NormalizedCategory{
+				StudentPoints:  normalizeNumber(category.StudentPoints),
+				MaximumPoints:  normalizeNumber(category.MaximumPoints),
+				Percent:        normalizeNumber(category.Percent),
+				Weight:         normalizeNumber(category.Weight),
+				CategoryPoints: normalizeNumber(category.CategoryPoints),
+			}
+		}
+	}
+
+	return classwork
+}
+
+// normalizeIPR fills in the normalized fields of an IPR.
+func normalizeIPR(ipr models.IPR) models.IPR {
+	ipr.Normalized = &models.NormalizedIPR{Date: normalizeDate(ipr.Date)}
+
+	for i := range ipr.Entries {
+		grade := normalizeAverage(ipr.Entries[i].Grade)
+		ipr.Entries[i].Normalized = &grade
+	}
+
+	return ipr
+}
+
+// normalizeReportCard fills in the normalized fields of a report card.
+func normalizeReportCard(reportCard models.ReportCard) models.ReportCard {
+	for i := range reportCard.Entries {
+		entry := &reportCard.Entries[i]
+		entry.Normalized = &models.NormalizedReportCardEntry{
+			AttemptedCredit: normalizeNumber(entry.AttemptedCredit),
+			EarnedCredit:    normalizeNumber(entry.EarnedCredit),
+		}
+
+		averages := &entry.Averages
+		averages.Normalized = &models.NormalizedSixWeeksGrades{
+			First:  normalizeAverage(averages.First),
+			Second: normalizeAverage(averages.Second),
+			Third:  normalizeAverage(averages.Third),
+			Fourth: normalizeAverage(averages.Fourth),
+			Fifth:  normalizeAverage(averages.Fifth),
+			Sixth:  normalizeAverage(averages.Sixth),
+			Exam1:  normalizeAverage(averages.Exam1),
+			Sem1:   normalizeAverage(averages.Sem1),
+			Exam2:  normalizeAverage(averages.Exam2),
+			Sem2:   normalizeAverage(averages.Sem2),
+		}
+	}
+
+	return reportCard
+}
+
+// normalizeTranscript fills in the normalized fields of a transcript.
+func normalizeTranscript(transcript models.Transcript) models.Transcript {
+	for i := range transcript.Entries {
+		for j := range transcript.Entries[i].Entries {
+			entry := &transcript.Entries[i].Entries[j]
+			entry.Normalized = &models.NormalizedTranscriptEntry{
+				Average: normalizeAverage(entry.Average),
+				Credit:  normalizeNumber(entry.Credit),
+			}
+		}
+	}
+
+	return transcript
+}
@@ -0,0 +1,109 @@
+package parsers
+
+import (
+	"testing"
+
+	"github.com/Threqt1/HACApi/app/models"
+	"github.com/google/go-cmp/cmp"
+)
+
+// testNormalize_Float returns a pointer to a float, for building expected grades.
+func testNormalize_Float(f float64) *float64 {
+	return &f
+}
+
+// Test if normalizeDate() converts HAC dates into ISO-8601.
+func TestNormalizeDate(t *testing.T) {
+	// Set up all test cases, mapping the input to the expected output.
+	cases := map[string]string{
+		"10/03/2022":   "2022-10-03",
+		"1/6/2016":     "2016-01-06",
+		" 09/06/2022 ": "2022-09-06",
+		"":             "",
+		"not a date":   "",
+	}
+
+	for input, expected := range cases {
+		if diff := cmp.Diff(expected, normalizeDate(input)); diff != "" {
+			t.Fatalf("Failed for normalizeDate() with input %q (-want, +got):\n%s", input, diff)
+		}
+	}
+}
+
+// testNormalizeGrade_Case represents an individual test case for normalizeGrade().
+type testNormalizeGrade_Case struct {
+	Grade     string
+	MaxPoints string
+	Dropped   bool
+	Test      models.NormalizedGrade
+}
+
+// Test if normalizeGrade() parses scores, points and special marks.
+func TestNormalizeGrade(t *testing.T) {
+	// Set up all test cases.
+	cases := []testNormalizeGrade_Case{
+		// Numeric grades.
+		{Grade: "95.00", MaxPoints: "100.00", Test: models.NormalizedGrade{Score: testNormalize_Float(95), MaxPoints: testNormalize_Float(100), Percentage: testNormalize_Float(95)}},
+		{Grade: "8", MaxPoints: "10", Test: models.NormalizedGrade{Score: testNormalize_Float(8), MaxPoints: testNormalize_Float(10), Percentage: testNormalize_Float(80)}},
+		// Zero max points should not divide by zero.
+		{Grade: "5", MaxPoints: "0", Test: models.NormalizedGrade{Score: testNormalize_Float(5), MaxPoints: testNormalize_Float(0)}},
+		// Special marks.
+		{Grade: "Z", MaxPoints: "100", Test: models.NormalizedGrade{MaxPoints: testNormalize_Float(100), Mark: models.SpecialMarkMissing}},
+		{Grade: "EXC", MaxPoints: "100", Test: models.NormalizedGrade{MaxPoints: testNormalize_Float(100), Mark: models.SpecialMarkExcused}},
+		{Grade: "X", MaxPoints: "100", Test: models.NormalizedGrade{MaxPoints: testNormalize_Float(100), Mark: models.SpecialMarkExcused}},
+		{Grade: "X", MaxPoints: "100", Dropped: true, Test: models.NormalizedGrade{MaxPoints: testNormalize_Float(100), Mark: models.SpecialMarkExcused}},
+		{Grade: "", MaxPoints: "100", Test: models.NormalizedGrade{MaxPoints: testNormalize_Float(100), Mark: models.SpecialMarkNotGraded}},
+		// Dropped grades keep their score.
+		{Grade: "50", MaxPoints: "100", Dropped: true, Test: models.NormalizedGrade{Score: testNormalize_Float(50), MaxPoints: testNormalize_Float(100), Percentage: testNormalize_Float(50), Mark: models.SpecialMarkDropped}},
+	}
+
+	for _, test := range cases {
+		got := normalizeGrade(test.Grade, test.MaxPoints, test.Dropped)
+
+		if diff := cmp.Diff(test.Test, got); diff != "" {
+			t.Fatalf("Failed for normalizeGrade() with grade %q (-want, +got):\n%s", test.Grade, diff)
+		}
+	}
+}
+
+// Test if normalizeAverage() treats averages as percentages.
+func TestNormalizeAverage(t *testing.T) {
+	// Set up all test cases, mapping the input to the expected output.
+	cases := map[string]models.NormalizedGrade{
+		"95.00": {Score: testNormalize_Float(95), Percentage: testNormalize_Float(95)},
+		"88%":   {Score: testNormalize_Float(88), Percentage: testNormalize_Float(88)},
+		"":      {Mark: models.SpecialMarkNotGraded},
+	}
+
+	for input, expected := range cases {
+		if diff := cmp.Diff(expected, normalizeAverage(input)); diff != "" {
+			t.Fatalf("Failed for normalizeAverage() with input %q (-want, +got):\n%s", input, diff)
+		}
+	}
+}
+
+// Test if normalizeTranscript() parses the average and credit of every entry.
+func TestNormalizeTranscript(t *testing.T) {
+	transcript := models.Transcript{Entries: []models.TranscriptGroup{{
+		Entries: []models.TranscriptGroupEntry{
+			{Average: "95.00", Credit: "0.500"},
+			{Average: "P", Credit: ""},
+		},
+	}}}
+
+	// Make expected output.
+	expected := []*models.NormalizedTranscriptEntry{
+		{Average: models.NormalizedGrade{Score: testNormalize_Float(95), Percentage: testNormalize_Float(95)}, Credit: testNormalize_Float(0.5)},
+		{Average: models.NormalizedGrade{}},
+	}
+
+	// Test.
+	got := []*models.NormalizedTranscriptEntry{}
+	for _, entry := range normalizeTranscript(transcript).Entries[0].Entries {
+		got = append(got, entry.Normalized)
+	}
+
+	if diff := cmp.Diff(expected, got); diff != "" {
+		t.Fatalf("Failed for normalizeTranscript() (-want, +got):\n%s", diff)
+	}
+}
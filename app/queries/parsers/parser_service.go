@@ -3,17 +3,18 @@ package parsers
 import (
 	"github.com/PuerkitoBio/goquery"
 	"github.com/Threqt1/HACApi/app/models"
+	"github.com/Threqt1/HACApi/pkg/repository"
 )
 
 type Parser struct {
 }
 
-func (parser Parser) ParseClasswork(html *goquery.Selection) models.Classwork {
-	return parseClasswork(html)
+func (parser Parser) ParseClasswork(html *goquery.Selection, selectors repository.Selectors) models.Classwork {
+	return parseClasswork(html, selectors)
 }
 
-func (parser Parser) ParseIPR(html *goquery.Selection) models.IPR {
-	return parseIPR(html)
+func (parser Parser) ParseIPR(html *goquery.Selection, selectors repository.Selectors) models.IPR {
+	return parseIPR(html, selectors)
 }
 
 func (parser Parser) ParseReportCard(html *goquery.Selection) models.ReportCard {
@@ -28,6 +29,26 @@ func (parser Parser) ParseTranscript(html *goquery.Selection) models.Transcript
 	return parseTranscript(html)
 }
 
+func (parser Parser) ParseWeekView(html *goquery.Selection) models.WeekView {
+	return parseWeekView(html)
+}
+
+func (parser Parser) NormalizeClasswork(classwork models.Classwork) models.Classwork {
+	return normalizeClasswork(classwork)
+}
+
+func (parser Parser) NormalizeIPR(ipr models.IPR) models.IPR {
+	return normalizeIPR(ipr)
+}
+
+func (parser Parser) NormalizeReportCard(reportCard models.ReportCard) models.ReportCard {
+	return normalizeReportCard(reportCard)
+}
+
+func (parser Parser) NormalizeTranscript(transcript models.Transcript) models.Transcript {
+	return normalizeTranscript(transcript)
+}
+
 func NewParser() Parser {
 	return Parser{}
 }
@@ -18,21 +18,17 @@ func parseReportCard(html *goquery.Selection) models.ReportCard {
 	reportCardEntryEles := html.Find("tr.sg-asp-table-data-row")
 
 	// Allocate space for array
-	reportCard.Entries = make([]models.ReportCardEntry, 0, reportCardEntryEles.Length())
+	reportCard.Entries = make([]models.ReportCardEntry, reportCardEntryEles.Length())
 
 	var wg sync.WaitGroup
-	var mutex sync.Mutex
 
 	// Go through each entry to parse it
-	reportCardEntryEles.Each(func(_ int, reportCardEntryEle *goquery.Selection) {
+	reportCardEntryEles.Each(func(i int, reportCardEntryEle *goquery.Selection) {
 		wg.Add(1)
 
 		go func() {
 			defer wg.Done()
-			reportCardEntry := parseReportCardEntry(reportCardEntryEle)
-			mutex.Lock()
-			defer mutex.Unlock()
-			reportCard.Entries = append(reportCard.Entries, reportCardEntry)
+			reportCard.Entries[i] = parseReportCardEntry(reportCardEntryEle)
 		}()
 	})
 
@@ -19,22 +19,18 @@ func parseSchedule(html *goquery.Selection) models.Schedule {
 
 	// Allocate memory for array
 
-	schedule.Entries = make([]models.ScheduleEntry, 0, scheduleEntryEles.Length())
+	schedule.Entries = make([]models.ScheduleEntry, scheduleEntryEles.Length())
 
 	var wg sync.WaitGroup
-	var mutex sync.Mutex
 
 	// Go through each class in the schedule
-	scheduleEntryEles.Each(func(_ int, scheduleEntryEle *goquery.Selection) {
+	scheduleEntryEles.Each(func(i int, scheduleEntryEle *goquery.Selection) {
 		wg.Add(1)
 
 		go func() {
 			defer wg.Done()
-			scheduleEntry := parseScheduleEntry(scheduleEntryEle)
-			mutex.Lock()
-			defer mutex.Unlock()
-			// Push the entry to the slice
-			schedule.Entries = append(schedule.Entries, scheduleEntry)
+			// Store the entry at its position in the slice
+			schedule.Entries[i] = parseScheduleEntry(scheduleEntryEle)
 		}()
 	})
 
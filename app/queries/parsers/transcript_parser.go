@@ -18,7 +18,7 @@ func parseTranscript(html *goquery.Selection) models.Transcript {
 	transcriptGroupEles := html.Find("td.sg-transcript-group")
 
 	// Allocate memory for the slice
-	transcript.Entries = make([]models.TranscriptGroup, 0, transcriptGroupEles.Length())
+	transcript.Entries = make([]models.TranscriptGroup, transcriptGroupEles.Length())
 
 	var wg sync.WaitGroup
 	var mutex sync.Mutex
@@ -29,11 +29,8 @@ func parseTranscript(html *goquery.Selection) models.Transcript {
 
 		go func() {
 			defer wg.Done()
-			transcriptGroup := parseTranscriptGroup(transcriptGroupEle)
-			mutex.Lock()
-			defer mutex.Unlock()
-			// Append group to slice
-			transcript.Entries = append(transcript.Entries, transcriptGroup)
+			// Store group at its position in the slice
+			transcript.Entries[transcriptGroupPos] = parseTranscriptGroup(transcriptGroupEle)
 		}()
 	})
 
@@ -71,7 +68,7 @@ func parseTranscriptGroup(transcriptGroupEle *goquery.Selection) models.Transcri
 	transcriptGroupEntryEles := transcriptGroupEle.Find("table.sg-asp-table tr.sg-asp-table-data-row")
 
 	// Allocate memory
-	transcriptGroup.Entries = make([]models.TranscriptGroupEntry, 0, transcriptGroupEntryEles.Length())
+	transcriptGroup.Entries = make([]models.TranscriptGroupEntry, transcriptGroupEntryEles.Length())
 
 	// Parse the top table for information about the group
 	transcriptGroupEle.Find("table:first-child td:nth-child(even)").Each(func(i int, dataEle *goquery.Selection) {
@@ -98,18 +95,14 @@ func parseTranscriptGroup(transcriptGroupEle *goquery.Selection) models.Transcri
 	transcriptGroup.TotalCredit = strings.TrimSpace(totalCreditText)
 
 	var wg sync.WaitGroup
-	var mutex sync.Mutex
 
 	// Parse each entry in the table
-	transcriptGroupEntryEles.Each(func(_ int, transcriptGroupEntryEle *goquery.Selection) {
+	transcriptGroupEntryEles.Each(func(i int, transcriptGroupEntryEle *goquery.Selection) {
 		wg.Add(1)
 
 		go func() {
 			defer wg.Done()
-			transcriptGroupEntry := parseTranscriptGroupEntry(transcriptGroupEntryEle)
-			mutex.Lock()
-			defer mutex.Unlock()
-			transcriptGroup.Entries = append(transcriptGroup.Entries, transcriptGroupEntry)
+			transcriptGroup.Entries[i] = parseTranscriptGroupEntry(transcriptGroupEntryEle)
 		}()
 	})
 
@@ -0,0 +1,117 @@
+package parsers
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/Threqt1/HACApi/app/models"
+)
+
+// The amount of columns before the day columns start in a week view row.
+const weekViewDayOffset = 3
+
+// parseWeekView takes in raw HTML and parses it into a
+// week view model.
+func parseWeekView(html *goquery.Selection) models.WeekView {
+	// Make a struct to store the parsed week view
+	weekView := models.WeekView{}
+
+	// Get every day from the header, skipping the class columns
+	dayEles := html.Find("tr.sg-asp-table-header-row th").Slice(weekViewDayOffset, goquery.ToEnd)
+
+	// Allocate memory for the days
+	weekView.Days = make([]models.WeekViewDay, dayEles.Length())
+
+	dayEles.Each(func(i int, dayEle *goquery.Selection) {
+		// The date is the last word in the header, after the weekday
+		splitDayText := strings.Fields(dayEle.Text())
+		if len(splitDayText) > 0 {
+			weekView.Days[i].Date = splitDayText[len(splitDayText)-1]
+		}
+		weekView.Days[i].Assignments = []models.WeekViewAssignment{}
+	})
+
+	// Get all class rows
+	weekViewRowEles := html.Find("tr.sg-asp-table-data-row")
+
+	// Allocate memory for the averages, and the assignments of every row
+	weekView.Averages = make([]models.WeekViewAverage, weekViewRowEles.Length())
+	rowAssignments := make([][][]models.WeekViewAssignment, weekViewRowEles.Length())
+
+	var wg sync.WaitGroup
+
+	// Go through each class row
+	weekViewRowEles.Each(func(i int, weekViewRowEle *goquery.Selection) {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+			weekView.Averages[i], rowAssignments[i] = parseWeekViewRow(weekViewRowEle, len(weekView.Days))
+		}()
+	})
+
+	wg.Wait()
+
+	// Push the assignments for each day, in the order of the rows
+	for _, assignments := range rowAssignments {
+		for i, dayAssignments := range assignments {
+			weekView.Days[i].Assignments = append(weekView.Days[i].Assignments, dayAssignments...)
+		}
+	}
+
+	return weekView
+}
+
+// parseWeekViewRow takes in the HTML element for a class row, and parses it into a
+// WeekViewAverage struct. It returns that plus the assignments due on each day.
+func parseWeekViewRow(weekViewRowEle *goquery.Selection, days int) (models.WeekViewAverage, [][]models.WeekViewAssignment) {
+	// Make structs for the parsed data
+	weekViewAverage := models.WeekViewAverage{}
+	assignments := make([][]models.WeekViewAssignment, days)
+
+	// Go through each td, using i to find what the text corresponds to
+	weekViewRowEle.Find("td").Each(func(i int, dataEle *goquery.Selection) {
+		// Day columns hold one element per assignment
+		if i >= weekViewDayOffset {
+			if i-weekViewDayOffset >= days {
+				return
+			}
+			dataEle.Find("a").Each(func(_ int, assignmentEle *goquery.Selection) {
+				assignments[i-weekViewDayOffset] = append(assignments[i-weekViewDayOffset], parseWeekViewAssignment(assignmentEle, weekViewAverage.Class))
+			})
+			return
+		}
+
+		// Parse text, return if there is none
+		text := strings.TrimSpace(dataEle.Text())
+		if text == "" {
+			return
+		}
+
+		// Fill in data using i
+		switch i {
+		case 0:
+			weekViewAverage.Class.Course = text
+		case 1:
+			weekViewAverage.Class.Name = text
+		case 2:
+			weekViewAverage.Average = text
+		}
+	})
+
+	return weekViewAverage, assignments
+}
+
+// parseWeekViewAssignment parses an individual assignment link inside a day column.
+func parseWeekViewAssignment(assignmentEle *goquery.Selection, class models.Class) models.WeekViewAssignment {
+	// Create the assignment
+	assignment := models.WeekViewAssignment{Class: class}
+
+	// The name is the link text, the grade is kept in a nested span if the assignment was graded
+	gradeText := strings.TrimSpace(assignmentEle.Find("span").Text())
+	assignment.Grade = gradeText
+	assignment.Name = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(assignmentEle.Text()), gradeText))
+
+	return assignment
+}
@@ -0,0 +1,109 @@
+package parsers
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/Threqt1/HACApi/app/models"
+	"github.com/google/go-cmp/cmp"
+)
+
+// testWeekView_Algebra and testWeekView_Biology are the classes on the week view fixture.
+var (
+	testWeekView_Algebra = models.Class{Name: "Algebra", Course: "0401A - 1"}
+	testWeekView_Biology = models.Class{Name: "AP Biology", Course: "AP0101 - 1"}
+)
+
+// testWeekView_HTML loads the week view fixture.
+func testWeekView_HTML(t *testing.T) *goquery.Selection {
+	page, err := os.ReadFile("../../../test/weekview.html")
+	if err != nil {
+		t.Fatalf("Failed to read week view fixture: %v", err)
+	}
+
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(string(page)))
+	if err != nil {
+		t.Fatalf("Failed to parse week view fixture: %v", err)
+	}
+	return doc.Find("body")
+}
+
+// Test if parseWeekView() sorts the assignments into their day columns,
+// in the order of the classes, and parses the average of every class.
+func TestParseWeekView(t *testing.T) {
+	html := testWeekView_HTML(t)
+
+	// Make expected value.
+	expected := models.WeekView{
+		Days: []models.WeekViewDay{
+			{Date: "10/03/2022", Assignments: []models.WeekViewAssignment{
+				{Class: testWeekView_Algebra, Name: "Homework 1", Grade: "100"},
+				{Class: testWeekView_Algebra, Name: "Quiz 1", Grade: "90"},
+				{Class: testWeekView_Biology, Name: "Lab Report", Grade: "85"},
+			}},
+			{Date: "10/04/2022", Assignments: []models.WeekViewAssignment{}},
+			{Date: "10/05/2022", Assignments: []models.WeekViewAssignment{
+				{Class: testWeekView_Algebra, Name: "Homework 2"},
+			}},
+			{Date: "10/06/2022", Assignments: []models.WeekViewAssignment{}},
+			{Date: "10/07/2022", Assignments: []models.WeekViewAssignment{
+				{Class: testWeekView_Biology, Name: "Unit Test"},
+			}},
+		},
+		Averages: []models.WeekViewAverage{
+			{Class: testWeekView_Algebra, Average: "95.00"},
+			{Class: testWeekView_Biology},
+		},
+	}
+
+	// Test repeatedly, as the parsing is concurrent.
+	for run := 0; run < 20; run++ {
+		got := parseWeekView(html)
+
+		if diff := cmp.Diff(expected, got); diff != "" {
+			t.Fatalf("Failed for parseWeekView() (-want, +got):\n%s", diff)
+		}
+	}
+}
+
+// Test if parseWeekViewRow() ignores day columns past the days in the header.
+func TestParseWeekViewRow_ExtraColumns(t *testing.T) {
+	row := testWeekView_HTML(t).Find("tr.sg-asp-table-data-row").First()
+
+	// Test.
+	average, assignments := parseWeekViewRow(row, 1)
+
+	if diff := cmp.Diff(models.WeekViewAverage{Class: testWeekView_Algebra, Average: "95.00"}, average); diff != "" {
+		t.Fatalf("Failed for parseWeekViewRow() average (-want, +got):\n%s", diff)
+	}
+
+	expected := [][]models.WeekViewAssignment{{
+		{Class: testWeekView_Algebra, Name: "Homework 1", Grade: "100"},
+		{Class: testWeekView_Algebra, Name: "Quiz 1", Grade: "90"},
+	}}
+	if diff := cmp.Diff(expected, assignments); diff != "" {
+		t.Fatalf("Failed for parseWeekViewRow() assignments (-want, +got):\n%s", diff)
+	}
+}
+
+// Test if parseWeekViewAssignment() splits the grade off the name.
+func TestParseWeekViewAssignment(t *testing.T) {
+	// Set up all test cases, mapping the link to the expected assignment.
+	cases := map[string]models.WeekViewAssignment{
+		`<a href="#">Homework 1 <span>100</span></a>`: {Class: testWeekView_Algebra, Name: "Homework 1", Grade: "100"},
+		`<a href="#"> Homework 2 </a>`:                {Class: testWeekView_Algebra, Name: "Homework 2"},
+	}
+
+	for link, expected := range cases {
+		doc, err := goquery.NewDocumentFromReader(strings.NewReader(link))
+		if err != nil {
+			t.Fatalf("Failed to parse test link: %v", err)
+		}
+
+		if diff := cmp.Diff(expected, parseWeekViewAssignment(doc.Find("a"), testWeekView_Algebra)); diff != "" {
+			t.Fatalf("Failed for parseWeekViewAssignment() with link %s (-want, +got):\n%s", link, diff)
+		}
+	}
+}
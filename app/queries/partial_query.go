@@ -0,0 +1,23 @@
+package queries
+
+import (
+	"github.com/Threqt1/HACApi/app/models"
+	"github.com/Threqt1/HACApi/pkg/repository"
+	"github.com/Threqt1/HACApi/pkg/utils"
+)
+
+// itemErrors converts the pieces a partial pipeline failed to gather into item errors,
+// naming each by its position in data. Messages are the same ones a failed query responds with.
+func itemErrors[V any](pipelineErrors []utils.PipelineError, data []V, name func(V) string) []models.ItemError {
+	if len(pipelineErrors) == 0 {
+		return nil
+	}
+
+	errs := make([]models.ItemError, 0, len(pipelineErrors))
+	for _, pipelineErr := range pipelineErrors {
+		_, queryErr := utils.QueryErrorResponse(pipelineErr.Err)
+		errs = append(errs, models.ItemError{Item: name(data[pipelineErr.Index]), Message: queryErr.Error(), Code: repository.ErrorCodeFor(queryErr)})
+	}
+
+	return errs
+}
@@ -1,44 +1,60 @@
 package queries
 
 import (
+	"context"
 	"github.com/Threqt1/HACApi/app/models"
 	"github.com/Threqt1/HACApi/pkg/repository"
 	"github.com/gocolly/colly"
 )
 
 type Querier struct {
-	Scraper repository.ScraperProvider
-	Parser  repository.ParserProvider
+	Scraper  repository.ScraperProvider
+	Parser   repository.ParserProvider
+	Profiles repository.ProfileProvider
 }
 
-func (queries Querier) GetClasswork(collector *colly.Collector, params models.ClassworkRequestBody) ([]models.Classwork, error) {
-	return getClasswork(queries.Scraper, queries.Parser, collector, params)
+func (queries Querier) GetClasswork(ctx context.Context, collector *colly.Collector, params models.ClassworkRequestBody) ([]models.Classwork, []models.ItemError, error) {
+	return getClasswork(ctx, queries.Scraper, queries.Parser, queries.Profiles.Profile(params.Base), collector, params, nil)
 }
 
-func (queries Querier) GetIPRAll(collector *colly.Collector, params models.IprAllRequestBody) ([]models.IPR, error) {
-	return getIPRAll(queries.Scraper, queries.Parser, collector, params)
+func (queries Querier) StreamClasswork(ctx context.Context, collector *colly.Collector, params models.ClassworkRequestBody, emit func(int, models.Classwork)) ([]models.ItemError, error) {
+	_, errs, err := getClasswork(ctx, queries.Scraper, queries.Parser, queries.Profiles.Profile(params.Base), collector, params, emit)
+	return errs, err
 }
 
-func (queries Querier) GetIPR(collector *colly.Collector, params models.IprRequestBody) ([]models.IPR, error) {
-	return getIPR(queries.Scraper, queries.Parser, collector, params)
+func (queries Querier) GetIPRAll(ctx context.Context, collector *colly.Collector, params models.IprAllRequestBody) ([]models.IPR, []models.ItemError, error) {
+	return getIPRAll(ctx, queries.Scraper, queries.Parser, queries.Profiles.Profile(params.Base), collector, params, nil)
 }
 
-func (queries Querier) GetLogin(collector *colly.Collector, params models.LoginRequestBody) ([]models.Login, error) {
-	return getLogin(queries.Scraper, queries.Parser, collector, params)
+func (queries Querier) StreamIPRAll(ctx context.Context, collector *colly.Collector, params models.IprAllRequestBody, emit func(int, models.IPR)) ([]models.ItemError, error) {
+	_, errs, err := getIPRAll(ctx, queries.Scraper, queries.Parser, queries.Profiles.Profile(params.Base), collector, params, emit)
+	return errs, err
 }
 
-func (queries Querier) GetReportCard(collector *colly.Collector, params models.ReportCardRequestBody) ([]models.ReportCard, error) {
-	return getReportCard(queries.Scraper, queries.Parser, collector, params)
+func (queries Querier) GetIPR(ctx context.Context, collector *colly.Collector, params models.IprRequestBody) ([]models.IPR, []models.ItemError, error) {
+	return getIPR(ctx, queries.Scraper, queries.Parser, queries.Profiles.Profile(params.Base), collector, params)
 }
 
-func (queries Querier) GetSchedule(collector *colly.Collector, params models.ScheduleRequestBody) ([]models.Schedule, error) {
-	return getSchedule(queries.Scraper, queries.Parser, collector, params)
+func (queries Querier) GetLogin(ctx context.Context, collector *colly.Collector, params models.LoginRequestBody) ([]models.Login, error) {
+	return getLogin(ctx, queries.Scraper, queries.Parser, queries.Profiles.Profile(params.Base), collector, params)
 }
 
-func (queries Querier) GetTranscript(collector *colly.Collector, params models.TranscriptRequestBody) ([]models.Transcript, error) {
-	return getTranscript(queries.Scraper, queries.Parser, collector, params)
+func (queries Querier) GetReportCard(ctx context.Context, collector *colly.Collector, params models.ReportCardRequestBody) ([]models.ReportCard, error) {
+	return getReportCard(ctx, queries.Scraper, queries.Parser, queries.Profiles.Profile(params.Base), collector, params)
 }
 
-func NewQuerier(scraper repository.ScraperProvider, parser repository.ParserProvider) Querier {
-	return Querier{Scraper: scraper, Parser: parser}
+func (queries Querier) GetSchedule(ctx context.Context, collector *colly.Collector, params models.ScheduleRequestBody) ([]models.Schedule, error) {
+	return getSchedule(ctx, queries.Scraper, queries.Parser, queries.Profiles.Profile(params.Base), collector, params)
+}
+
+func (queries Querier) GetTranscript(ctx context.Context, collector *colly.Collector, params models.TranscriptRequestBody) ([]models.Transcript, error) {
+	return getTranscript(ctx, queries.Scraper, queries.Parser, queries.Profiles.Profile(params.Base), collector, params)
+}
+
+func (queries Querier) GetWeekView(ctx context.Context, collector *colly.Collector, params models.WeekViewRequestBody) ([]models.WeekView, error) {
+	return getWeekView(ctx, queries.Scraper, queries.Parser, queries.Profiles.Profile(params.Base), collector, params)
+}
+
+func NewQuerier(scraper repository.ScraperProvider, parser repository.ParserProvider, profiles repository.ProfileProvider) Querier {
+	return Querier{Scraper: scraper, Parser: parser, Profiles: profiles}
 }
@@ -1,18 +1,19 @@
 package queries
 
 import (
+	"context"
 	"github.com/Threqt1/HACApi/app/models"
 	"github.com/Threqt1/HACApi/pkg/repository"
 	"github.com/gocolly/colly"
 )
 
 // getReportCard returns the parsed report card for the user.
-func getReportCard(scraper repository.ScraperProvider, parser repository.ParserProvider, collector *colly.Collector, params models.ReportCardRequestBody) ([]models.ReportCard, error) {
+func getReportCard(ctx context.Context, scraper repository.ScraperProvider, parser repository.ParserProvider, profile repository.DistrictProfile, collector *colly.Collector, params models.ReportCardRequestBody) ([]models.ReportCard, error) {
 	// Create empty report card model
 	var reportCard []models.ReportCard
 
 	// Get initial page
-	_, html, err := scraper.Navigate(collector, params.Base, repository.REPORT_CARD_ROUTE)
+	_, html, err := scraper.Navigate(ctx, collector, params.Base, profile.Routes.ReportCard)
 
 	// Check for initial success
 	if err != nil {
@@ -20,7 +21,11 @@ func getReportCard(scraper repository.ScraperProvider, parser repository.ParserP
 	}
 
 	// Parse report card HTML
-	reportCard = append(reportCard, parser.ParseReportCard(html))
+	parsedReportCard := parser.ParseReportCard(html)
+	if params.Normalize {
+		parsedReportCard = parser.NormalizeReportCard(parsedReportCard)
+	}
+	reportCard = append(reportCard, parsedReportCard)
 
 	return reportCard, nil
 }
@@ -1,18 +1,19 @@
 package queries
 
 import (
+	"context"
 	"github.com/Threqt1/HACApi/app/models"
 	"github.com/Threqt1/HACApi/pkg/repository"
 	"github.com/gocolly/colly"
 )
 
 // getSchedule returns the parsed schedule for the user.
-func getSchedule(scraper repository.ScraperProvider, parser repository.ParserProvider, collector *colly.Collector, params models.ScheduleRequestBody) ([]models.Schedule, error) {
+func getSchedule(ctx context.Context, scraper repository.ScraperProvider, parser repository.ParserProvider, profile repository.DistrictProfile, collector *colly.Collector, params models.ScheduleRequestBody) ([]models.Schedule, error) {
 	// Create empty schedule
 	var schedule []models.Schedule
 
 	// Get initial page
-	_, html, err := scraper.Navigate(collector, params.Base, repository.SCHEDULE_ROUTE)
+	_, html, err := scraper.Navigate(ctx, collector, params.Base, profile.Routes.Schedule)
 
 	// Check for initial success
 	if err != nil {
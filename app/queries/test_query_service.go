@@ -1,10 +1,13 @@
 package queries
 
 import (
+	"context"
 	"errors"
 	"math"
+	"strconv"
 
 	"github.com/Threqt1/HACApi/app/models"
+	"github.com/Threqt1/HACApi/pkg/repository"
 	"github.com/gocolly/colly"
 )
 
@@ -15,38 +18,72 @@ type TestQuerier struct {
 }
 
 // Send back a slice of the same length as params.MarkingPeriods, or one if the length is 0.
-func (queries TestQuerier) GetClasswork(collector *colly.Collector, params models.ClassworkRequestBody) ([]models.Classwork, error) {
+// If partial results were requested, the last marking period fails instead.
+func (queries TestQuerier) GetClasswork(ctx context.Context, collector *colly.Collector, params models.ClassworkRequestBody) ([]models.Classwork, []models.ItemError, error) {
 	// Get length in the interval [1, 6].
 	length := int(math.Max(1, float64(len(params.MarkingPeriods))))
+
+	// Fail the last marking period.
+	if params.Partial && len(params.MarkingPeriods) > 0 {
+		failed := models.ItemError{
+			Item:    strconv.Itoa(params.MarkingPeriods[length-1]),
+			Message: repository.ErrorServerUnreachable.Error(),
+			Code:    models.ErrorCodeHACUnavailable,
+		}
+		return make([]models.Classwork, length-1), []models.ItemError{failed}, nil
+	}
+
 	// Make the slice and return.
-	return make([]models.Classwork, length), nil
+	return make([]models.Classwork, length), nil, nil
+}
+
+func (queries TestQuerier) GetIPRAll(ctx context.Context, collector *colly.Collector, params models.IprAllRequestBody) ([]models.IPR, []models.ItemError, error) {
+	return []models.IPR{{}}, nil, nil
 }
 
-func (queries TestQuerier) GetIPRAll(collector *colly.Collector, params models.IprAllRequestBody) ([]models.IPR, error) {
-	return []models.IPR{{}}, nil
+// Emit the classwork GetClasswork would send back, one at a time.
+func (queries TestQuerier) StreamClasswork(ctx context.Context, collector *colly.Collector, params models.ClassworkRequestBody, emit func(int, models.Classwork)) ([]models.ItemError, error) {
+	classwork, errs, err := queries.GetClasswork(ctx, collector, params)
+	for i, piece := range classwork {
+		emit(i, piece)
+	}
+	return errs, err
 }
 
-func (queries TestQuerier) GetIPR(collector *colly.Collector, params models.IprRequestBody) ([]models.IPR, error) {
-	return []models.IPR{{}}, nil
+// Emit the IPRs GetIPRAll would send back, one at a time.
+func (queries TestQuerier) StreamIPRAll(ctx context.Context, collector *colly.Collector, params models.IprAllRequestBody, emit func(int, models.IPR)) ([]models.ItemError, error) {
+	iprs, errs, err := queries.GetIPRAll(ctx, collector, params)
+	for i, ipr := range iprs {
+		emit(i, ipr)
+	}
+	return errs, err
 }
 
-// Send back the credentials recieved.
-func (queries TestQuerier) GetLogin(collector *colly.Collector, params models.LoginRequestBody) ([]models.Login, error) {
-	return []models.Login{models.Login(params.BaseRequestBody)}, nil
+func (queries TestQuerier) GetIPR(ctx context.Context, collector *colly.Collector, params models.IprRequestBody) ([]models.IPR, []models.ItemError, error) {
+	return []models.IPR{{}}, nil, nil
 }
 
-func (queries TestQuerier) GetReportCard(collector *colly.Collector, params models.ReportCardRequestBody) ([]models.ReportCard, error) {
+// Send back the username and base recieved.
+func (queries TestQuerier) GetLogin(ctx context.Context, collector *colly.Collector, params models.LoginRequestBody) ([]models.Login, error) {
+	return []models.Login{{Username: params.Username, Base: params.Base}}, nil
+}
+
+func (queries TestQuerier) GetReportCard(ctx context.Context, collector *colly.Collector, params models.ReportCardRequestBody) ([]models.ReportCard, error) {
 	return []models.ReportCard{{}}, nil
 }
 
-func (queries TestQuerier) GetSchedule(collector *colly.Collector, params models.ScheduleRequestBody) ([]models.Schedule, error) {
+func (queries TestQuerier) GetSchedule(ctx context.Context, collector *colly.Collector, params models.ScheduleRequestBody) ([]models.Schedule, error) {
 	return []models.Schedule{{}}, nil
 }
 
-func (queries TestQuerier) GetTranscript(collector *colly.Collector, params models.TranscriptRequestBody) ([]models.Transcript, error) {
+func (queries TestQuerier) GetTranscript(ctx context.Context, collector *colly.Collector, params models.TranscriptRequestBody) ([]models.Transcript, error) {
 	return []models.Transcript{{}}, nil
 }
 
+func (queries TestQuerier) GetWeekView(ctx context.Context, collector *colly.Collector, params models.WeekViewRequestBody) ([]models.WeekView, error) {
+	return []models.WeekView{{}}, nil
+}
+
 // NewTestQuerier makes a new test querier.
 func NewTestQuerier() TestQuerier {
 	return TestQuerier{}
@@ -61,31 +98,43 @@ var ErrorBadQuery = errors.New("bad query")
 type TestErrorQuerier struct {
 }
 
-func (queries TestErrorQuerier) GetClasswork(collector *colly.Collector, params models.ClassworkRequestBody) ([]models.Classwork, error) {
+func (queries TestErrorQuerier) GetClasswork(ctx context.Context, collector *colly.Collector, params models.ClassworkRequestBody) ([]models.Classwork, []models.ItemError, error) {
+	return nil, nil, ErrorBadQuery
+}
+
+func (queries TestErrorQuerier) GetIPRAll(ctx context.Context, collector *colly.Collector, params models.IprAllRequestBody) ([]models.IPR, []models.ItemError, error) {
+	return nil, nil, ErrorBadQuery
+}
+
+func (queries TestErrorQuerier) StreamClasswork(ctx context.Context, collector *colly.Collector, params models.ClassworkRequestBody, emit func(int, models.Classwork)) ([]models.ItemError, error) {
 	return nil, ErrorBadQuery
 }
 
-func (queries TestErrorQuerier) GetIPRAll(collector *colly.Collector, params models.IprAllRequestBody) ([]models.IPR, error) {
+func (queries TestErrorQuerier) StreamIPRAll(ctx context.Context, collector *colly.Collector, params models.IprAllRequestBody, emit func(int, models.IPR)) ([]models.ItemError, error) {
 	return nil, ErrorBadQuery
 }
 
-func (queries TestErrorQuerier) GetIPR(collector *colly.Collector, params models.IprRequestBody) ([]models.IPR, error) {
+func (queries TestErrorQuerier) GetIPR(ctx context.Context, collector *colly.Collector, params models.IprRequestBody) ([]models.IPR, []models.ItemError, error) {
+	return nil, nil, ErrorBadQuery
+}
+
+func (queries TestErrorQuerier) GetLogin(ctx context.Context, collector *colly.Collector, params models.LoginRequestBody) ([]models.Login, error) {
 	return nil, ErrorBadQuery
 }
 
-func (queries TestErrorQuerier) GetLogin(collector *colly.Collector, params models.LoginRequestBody) ([]models.Login, error) {
+func (queries TestErrorQuerier) GetReportCard(ctx context.Context, collector *colly.Collector, params models.ReportCardRequestBody) ([]models.ReportCard, error) {
 	return nil, ErrorBadQuery
 }
 
-func (queries TestErrorQuerier) GetReportCard(collector *colly.Collector, params models.ReportCardRequestBody) ([]models.ReportCard, error) {
+func (queries TestErrorQuerier) GetSchedule(ctx context.Context, collector *colly.Collector, params models.ScheduleRequestBody) ([]models.Schedule, error) {
 	return nil, ErrorBadQuery
 }
 
-func (queries TestErrorQuerier) GetSchedule(collector *colly.Collector, params models.ScheduleRequestBody) ([]models.Schedule, error) {
+func (queries TestErrorQuerier) GetTranscript(ctx context.Context, collector *colly.Collector, params models.TranscriptRequestBody) ([]models.Transcript, error) {
 	return nil, ErrorBadQuery
 }
 
-func (queries TestErrorQuerier) GetTranscript(collector *colly.Collector, params models.TranscriptRequestBody) ([]models.Transcript, error) {
+func (queries TestErrorQuerier) GetWeekView(ctx context.Context, collector *colly.Collector, params models.WeekViewRequestBody) ([]models.WeekView, error) {
 	return nil, ErrorBadQuery
 }
 
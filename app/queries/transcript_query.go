@@ -1,18 +1,19 @@
 package queries
 
 import (
+	"context"
 	"github.com/Threqt1/HACApi/app/models"
 	"github.com/Threqt1/HACApi/pkg/repository"
 	"github.com/gocolly/colly"
 )
 
 // getTranscript returns the parsed transcript for the user.
-func getTranscript(scraper repository.ScraperProvider, parser repository.ParserProvider, collector *colly.Collector, params models.TranscriptRequestBody) ([]models.Transcript, error) {
+func getTranscript(ctx context.Context, scraper repository.ScraperProvider, parser repository.ParserProvider, profile repository.DistrictProfile, collector *colly.Collector, params models.TranscriptRequestBody) ([]models.Transcript, error) {
 	// Create empty transcript
 	var transcript []models.Transcript
 
 	// Get initial page
-	_, html, err := scraper.Navigate(collector, params.Base, repository.TRANSCRIPT_ROUTE)
+	_, html, err := scraper.Navigate(ctx, collector, params.Base, profile.Routes.Transcript)
 
 	// Check for initial success
 	if err != nil {
@@ -20,7 +21,11 @@ func getTranscript(scraper repository.ScraperProvider, parser repository.ParserP
 	}
 
 	// Parse transcript HTML
-	transcript = append(transcript, parser.ParseTranscript(html))
+	parsedTranscript := parser.ParseTranscript(html)
+	if params.Normalize {
+		parsedTranscript = parser.NormalizeTranscript(parsedTranscript)
+	}
+	transcript = append(transcript, parsedTranscript)
 
 	return transcript, nil
 }
@@ -0,0 +1,36 @@
+package queries
+
+import (
+	"context"
+	"net/url"
+
+	"github.com/Threqt1/HACApi/app/models"
+	"github.com/Threqt1/HACApi/pkg/repository"
+	"github.com/gocolly/colly"
+)
+
+// getWeekView returns the parsed week view for the user, for the week containing
+// the date specified or the current week.
+func getWeekView(ctx context.Context, scraper repository.ScraperProvider, parser repository.ParserProvider, profile repository.DistrictProfile, collector *colly.Collector, params models.WeekViewRequestBody) ([]models.WeekView, error) {
+	// Create empty week view
+	var weekView []models.WeekView
+
+	// Request a specific week if a date was passed
+	endpoint := profile.Routes.WeekView
+	if len(params.Date) > 0 {
+		endpoint += "?startDate=" + url.QueryEscape(params.Date)
+	}
+
+	// Get initial page
+	_, html, err := scraper.Navigate(ctx, collector, params.Base, endpoint)
+
+	// Check for initial success
+	if err != nil {
+		return weekView, err
+	}
+
+	// Parse week view HTML
+	weekView = append(weekView, parser.ParseWeekView(html))
+
+	return weekView, nil
+}
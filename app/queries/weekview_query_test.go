@@ -0,0 +1,57 @@
+package queries
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Threqt1/HACApi/app/models"
+	"github.com/Threqt1/HACApi/app/queries/parsers"
+	"github.com/Threqt1/HACApi/pkg/repository"
+	"github.com/Threqt1/HACApi/pkg/utils"
+	"github.com/google/go-cmp/cmp"
+)
+
+// testWeekView_Dates returns the dates of the days of a week view.
+func testWeekView_Dates(weekView []models.WeekView) []string {
+	dates := []string{}
+	for _, view := range weekView {
+		for _, day := range view.Days {
+			dates = append(dates, day.Date)
+		}
+	}
+	return dates
+}
+
+// Test if getWeekView() returns the current week, or the week containing the date passed.
+func TestGetWeekView(t *testing.T) {
+	// Create testing server and scraper.
+	ts := utils.CreateTestingServer()
+	defer ts.Close()
+
+	scraper := utils.NewScraper(nil, nil)
+	collector, err := scraper.Restore(ts.URL, nil)
+	if err != nil {
+		t.Fatalf("Failed for Restore(): %v", err)
+	}
+
+	// Set up all test cases, mapping the date passed to the expected days.
+	cases := map[string][]string{
+		"":           {"10/03/2022", "10/04/2022", "10/05/2022", "10/06/2022", "10/07/2022"},
+		"10/12/2022": {"10/10/2022", "10/11/2022", "10/12/2022", "10/13/2022", "10/14/2022"},
+	}
+
+	for date, expected := range cases {
+		// Test.
+		weekView, err := getWeekView(context.Background(), scraper, parsers.NewParser(), repository.DefaultDistrictProfile(), collector, models.WeekViewRequestBody{
+			BaseRequestBody: models.BaseRequestBody{Base: ts.URL},
+			Date:            date,
+		})
+		if err != nil {
+			t.Fatalf("Failed for getWeekView() with date %q: %v", date, err)
+		}
+
+		if diff := cmp.Diff(expected, testWeekView_Dates(weekView)); diff != "" {
+			t.Fatalf("Failed for getWeekView() with date %q (-want, +got):\n%s", date, diff)
+		}
+	}
+}
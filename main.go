@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"log"
 	"os"
 	"os/signal"
@@ -38,6 +39,18 @@ import (
 //
 //	@tag.name			transcript
 //	@tag.description	Get data about the transcript
+//
+//	@tag.name			gpa
+//	@tag.description	Calculate the current, projected and what-if GPA
+//
+//	@tag.name			weekview
+//	@tag.description	Get data about the assignments due in a week
+//
+//	@tag.name			calendar
+//	@tag.description	Export assignments and the schedule as iCalendar files
+//
+//	@tag.name			subscriptions
+//	@tag.description	Get notified of grade changes through webhooks
 
 func main() {
 	// Register .env
@@ -47,16 +60,29 @@ func main() {
 	}
 
 	// Make new server
-	server := configs.ServerConfig()
+	server, err := configs.ServerConfig()
+	if err != nil {
+		log.Fatalf("Server failed to configure. Reason: %v", err)
+	}
 
 	// Register middleware(s)
 	middleware.FiberMiddleware(server)
 
 	// Register routes
 	routes.SwaggerRoute(server)
+	routes.MetricsRoute(server)
+	routes.HealthRoutes(server)
 	routes.PublicRoutes(server)
 	routes.NotFoundRoute(server)
 
+	// Start checking subscriptions for changes
+	subscriptionsCtx, stopSubscriptions := context.WithCancel(context.Background())
+	go server.Subscriptions.Run(subscriptionsCtx)
+
+	// Start deleting expired cache entries
+	cacheCtx, stopCache := context.WithCancel(context.Background())
+	go server.Cache.Run(cacheCtx)
+
 	// Start server
 
 	// Create channel to confirm when connections are closed
@@ -69,9 +95,11 @@ func main() {
 		<-sigint
 
 		// Gracefully shutdown
+		stopSubscriptions()
 		if err := server.App.Shutdown(); err != nil {
 			log.Fatalf("Server failed to shutdown. Reason: %v", err)
 		}
+		stopCache()
 
 		close(connsClosedChan)
 	}()
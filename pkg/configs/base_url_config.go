@@ -0,0 +1,44 @@
+package configs
+
+import (
+	"os"
+	"strings"
+
+	"github.com/Threqt1/HACApi/pkg/utils"
+)
+
+// BaseURLConfig returns the policy for the base URLs users
+// can send, configured by the BASE_ALLOWED_HOSTS,
+// BASE_ALLOW_INSECURE and BASE_ALLOW_PRIVATE environment variables.
+func BaseURLConfig() *utils.BaseURLPolicy {
+	var allowedHosts []string
+	for _, host := range strings.Split(os.Getenv("BASE_ALLOWED_HOSTS"), ",") {
+		if host = strings.TrimSpace(host); host != "" {
+			allowedHosts = append(allowedHosts, host)
+		}
+	}
+
+	return utils.NewBaseURLPolicy(
+		allowedHosts,
+		os.Getenv("BASE_ALLOW_INSECURE") == "true",
+		os.Getenv("BASE_ALLOW_PRIVATE") == "true",
+	)
+}
+
+// WebhookURLConfig returns the policy for the webhook URLs users
+// can subscribe with, configured by the WEBHOOK_ALLOWED_HOSTS,
+// WEBHOOK_ALLOW_INSECURE and WEBHOOK_ALLOW_PRIVATE environment variables.
+func WebhookURLConfig() *utils.BaseURLPolicy {
+	var allowedHosts []string
+	for _, host := range strings.Split(os.Getenv("WEBHOOK_ALLOWED_HOSTS"), ",") {
+		if host = strings.TrimSpace(host); host != "" {
+			allowedHosts = append(allowedHosts, host)
+		}
+	}
+
+	return utils.NewBaseURLPolicy(
+		allowedHosts,
+		os.Getenv("WEBHOOK_ALLOW_INSECURE") == "true",
+		os.Getenv("WEBHOOK_ALLOW_PRIVATE") == "true",
+	)
+}
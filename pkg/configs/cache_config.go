@@ -0,0 +1,35 @@
+package configs
+
+import (
+	"errors"
+	"os"
+
+	"github.com/Threqt1/HACApi/pkg/utils"
+	"github.com/Threqt1/HACApi/platform/cache"
+)
+
+// ErrorCacheBackend is the error thrown when an unsupported cache backend is configured.
+var ErrorCacheBackend = errors.New("cache backend not supported")
+
+// CacheBackendConfig returns the cache backend selected
+// by the CACHE_BACKEND environment variable.
+func CacheBackendConfig() (cache.Backend, error) {
+	switch os.Getenv("CACHE_BACKEND") {
+	case "", "memory":
+		return cache.NewMemoryBackend(), nil
+	case "file":
+		dir := os.Getenv("CACHE_FILE_DIR")
+		if dir == "" {
+			dir = "cache"
+		}
+		return cache.NewFileBackend(dir)
+	case "redis":
+		redisConnURL, err := utils.BuildConnectionURL("redis")
+		if err != nil {
+			return nil, err
+		}
+		return cache.NewRedisBackend(redisConnURL, os.Getenv("REDIS_PASSWORD")), nil
+	default:
+		return nil, ErrorCacheBackend
+	}
+}
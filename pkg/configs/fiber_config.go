@@ -1,6 +1,7 @@
 package configs
 
 import (
+	"os"
 	"time"
 
 	"github.com/bytedance/sonic"
@@ -16,3 +17,14 @@ func FiberConfig() fiber.Config {
 		ReadTimeout: 30 * time.Second,
 	}
 }
+
+// RequestTimeoutConfig returns how long a request can spend
+// querying HAC, from the REQUEST_TIMEOUT environment variable
+// (Ex: 20s). It defaults to 30 seconds.
+func RequestTimeoutConfig() time.Duration {
+	timeout, err := time.ParseDuration(os.Getenv("REQUEST_TIMEOUT"))
+	if err != nil || timeout <= 0 {
+		return 30 * time.Second
+	}
+	return timeout
+}
@@ -0,0 +1,34 @@
+package configs
+
+import (
+	"os"
+	"strings"
+	"time"
+
+	"github.com/Threqt1/HACApi/pkg/repository"
+	"github.com/Threqt1/HACApi/platform/health"
+	"github.com/Threqt1/HACApi/platform/profiles"
+)
+
+// HealthConfig returns the checker probing the districts in the
+// HEALTH_DISTRICTS environment variable, or the districts with a
+// profile if it is not set. Probe results are reused for the
+// HEALTH_CACHE_TTL environment variable (Ex: 1m), 30 seconds by default.
+func HealthConfig(scraper repository.ScraperProvider, profileService *profiles.Profiles) *health.Checker {
+	var bases []string
+	for _, base := range strings.Split(os.Getenv("HEALTH_DISTRICTS"), ",") {
+		if base = strings.TrimRight(strings.TrimSpace(base), "/"); base != "" {
+			bases = append(bases, base)
+		}
+	}
+	if len(bases) == 0 {
+		bases = profileService.Bases()
+	}
+
+	ttl, err := time.ParseDuration(os.Getenv("HEALTH_CACHE_TTL"))
+	if err != nil || ttl <= 0 {
+		ttl = 30 * time.Second
+	}
+
+	return health.NewChecker(scraper, bases, ttl)
+}
@@ -0,0 +1,19 @@
+package configs
+
+import (
+	"os"
+
+	"github.com/Threqt1/HACApi/platform/logging"
+)
+
+// LoggingConfig configures the logger from the LOG_LEVEL environment
+// variable (Ex: debug), info by default, and the key user identifiers
+// are hashed with from the LOG_HASH_KEY environment variable. Without
+// a key, identifiers change whenever the API restarts.
+func LoggingConfig() {
+	logging.Default.SetLevel(logging.ParseLevel(os.Getenv("LOG_LEVEL")))
+
+	if key := os.Getenv("LOG_HASH_KEY"); key != "" {
+		logging.SetHashKey([]byte(key))
+	}
+}
@@ -0,0 +1,18 @@
+package configs
+
+import (
+	"os"
+
+	"github.com/Threqt1/HACApi/platform/profiles"
+)
+
+// ProfileConfig returns the district profiles stored in the
+// file at the DISTRICT_PROFILES environment variable, or
+// only the default profile if it is not set.
+func ProfileConfig() (*profiles.Profiles, error) {
+	path := os.Getenv("DISTRICT_PROFILES")
+	if path == "" {
+		return profiles.NewProfiles(), nil
+	}
+	return profiles.LoadProfiles(path)
+}
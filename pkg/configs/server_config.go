@@ -6,24 +6,44 @@ import (
 	"github.com/Threqt1/HACApi/pkg/repository"
 	"github.com/Threqt1/HACApi/pkg/utils"
 	"github.com/Threqt1/HACApi/platform/cache"
-	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
 )
 
-func ServerConfig() *repository.Server {
-	scraperService := utils.NewScraper()
-	cacheService := cache.NewCache(scraperService)
+func ServerConfig() (*repository.Server, error) {
+	LoggingConfig()
+
+	backendService, err := CacheBackendConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	profileService, err := ProfileConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	baseURLService := BaseURLConfig()
+	scraperService := utils.NewScraper(profileService, baseURLService.Transport())
+	cacheService := cache.NewCache(scraperService, backendService)
 	parserService := parsers.NewParser()
-	queryService := queries.NewQuerier(scraperService, parserService)
+	queryService := queries.NewQuerier(scraperService, parserService, profileService)
 	appService := fiber.New(FiberConfig())
-	validatorService := validator.New()
+	validatorService := utils.NewValidator()
+	healthService := HealthConfig(scraperService, profileService)
+	webhookURLService := WebhookURLConfig()
+	subscriptionService := SubscriptionConfig(cacheService, queryService, webhookURLService)
 
 	return &repository.Server{
-		Scraper:   scraperService,
-		Cache:     cacheService,
-		App:       appService,
-		Validator: validatorService,
-		Querier:   queryService,
-		Parser:    parserService,
-	}
+		Scraper:       scraperService,
+		Cache:         cacheService,
+		App:           appService,
+		Validator:     validatorService,
+		Querier:       queryService,
+		Parser:        parserService,
+		Profiles:      profileService,
+		BaseURLs:      baseURLService,
+		Health:        healthService,
+		Subscriptions: subscriptionService,
+		WebhookURLs:   webhookURLService,
+	}, nil
 }
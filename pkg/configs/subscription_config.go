@@ -0,0 +1,26 @@
+package configs
+
+import (
+	"os"
+	"strconv"
+
+	"github.com/Threqt1/HACApi/pkg/repository"
+	"github.com/Threqt1/HACApi/platform/subscriptions"
+)
+
+// SubscriptionConfig returns the subscription service, posting to webhooks
+// allowed by webhookURLs. The amount of subscriptions is limited by the
+// SUBSCRIPTION_LIMIT environment variable overall, 1000 by default, and by
+// SUBSCRIPTION_ACCOUNT_LIMIT per account, 5 by default.
+func SubscriptionConfig(cache repository.CacheProvider, querier repository.QuerierProvider, webhookURLs repository.BaseURLProvider) *subscriptions.Service {
+	service := subscriptions.NewService(cache, querier, webhookURLs)
+
+	if limit, err := strconv.Atoi(os.Getenv("SUBSCRIPTION_LIMIT")); err == nil && limit >= 0 {
+		service.MaxSubscriptions = limit
+	}
+	if limit, err := strconv.Atoi(os.Getenv("SUBSCRIPTION_ACCOUNT_LIMIT")); err == nil && limit >= 0 {
+		service.MaxAccountSubscriptions = limit
+	}
+
+	return service
+}
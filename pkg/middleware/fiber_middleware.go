@@ -1,9 +1,11 @@
 package middleware
 
 import (
+	"github.com/Threqt1/HACApi/pkg/configs"
 	"github.com/Threqt1/HACApi/pkg/repository"
+	"github.com/Threqt1/HACApi/pkg/utils"
 	"github.com/gofiber/fiber/v2/middleware/cors"
-	"github.com/gofiber/fiber/v2/middleware/logger"
+	"github.com/gofiber/fiber/v2/middleware/requestid"
 )
 
 // FiberMiddleware sets up fiber's middleware for
@@ -13,7 +15,16 @@ func FiberMiddleware(server *repository.Server) {
 		// Enable CORS
 		cors.New(),
 
-		// Add a logger
-		logger.New(),
+		// Tag every request with an ID, sent back in the X-Request-ID header and in errors
+		requestid.New(requestid.Config{ContextKey: utils.RequestIDKey}),
+
+		// Count requests and time them per route
+		RequestMetrics(),
+
+		// Log requests as JSON, without credentials
+		RequestLogger(),
+
+		// Add a deadline for querying HAC
+		RequestTimeout(configs.RequestTimeoutConfig()),
 	)
 }
@@ -0,0 +1,70 @@
+package middleware
+
+import (
+	"errors"
+	"time"
+
+	"github.com/Threqt1/HACApi/pkg/utils"
+	"github.com/Threqt1/HACApi/platform/logging"
+	"github.com/gofiber/fiber/v2"
+	fiberutils "github.com/gofiber/fiber/v2/utils"
+)
+
+// RequestLogger logs every request as a JSON line, with its request ID, route,
+// status and latency. Controllers add the district and a hash of the user with
+// utils.SetLogIdentity. The request ID is also attached to the user context,
+// so the HAC requests made for a request are logged with it.
+func RequestLogger() fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		start := time.Now()
+
+		ctx.SetUserContext(logging.WithFields(ctx.UserContext(), logging.Fields{
+			"requestId": utils.RequestID(ctx),
+		}))
+
+		err := ctx.Next()
+
+		// Errors returned by handlers only get their status from the error handler later.
+		status := ctx.Response().StatusCode()
+		if err != nil {
+			status = fiber.StatusInternalServerError
+
+			var fiberErr *fiber.Error
+			if errors.As(err, &fiberErr) {
+				status = fiberErr.Code
+			}
+		}
+
+		// The request's context is reused before a stream ends, so copy what's logged.
+		userCtx := ctx.UserContext()
+		fields := logging.Fields{
+			"method": fiberutils.CopyString(ctx.Method()),
+			"route":  ctx.Route().Path,
+			"path":   fiberutils.CopyString(ctx.Path()),
+			"status": status,
+		}
+		if err != nil {
+			fields["error"] = err
+		}
+
+		level := logging.LevelInfo
+		switch {
+		case status >= fiber.StatusInternalServerError:
+			level = logging.LevelError
+		case status >= fiber.StatusBadRequest:
+			level = logging.LevelWarn
+		}
+
+		record := func() {
+			fields["latencyMs"] = time.Since(start).Milliseconds()
+			logging.Log(userCtx, level, "request", fields)
+		}
+
+		// Streamed responses are only done once the stream ends.
+		if !utils.OnStreamEnd(ctx, record) {
+			record()
+		}
+
+		return err
+	}
+}
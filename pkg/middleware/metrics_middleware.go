@@ -0,0 +1,48 @@
+package middleware
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/Threqt1/HACApi/pkg/utils"
+	"github.com/Threqt1/HACApi/platform/metrics"
+	"github.com/gofiber/fiber/v2"
+	fiberutils "github.com/gofiber/fiber/v2/utils"
+)
+
+// RequestMetrics records the amount of requests and how long they
+// took for every route. Routes are labelled by their pattern, so
+// unknown paths share the label of the route which caught them.
+func RequestMetrics() fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		start := time.Now()
+
+		err := ctx.Next()
+
+		// Errors returned by handlers only get their status from the error handler later.
+		status := ctx.Response().StatusCode()
+		if err != nil {
+			status = fiber.StatusInternalServerError
+
+			var fiberErr *fiber.Error
+			if errors.As(err, &fiberErr) {
+				status = fiberErr.Code
+			}
+		}
+
+		// The request's context is reused before a stream ends, so copy what's recorded.
+		method, route := fiberutils.CopyString(ctx.Method()), ctx.Route().Path
+		record := func() {
+			metrics.Requests.Inc(method, route, strconv.Itoa(status))
+			metrics.RequestDuration.Observe(time.Since(start).Seconds(), method, route)
+		}
+
+		// Streamed responses are only done once the stream ends.
+		if !utils.OnStreamEnd(ctx, record) {
+			record()
+		}
+
+		return err
+	}
+}
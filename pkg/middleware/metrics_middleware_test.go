@@ -0,0 +1,69 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Threqt1/HACApi/app/models"
+	"github.com/Threqt1/HACApi/pkg/utils"
+	"github.com/Threqt1/HACApi/platform/metrics"
+	"github.com/gocolly/colly"
+	"github.com/gofiber/fiber/v2"
+)
+
+// How long the test stream takes to finish.
+const testMetrics_StreamDelay = 200 * time.Millisecond
+
+// Test if RequestMetrics() times streamed responses until the stream ends.
+func TestRequestMetrics_Stream(t *testing.T) {
+	app := fiber.New()
+	app.Use(RequestMetrics())
+
+	// Stream an event once the delay is up, long after the handler returned.
+	app.Get("/metrics-stream-test", func(ctx *fiber.Ctx) error {
+		utils.StreamEvents(ctx, nil, "", nil, "event", func(ctx context.Context, collector *colly.Collector, emit func(int, int)) ([]models.ItemError, error) {
+			time.Sleep(testMetrics_StreamDelay)
+			emit(0, 1)
+			return nil, nil
+		})
+		return nil
+	})
+
+	// Test.
+	resp, err := app.Test(httptest.NewRequest("GET", "/metrics-stream-test", nil), -1)
+	if err != nil {
+		t.Fatalf("Failed for RequestMetrics() with a stream:\n%v", err)
+	}
+	if _, err := io.ReadAll(resp.Body); err != nil {
+		t.Fatalf("Failed for RequestMetrics() with a stream:\n%v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := metrics.Default.Write(&buf); err != nil {
+		t.Fatalf("Failed for RequestMetrics() writing the metrics:\n%v", err)
+	}
+
+	prefix := `hacapi_http_request_duration_seconds_sum{method="GET",route="/metrics-stream-test"} `
+	for _, line := range strings.Split(buf.String(), "\n") {
+		if !strings.HasPrefix(line, prefix) {
+			continue
+		}
+
+		seconds, err := strconv.ParseFloat(strings.TrimPrefix(line, prefix), 64)
+		if err != nil {
+			t.Fatalf("Failed for RequestMetrics() parsing %q:\n%v", line, err)
+		}
+		if seconds < testMetrics_StreamDelay.Seconds() {
+			t.Fatalf("Failed for RequestMetrics(), expected the stream to take at least %v, got %vs", testMetrics_StreamDelay, seconds)
+		}
+		return
+	}
+
+	t.Fatalf("Failed for RequestMetrics(), stream not timed:\n%s", buf.String())
+}
@@ -0,0 +1,25 @@
+package middleware
+
+import (
+	"context"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// RequestTimeout bounds how long a request can spend querying HAC.
+// The deadline is carried by the request's user context, which is
+// passed down to the scraper and cancels any in-flight HAC requests.
+// Only the deadline cancels it: fasthttp doesn't tell handlers when
+// the client disconnects, so the HAC requests of a request whose
+// client left keep running until they finish or time out.
+func RequestTimeout(timeout time.Duration) fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		userCtx, cancel := context.WithTimeout(ctx.UserContext(), timeout)
+		defer cancel()
+
+		ctx.SetUserContext(userCtx)
+
+		return ctx.Next()
+	}
+}
@@ -0,0 +1,93 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Threqt1/HACApi/app/models"
+	"github.com/Threqt1/HACApi/app/queries"
+	"github.com/Threqt1/HACApi/pkg/repository"
+	"github.com/Threqt1/HACApi/pkg/utils"
+	"github.com/gocolly/colly"
+	"github.com/gofiber/fiber/v2"
+)
+
+// testTimeout_Querier is a test querier which loads a page of the testing
+// server that hangs, and reports the error it got back.
+type testTimeout_Querier struct {
+	queries.TestQuerier
+	Scraper repository.ScraperProvider
+	URL     string
+	Errors  chan error
+}
+
+func (querier testTimeout_Querier) GetSchedule(ctx context.Context, collector *colly.Collector, params models.ScheduleRequestBody) ([]models.Schedule, error) {
+	_, _, err := querier.Scraper.Navigate(ctx, collector, querier.URL, "/slow")
+	querier.Errors <- err
+	return nil, err
+}
+
+// Test if RequestTimeout() passes a deadline down to the querier.
+func TestRequestTimeout_Deadline(t *testing.T) {
+	app := fiber.New()
+	app.Use(RequestTimeout(time.Minute))
+
+	deadlines := make(chan time.Time, 1)
+	app.Get("/", func(ctx *fiber.Ctx) error {
+		deadline, ok := ctx.UserContext().Deadline()
+		if !ok {
+			return fiber.ErrInternalServerError
+		}
+		deadlines <- deadline
+		return ctx.SendStatus(fiber.StatusOK)
+	})
+
+	// Test.
+	start := time.Now()
+	resp, err := app.Test(httptest.NewRequest("GET", "/", nil))
+	if err != nil || resp.StatusCode != fiber.StatusOK {
+		t.Fatalf("Failed for RequestTimeout(), expected the user context to have a deadline (status %v, error %v)", resp.StatusCode, err)
+	}
+
+	if deadline := <-deadlines; deadline.Before(start) || deadline.After(start.Add(time.Minute+time.Second)) {
+		t.Fatalf("Failed for RequestTimeout(), expected a deadline a minute away, got %v", deadline.Sub(start))
+	}
+}
+
+// Test if RequestTimeout() cancels the in-flight HAC requests of the querier.
+func TestRequestTimeout_CancelsHACRequests(t *testing.T) {
+	// Create testing server and scraper.
+	ts := utils.CreateTestingServer()
+	defer ts.Close()
+
+	scraper := utils.NewScraper(nil, nil)
+	querier := testTimeout_Querier{Scraper: scraper, URL: ts.URL, Errors: make(chan error, 1)}
+
+	app := fiber.New()
+	app.Use(RequestTimeout(50 * time.Millisecond))
+	app.Get("/", func(ctx *fiber.Ctx) error {
+		collector, err := scraper.Restore(ts.URL, nil)
+		if err != nil {
+			return err
+		}
+		_, err = querier.GetSchedule(ctx.UserContext(), collector, models.ScheduleRequestBody{})
+		return err
+	})
+
+	// Test.
+	start := time.Now()
+	if _, err := app.Test(httptest.NewRequest("GET", "/", nil), 5000); err != nil {
+		t.Fatalf("Failed for RequestTimeout(): %v", err)
+	}
+
+	if err := <-querier.Errors; !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Failed for RequestTimeout() (-want, +got):\n- %v\n+ %v", context.DeadlineExceeded, err)
+	}
+
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("Failed for RequestTimeout(), the HAC request was not cancelled (took %v)", elapsed)
+	}
+}
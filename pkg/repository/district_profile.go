@@ -0,0 +1,68 @@
+package repository
+
+// DistrictProfile describes how a district's HAC differs
+// from the defaults, which are configured like Katy ISD.
+type DistrictProfile struct {
+	LoginFields   map[string]string `json:"loginFields"`   // The form fields posted alongside the credentials on login
+	LoginRedirect string            `json:"loginRedirect"` // The route HAC redirects to after a successful login
+	Routes        Routes            `json:"routes"`        // The routes of each HAC page
+	Selectors     Selectors         `json:"selectors"`     // The selectors of the controls on HAC pages
+
+	PipelineWorkers   int     `json:"pipelineWorkers"`   // The maximum amount of concurrent POST requests per query, unlimited if 0
+	RequestsPerSecond float64 `json:"requestsPerSecond"` // The rate of requests to the district's HAC shared by all users, unlimited if 0
+	RequestBurst      int     `json:"requestBurst"`      // The amount of requests allowed at once before the rate applies
+	MaxRetries        int     `json:"maxRetries"`        // The amount of times a request failing transiently is retried
+}
+
+// Routes represents the routes of each HAC page.
+type Routes struct {
+	Login      string `json:"login"`
+	Classwork  string `json:"classwork"`
+	Schedule   string `json:"schedule"`
+	IPR        string `json:"ipr"`
+	ReportCard string `json:"reportCard"`
+	Transcript string `json:"transcript"`
+	WeekView   string `json:"weekView"`
+}
+
+// Selectors represents the selectors of the controls on HAC pages
+// which vary between districts.
+type Selectors struct {
+	ReportCardRuns      string `json:"reportCardRuns"`      // The marking period dropdown on the classwork page
+	IPRDates            string `json:"iprDates"`            // The date dropdown on the IPR page
+	ClassworkCategories string `json:"classworkCategories"` // The category breakdown of each class on the classwork page
+}
+
+// DefaultDistrictProfile returns the profile used for districts
+// which aren't configured.
+func DefaultDistrictProfile() DistrictProfile {
+	return DistrictProfile{
+		LoginFields: map[string]string{
+			"SCKTY00328510CustomEnabled": "true",
+			"SCKTY00436568CustomEnabled": "true",
+			"Database":                   "10",
+			"VerificationOption":         "UsernamePassword",
+			"tempUN":                     "",
+			"tempPW":                     "",
+		},
+		LoginRedirect: "/HomeAccess/Classes/Classwork",
+		Routes: Routes{
+			Login:      LOGIN_ROUTE,
+			Classwork:  CLASSWORK_ROUTE,
+			Schedule:   SCHEDULE_ROUTE,
+			IPR:        IPR_ROUTE,
+			ReportCard: REPORT_CARD_ROUTE,
+			Transcript: TRANSCRIPT_ROUTE,
+			WeekView:   WEEK_VIEW_ROUTE,
+		},
+		Selectors: Selectors{
+			ReportCardRuns:      "#plnMain_ddlReportCardRuns",
+			IPRDates:            "#plnMain_ddlIPRDates",
+			ClassworkCategories: "table[id*='CourseCategories']",
+		},
+		PipelineWorkers:   3,
+		RequestsPerSecond: 5,
+		RequestBurst:      10,
+		MaxRetries:        2,
+	}
+}
@@ -0,0 +1,41 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/Threqt1/HACApi/app/models"
+)
+
+// errorCodes maps each error responded with to its machine-readable code.
+var errorCodes = []struct {
+	Err  error
+	Code models.ErrorCode
+}{
+	{ErrorBadBodyParams, models.ErrorCodeBadRequestField},
+	{ErrorInvalidBase, models.ErrorCodeBadRequestField},
+	{ErrorInvalidAuthentication, models.ErrorCodeInvalidCredentials},
+	{ErrorInvalidSession, models.ErrorCodeSessionExpired},
+	{ErrorSessionExpired, models.ErrorCodeSessionExpired},
+	{ErrorAccountLocked, models.ErrorCodeAccountLocked},
+	{ErrorServerUnreachable, models.ErrorCodeHACUnavailable},
+	{ErrorUnexpectedLoginPage, models.ErrorCodePageLayoutChanged},
+	{ErrorPageLayoutChanged, models.ErrorCodePageLayoutChanged},
+	{ErrorRateLimited, models.ErrorCodeRateLimited},
+	{ErrorRequestTimeout, models.ErrorCodeTimeout},
+	{ErrorRequestCanceled, models.ErrorCodeCanceled},
+	{ErrorEndpointNotFound, models.ErrorCodeNotFound},
+	{ErrorSubscriptionNotFound, models.ErrorCodeNotFound},
+	{ErrorSubscriptionLimit, models.ErrorCodeLimitReached},
+	{ErrorInvalidWebhookURL, models.ErrorCodeBadRequestField},
+	{ErrorInvalidWhatIf, models.ErrorCodeBadRequestField},
+}
+
+// ErrorCodeFor returns the machine-readable code for an error responded with.
+func ErrorCodeFor(err error) models.ErrorCode {
+	for _, errorCode := range errorCodes {
+		if errors.Is(err, errorCode.Err) {
+			return errorCode.Code
+		}
+	}
+	return models.ErrorCodeInternal
+}
@@ -8,5 +8,50 @@ var ErrorBadBodyParams = errors.New("bad body params")
 // The error thrown when there is an invalid authentication field.
 var ErrorInvalidAuthentication = errors.New("invalid username/password/base")
 
+// The error thrown when the session token is missing, invalid or expired.
+var ErrorInvalidSession = errors.New("invalid or expired session token")
+
 // The error thrown when there is an internal error.
 var ErrorInternalError = errors.New("resource not found. possibly an internal error")
+
+// The error thrown when the district's HAC server can't be reached, or errors out.
+var ErrorServerUnreachable = errors.New("district server unreachable. try again later")
+
+// The error thrown when the district's HAC login page doesn't look as expected.
+var ErrorUnexpectedLoginPage = errors.New("unexpected login page from district server")
+
+// The error thrown when the account is locked by HAC.
+var ErrorAccountLocked = errors.New("account locked")
+
+// The error thrown when the base URL is rejected.
+var ErrorInvalidBase = errors.New("invalid base url")
+
+// The error thrown when HAC takes longer to respond than the request allows.
+var ErrorRequestTimeout = errors.New("request timed out waiting for the district server")
+
+// The error thrown when the request is canceled before HAC responds.
+var ErrorRequestCanceled = errors.New("request canceled")
+
+// The error thrown when HAC keeps rejecting the session, even after logging in again.
+var ErrorSessionExpired = errors.New("session expired on district server. log in again")
+
+// The error thrown when a HAC page doesn't look as expected.
+var ErrorPageLayoutChanged = errors.New("unexpected page layout from district server")
+
+// The error thrown when the district's HAC server rate limits requests.
+var ErrorRateLimited = errors.New("rate limited by district server. try again later")
+
+// The error thrown when no endpoint matches the request.
+var ErrorEndpointNotFound = errors.New("No endpoint found")
+
+// The error thrown when no subscription has the ID given.
+var ErrorSubscriptionNotFound = errors.New("subscription not found")
+
+// The error thrown when an account or the API already has as many subscriptions as allowed.
+var ErrorSubscriptionLimit = errors.New("subscription limit reached")
+
+// The error thrown when the webhook URL is rejected.
+var ErrorInvalidWebhookURL = errors.New("invalid webhook url")
+
+// The error thrown when a hypothetical assignment doesn't match the classwork.
+var ErrorInvalidWhatIf = errors.New("hypothetical assignment doesn't match the classwork")
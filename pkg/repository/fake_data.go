@@ -3,3 +3,5 @@ package repository
 const FakeUsername = "john"
 const FakePassword = "doe"
 const FakeBase = "https://fake.url"
+const FakeToken = "fake-token"
+const FakeCalendarToken = "fake-calendar-token"
@@ -1,6 +1,9 @@
 package repository
 
 import (
+	"context"
+	"net/http"
+
 	"github.com/PuerkitoBio/goquery"
 	"github.com/Threqt1/HACApi/app/models"
 	"github.com/gocolly/colly"
@@ -8,13 +11,43 @@ import (
 )
 
 type CacheProvider interface {
-	GetOrLogin(key string) (*colly.Collector, error)
+	GetOrLogin(ctx context.Context, key string) (*colly.Collector, error)
+	Relogin(ctx context.Context, key string) (*colly.Collector, error)
+	NewSession(credentials models.BaseRequestBody) (models.Session, error)
+	GetSession(token string) (models.BaseRequestBody, error)
+	DeleteSession(token string) error
+	NewCalendarSession(credentials models.BaseRequestBody) (models.Session, error)
+	GetCalendarSession(token string) (models.BaseRequestBody, error)
+	DeleteCalendarSession(token string) error
+	Run(ctx context.Context)
 }
 
 type ScraperProvider interface {
-	Login(base, username, password string) (*colly.Collector, error)
-	Navigate(collector *colly.Collector, url, endpoint string) (*colly.Collector, *goquery.Selection, error)
-	Post(collector *colly.Collector, url, endpoint string, formData map[string]string) (*colly.Collector, *goquery.Selection, error)
+	Login(ctx context.Context, base, username, password string) (*colly.Collector, error)
+	Restore(base string, cookies []*http.Cookie) (*colly.Collector, error)
+	Probe(ctx context.Context, base string) error
+	Navigate(ctx context.Context, collector *colly.Collector, url, endpoint string) (*colly.Collector, *goquery.Selection, error)
+	Post(ctx context.Context, collector *colly.Collector, url, endpoint string, formData map[string]string) (*colly.Collector, *goquery.Selection, error)
+}
+
+type ProfileProvider interface {
+	Profile(base string) DistrictProfile
+}
+
+type BaseURLProvider interface {
+	Validate(base string) (string, error)
+	ValidateURL(rawURL string) (string, error)
+	Transport() *http.Transport
+}
+
+type HealthProvider interface {
+	Readiness(ctx context.Context) models.ReadinessResponse
+}
+
+type SubscriptionProvider interface {
+	Subscribe(params models.SubscriptionRequestBody, token string) (models.Subscription, error)
+	Unsubscribe(id string) error
+	Run(ctx context.Context)
 }
 
 type ValidationProvider interface {
@@ -22,28 +55,41 @@ type ValidationProvider interface {
 }
 
 type QuerierProvider interface {
-	GetClasswork(collector *colly.Collector, params models.ClassworkRequestBody) ([]models.Classwork, error)
-	GetIPRAll(collector *colly.Collector, params models.IprAllRequestBody) ([]models.IPR, error)
-	GetIPR(collector *colly.Collector, params models.IprRequestBody) ([]models.IPR, error)
-	GetLogin(collector *colly.Collector, params models.LoginRequestBody) ([]models.Login, error)
-	GetReportCard(collector *colly.Collector, params models.ReportCardRequestBody) ([]models.ReportCard, error)
-	GetSchedule(collector *colly.Collector, params models.ScheduleRequestBody) ([]models.Schedule, error)
-	GetTranscript(collector *colly.Collector, params models.TranscriptRequestBody) ([]models.Transcript, error)
+	GetClasswork(ctx context.Context, collector *colly.Collector, params models.ClassworkRequestBody) ([]models.Classwork, []models.ItemError, error)
+	GetIPRAll(ctx context.Context, collector *colly.Collector, params models.IprAllRequestBody) ([]models.IPR, []models.ItemError, error)
+	StreamClasswork(ctx context.Context, collector *colly.Collector, params models.ClassworkRequestBody, emit func(int, models.Classwork)) ([]models.ItemError, error)
+	StreamIPRAll(ctx context.Context, collector *colly.Collector, params models.IprAllRequestBody, emit func(int, models.IPR)) ([]models.ItemError, error)
+	GetIPR(ctx context.Context, collector *colly.Collector, params models.IprRequestBody) ([]models.IPR, []models.ItemError, error)
+	GetLogin(ctx context.Context, collector *colly.Collector, params models.LoginRequestBody) ([]models.Login, error)
+	GetReportCard(ctx context.Context, collector *colly.Collector, params models.ReportCardRequestBody) ([]models.ReportCard, error)
+	GetSchedule(ctx context.Context, collector *colly.Collector, params models.ScheduleRequestBody) ([]models.Schedule, error)
+	GetTranscript(ctx context.Context, collector *colly.Collector, params models.TranscriptRequestBody) ([]models.Transcript, error)
+	GetWeekView(ctx context.Context, collector *colly.Collector, params models.WeekViewRequestBody) ([]models.WeekView, error)
 }
 
 type ParserProvider interface {
-	ParseClasswork(html *goquery.Selection) models.Classwork
-	ParseIPR(html *goquery.Selection) models.IPR
+	ParseClasswork(html *goquery.Selection, selectors Selectors) models.Classwork
+	ParseIPR(html *goquery.Selection, selectors Selectors) models.IPR
 	ParseReportCard(html *goquery.Selection) models.ReportCard
 	ParseSchedule(html *goquery.Selection) models.Schedule
 	ParseTranscript(html *goquery.Selection) models.Transcript
+	ParseWeekView(html *goquery.Selection) models.WeekView
+	NormalizeClasswork(classwork models.Classwork) models.Classwork
+	NormalizeIPR(ipr models.IPR) models.IPR
+	NormalizeReportCard(reportCard models.ReportCard) models.ReportCard
+	NormalizeTranscript(transcript models.Transcript) models.Transcript
 }
 
 type Server struct {
-	App       *fiber.App
-	Cache     CacheProvider
-	Scraper   ScraperProvider
-	Validator ValidationProvider
-	Querier   QuerierProvider
-	Parser    ParserProvider
+	App           *fiber.App
+	Cache         CacheProvider
+	Scraper       ScraperProvider
+	Validator     ValidationProvider
+	Querier       QuerierProvider
+	Parser        ParserProvider
+	Profiles      ProfileProvider
+	BaseURLs      BaseURLProvider
+	Health        HealthProvider
+	Subscriptions SubscriptionProvider
+	WebhookURLs   BaseURLProvider
 }
@@ -0,0 +1,13 @@
+package routes
+
+import (
+	"github.com/Threqt1/HACApi/app/controllers"
+	"github.com/Threqt1/HACApi/pkg/repository"
+	"github.com/Threqt1/HACApi/pkg/utils"
+)
+
+// HealthRoutes sets up the liveness and readiness endpoints.
+func HealthRoutes(server *repository.Server) {
+	server.App.Get("/healthz", utils.WrapController(server, controllers.GetHealthz)) // get liveness
+	server.App.Get("/readyz", utils.WrapController(server, controllers.GetReadyz))   // get readiness
+}
@@ -0,0 +1,15 @@
+package routes
+
+import (
+	"github.com/Threqt1/HACApi/pkg/repository"
+	"github.com/Threqt1/HACApi/platform/metrics"
+	"github.com/gofiber/fiber/v2"
+)
+
+// MetricsRoute sets up the Prometheus metrics endpoint.
+func MetricsRoute(server *repository.Server) {
+	server.App.Get("/metrics", func(ctx *fiber.Ctx) error {
+		ctx.Set(fiber.HeaderContentType, "text/plain; version=0.0.4; charset=utf-8")
+		return metrics.Default.Write(ctx)
+	})
+}
@@ -14,14 +14,18 @@ func PublicRoutes(server *repository.Server) {
 	// Routes for POST methods.
 
 	// login.
-	route.Post("/login", utils.WrapController(server, controllers.PostLogin)) // post login
+	route.Post("/login", utils.WrapController(server, controllers.PostLogin))   // post login
+	route.Post("/logout", utils.WrapController(server, controllers.PostLogout)) // post logout
 
 	// classwork.
-	route.Post("/classwork", utils.WrapController(server, controllers.PostClasswork)) // post classwork
+	route.Post("/classwork", utils.WrapController(server, controllers.PostClasswork))              // post classwork
+	route.Post("/classwork/stream", utils.WrapController(server, controllers.PostClassworkStream)) // stream classwork
+	route.Post("/classwork/whatif", utils.WrapController(server, controllers.PostWhatIf))          // recalculate averages with hypothetical assignments
 
 	// ipr.
-	route.Post("/ipr", utils.WrapController(server, controllers.PostIPR))        // post interim progress report
-	route.Post("/ipr/all", utils.WrapController(server, controllers.PostIPRAll)) // post all interim progress reports
+	route.Post("/ipr", utils.WrapController(server, controllers.PostIPR))                     // post interim progress report
+	route.Post("/ipr/all", utils.WrapController(server, controllers.PostIPRAll))              // post all interim progress reports
+	route.Post("/ipr/all/stream", utils.WrapController(server, controllers.PostIPRAllStream)) // stream all interim progress reports
 
 	// report card.
 	route.Post("/reportcard", utils.WrapController(server, controllers.PostReportCard)) // post report card
@@ -31,4 +35,25 @@ func PublicRoutes(server *repository.Server) {
 
 	// transcript.
 	route.Post("/transcript", utils.WrapController(server, controllers.PostTranscript)) // post transcript
+
+	// gpa.
+	route.Post("/gpa", utils.WrapController(server, controllers.PostGPA)) // calculate the current, projected and what-if GPA
+
+	// week view.
+	route.Post("/weekview", utils.WrapController(server, controllers.PostWeekView)) // post week view
+
+	// batch.
+	route.Post("/batch", utils.WrapController(server, controllers.PostBatch)) // post several resources at once
+
+	// calendar.
+	route.Get("/calendar/assignments.ics", utils.WrapController(server, controllers.GetAssignmentCalendar))  // subscribe to assignment due dates
+	route.Post("/calendar/assignments.ics", utils.WrapController(server, controllers.GetAssignmentCalendar)) // export assignment due dates
+	route.Get("/calendar/schedule.ics", utils.WrapController(server, controllers.GetScheduleCalendar))       // subscribe to the class schedule
+	route.Post("/calendar/schedule.ics", utils.WrapController(server, controllers.GetScheduleCalendar))      // export the class schedule
+	route.Post("/calendar/token", utils.WrapController(server, controllers.PostCalendarToken))               // mint a calendar token
+	route.Delete("/calendar/token", utils.WrapController(server, controllers.DeleteCalendarToken))           // revoke a calendar token
+
+	// subscriptions.
+	route.Post("/subscriptions", utils.WrapController(server, controllers.PostSubscription))         // register a webhook
+	route.Delete("/subscriptions/:id", utils.WrapController(server, controllers.DeleteSubscription)) // remove a webhook
 }
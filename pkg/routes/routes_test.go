@@ -1,8 +1,12 @@
 package routes
 
 import (
+	"io"
+	"net/http/httptest"
+	"strings"
 	"testing"
 
+	"github.com/Threqt1/HACApi/pkg/middleware"
 	"github.com/Threqt1/HACApi/pkg/repository"
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/go-cmp/cmp"
@@ -27,18 +31,57 @@ func TestPublicRoutes(t *testing.T) {
 	// Make expected output.
 	apiRoute := "/api/v1"
 	expected := []fiber.Route{
+		// Calendar subscriptions, which are also registered for HEAD.
+		{
+			Method: "GET",
+			Path:   apiRoute + "/calendar/assignments.ics",
+			Params: nil,
+		},
+		{
+			Method: "GET",
+			Path:   apiRoute + "/calendar/schedule.ics",
+			Params: nil,
+		},
+		{
+			Method: "HEAD",
+			Path:   apiRoute + "/calendar/assignments.ics",
+			Params: nil,
+		},
+		{
+			Method: "HEAD",
+			Path:   apiRoute + "/calendar/schedule.ics",
+			Params: nil,
+		},
 		// Login.
 		{
 			Method: "POST",
 			Path:   apiRoute + "/login",
 			Params: nil,
 		},
+		// Logout.
+		{
+			Method: "POST",
+			Path:   apiRoute + "/logout",
+			Params: nil,
+		},
 		// Classwork.
 		{
 			Method: "POST",
 			Path:   apiRoute + "/classwork",
 			Params: nil,
 		},
+		// Classwork Stream.
+		{
+			Method: "POST",
+			Path:   apiRoute + "/classwork/stream",
+			Params: nil,
+		},
+		// Classwork What-If.
+		{
+			Method: "POST",
+			Path:   apiRoute + "/classwork/whatif",
+			Params: nil,
+		},
 		// IPR.
 		{
 			Method: "POST",
@@ -51,6 +94,12 @@ func TestPublicRoutes(t *testing.T) {
 			Path:   apiRoute + "/ipr/all",
 			Params: nil,
 		},
+		// IPR All Stream.
+		{
+			Method: "POST",
+			Path:   apiRoute + "/ipr/all/stream",
+			Params: nil,
+		},
 		// Report Card.
 		{
 			Method: "POST",
@@ -69,6 +118,56 @@ func TestPublicRoutes(t *testing.T) {
 			Path:   apiRoute + "/transcript",
 			Params: nil,
 		},
+		// GPA.
+		{
+			Method: "POST",
+			Path:   apiRoute + "/gpa",
+			Params: nil,
+		},
+		// Week View.
+		{
+			Method: "POST",
+			Path:   apiRoute + "/weekview",
+			Params: nil,
+		},
+		// Batch.
+		{
+			Method: "POST",
+			Path:   apiRoute + "/batch",
+			Params: nil,
+		},
+		// Calendar.
+		{
+			Method: "POST",
+			Path:   apiRoute + "/calendar/assignments.ics",
+			Params: nil,
+		},
+		{
+			Method: "POST",
+			Path:   apiRoute + "/calendar/schedule.ics",
+			Params: nil,
+		},
+		{
+			Method: "POST",
+			Path:   apiRoute + "/calendar/token",
+			Params: nil,
+		},
+		// Subscriptions.
+		{
+			Method: "POST",
+			Path:   apiRoute + "/subscriptions",
+			Params: nil,
+		},
+		{
+			Method: "DELETE",
+			Path:   apiRoute + "/calendar/token",
+			Params: nil,
+		},
+		{
+			Method: "DELETE",
+			Path:   apiRoute + "/subscriptions/:id",
+			Params: []string{"id"},
+		},
 	}
 
 	// Compare them.
@@ -110,3 +209,94 @@ func TestSwaggerRoute(t *testing.T) {
 		t.Fatalf("Failed for TestSwaggerRoute() (-want, +got)\n%s", diff)
 	}
 }
+
+// TestMetricsRoute tests if the metrics route is
+// registered properly, and serves the metrics.
+func TestMetricsRoute(t *testing.T) {
+	// Create a testing server.
+	server := repository.Server{App: fiber.New(fiber.Config{})}
+	server.App.Use(middleware.RequestMetrics())
+
+	// Register the routes.
+	MetricsRoute(&server)
+
+	// Confirm routes were registered.
+	registered := server.App.GetRoutes(true)
+
+	expected := []fiber.Route{
+		{
+			Method: "GET",
+			Path:   "/metrics",
+			Params: nil,
+		},
+		{
+			Method: "HEAD",
+			Path:   "/metrics",
+			Params: nil,
+		},
+	}
+
+	if diff := cmp.Diff(expected, registered, testRoute_Comparer); diff != "" {
+		t.Fatalf("Failed for TestMetricsRoute() (-want, +got)\n%s", diff)
+	}
+
+	// Scrape twice, so the first scrape is counted in the second.
+	for i := 0; i < 2; i++ {
+		res, err := server.App.Test(httptest.NewRequest("GET", "/metrics", nil))
+		if err != nil {
+			t.Fatalf("Failed for GET /metrics:\n%v", err)
+		}
+
+		body, err := io.ReadAll(res.Body)
+		if err != nil {
+			t.Fatalf("Failed for GET /metrics:\n%v", err)
+		}
+
+		if i == 1 && !strings.Contains(string(body), `hacapi_http_requests_total{method="GET",route="/metrics",status="200"}`) {
+			t.Fatalf("Failed for GET /metrics, request not counted:\n%s", body)
+		}
+	}
+}
+
+// TestHealthRoutes tests if the health routes are
+// registered properly.
+func TestHealthRoutes(t *testing.T) {
+	// Create a testing server.
+	server := repository.Server{App: fiber.New(fiber.Config{})}
+
+	// Register the routes.
+	HealthRoutes(&server)
+
+	// Confirm routes were registered.
+	registered := server.App.GetRoutes(true)
+
+	// Make expected output.
+	expected := []fiber.Route{
+		// Liveness and readiness.
+		{
+			Method: "GET",
+			Path:   "/healthz",
+			Params: nil,
+		},
+		{
+			Method: "GET",
+			Path:   "/readyz",
+			Params: nil,
+		},
+		{
+			Method: "HEAD",
+			Path:   "/healthz",
+			Params: nil,
+		},
+		{
+			Method: "HEAD",
+			Path:   "/readyz",
+			Params: nil,
+		},
+	}
+
+	// Compare them.
+	if diff := cmp.Diff(expected, registered, testRoute_Comparer); diff != "" {
+		t.Fatalf("Failed for TestHealthRoutes() (-want, +got)\n%s", diff)
+	}
+}
@@ -0,0 +1,249 @@
+package utils
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// ErrorBaseMalformed is the error thrown when the base is not a valid URL.
+var ErrorBaseMalformed = errors.New("base is not a valid url")
+
+// ErrorBaseInsecure is the error thrown when the base does not use HTTPS.
+var ErrorBaseInsecure = errors.New("base must use https")
+
+// ErrorBaseNotAllowed is the error thrown when the base's host is not in the allowlist.
+var ErrorBaseNotAllowed = errors.New("base host is not allowed")
+
+// ErrorBasePortNotAllowed is the error thrown when the base uses a port other than 80 or 443 the allowlist doesn't allow.
+var ErrorBasePortNotAllowed = errors.New("base port is not allowed")
+
+// ErrorBasePrivateAddress is the error thrown when the base resolves to a private or loopback address.
+var ErrorBasePrivateAddress = errors.New("base resolves to a private address")
+
+// The ports bases can use without the allowlist naming them.
+var defaultPorts = map[string]bool{"": true, "80": true, "443": true}
+
+// The address ranges that can't be reached from the public internet,
+// beyond those net.IP already knows about.
+var privateNetworks = []*net.IPNet{
+	mustParseCIDR("0.0.0.0/8"),     // "This" network
+	mustParseCIDR("100.64.0.0/10"), // Carrier-grade NAT
+	mustParseCIDR("192.0.0.0/24"),  // IETF protocol assignments
+	mustParseCIDR("198.18.0.0/15"), // Benchmarking
+	mustParseCIDR("240.0.0.0/4"),   // Reserved, and broadcast
+}
+
+// The IPv6 prefixes that embed an IPv4 address in their last 4 bytes.
+var embeddingNetworks = []*net.IPNet{
+	mustParseCIDR("::/96"),          // IPv4-compatible
+	mustParseCIDR("64:ff9b::/96"),   // NAT64
+	mustParseCIDR("64:ff9b:1::/48"), // Local-use NAT64
+}
+
+// BaseURLPolicy validates the base URLs of HAC instances
+// sent by users, so the API can't be used to make requests
+// to arbitrary or internal hosts.
+type BaseURLPolicy struct {
+	AllowedHosts  []string // Host patterns allowed as bases (Ex: *.katyisd.org, hac.example.org:8443), any host is allowed on ports 80 and 443 if empty
+	AllowInsecure bool     // Whether HTTP bases are allowed
+	AllowPrivate  bool     // Whether bases resolving to private or loopback addresses are allowed
+}
+
+// NewBaseURLPolicy creates a new base URL policy.
+func NewBaseURLPolicy(allowedHosts []string, allowInsecure, allowPrivate bool) *BaseURLPolicy {
+	return &BaseURLPolicy{
+		AllowedHosts:  allowedHosts,
+		AllowInsecure: allowInsecure,
+		AllowPrivate:  allowPrivate,
+	}
+}
+
+// Validate checks a base against the policy, and returns it
+// normalized to only its scheme and host.
+func (policy BaseURLPolicy) Validate(base string) (string, error) {
+	// Parse the base.
+	baseURL, err := url.Parse(strings.TrimSpace(base))
+	if err != nil || baseURL.Hostname() == "" || baseURL.User != nil {
+		return "", ErrorBaseMalformed
+	}
+
+	// Check the scheme.
+	scheme := strings.ToLower(baseURL.Scheme)
+	if scheme != "https" && !(scheme == "http" && policy.AllowInsecure) {
+		if scheme != "http" {
+			return "", ErrorBaseMalformed
+		}
+		return "", ErrorBaseInsecure
+	}
+
+	// Check the host and port against the allowlist.
+	host := strings.ToLower(strings.TrimSuffix(baseURL.Hostname(), "."))
+	if !policy.allowed(host) {
+		return "", ErrorBaseNotAllowed
+	}
+	port := baseURL.Port()
+	if !policy.allowedPort(host, port) {
+		return "", ErrorBasePortNotAllowed
+	}
+
+	// Reject private IP literals early. The addresses hosts resolve
+	// to are checked when connecting, by the policy's transport.
+	if address := net.ParseIP(host); address != nil && !policy.AllowPrivate && isPrivateAddress(address) {
+		return "", ErrorBasePrivateAddress
+	}
+
+	// Drop any path, query or trailing slash.
+	normalized := url.URL{Scheme: scheme, Host: host}
+	if port != "" {
+		normalized.Host = net.JoinHostPort(host, port)
+	}
+
+	return normalized.String(), nil
+}
+
+// ValidateURL checks a URL's host against the policy, and returns the
+// URL with its host normalized, keeping its path and query.
+func (policy BaseURLPolicy) ValidateURL(rawURL string) (string, error) {
+	base, err := policy.Validate(rawURL)
+	if err != nil {
+		return "", err
+	}
+
+	parsed, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil {
+		return "", ErrorBaseMalformed
+	}
+
+	normalized, _ := url.Parse(base)
+	normalized.Path = parsed.Path
+	normalized.RawPath = parsed.RawPath
+	normalized.RawQuery = parsed.RawQuery
+
+	return normalized.String(), nil
+}
+
+// Transport returns a transport for requests to URLs passing the policy. Unless the
+// policy allows private addresses, it refuses to connect to them once the host has
+// been resolved, so a host can't pass Validate and then resolve to an internal
+// address when connecting. Requests aren't sent through proxies, since the proxy's
+// address would be checked instead of the host's.
+func (policy BaseURLPolicy) Transport() *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if policy.AllowPrivate {
+		return transport
+	}
+
+	dialer := &net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+		Control:   rejectPrivateAddress,
+	}
+	transport.DialContext = dialer.DialContext
+	transport.Proxy = nil
+
+	return transport
+}
+
+// allowed returns whether a host matches the allowlist.
+func (policy BaseURLPolicy) allowed(host string) bool {
+	if len(policy.AllowedHosts) == 0 {
+		return true
+	}
+
+	for _, pattern := range policy.AllowedHosts {
+		if patternHost, _ := splitPattern(pattern); matchHost(patternHost, host) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// allowedPort returns whether a host may use a port. Ports 80 and 443 are always
+// allowed, others only if an allowlist pattern matching the host names them
+// (Ex: hac.example.org:8443, or 127.0.0.1:* for any port).
+func (policy BaseURLPolicy) allowedPort(host, port string) bool {
+	if defaultPorts[port] {
+		return true
+	}
+
+	for _, pattern := range policy.AllowedHosts {
+		patternHost, patternPort := splitPattern(pattern)
+		if portMatched, _ := path.Match(patternPort, port); portMatched && patternPort != "" && matchHost(patternHost, host) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// splitPattern splits an allowlist pattern into its host pattern and port, if it has one.
+func splitPattern(pattern string) (string, string) {
+	pattern = strings.ToLower(strings.TrimSpace(pattern))
+	if host, port, err := net.SplitHostPort(pattern); err == nil {
+		return host, port
+	}
+	return pattern, ""
+}
+
+// matchHost returns whether a host matches a host pattern.
+func matchHost(pattern, host string) bool {
+	matched, _ := path.Match(pattern, host)
+	return matched
+}
+
+// rejectPrivateAddress is a dialer control function which refuses connections to
+// private addresses, after the host has been resolved.
+func rejectPrivateAddress(network, address string, conn syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+
+	if ip := net.ParseIP(host); ip == nil || isPrivateAddress(ip) {
+		return ErrorBasePrivateAddress
+	}
+	return nil
+}
+
+// isPrivateAddress returns whether an address can't be reached from the public internet.
+// IPv4 addresses embedded in IPv6 ones are checked as IPv4 addresses.
+func isPrivateAddress(address net.IP) bool {
+	if address.To4() == nil {
+		for _, network := range embeddingNetworks {
+			if network.Contains(address) {
+				address = net.IP(address[len(address)-net.IPv4len:]).To4()
+				break
+			}
+		}
+	}
+
+	for _, network := range privateNetworks {
+		if network.Contains(address) {
+			return true
+		}
+	}
+
+	return address.IsLoopback() ||
+		address.IsPrivate() ||
+		address.IsUnspecified() ||
+		address.IsLinkLocalUnicast() ||
+		address.IsLinkLocalMulticast() ||
+		address.IsInterfaceLocalMulticast() ||
+		address.IsMulticast()
+}
+
+// mustParseCIDR parses a CIDR network, panicking if it is invalid.
+func mustParseCIDR(cidr string) *net.IPNet {
+	_, network, err := net.ParseCIDR(cidr)
+	if err != nil {
+		panic(err)
+	}
+	return network
+}
@@ -0,0 +1,20 @@
+package utils
+
+import (
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// GetBearerToken returns the token in the Authorization header of a request, or
+// an empty string if there is no Bearer token.
+func GetBearerToken(ctx *fiber.Ctx) string {
+	// Split the header into its scheme and token.
+	scheme, token, found := strings.Cut(strings.TrimSpace(ctx.Get(fiber.HeaderAuthorization)), " ")
+
+	if !found || !strings.EqualFold(scheme, "Bearer") {
+		return ""
+	}
+
+	return strings.TrimSpace(token)
+}
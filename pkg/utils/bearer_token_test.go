@@ -0,0 +1,45 @@
+package utils
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/go-cmp/cmp"
+)
+
+// TestGetBearerToken tests GetBearerToken() for different headers.
+func TestGetBearerToken(t *testing.T) {
+	// Set up all test cases, mapping the header to the expected token.
+	cases := map[string]string{
+		"":                  "",
+		"Bearer abc123":     "abc123",
+		"bearer abc123":     "abc123",
+		"Bearer   abc123  ": "abc123",
+		"Basic abc123":      "",
+		"Bearer":            "",
+	}
+
+	for header, expected := range cases {
+		// Create a server which echoes the token back.
+		app := fiber.New()
+		app.Get("/", func(ctx *fiber.Ctx) error {
+			return ctx.SendString(GetBearerToken(ctx))
+		})
+
+		// Create a test request.
+		req := httptest.NewRequest("GET", "http://fake.url/", nil)
+		if header != "" {
+			req.Header.Set("Authorization", header)
+		}
+
+		// Test.
+		resp, _ := app.Test(req)
+		got := make([]byte, resp.ContentLength)
+		resp.Body.Read(got)
+
+		if diff := cmp.Diff(expected, string(got)); diff != "" {
+			t.Fatalf("Failed for GetBearerToken() with header %q (-want, +got):\n%s", header, diff)
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package utils
+
+import (
+	"fmt"
+
+	"github.com/Threqt1/HACApi/app/models"
+	"github.com/Threqt1/HACApi/pkg/repository"
+	"github.com/gofiber/fiber/v2"
+)
+
+// ParseCalendarParams parses the params of a calendar request. GET requests,
+// which calendar apps subscribe with, pass them in the query string, and POST
+// requests in the body.
+func ParseCalendarParams(ctx *fiber.Ctx, params interface{}) error {
+	if ctx.Method() == fiber.MethodGet {
+		return ctx.QueryParser(params)
+	}
+	return ParseBody(ctx, params)
+}
+
+// GetCalendarToken returns the calendar or session token of a calendar request.
+// Calendar apps can't set headers, so GET requests can also pass it as the token
+// query parameter.
+func GetCalendarToken(ctx *fiber.Ctx) string {
+	if token := GetBearerToken(ctx); token != "" || ctx.Method() != fiber.MethodGet {
+		return token
+	}
+	return ctx.Query("token")
+}
+
+// GetCalendarCredentials returns the credentials bound to the token of a calendar
+// request, which is either a calendar token or a session token.
+func GetCalendarCredentials(cache repository.CacheProvider, token string) (models.BaseRequestBody, error) {
+	if credentials, err := cache.GetCalendarSession(token); err == nil {
+		return credentials, nil
+	}
+	return cache.GetSession(token)
+}
+
+// SetCalendarHeaders marks a response as an iCalendar file with the given name.
+func SetCalendarHeaders(ctx *fiber.Ctx, filename string) {
+	ctx.Set(fiber.HeaderContentType, "text/calendar; charset=utf-8")
+	ctx.Set(fiber.HeaderContentDisposition, fmt.Sprintf("inline; filename=%q", filename))
+	ctx.Set(fiber.HeaderCacheControl, "private, no-store")
+}
@@ -0,0 +1,140 @@
+package utils
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/Threqt1/HACApi/app/models"
+	"github.com/Threqt1/HACApi/pkg/repository"
+	"github.com/Threqt1/HACApi/platform/logging"
+	"github.com/gocolly/colly"
+	"github.com/gofiber/fiber/v2"
+)
+
+// The key the end of a stream is stored under in the request's locals.
+const streamEndKey = "streamend"
+
+// streamEnd holds the callbacks run once a stream ends. fasthttp starts
+// writing the stream as soon as it's set, so it can end at any time.
+type streamEnd struct {
+	mutex     sync.Mutex
+	ended     bool
+	callbacks []func()
+}
+
+// end marks the stream as ended, and runs the callbacks.
+func (end *streamEnd) end() {
+	end.mutex.Lock()
+	end.ended = true
+	callbacks := end.callbacks
+	end.mutex.Unlock()
+
+	for _, callback := range callbacks {
+		callback()
+	}
+}
+
+// SetEventStreamHeaders marks a response as a stream of Server-Sent Events.
+func SetEventStreamHeaders(ctx *fiber.Ctx) {
+	ctx.Set(fiber.HeaderContentType, "text/event-stream")
+	ctx.Set(fiber.HeaderCacheControl, "no-cache")
+	ctx.Set(fiber.HeaderConnection, "keep-alive")
+	ctx.Set("X-Accel-Buffering", "no")
+}
+
+// WriteEvent writes a Server-Sent Event with a JSON payload, and flushes it to the client.
+// An error means the client can't be written to anymore.
+func WriteEvent(w *bufio.Writer, event string, data interface{}) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, payload); err != nil {
+		return err
+	}
+
+	return w.Flush()
+}
+
+// DetachContext returns a context carrying the request's deadline and log fields,
+// which isn't cancelled once the handler returns, for work done while streaming
+// the response.
+func DetachContext(ctx *fiber.Ctx) (context.Context, context.CancelFunc) {
+	detached := logging.WithFields(context.Background(), logging.ContextFields(ctx.UserContext()))
+	if deadline, ok := ctx.UserContext().Deadline(); ok {
+		return context.WithDeadline(detached, deadline)
+	}
+	return context.WithCancel(detached)
+}
+
+// StreamEvents streams the results of a query to the client as Server-Sent Events,
+// once the handler returns. Each value the query emits is sent as an event named
+// event, and only once per index, since the query is run again if the session
+// behind the collector cached under key expired. The stream ends with a "done"
+// event listing the items which failed, or an "error" event if the query failed.
+func StreamEvents[T any](ctx *fiber.Ctx, cache repository.CacheProvider, key string, collector *colly.Collector, event string, query func(context.Context, *colly.Collector, func(int, T)) ([]models.ItemError, error)) {
+	streamCtx, cancel := DetachContext(ctx)
+	requestID := RequestID(ctx)
+	SetEventStreamHeaders(ctx)
+
+	// Let the middleware wait for the stream, before timing and logging the request.
+	end := &streamEnd{}
+	ctx.Locals(streamEndKey, end)
+
+	ctx.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		defer end.end()
+		defer cancel()
+
+		// Keep track of what was sent, since the query is run again if the session expired.
+		sent := make(map[int]bool)
+
+		itemErrors, err := RetryOnExpiredSession(streamCtx, cache, key, collector, func(collector *colly.Collector) ([]models.ItemError, error) {
+			return query(streamCtx, collector, func(i int, value T) {
+				if sent[i] {
+					return
+				}
+				sent[i] = true
+
+				// Stop the query if the client is gone.
+				if err := WriteEvent(w, event, value); err != nil {
+					cancel()
+				}
+			})
+		})
+
+		// End the stream with an error event if the query failed.
+		if err != nil {
+			_, queryErr := QueryErrorResponse(err)
+			WriteEvent(w, "error", NewHTTPErrorForRequest(requestID, queryErr))
+			return
+		}
+
+		WriteEvent(w, "done", models.StreamDone{Errors: itemErrors})
+	})
+}
+
+// OnStreamEnd runs callback once the response streamed by StreamEvents ends,
+// returning false if the response isn't streamed, so the caller can run it
+// right away. The request's context is reused once the handler returns, so
+// callback can't use it.
+func OnStreamEnd(ctx *fiber.Ctx, callback func()) bool {
+	end, ok := ctx.Locals(streamEndKey).(*streamEnd)
+	if !ok {
+		return false
+	}
+
+	end.mutex.Lock()
+	if !end.ended {
+		end.callbacks = append(end.callbacks, callback)
+		end.mutex.Unlock()
+		return true
+	}
+	end.mutex.Unlock()
+
+	callback()
+	return true
+}
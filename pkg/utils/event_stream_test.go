@@ -0,0 +1,24 @@
+package utils
+
+import (
+	"bufio"
+	"bytes"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// Test if WriteEvent() writes and flushes a Server-Sent Event.
+func TestWriteEvent(t *testing.T) {
+	var buf bytes.Buffer
+	w := bufio.NewWriter(&buf)
+
+	// Test.
+	if err := WriteEvent(w, "classwork", map[string]int{"sixWeeks": 1}); err != nil {
+		t.Fatalf("Failed for WriteEvent():\n%v", err)
+	}
+
+	if diff := cmp.Diff("event: classwork\ndata: {\"sixWeeks\":1}\n\n", buf.String()); diff != "" {
+		t.Fatalf("Failed for WriteEvent() (-want, +got)\n%s", diff)
+	}
+}
@@ -0,0 +1,52 @@
+package utils
+
+import (
+	"context"
+	"time"
+
+	"github.com/Threqt1/HACApi/platform/logging"
+	"github.com/gocolly/colly"
+)
+
+// The key of the time a HAC request was sent, in the request's colly context.
+const requestStartKey = "hacapiRequestStart"
+
+// logHACEvents logs every request a collector sends to HAC and its response,
+// tagged with the fields ctx carries. Only the method, the URL without its
+// query and the status are logged, never the form data, headers or cookies.
+func logHACEvents(ctx context.Context, collector *colly.Collector) {
+	collector.OnRequest(func(req *colly.Request) {
+		req.Ctx.Put(requestStartKey, time.Now())
+		logging.Log(ctx, logging.LevelDebug, "hac request", logging.Fields{
+			"method": req.Method,
+			"url":    req.URL.Scheme + "://" + req.URL.Host + req.URL.Path,
+		})
+	})
+
+	collector.OnResponse(func(res *colly.Response) {
+		logging.Log(ctx, logging.LevelDebug, "hac response", hacResponseFields(res))
+	})
+
+	collector.OnError(func(res *colly.Response, err error) {
+		fields := hacResponseFields(res)
+		fields["error"] = err
+		logging.Log(ctx, logging.LevelError, "hac request failed", fields)
+	})
+}
+
+// hacResponseFields returns the fields logged for a response from HAC.
+func hacResponseFields(res *colly.Response) logging.Fields {
+	fields := logging.Fields{}
+	if res == nil || res.Request == nil {
+		return fields
+	}
+
+	fields["method"] = res.Request.Method
+	fields["url"] = res.Request.URL.Scheme + "://" + res.Request.URL.Host + res.Request.URL.Path
+	fields["status"] = res.StatusCode
+	if start, ok := res.Ctx.GetAny(requestStartKey).(time.Time); ok {
+		fields["latencyMs"] = time.Since(start).Milliseconds()
+	}
+
+	return fields
+}
@@ -0,0 +1,60 @@
+package utils
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/Threqt1/HACApi/platform/logging"
+)
+
+// Test if logins and requests to HAC are logged with the request's fields, without credentials.
+func TestHACLogging(t *testing.T) {
+	// Capture the logs.
+	var out bytes.Buffer
+	previous := logging.Default
+	logging.Default = logging.New(&out, logging.LevelDebug)
+	defer func() { logging.Default = previous }()
+
+	// Create testing server and scraper.
+	ts := CreateTestingServer()
+	defer ts.Close()
+
+	scraper := NewScraper(nil, nil)
+
+	// Log in with credentials which must not leak, and navigate.
+	if _, err := scraper.Login(context.Background(), ts.URL, "leaky-username", "leaky-password"); err != ErrorInvalidCredentials {
+		t.Fatalf("Failed for Login() with invalid credentials:\n%v", err)
+	}
+	collector, err := scraper.Login(context.Background(), ts.URL, "ABC", "123")
+	if err != nil {
+		t.Fatalf("Failed for Login() with valid credentials:\n%v", err)
+	}
+
+	ctx := logging.WithFields(context.Background(), logging.Fields{"requestId": "test-request"})
+	if _, _, err := scraper.Navigate(ctx, collector, ts.URL, "/broken"); err == nil {
+		t.Fatalf("Failed for Navigate() with a broken page, expected an error")
+	}
+
+	logs := out.String()
+
+	for _, expected := range []string{
+		`"msg":"hac request"`,
+		`"msg":"hac response"`,
+		`"msg":"hac login rejected"`,
+		`"user":"` + logging.HashUser("leaky-username", ts.URL) + `"`,
+		`"msg":"hac request failed","error":"Internal Server Error"`,
+		`"requestId":"test-request"`,
+	} {
+		if !strings.Contains(logs, expected) {
+			t.Fatalf("Failed for HAC logging, expected %q in:\n%s", expected, logs)
+		}
+	}
+
+	for _, leaked := range []string{"leaky-username", "leaky-password", "ABCD12345", "Cookie", "ASP.NET"} {
+		if strings.Contains(logs, leaked) {
+			t.Fatalf("Failed for HAC logging, %q leaked into:\n%s", leaked, logs)
+		}
+	}
+}
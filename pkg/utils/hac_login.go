@@ -1,27 +1,90 @@
 package utils
 
 import (
+	"bytes"
+	"context"
 	"errors"
+	"fmt"
+	"net/http"
 	"strings"
 
+	"github.com/PuerkitoBio/goquery"
 	"github.com/Threqt1/HACApi/pkg/repository"
+	"github.com/Threqt1/HACApi/platform/logging"
 	"github.com/gocolly/colly"
 )
 
 var ErrorInvalidCredentials = errors.New("invalid credentials")
 
-// login logs a colly collector into Home Access Center.
-func login(url, username, password string) (*colly.Collector, error) {
-	// Get the base of the URL.
-	base := strings.Split(url, "//")[1]
+// ErrorServerUnreachable is the error thrown when HAC can't be reached, or responds with an error.
+var ErrorServerUnreachable = errors.New("server unreachable")
 
-	// Create a new Colly collector.
+// ErrorUnexpectedLoginPage is the error thrown when the HAC login page doesn't look as expected.
+var ErrorUnexpectedLoginPage = errors.New("unexpected login page")
+
+// ErrorAccountLocked is the error thrown when HAC reports the account as locked.
+var ErrorAccountLocked = errors.New("account locked")
+
+// newCollector creates a new colly collector for a Home Access Center URL,
+// which sends its requests with a transport, or http.DefaultTransport if nil.
+func newCollector(url string, transport http.RoundTripper) *colly.Collector {
 	collector := colly.NewCollector(
-		colly.AllowedDomains(base),
+		colly.AllowedDomains(strings.Split(url, "//")[1]),
 		colly.Async(true),
 		colly.AllowURLRevisit(),
 	)
 
+	// Allow in-flight requests to be cancelled.
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	collector.WithTransport(contextTransport{Transport: transport})
+
+	return collector
+}
+
+// restore creates a colly collector which is logged into Home Access Center with
+// the cookies of a previous login.
+func restore(url string, cookies []*http.Cookie, transport http.RoundTripper) (*colly.Collector, error) {
+	// Create a new Colly collector.
+	collector := newCollector(url, transport)
+
+	// Load the cookies into its jar.
+	if err := collector.SetCookies(url, cookies); err != nil {
+		return nil, err
+	}
+
+	return collector, nil
+}
+
+// login logs a colly collector into Home Access Center, using the
+// login form fields and routes of the district's profile. The login's
+// requests are cancelled along with ctx.
+func login(ctx context.Context, url, username, password string, profile repository.DistrictProfile, transport http.RoundTripper) (*colly.Collector, error) {
+	// Get the base of the URL.
+	base := strings.Split(url, "//")[1]
+
+	// Abort if the request was already cancelled.
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	// Tag the login's logs with the district and a hash of the user.
+	logCtx := logging.WithFields(ctx, logging.Fields{
+		"district": logging.District(url),
+		"user":     logging.HashUser(username, url),
+	})
+
+	// Create a new Colly collector.
+	collector := newCollector(url, transport)
+
+	// Cancel the HAC requests along with the context.
+	release := bindContext(ctx, collector)
+	defer release()
+
+	// Log the HAC requests and responses.
+	logHACEvents(logCtx, collector)
+
 	// Create a channel to pass the request verification token into from HTML.
 	reqVerChan := make(chan string, 1)
 
@@ -35,11 +98,11 @@ func login(url, username, password string) (*colly.Collector, error) {
 
 	// Handle any errors.
 	collector.OnError(func(r *colly.Response, err error) {
-		errChan <- err
+		errChan <- fmt.Errorf("%w: %v", ErrorServerUnreachable, err)
 	})
 
 	// Form login URL.
-	loginURL := url + repository.LOGIN_ROUTE
+	loginURL := url + profile.Routes.Login
 
 	// Get request verification token, abort if there are any errors.
 	err := collector.Visit(loginURL)
@@ -49,7 +112,15 @@ func login(url, username, password string) (*colly.Collector, error) {
 		return nil, err
 	}
 
+	// If the context was cancelled or timed out, return why.
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
 	collector = collector.Clone()
+	releaseClone := bindContext(ctx, collector)
+	defer releaseClone()
+	logHACEvents(logCtx, collector)
 
 	// Get Request Verification Token or return any errors.
 	var reqVerToken string
@@ -60,31 +131,30 @@ func login(url, username, password string) (*colly.Collector, error) {
 	// Error obtained.
 	case err := <-errChan:
 		return nil, err
+	// The page loaded, but had no token.
+	default:
+		return nil, ErrorUnexpectedLoginPage
 	}
 
 	// Create payload data.
-	payload := map[string]string{
-		"__RequestVerificationToken": reqVerToken,
-		"LogOnDetails.UserName":      username,
-		"LogOnDetails.Password":      password,
-		"SCKTY00328510CustomEnabled": "true",
-		"SCKTY00436568CustomEnabled": "true",
-		"Database":                   "10",
-		"VerificationOption":         "UsernamePassword",
-		"tempUN":                     "",
-		"tempPW":                     "",
+	payload := make(map[string]string, len(profile.LoginFields)+3)
+	for field, value := range profile.LoginFields {
+		payload[field] = value
 	}
+	payload["__RequestVerificationToken"] = reqVerToken
+	payload["LogOnDetails.UserName"] = username
+	payload["LogOnDetails.Password"] = password
 
-	// Channel to signal whether the login failed or not.
-	loginWrongChan := make(chan bool, 1)
+	// Channel to signal why the login failed, if it did.
+	loginWrongChan := make(chan error, 1)
 
 	// Form the expected URL to be redirected to after the request.
-	expectedURL := url + "/HomeAccess/Classes/Classwork"
+	expectedURL := url + profile.LoginRedirect
 
 	// Check if we are at expected URL. If not, the login has failed.
 	collector.OnResponse(func(res *colly.Response) {
 		if res.Request.URL.String() != expectedURL {
-			loginWrongChan <- true
+			loginWrongChan <- loginFailure(res)
 		}
 	})
 
@@ -98,7 +168,7 @@ func login(url, username, password string) (*colly.Collector, error) {
 
 	// Handle errors.
 	collector.OnError(func(r *colly.Response, err error) {
-		errChan <- err
+		errChan <- fmt.Errorf("%w: %v", ErrorServerUnreachable, err)
 	})
 
 	// Post to login.
@@ -110,11 +180,17 @@ func login(url, username, password string) (*colly.Collector, error) {
 		return nil, err
 	}
 
+	// If the context was cancelled or timed out, return why.
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
 	// Handle any errors.
 	select {
-	// Credentials were wrong.
-	case <-loginWrongChan:
-		return nil, ErrorInvalidCredentials
+	// Login was rejected.
+	case err := <-loginWrongChan:
+		logging.Log(logCtx, logging.LevelInfo, "hac login rejected", logging.Fields{"error": err})
+		return nil, err
 	// Other error.
 	case err := <-errChan:
 		return nil, err
@@ -124,3 +200,24 @@ func login(url, username, password string) (*colly.Collector, error) {
 	// Return logged-in collector.
 	return collector, nil
 }
+
+// loginFailure works out why HAC rejected a login from the page it responded with.
+func loginFailure(res *colly.Response) error {
+	html, err := goquery.NewDocumentFromReader(bytes.NewReader(res.Body))
+	if err != nil {
+		return ErrorUnexpectedLoginPage
+	}
+
+	// Being sent back to the login form means the login was rejected.
+	if html.Find("input[name='__RequestVerificationToken']").Length() == 0 {
+		return ErrorUnexpectedLoginPage
+	}
+
+	// Check the validation messages for a locked account.
+	validation := html.Find(".validation-summary-errors, .field-validation-error").Text()
+	if strings.Contains(strings.ToLower(validation), "locked") {
+		return ErrorAccountLocked
+	}
+
+	return ErrorInvalidCredentials
+}
@@ -0,0 +1,77 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/Threqt1/HACApi/pkg/repository"
+	"github.com/Threqt1/HACApi/platform/metrics"
+)
+
+// hacEndpoint returns the name of the HAC page an endpoint points to, which
+// labels its metrics. Endpoints are never used as labels themselves, so
+// nothing a user sent can end up in a metric.
+func hacEndpoint(profile repository.DistrictProfile, endpoint string) string {
+	// Strip the query string, like the start date of the week view.
+	endpoint, _, _ = strings.Cut(endpoint, "?")
+
+	routes := map[string]string{
+		"login":      profile.Routes.Login,
+		"classwork":  profile.Routes.Classwork,
+		"schedule":   profile.Routes.Schedule,
+		"ipr":        profile.Routes.IPR,
+		"reportcard": profile.Routes.ReportCard,
+		"transcript": profile.Routes.Transcript,
+		"weekview":   profile.Routes.WeekView,
+	}
+	for name, route := range routes {
+		if route, _, _ := strings.Cut(route, "?"); strings.EqualFold(route, endpoint) {
+			return name
+		}
+	}
+	return "other"
+}
+
+// hacErrorReason sorts why a request to HAC failed into a few reasons.
+func hacErrorReason(err error) string {
+	switch {
+	case errors.Is(err, ErrorSessionExpired):
+		return "session_expired"
+	case errors.Is(err, context.Canceled):
+		return "canceled"
+	case errors.Is(err, context.DeadlineExceeded):
+		return "timeout"
+	case errors.Is(err, ErrorPageNotAvaliable):
+		return "page_not_available"
+	case isTransient(err), errors.Is(err, ErrorServerUnreachable), errors.As(err, &HTTPStatusError{}):
+		return "unreachable"
+	}
+	return "error"
+}
+
+// observeHACRequest records how long a request to HAC took, and why it failed if it did.
+func observeHACRequest(endpoint string, start time.Time, err error) {
+	metrics.HACRequestDuration.Observe(time.Since(start).Seconds(), endpoint)
+	if err != nil {
+		metrics.HACRequestErrors.Inc(endpoint, hacErrorReason(err))
+	}
+}
+
+// loginResult sorts the outcome of a login into a few results.
+func loginResult(err error) string {
+	switch {
+	case err == nil:
+		return "success"
+	case errors.Is(err, ErrorInvalidCredentials):
+		return "invalid_credentials"
+	case errors.Is(err, ErrorAccountLocked):
+		return "account_locked"
+	case errors.Is(err, ErrorUnexpectedLoginPage):
+		return "unexpected_page"
+	case errors.Is(err, ErrorServerUnreachable):
+		return "unreachable"
+	}
+	return "error"
+}
@@ -0,0 +1,128 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/Threqt1/HACApi/pkg/repository"
+	"github.com/Threqt1/HACApi/platform/metrics"
+	"github.com/google/go-cmp/cmp"
+)
+
+// Test if endpoints are labelled by the HAC page they point to.
+func TestHACEndpoint(t *testing.T) {
+	profile := repository.DefaultDistrictProfile()
+
+	tests := map[string]string{
+		repository.LOGIN_ROUTE:                          "login",
+		repository.CLASSWORK_ROUTE:                      "classwork",
+		repository.SCHEDULE_ROUTE:                       "schedule",
+		repository.IPR_ROUTE:                            "ipr",
+		repository.REPORT_CARD_ROUTE:                    "reportcard",
+		repository.TRANSCRIPT_ROUTE:                     "transcript",
+		repository.WEEK_VIEW_ROUTE + "?startDate=1/2/3": "weekview",
+		"/HomeAccess/Unknown?user=ABC":                  "other",
+	}
+
+	for endpoint, expected := range tests {
+		if diff := cmp.Diff(expected, hacEndpoint(profile, endpoint)); diff != "" {
+			t.Fatalf("Failed for hacEndpoint(%q) (-want, +got):\n%s", endpoint, diff)
+		}
+	}
+}
+
+// Test if errors are sorted into reasons.
+func TestHACErrorReason(t *testing.T) {
+	tests := []struct {
+		Err      error
+		Expected string
+	}{
+		{Err: ErrorSessionExpired, Expected: "session_expired"},
+		{Err: context.Canceled, Expected: "canceled"},
+		{Err: context.DeadlineExceeded, Expected: "timeout"},
+		{Err: ErrorPageNotAvaliable, Expected: "page_not_available"},
+		{Err: HTTPStatusError{StatusCode: 500}, Expected: "unreachable"},
+		{Err: fmt.Errorf("%w: refused", ErrorServerUnreachable), Expected: "unreachable"},
+		{Err: ErrorBadHTML, Expected: "error"},
+	}
+
+	for _, test := range tests {
+		if diff := cmp.Diff(test.Expected, hacErrorReason(test.Err)); diff != "" {
+			t.Fatalf("Failed for hacErrorReason(%v) (-want, +got):\n%s", test.Err, diff)
+		}
+	}
+}
+
+// Test if login outcomes are sorted into results.
+func TestLoginResult(t *testing.T) {
+	tests := []struct {
+		Err      error
+		Expected string
+	}{
+		{Err: nil, Expected: "success"},
+		{Err: ErrorInvalidCredentials, Expected: "invalid_credentials"},
+		{Err: ErrorAccountLocked, Expected: "account_locked"},
+		{Err: ErrorUnexpectedLoginPage, Expected: "unexpected_page"},
+		{Err: fmt.Errorf("%w: refused", ErrorServerUnreachable), Expected: "unreachable"},
+		{Err: context.Canceled, Expected: "error"},
+	}
+
+	for _, test := range tests {
+		if diff := cmp.Diff(test.Expected, loginResult(test.Err)); diff != "" {
+			t.Fatalf("Failed for loginResult(%v) (-want, +got):\n%s", test.Err, diff)
+		}
+	}
+}
+
+// Test if logins, probes and requests to HAC are recorded, without the credentials ending up in any label.
+func TestScraper_Metrics(t *testing.T) {
+	// Create testing server and scraper.
+	ts := CreateTestingServer()
+	defer ts.Close()
+
+	scraper := NewScraper(nil, nil)
+
+	// Log in with credentials which must not leak, and navigate.
+	if _, err := scraper.Login(context.Background(), ts.URL, "leaky-username", "leaky-password"); err != ErrorInvalidCredentials {
+		t.Fatalf("Failed for Login() with invalid credentials:\n%v", err)
+	}
+	collector, err := scraper.Login(context.Background(), ts.URL, "ABC", "123")
+	if err != nil {
+		t.Fatalf("Failed for Login() with valid credentials:\n%v", err)
+	}
+	if _, _, err := scraper.Navigate(context.Background(), collector, ts.URL, "/default"); err != nil {
+		t.Fatalf("Failed for Navigate():\n%v", err)
+	}
+	if _, _, err := scraper.Navigate(context.Background(), collector, ts.URL, "/broken"); err == nil {
+		t.Fatalf("Failed for Navigate() with a broken page, expected an error")
+	}
+	if err := scraper.Probe(context.Background(), ts.URL); err != nil {
+		t.Fatalf("Failed for Probe():\n%v", err)
+	}
+
+	var output strings.Builder
+	if err := metrics.Default.Write(&output); err != nil {
+		t.Fatalf("Failed for Write():\n%v", err)
+	}
+
+	for _, expected := range []string{
+		`hacapi_logins_total{result="success"}`,
+		`hacapi_logins_total{result="invalid_credentials"}`,
+		`hacapi_hac_request_duration_seconds_count{endpoint="login"}`,
+		`hacapi_hac_request_duration_seconds_count{endpoint="probe"}`,
+		`hacapi_hac_request_duration_seconds_count{endpoint="other"}`,
+		`hacapi_hac_request_errors_total{endpoint="other",reason="unreachable"}`,
+	} {
+		if !strings.Contains(output.String(), expected) {
+			t.Fatalf("Failed for scraper metrics, expected %q in:\n%s", expected, output.String())
+		}
+	}
+
+	for _, leaked := range []string{"leaky-username", "leaky-password", ts.URL} {
+		if strings.Contains(output.String(), leaked) {
+			t.Fatalf("Failed for scraper metrics, %q leaked into:\n%s", leaked, output.String())
+		}
+	}
+}
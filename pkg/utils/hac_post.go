@@ -1,22 +1,39 @@
 package utils
 
 import (
+	"context"
 	"strings"
 
 	"github.com/PuerkitoBio/goquery"
+	"github.com/Threqt1/HACApi/platform/logging"
 	"github.com/gocolly/colly"
 )
 
 // post posts to a given endpoint with the given formdata, handling failures and returning HTML.
-func post(collector *colly.Collector, url, endpoint string, formData map[string]string) (*colly.Collector, *goquery.Selection, error) {
+func post(ctx context.Context, collector *colly.Collector, url, endpoint, loginRoute string, formData map[string]string) (*colly.Collector, *goquery.Selection, error) {
 	// Form URL.
 	formedUrl := url + endpoint
 
+	// Abort if the request was already cancelled.
+	if err := ctx.Err(); err != nil {
+		return nil, nil, err
+	}
+
 	collector = collector.Clone()
 
+	// Cancel the HAC request along with the context.
+	release := bindContext(ctx, collector)
+	defer release()
+
+	// Log the HAC request and response.
+	logHACEvents(ctx, collector)
+
 	// Make a channel to signal if the page is avaliable.
 	pageAvaliableChan := make(chan bool, 1)
 
+	// Make a channel to signal if the page redirected due to an expired session.
+	sessionExpiredChan := make(chan bool, 1)
+
 	// Make a channel to signal any errors.
 	errChan := make(chan error, 1)
 
@@ -28,6 +45,8 @@ func post(collector *colly.Collector, url, endpoint string, formData map[string]
 		// If final URL is not equal to input URL, the request failed.
 		if res.Request.URL.String() != formedUrl {
 			pageAvaliableChan <- false
+			// Being sent back to the LogOn page means the session expired.
+			sessionExpiredChan <- isLoginRedirect(res.Request.URL, loginRoute)
 		}
 	})
 
@@ -38,7 +57,7 @@ func post(collector *colly.Collector, url, endpoint string, formData map[string]
 
 	// Handle any errors.
 	collector.OnError(func(r *colly.Response, err error) {
-		errChan <- err
+		errChan <- responseError(r, err)
 	})
 
 	// Set request headers.
@@ -57,10 +76,20 @@ func post(collector *colly.Collector, url, endpoint string, formData map[string]
 		return nil, nil, err
 	}
 
+	// If the context was cancelled or timed out, return why.
+	if err := ctx.Err(); err != nil {
+		return nil, nil, err
+	}
+
 	// Handle errors
 	select {
 	// Page not avaliable.
 	case <-pageAvaliableChan:
+		if <-sessionExpiredChan {
+			logging.Log(ctx, logging.LevelInfo, "hac session expired", logging.Fields{"endpoint": endpoint})
+			return nil, nil, ErrorSessionExpired
+		}
+		logging.Log(ctx, logging.LevelWarn, "hac page not available", logging.Fields{"endpoint": endpoint})
 		return nil, nil, ErrorPageNotAvaliable
 	// Colly error.
 	case err := <-errChan:
@@ -0,0 +1,74 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/Threqt1/HACApi/pkg/repository"
+	"github.com/Threqt1/HACApi/platform/logging"
+	"github.com/gocolly/colly"
+)
+
+// probe checks that a district's LogOn page can be reached, and still
+// contains the request verification token logging in relies on.
+func probe(ctx context.Context, url string, profile repository.DistrictProfile, transport http.RoundTripper) error {
+	// Abort if the request was already cancelled.
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	// Create a new Colly collector.
+	collector := newCollector(url, transport)
+
+	// Cancel the HAC request along with the context.
+	release := bindContext(ctx, collector)
+	defer release()
+
+	// Log the HAC request and response.
+	logHACEvents(logging.WithFields(ctx, logging.Fields{"district": logging.District(url)}), collector)
+
+	// Create a channel to signal the token was found.
+	tokenChan := make(chan bool, 1)
+
+	// Create a channel to signal any errors.
+	errChan := make(chan error, 1)
+
+	// Look for the request verification token.
+	collector.OnHTML("input[name='__RequestVerificationToken']", func(elem *colly.HTMLElement) {
+		select {
+		case tokenChan <- true:
+		default:
+		}
+	})
+
+	// Handle any errors.
+	collector.OnError(func(r *colly.Response, err error) {
+		errChan <- fmt.Errorf("%w: %v", ErrorServerUnreachable, responseError(r, err))
+	})
+
+	// Visit the LogOn page and wait.
+	err := collector.Visit(url + profile.Routes.Login)
+	collector.Wait()
+
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrorServerUnreachable, err)
+	}
+
+	// If the context was cancelled or timed out, return why.
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	select {
+	// Token found.
+	case <-tokenChan:
+		return nil
+	// Colly error.
+	case err := <-errChan:
+		return err
+	// The page loaded, but had no token.
+	default:
+		return ErrorUnexpectedLoginPage
+	}
+}
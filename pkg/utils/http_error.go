@@ -0,0 +1,71 @@
+package utils
+
+import (
+	"reflect"
+	"strings"
+	"unicode"
+
+	"github.com/Threqt1/HACApi/app/models"
+	"github.com/Threqt1/HACApi/pkg/repository"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+)
+
+// The key the request ID is stored under in the request's locals.
+const RequestIDKey = "requestid"
+
+// RequestID returns the ID of a request, or an empty string if it has none.
+func RequestID(ctx *fiber.Ctx) string {
+	requestID, _ := ctx.Locals(RequestIDKey).(string)
+	return requestID
+}
+
+// NewHTTPError creates the error attached to a response, with its machine-readable code
+// and the ID of the request. fields lists the body parameters which are invalid, if any.
+func NewHTTPError(ctx *fiber.Ctx, err error, fields ...string) models.HTTPError {
+	return NewHTTPErrorForRequest(RequestID(ctx), err, fields...)
+}
+
+// NewHTTPErrorForRequest works like NewHTTPError, for when the request's context
+// can't be used anymore, like while streaming the response.
+func NewHTTPErrorForRequest(requestID string, err error, fields ...string) models.HTTPError {
+	return models.HTTPError{
+		Error:     true,
+		Message:   err.Error(),
+		Code:      repository.ErrorCodeFor(err),
+		Fields:    fields,
+		RequestID: requestID,
+	}
+}
+
+// NewValidator creates a validator which names fields in errors by their JSON names.
+func NewValidator() *validator.Validate {
+	validate := validator.New()
+	validate.RegisterTagNameFunc(func(field reflect.StructField) string {
+		return strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+	})
+	return validate
+}
+
+// ValidationFields returns the paths of the fields which failed validation, like "requests[0].resource".
+func ValidationFields(err error) []string {
+	validationErrors, ok := err.(validator.ValidationErrors)
+	if !ok {
+		return nil
+	}
+
+	fields := make([]string, 0, len(validationErrors))
+	for _, fieldError := range validationErrors {
+		// Leave out the request body itself, and the structs embedded in it, whose names are upper case unlike JSON names.
+		segments := strings.Split(fieldError.Namespace(), ".")[1:]
+		path := make([]string, 0, len(segments))
+		for _, segment := range segments {
+			if segment != "" && !unicode.IsUpper(rune(segment[0])) {
+				path = append(path, segment)
+			}
+		}
+		fields = append(fields, strings.Join(path, "."))
+	}
+
+	return fields
+}
@@ -0,0 +1,94 @@
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Threqt1/HACApi/app/models"
+	"github.com/Threqt1/HACApi/pkg/repository"
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/requestid"
+	"github.com/google/go-cmp/cmp"
+)
+
+// Test if NewHTTPError() attaches the error's code and the request's ID.
+func TestNewHTTPError(t *testing.T) {
+	// Set up a server tagging requests with a fixed ID.
+	app := fiber.New()
+	app.Use(requestid.New(requestid.Config{
+		ContextKey: RequestIDKey,
+		Generator:  func() string { return "ABC123" },
+	}))
+	app.Get("/", func(ctx *fiber.Ctx) error {
+		return ctx.JSON(NewHTTPError(ctx, fmt.Errorf("%w: %s", repository.ErrorInvalidBase, ErrorBaseInsecure), "base"))
+	})
+
+	// Test the request.
+	resp, _ := app.Test(httptest.NewRequest("GET", "http://fake.url/", nil))
+
+	resBody, _ := io.ReadAll(resp.Body)
+	got := models.HTTPError{}
+	json.Unmarshal(resBody, &got)
+
+	expected := models.HTTPError{
+		Error:     true,
+		Message:   fmt.Sprintf("%s: %s", repository.ErrorInvalidBase, ErrorBaseInsecure),
+		Code:      models.ErrorCodeBadRequestField,
+		Fields:    []string{"base"},
+		RequestID: "ABC123",
+	}
+
+	if diff := cmp.Diff(expected, got); diff != "" {
+		t.Fatalf("Failed for NewHTTPError() (-want, +got)\n%s", diff)
+	}
+
+	if diff := cmp.Diff("ABC123", resp.Header.Get(fiber.HeaderXRequestID)); diff != "" {
+		t.Fatalf("Failed for NewHTTPError() request ID header (-want, +got)\n%s", diff)
+	}
+}
+
+// Test the codes attached to the errors responded with.
+func TestErrorCodeFor(t *testing.T) {
+	cases := map[error]models.ErrorCode{
+		repository.ErrorBadBodyParams:         models.ErrorCodeBadRequestField,
+		repository.ErrorInvalidAuthentication: models.ErrorCodeInvalidCredentials,
+		repository.ErrorInvalidSession:        models.ErrorCodeSessionExpired,
+		repository.ErrorAccountLocked:         models.ErrorCodeAccountLocked,
+		repository.ErrorServerUnreachable:     models.ErrorCodeHACUnavailable,
+		repository.ErrorUnexpectedLoginPage:   models.ErrorCodePageLayoutChanged,
+		repository.ErrorPageLayoutChanged:     models.ErrorCodePageLayoutChanged,
+		repository.ErrorRateLimited:           models.ErrorCodeRateLimited,
+		repository.ErrorRequestTimeout:        models.ErrorCodeTimeout,
+		repository.ErrorRequestCanceled:       models.ErrorCodeCanceled,
+		repository.ErrorSessionExpired:        models.ErrorCodeSessionExpired,
+		repository.ErrorEndpointNotFound:      models.ErrorCodeNotFound,
+		repository.ErrorInternalError:         models.ErrorCodeInternal,
+	}
+
+	for input, expected := range cases {
+		if diff := cmp.Diff(expected, repository.ErrorCodeFor(input)); diff != "" {
+			t.Fatalf("Failed for ErrorCodeFor() with error %v (-want, +got)\n%s", input, diff)
+		}
+	}
+}
+
+// testHTTPError_Body represents a request body with nested and embedded fields.
+type testHTTPError_Body struct {
+	models.BaseRequestBody
+	Requests []models.BatchResourceRequest `json:"requests" validate:"dive"`
+}
+
+// Test if ValidationFields() names failing fields by their JSON paths.
+func TestValidationFields(t *testing.T) {
+	err := NewValidator().Struct(testHTTPError_Body{
+		BaseRequestBody: models.BaseRequestBody{Username: "ABC", Password: "123"},
+		Requests:        []models.BatchResourceRequest{{Resource: models.BatchResourceSchedule}, {Resource: "unknown"}},
+	})
+
+	if diff := cmp.Diff([]string{"base", "requests[1].resource"}, ValidationFields(err)); diff != "" {
+		t.Fatalf("Failed for ValidationFields() (-want, +got)\n%s", diff)
+	}
+}
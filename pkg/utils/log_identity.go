@@ -0,0 +1,16 @@
+package utils
+
+import (
+	"github.com/Threqt1/HACApi/platform/logging"
+	"github.com/gofiber/fiber/v2"
+)
+
+// SetLogIdentity tags the logs of a request, including the HAC requests made
+// for it, with the district and a hash of the user. The username itself is
+// never logged.
+func SetLogIdentity(ctx *fiber.Ctx, username, base string) {
+	ctx.SetUserContext(logging.WithFields(ctx.UserContext(), logging.Fields{
+		"district": logging.District(base),
+		"user":     logging.HashUser(username, base),
+	}))
+}
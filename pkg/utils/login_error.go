@@ -0,0 +1,26 @@
+package utils
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Threqt1/HACApi/pkg/repository"
+	"github.com/gofiber/fiber/v2"
+)
+
+// LoginErrorResponse maps an error from logging in to the
+// status code and error to respond with.
+func LoginErrorResponse(err error) (int, error) {
+	switch {
+	case errors.Is(err, context.DeadlineExceeded), errors.Is(err, context.Canceled):
+		return fiber.StatusGatewayTimeout, repository.ErrorRequestTimeout
+	case errors.Is(err, ErrorServerUnreachable):
+		return fiber.StatusServiceUnavailable, repository.ErrorServerUnreachable
+	case errors.Is(err, ErrorUnexpectedLoginPage):
+		return fiber.StatusBadGateway, repository.ErrorUnexpectedLoginPage
+	case errors.Is(err, ErrorAccountLocked):
+		return fiber.StatusForbidden, repository.ErrorAccountLocked
+	default:
+		return fiber.StatusBadRequest, repository.ErrorInvalidAuthentication
+	}
+}
@@ -0,0 +1,40 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/Threqt1/HACApi/pkg/repository"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+// testLoginError_Test represents an expected output from LoginErrorResponse().
+type testLoginError_Test struct {
+	Status int
+	Error  error
+}
+
+// TestLoginErrorResponse tests LoginErrorResponse() for every kind of login error.
+func TestLoginErrorResponse(t *testing.T) {
+	cases := map[error]testLoginError_Test{
+		ErrorInvalidCredentials:                           {Status: fiber.StatusBadRequest, Error: repository.ErrorInvalidAuthentication},
+		repository.ErrorInvalidAuthentication:             {Status: fiber.StatusBadRequest, Error: repository.ErrorInvalidAuthentication},
+		fmt.Errorf("%w: timeout", ErrorServerUnreachable): {Status: fiber.StatusServiceUnavailable, Error: repository.ErrorServerUnreachable},
+		ErrorUnexpectedLoginPage:                          {Status: fiber.StatusBadGateway, Error: repository.ErrorUnexpectedLoginPage},
+		ErrorAccountLocked:                                {Status: fiber.StatusForbidden, Error: repository.ErrorAccountLocked},
+		context.DeadlineExceeded:                          {Status: fiber.StatusGatewayTimeout, Error: repository.ErrorRequestTimeout},
+		context.Canceled:                                  {Status: fiber.StatusGatewayTimeout, Error: repository.ErrorRequestTimeout},
+	}
+
+	for input, expected := range cases {
+		// Test.
+		status, err := LoginErrorResponse(input)
+
+		if diff := cmp.Diff(expected, testLoginError_Test{Status: status, Error: err}, cmpopts.EquateErrors()); diff != "" {
+			t.Fatalf("Failed for LoginErrorResponse() with error %v (-want, +got)\n%s", input, diff)
+		}
+	}
+}
@@ -1,17 +1,21 @@
 package utils
 
 import (
+	"context"
 	"errors"
+	"sort"
 	"sync"
 
 	"github.com/PuerkitoBio/goquery"
 	"github.com/Threqt1/HACApi/pkg/repository"
+	"github.com/Threqt1/HACApi/platform/metrics"
 	"github.com/gocolly/colly"
 )
 
 // pipelineResponse represents a intermediary
 // response between pipelines.
 type pipelineResponse[T any] struct {
+	Index int   // The position of the response's data piece, used for ordering.
 	Value T     // The value of the response.
 	Err   error // An error, if any.
 }
@@ -45,54 +49,127 @@ type PipelineRecievedValue[V any] interface {
 // Represents an error due to no found HTML.
 var ErrorBadHTML = errors.New("bad html")
 
+// PipelineError represents a piece of data the pipeline
+// failed to gather, when partial results are allowed.
+type PipelineError struct {
+	Index int   // The position of the failed data piece.
+	Err   error // Why the data piece failed.
+}
+
 // GeneratePipeline creates a new pipeline which will gather data from a POST request, parse it, and return it in an array format. T represents the model struct,
-// V represents the recieved value's type.
-func GeneratePipeline[T any, V any](scraper repository.ScraperProvider, collector *colly.Collector, data []V, recievedInfo PipelineRecievedValue[V], formData *PartialFormData, functions PipelineFunctions[T, V]) ([]T, error) {
-	// Make a done channel for cancelling on error.
-	doneChan := make(chan struct{})
-	defer close(doneChan)
+// V represents the recieved value's type. The returned array follows the order of data, regardless of the order the requests finish in.
+// Cancelling ctx stops the pipeline, and cancels any in-flight requests. At most workers POST requests are made at once, or unlimited if 0.
+func GeneratePipeline[T any, V any](ctx context.Context, scraper repository.ScraperProvider, collector *colly.Collector, workers int, data []V, recievedInfo PipelineRecievedValue[V], formData *PartialFormData, functions PipelineFunctions[T, V]) ([]T, error) {
+	dataArray, _, err := generatePipeline(ctx, scraper, collector, workers, data, recievedInfo, formData, functions, false, nil)
+	return dataArray, err
+}
+
+// GeneratePartialPipeline works like GeneratePipeline, except a data piece failing doesn't fail the whole pipeline. The returned array
+// only holds the data pieces which succeeded, in the order of data, and the ones which failed are returned as errors. An expired session,
+// ctx being cancelled or the recieved value failing without data still fails the whole pipeline.
+func GeneratePartialPipeline[T any, V any](ctx context.Context, scraper repository.ScraperProvider, collector *colly.Collector, workers int, data []V, recievedInfo PipelineRecievedValue[V], formData *PartialFormData, functions PipelineFunctions[T, V]) ([]T, []PipelineError, error) {
+	return generatePipeline(ctx, scraper, collector, workers, data, recievedInfo, formData, functions, true, nil)
+}
+
+// StreamPipeline works like GeneratePipeline, or GeneratePartialPipeline if partial is true, except each data piece is passed to emit
+// along with its position in data as soon as it is parsed, instead of being returned. Data pieces are emitted in the order they finish in.
+func StreamPipeline[T any, V any](ctx context.Context, scraper repository.ScraperProvider, collector *colly.Collector, workers int, data []V, recievedInfo PipelineRecievedValue[V], formData *PartialFormData, functions PipelineFunctions[T, V], partial bool, emit func(int, T)) ([]PipelineError, error) {
+	_, pipelineErrors, err := generatePipeline(ctx, scraper, collector, workers, data, recievedInfo, formData, functions, partial, emit)
+	return pipelineErrors, err
+}
+
+// generatePipeline runs the pipeline, failing on the first error unless partial is true. If emit isn't nil, data pieces are passed to it as they are parsed.
+func generatePipeline[T any, V any](ctx context.Context, scraper repository.ScraperProvider, collector *colly.Collector, workers int, data []V, recievedInfo PipelineRecievedValue[V], formData *PartialFormData, functions PipelineFunctions[T, V], partial bool, emit func(int, T)) ([]T, []PipelineError, error) {
+	// Make a context for cancelling on error.
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// Record how many requests the pipeline fans out to.
+	metrics.PipelineFanOut.Observe(float64(len(data)))
 
 	// Recieve parsed data.
-	parsedDataChan := pipelineParseHTML(scraper, collector, doneChan, data, recievedInfo, formData, functions)
+	parsedDataChan := pipelineParseHTML(ctx, scraper, collector, workers, data, recievedInfo, formData, functions, partial)
 
-	// Append recieved data to array.
-	dataArray := make([]T, 0, len(data))
+	// Store recieved data at its position in the array, there is only the recieved value if there is no data.
+	dataArray := make([]T, len(data))
+	if len(data) == 0 {
+		dataArray = make([]T, 1)
+	}
+	failed := make([]bool, len(dataArray))
+	var pipelineErrors []PipelineError
 
 	for res := range parsedDataChan {
-		// If there's an error, cancel.
+		// If there's an error, cancel, unless only this data piece failed.
 		if res.Err != nil {
-			return nil, res.Err
+			if !partial || len(data) == 0 || errors.Is(res.Err, ErrorSessionExpired) {
+				return nil, nil, res.Err
+			}
+
+			failed[res.Index] = true
+			pipelineErrors = append(pipelineErrors, PipelineError{Index: res.Index, Err: res.Err})
+			continue
 		}
 
-		dataArray = append(dataArray, res.Value)
+		dataArray[res.Index] = res.Value
+
+		if emit != nil {
+			emit(res.Index, res.Value)
+		}
 	}
 
-	return dataArray, nil
+	// If the pipeline stopped because ctx was cancelled, the data is incomplete.
+	if err := ctx.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	// Leave out the data pieces which failed, and order their errors like data.
+	if len(pipelineErrors) > 0 {
+		succeeded := make([]T, 0, len(dataArray)-len(pipelineErrors))
+		for i, value := range dataArray {
+			if !failed[i] {
+				succeeded = append(succeeded, value)
+			}
+		}
+		dataArray = succeeded
+
+		sort.Slice(pipelineErrors, func(i, j int) bool {
+			return pipelineErrors[i].Index < pipelineErrors[j].Index
+		})
+	}
+
+	return dataArray, pipelineErrors, nil
 }
 
 // pipelineParseHTML represents the step in the pipeline where raw HTML is recieved through a channel, parsed, and emitted out through another channel.
-func pipelineParseHTML[T any, V any](scraper repository.ScraperProvider, collector *colly.Collector, doneChan <-chan struct{}, data []V, recievedInfo PipelineRecievedValue[V], formData *PartialFormData, functions PipelineFunctions[T, V]) chan pipelineResponse[T] {
+// Unless partial is true, it stops at the first error.
+func pipelineParseHTML[T any, V any](ctx context.Context, scraper repository.ScraperProvider, collector *colly.Collector, workers int, data []V, recievedInfo PipelineRecievedValue[V], formData *PartialFormData, functions PipelineFunctions[T, V], partial bool) chan pipelineResponse[T] {
 	// Make a channel to emit parsed data/errors.
 	parsedDataChan := make(chan pipelineResponse[T])
 
 	go func() {
 		// Recieve raw HTML.
-		rawHTMLChan := pipelineGetHTML(scraper, collector, doneChan, data, recievedInfo, formData, functions)
+		rawHTMLChan := pipelineGetHTML(ctx, scraper, collector, workers, data, recievedInfo, formData, functions)
 
 		var wg sync.WaitGroup
 
 		// Parse HTML concurrently.
 		for res := range rawHTMLChan {
-			// If error, cascade it down and break.
-			if res.Err != nil {
-				parsedDataChan <- pipelineResponse[T]{Err: res.Err}
-				break
-			}
+			// If error or no HTML, cascade it down, and break unless partial results are allowed.
+			if res.Err != nil || res.Value == nil {
+				err := res.Err
+				if err == nil {
+					err = ErrorBadHTML
+				}
+
+				select {
+				case parsedDataChan <- pipelineResponse[T]{Index: res.Index, Err: err}:
+				case <-ctx.Done():
+				}
 
-			// If no HTML, error out.
-			if res.Value == nil {
-				parsedDataChan <- pipelineResponse[T]{Err: ErrorBadHTML}
-				break
+				if !partial {
+					break
+				}
+				continue
 			}
 
 			// Otherwise, start goroutine to parse.
@@ -100,9 +177,9 @@ func pipelineParseHTML[T any, V any](scraper repository.ScraperProvider, collect
 			go func(res pipelineResponse[*goquery.Selection]) {
 				defer wg.Done()
 
-				// Check if done was called.
+				// Check if the pipeline was cancelled.
 				select {
-				case <-doneChan:
+				case <-ctx.Done():
 					return
 				default:
 				}
@@ -112,8 +189,8 @@ func pipelineParseHTML[T any, V any](scraper repository.ScraperProvider, collect
 
 				// Try emitting parsed data.
 				select {
-				case parsedDataChan <- pipelineResponse[T]{Value: parsedData, Err: nil}:
-				case <-doneChan:
+				case parsedDataChan <- pipelineResponse[T]{Index: res.Index, Value: parsedData, Err: nil}:
+				case <-ctx.Done():
 				}
 			}(res)
 		}
@@ -129,7 +206,7 @@ func pipelineParseHTML[T any, V any](scraper repository.ScraperProvider, collect
 }
 
 // pipelineGetHTML represents the step in the pipeline where raw HTML is gathered using POST requests, and emitted out using a channel.
-func pipelineGetHTML[T any, V any](scraper repository.ScraperProvider, collector *colly.Collector, doneChan <-chan struct{}, data []V, recievedInfo PipelineRecievedValue[V], formData *PartialFormData, functions PipelineFunctions[T, V]) chan pipelineResponse[*goquery.Selection] {
+func pipelineGetHTML[T any, V any](ctx context.Context, scraper repository.ScraperProvider, collector *colly.Collector, workers int, data []V, recievedInfo PipelineRecievedValue[V], formData *PartialFormData, functions PipelineFunctions[T, V]) chan pipelineResponse[*goquery.Selection] {
 	// Make channel for outputting raw HTML.
 	rawHTMLChan := make(chan pipelineResponse[*goquery.Selection])
 
@@ -141,20 +218,26 @@ func pipelineGetHTML[T any, V any](scraper repository.ScraperProvider, collector
 		if len(data) == 0 {
 			select {
 			case rawHTMLChan <- pipelineResponse[*goquery.Selection]{Value: recievedInfo.Html(), Err: nil}:
-			case <-doneChan:
+			case <-ctx.Done():
 			}
 		}
 
+		// Make a semaphore to limit the amount of concurrent POST requests.
+		var workerChan chan struct{}
+		if workers > 0 {
+			workerChan = make(chan struct{}, workers)
+		}
+
 		// Scrape in parallel.
-		for _, piece := range data {
+		for i, piece := range data {
 			wg.Add(1)
 
-			go func(piece V) {
+			go func(i int, piece V) {
 				defer wg.Done()
 
-				// Check if done's been called.
+				// Check if the pipeline was cancelled.
 				select {
-				case <-doneChan:
+				case <-ctx.Done():
 					return
 				default:
 				}
@@ -166,15 +249,25 @@ func pipelineGetHTML[T any, V any](scraper repository.ScraperProvider, collector
 				if recievedInfo.Equal(piece) {
 					html = recievedInfo.Html()
 				} else {
-					_, html, err = scraper.Post(collector, formData.Base, formData.Url, functions.GenFormData(functions.ToFormData(piece), *formData))
+					// Wait for a free worker, unless the pipeline is cancelled first.
+					if workerChan != nil {
+						select {
+						case workerChan <- struct{}{}:
+							defer func() { <-workerChan }()
+						case <-ctx.Done():
+							return
+						}
+					}
+
+					_, html, err = scraper.Post(ctx, collector, formData.Base, formData.Url, functions.GenFormData(functions.ToFormData(piece), *formData))
 				}
 
 				// Try emitting HTML to channel.
 				select {
-				case rawHTMLChan <- pipelineResponse[*goquery.Selection]{Value: html, Err: err}:
-				case <-doneChan:
+				case rawHTMLChan <- pipelineResponse[*goquery.Selection]{Index: i, Value: html, Err: err}:
+				case <-ctx.Done():
 				}
-			}(piece)
+			}(i, piece)
 		}
 
 		// Close channel once done.
@@ -1,10 +1,14 @@
 package utils
 
 import (
+	"context"
 	"errors"
+	"net/http"
 	"strconv"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/PuerkitoBio/goquery"
 	"github.com/gocolly/colly"
@@ -42,17 +46,27 @@ type testPipeline_Return struct {
 type testPipeline_DummyScraper struct{}
 
 // Represents the Login method for a dummy scraper (not needed).
-func (scraper testPipeline_DummyScraper) Login(base, username, password string) (*colly.Collector, error) {
+func (scraper testPipeline_DummyScraper) Login(ctx context.Context, base, username, password string) (*colly.Collector, error) {
 	return nil, nil
 }
 
+// Represents the Restore method for a dummy scraper (not needed).
+func (scraper testPipeline_DummyScraper) Restore(base string, cookies []*http.Cookie) (*colly.Collector, error) {
+	return nil, nil
+}
+
+// Represents the Probe method for a dummy scraper (not needed).
+func (scraper testPipeline_DummyScraper) Probe(ctx context.Context, base string) error {
+	return nil
+}
+
 // Represents the Navigate method for a dummy scraper (not needed).
-func (scraper testPipeline_DummyScraper) Navigate(collector *colly.Collector, base, url string) (*colly.Collector, *goquery.Selection, error) {
+func (scraper testPipeline_DummyScraper) Navigate(ctx context.Context, collector *colly.Collector, base, url string) (*colly.Collector, *goquery.Selection, error) {
 	return nil, nil, nil
 }
 
 // Represents the Post method for a dummy scraper.
-func (scraper testPipeline_DummyScraper) Post(collector *colly.Collector, base, url string, formData map[string]string) (*colly.Collector, *goquery.Selection, error) {
+func (scraper testPipeline_DummyScraper) Post(ctx context.Context, collector *colly.Collector, base, url string, formData map[string]string) (*colly.Collector, *goquery.Selection, error) {
 	// Embed the form data and the given I value into HTML, and return it.
 	doc, err := goquery.NewDocumentFromReader(strings.NewReader(`<html><body><h1>` + formData["I"] + `</h1><div class="fd">` + formData["__VIEWSTATE"] + `,` + formData["__VIEWSTATEGENERATOR"] + `,` + formData["__EVENTVALIDATION"] + `,` + formData["__URL"] + `,` + formData["__BASE"] + `</div></body></html>`))
 	if err != nil {
@@ -104,7 +118,7 @@ func TestGeneratePipeline_RecievedValueOnly(t *testing.T) {
 	expected := []testPipeline_Return{{J: 1, FD: "A,B,C,D,E"}}
 
 	// Test.
-	parsed, err := GeneratePipeline[testPipeline_Return, int](testPipeline_DummyScraper{}, nil, data, recieved, testPipeline_Formdata, testPipeline_Funcs)
+	parsed, err := GeneratePipeline[testPipeline_Return, int](context.Background(), testPipeline_DummyScraper{}, nil, 0, data, recieved, testPipeline_Formdata, testPipeline_Funcs)
 	if err != nil {
 		t.Fatalf("Failed for GeneratePipeline() Recieved Value Only:\n%v", err)
 	}
@@ -124,7 +138,7 @@ func TestGeneratePipeline_NoValues_WithRecieved(t *testing.T) {
 	expected := []testPipeline_Return{{J: 1, FD: "A,B,C,D,E"}}
 
 	// Test.
-	parsed, err := GeneratePipeline[testPipeline_Return, int](testPipeline_DummyScraper{}, nil, data, recieved, testPipeline_Formdata, testPipeline_Funcs)
+	parsed, err := GeneratePipeline[testPipeline_Return, int](context.Background(), testPipeline_DummyScraper{}, nil, 0, data, recieved, testPipeline_Formdata, testPipeline_Funcs)
 	if err != nil {
 		t.Fatalf("Failed for GeneratePipeline() No Values With Recieved:\n%v", err)
 	}
@@ -149,7 +163,7 @@ func TestGeneratePipeline_MultipleValues_WithRecieved(t *testing.T) {
 	expected := []testPipeline_Return{{J: 1, FD: "A,B,C,D,E"}, {J: 2, FD: "A,B,C,D,E"}, {J: 3, FD: "A,B,C,D,E"}, {J: 4, FD: "A,B,C,D,E"}, {J: 5, FD: "A,B,C,D,E"}}
 
 	// Test.
-	parsed, err := GeneratePipeline[testPipeline_Return, int](testPipeline_DummyScraper{}, nil, data, recieved, testPipeline_Formdata, testPipeline_Funcs)
+	parsed, err := GeneratePipeline[testPipeline_Return, int](context.Background(), testPipeline_DummyScraper{}, nil, 0, data, recieved, testPipeline_Formdata, testPipeline_Funcs)
 	if err != nil {
 		t.Fatalf("Failed for GeneratePipeline() Multiple Values With Recieved:\n%v", err)
 	}
@@ -174,7 +188,7 @@ func TestGeneratePipeline_MultipleValues_WithoutRecieved(t *testing.T) {
 	expected := []testPipeline_Return{{J: 1, FD: "A,B,C,D,E"}, {J: 2, FD: "A,B,C,D,E"}, {J: 3, FD: "A,B,C,D,E"}, {J: 4, FD: "A,B,C,D,E"}, {J: 5, FD: "A,B,C,D,E"}}
 
 	// Test.
-	parsed, err := GeneratePipeline[testPipeline_Return, int](testPipeline_DummyScraper{}, nil, data, recieved, testPipeline_Formdata, testPipeline_Funcs)
+	parsed, err := GeneratePipeline[testPipeline_Return, int](context.Background(), testPipeline_DummyScraper{}, nil, 0, data, recieved, testPipeline_Formdata, testPipeline_Funcs)
 	if err != nil {
 		t.Fatalf("Failed for GeneratePipeline() Multiple Values Without Recieved:\n%v", err)
 	}
@@ -184,21 +198,63 @@ func TestGeneratePipeline_MultipleValues_WithoutRecieved(t *testing.T) {
 	}
 }
 
+// Represents a dummy scraper which finishes later requests first.
+type testPipeline_DummyReversedScraper struct {
+	testPipeline_DummyScraper
+}
+
+// Represents the Post method for a dummy scraper, which waits less the larger I is.
+func (scraper testPipeline_DummyReversedScraper) Post(ctx context.Context, collector *colly.Collector, base, url string, formData map[string]string) (*colly.Collector, *goquery.Selection, error) {
+	i, _ := strconv.Atoi(formData["I"])
+	time.Sleep(time.Duration(10-i) * 5 * time.Millisecond)
+	return scraper.testPipeline_DummyScraper.Post(ctx, collector, base, url, formData)
+}
+
+// Test if GeneratePipeline() returns values in the order of the data, not the order they finish in.
+func TestGeneratePipeline_MultipleValues_Ordered(t *testing.T) {
+	// Set up test pipeline data.
+	recieved := testPipeline_Data{I: 3}
+	data := []int{1, 2, 3, 4, 5}
+
+	// Make expected value.
+	expected := []testPipeline_Return{{J: 1, FD: "A,B,C,D,E"}, {J: 2, FD: "A,B,C,D,E"}, {J: 3, FD: "A,B,C,D,E"}, {J: 4, FD: "A,B,C,D,E"}, {J: 5, FD: "A,B,C,D,E"}}
+
+	// Test.
+	parsed, err := GeneratePipeline[testPipeline_Return, int](context.Background(), testPipeline_DummyReversedScraper{}, nil, 0, data, recieved, testPipeline_Formdata, testPipeline_Funcs)
+	if err != nil {
+		t.Fatalf("Failed for GeneratePipeline() Multiple Values Ordered:\n%v", err)
+	}
+
+	if diff := cmp.Diff(expected, parsed); diff != "" {
+		t.Fatalf("Failed for GeneratePipeline() Multiple Values Ordered (-want, +got):\n%s", diff)
+	}
+}
+
 // Represents a dummy scraper which always fails.
 type testPipeline_DummyBadHTMLScraper struct{}
 
 // Represents the Login method for a dummy scraper (not needed).
-func (scraper testPipeline_DummyBadHTMLScraper) Login(base, username, password string) (*colly.Collector, error) {
+func (scraper testPipeline_DummyBadHTMLScraper) Login(ctx context.Context, base, username, password string) (*colly.Collector, error) {
 	return nil, nil
 }
 
+// Represents the Restore method for a dummy scraper (not needed).
+func (scraper testPipeline_DummyBadHTMLScraper) Restore(base string, cookies []*http.Cookie) (*colly.Collector, error) {
+	return nil, nil
+}
+
+// Represents the Probe method for a dummy scraper (not needed).
+func (scraper testPipeline_DummyBadHTMLScraper) Probe(ctx context.Context, base string) error {
+	return nil
+}
+
 // Represents the Navigate method for a dummy scraper (not needed).
-func (scraper testPipeline_DummyBadHTMLScraper) Navigate(collector *colly.Collector, base, url string) (*colly.Collector, *goquery.Selection, error) {
+func (scraper testPipeline_DummyBadHTMLScraper) Navigate(ctx context.Context, collector *colly.Collector, base, url string) (*colly.Collector, *goquery.Selection, error) {
 	return nil, nil, nil
 }
 
 // Represents the Post method for a dummy scraper, should always error out.
-func (scraper testPipeline_DummyBadHTMLScraper) Post(collector *colly.Collector, base, url string, formData map[string]string) (*colly.Collector, *goquery.Selection, error) {
+func (scraper testPipeline_DummyBadHTMLScraper) Post(ctx context.Context, collector *colly.Collector, base, url string, formData map[string]string) (*colly.Collector, *goquery.Selection, error) {
 	return nil, nil, ErrorBadHTML
 }
 
@@ -209,7 +265,7 @@ func TestGeneratePipeline_MalformedHTML(t *testing.T) {
 	data := []int{1, 2, 3, 4, 5}
 
 	// Test.
-	_, err := GeneratePipeline[testPipeline_Return, int](testPipeline_DummyBadHTMLScraper{}, nil, data, recieved, testPipeline_Formdata, testPipeline_Funcs)
+	_, err := GeneratePipeline[testPipeline_Return, int](context.Background(), testPipeline_DummyBadHTMLScraper{}, nil, 0, data, recieved, testPipeline_Formdata, testPipeline_Funcs)
 
 	if err == nil {
 		t.Fatalf("Failed for GeneratePipeline() Malformed HTML (-want, +got):\n- %v\n+ nil", ErrorBadHTML)
@@ -224,17 +280,27 @@ func TestGeneratePipeline_MalformedHTML(t *testing.T) {
 type testPipeline_DummyNilHTMLScraper struct{}
 
 // Represents the Login method for a dummy scraper (not needed).
-func (scraper testPipeline_DummyNilHTMLScraper) Login(base, username, password string) (*colly.Collector, error) {
+func (scraper testPipeline_DummyNilHTMLScraper) Login(ctx context.Context, base, username, password string) (*colly.Collector, error) {
+	return nil, nil
+}
+
+// Represents the Restore method for a dummy scraper (not needed).
+func (scraper testPipeline_DummyNilHTMLScraper) Restore(base string, cookies []*http.Cookie) (*colly.Collector, error) {
 	return nil, nil
 }
 
+// Represents the Probe method for a dummy scraper (not needed).
+func (scraper testPipeline_DummyNilHTMLScraper) Probe(ctx context.Context, base string) error {
+	return nil
+}
+
 // Represents the Navigate method for a dummy scraper (not needed).
-func (scraper testPipeline_DummyNilHTMLScraper) Navigate(collector *colly.Collector, base, url string) (*colly.Collector, *goquery.Selection, error) {
+func (scraper testPipeline_DummyNilHTMLScraper) Navigate(ctx context.Context, collector *colly.Collector, base, url string) (*colly.Collector, *goquery.Selection, error) {
 	return nil, nil, nil
 }
 
 // Represents the Post method for a dummy scraper, should always return nil HTML.
-func (scraper testPipeline_DummyNilHTMLScraper) Post(collector *colly.Collector, base, url string, formData map[string]string) (*colly.Collector, *goquery.Selection, error) {
+func (scraper testPipeline_DummyNilHTMLScraper) Post(ctx context.Context, collector *colly.Collector, base, url string, formData map[string]string) (*colly.Collector, *goquery.Selection, error) {
 	return nil, nil, nil
 }
 
@@ -245,7 +311,7 @@ func TestGeneratePipeline_NilHTML(t *testing.T) {
 	data := []int{1, 2, 3, 4, 5}
 
 	// Test.
-	_, err := GeneratePipeline[testPipeline_Return, int](testPipeline_DummyNilHTMLScraper{}, nil, data, recieved, testPipeline_Formdata, testPipeline_Funcs)
+	_, err := GeneratePipeline[testPipeline_Return, int](context.Background(), testPipeline_DummyNilHTMLScraper{}, nil, 0, data, recieved, testPipeline_Formdata, testPipeline_Funcs)
 
 	if err == nil {
 		t.Fatalf("Failed for GeneratePipeline() Malformed HTML (-want, +got):\n- %v\n+ nil", ErrorBadHTML)
@@ -255,3 +321,175 @@ func TestGeneratePipeline_NilHTML(t *testing.T) {
 		t.Fatalf("Failed for GeneratePipeline() Malformed HTML (-want, +got):\n- %v\n+ %v", ErrorBadHTML, err)
 	}
 }
+
+// Test if GeneratePipeline() stops and errors out once the context times out.
+func TestGeneratePipeline_ContextTimeout(t *testing.T) {
+	// Set up test pipeline data.
+	recieved := testPipeline_Data{I: -1}
+	data := []int{1, 2, 3, 4, 5}
+
+	// Time out before the slowest request finishes.
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	// Test.
+	parsed, err := GeneratePipeline[testPipeline_Return, int](ctx, testPipeline_DummyReversedScraper{}, nil, 0, data, recieved, testPipeline_Formdata, testPipeline_Funcs)
+
+	if !errors.Is(err, context.DeadlineExceeded) || parsed != nil {
+		t.Fatalf("Failed for GeneratePipeline() Context Timeout (-want, +got):\n- %v\n+ %v", context.DeadlineExceeded, err)
+	}
+}
+
+// Represents a dummy scraper which records the most POST requests made at once.
+type testPipeline_DummyCountingScraper struct {
+	testPipeline_DummyScraper
+	Mutex    *sync.Mutex
+	Active   *int // The amount of POST requests in flight.
+	MaxCount *int // The most POST requests in flight at once.
+}
+
+// Represents the Post method for a dummy scraper, which counts requests in flight.
+func (scraper testPipeline_DummyCountingScraper) Post(ctx context.Context, collector *colly.Collector, base, url string, formData map[string]string) (*colly.Collector, *goquery.Selection, error) {
+	scraper.Mutex.Lock()
+	*scraper.Active++
+	if *scraper.Active > *scraper.MaxCount {
+		*scraper.MaxCount = *scraper.Active
+	}
+	scraper.Mutex.Unlock()
+
+	time.Sleep(10 * time.Millisecond)
+
+	scraper.Mutex.Lock()
+	*scraper.Active--
+	scraper.Mutex.Unlock()
+
+	return scraper.testPipeline_DummyScraper.Post(ctx, collector, base, url, formData)
+}
+
+// Test if GeneratePipeline() makes at most the given amount of POST requests at once.
+func TestGeneratePipeline_Workers(t *testing.T) {
+	// Set up test pipeline data.
+	recieved := testPipeline_Data{I: -1}
+	data := []int{1, 2, 3, 4, 5}
+	active, maxCount := 0, 0
+	scraper := testPipeline_DummyCountingScraper{Mutex: &sync.Mutex{}, Active: &active, MaxCount: &maxCount}
+
+	// Make expected value.
+	expected := []testPipeline_Return{{J: 1, FD: "A,B,C,D,E"}, {J: 2, FD: "A,B,C,D,E"}, {J: 3, FD: "A,B,C,D,E"}, {J: 4, FD: "A,B,C,D,E"}, {J: 5, FD: "A,B,C,D,E"}}
+
+	// Test.
+	parsed, err := GeneratePipeline[testPipeline_Return, int](context.Background(), scraper, nil, 2, data, recieved, testPipeline_Formdata, testPipeline_Funcs)
+	if err != nil {
+		t.Fatalf("Failed for GeneratePipeline() Workers:\n%v", err)
+	}
+
+	if diff := cmp.Diff(expected, parsed); diff != "" {
+		t.Fatalf("Failed for GeneratePipeline() Workers (-want, +got):\n%s", diff)
+	}
+
+	if maxCount > 2 {
+		t.Fatalf("Failed for GeneratePipeline() Workers, expected at most 2 requests at once, got %d", maxCount)
+	}
+}
+
+// Test if GeneratePipeline() stops waiting for a worker once the context times out.
+func TestGeneratePipeline_Workers_ContextTimeout(t *testing.T) {
+	// Set up test pipeline data.
+	recieved := testPipeline_Data{I: -1}
+	data := []int{1, 2, 3, 4, 5}
+	active, maxCount := 0, 0
+	scraper := testPipeline_DummyCountingScraper{Mutex: &sync.Mutex{}, Active: &active, MaxCount: &maxCount}
+
+	// Time out while most requests are still queued.
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Millisecond)
+	defer cancel()
+
+	// Test.
+	parsed, err := GeneratePipeline[testPipeline_Return, int](ctx, scraper, nil, 1, data, recieved, testPipeline_Formdata, testPipeline_Funcs)
+
+	if !errors.Is(err, context.DeadlineExceeded) || parsed != nil {
+		t.Fatalf("Failed for GeneratePipeline() Workers Context Timeout (-want, +got):\n- %v\n+ %v", context.DeadlineExceeded, err)
+	}
+
+	if maxCount > 1 {
+		t.Fatalf("Failed for GeneratePipeline() Workers Context Timeout, expected at most 1 request at once, got %d", maxCount)
+	}
+}
+
+// Represents a dummy scraper which fails for the given values of I.
+type testPipeline_DummyFailingScraper struct {
+	testPipeline_DummyScraper
+	Failing map[string]error // The error to fail with for each value of I.
+}
+
+// Represents the Post method for a dummy scraper, which fails for some values of I.
+func (scraper testPipeline_DummyFailingScraper) Post(ctx context.Context, collector *colly.Collector, base, url string, formData map[string]string) (*colly.Collector, *goquery.Selection, error) {
+	if err, ok := scraper.Failing[formData["I"]]; ok {
+		return nil, nil, err
+	}
+	return scraper.testPipeline_DummyScraper.Post(ctx, collector, base, url, formData)
+}
+
+// Test if GeneratePartialPipeline() returns the values which succeeded, and errors for the ones which failed.
+func TestGeneratePartialPipeline(t *testing.T) {
+	// Set up test pipeline data.
+	recieved := testPipeline_Data{I: 1}
+	data := []int{1, 2, 3, 4, 5}
+	scraper := testPipeline_DummyFailingScraper{Failing: map[string]error{"4": ErrorBadHTML, "2": ErrorPageNotAvaliable}}
+
+	// Make expected values.
+	expected := []testPipeline_Return{{J: 1, FD: "A,B,C,D,E"}, {J: 3, FD: "A,B,C,D,E"}, {J: 5, FD: "A,B,C,D,E"}}
+	expectedErrors := []PipelineError{{Index: 1, Err: ErrorPageNotAvaliable}, {Index: 3, Err: ErrorBadHTML}}
+
+	// Test.
+	parsed, pipelineErrors, err := GeneratePartialPipeline[testPipeline_Return, int](context.Background(), scraper, nil, 0, data, recieved, testPipeline_Formdata, testPipeline_Funcs)
+	if err != nil {
+		t.Fatalf("Failed for GeneratePartialPipeline():\n%v", err)
+	}
+
+	if diff := cmp.Diff(expected, parsed); diff != "" {
+		t.Fatalf("Failed for GeneratePartialPipeline() (-want, +got):\n%s", diff)
+	}
+
+	if diff := cmp.Diff(expectedErrors, pipelineErrors, cmpopts.EquateErrors()); diff != "" {
+		t.Fatalf("Failed for GeneratePartialPipeline() errors (-want, +got):\n%s", diff)
+	}
+}
+
+// Test if GeneratePartialPipeline() still errors out if the session expired.
+func TestGeneratePartialPipeline_ExpiredSession(t *testing.T) {
+	// Set up test pipeline data.
+	recieved := testPipeline_Data{I: 1}
+	data := []int{1, 2, 3}
+	scraper := testPipeline_DummyFailingScraper{Failing: map[string]error{"2": ErrorSessionExpired}}
+
+	// Test.
+	parsed, _, err := GeneratePartialPipeline[testPipeline_Return, int](context.Background(), scraper, nil, 0, data, recieved, testPipeline_Formdata, testPipeline_Funcs)
+
+	if err != ErrorSessionExpired || parsed != nil {
+		t.Fatalf("Failed for GeneratePartialPipeline() Expired Session (-want, +got):\n- %v\n+ %v", ErrorSessionExpired, err)
+	}
+}
+
+// Test if StreamPipeline() emits every value along with its position in the data.
+func TestStreamPipeline(t *testing.T) {
+	// Set up test pipeline data.
+	recieved := testPipeline_Data{I: 2}
+	data := []int{1, 2, 3}
+
+	// Make expected value.
+	expected := map[int]testPipeline_Return{0: {J: 1, FD: "A,B,C,D,E"}, 1: {J: 2, FD: "A,B,C,D,E"}, 2: {J: 3, FD: "A,B,C,D,E"}}
+
+	// Test.
+	emitted := make(map[int]testPipeline_Return)
+	pipelineErrors, err := StreamPipeline[testPipeline_Return, int](context.Background(), testPipeline_DummyReversedScraper{}, nil, 0, data, recieved, testPipeline_Formdata, testPipeline_Funcs, false, func(i int, value testPipeline_Return) {
+		emitted[i] = value
+	})
+	if err != nil || pipelineErrors != nil {
+		t.Fatalf("Failed for StreamPipeline():\n%v", err)
+	}
+
+	if diff := cmp.Diff(expected, emitted); diff != "" {
+		t.Fatalf("Failed for StreamPipeline() (-want, +got):\n%s", diff)
+	}
+}
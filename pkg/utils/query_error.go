@@ -0,0 +1,40 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/Threqt1/HACApi/pkg/repository"
+	"github.com/gofiber/fiber/v2"
+)
+
+// StatusClientClosedRequest is the non-standard status code
+// responded with when the request is canceled.
+const StatusClientClosedRequest = 499
+
+// QueryErrorResponse maps an error from querying HAC to the
+// status code and error to respond with.
+func QueryErrorResponse(err error) (int, error) {
+	var statusErr HTTPStatusError
+
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return fiber.StatusGatewayTimeout, repository.ErrorRequestTimeout
+	case errors.Is(err, context.Canceled):
+		return StatusClientClosedRequest, repository.ErrorRequestCanceled
+	case errors.Is(err, ErrorSessionExpired):
+		// The session expired again after logging in again.
+		return fiber.StatusUnauthorized, repository.ErrorSessionExpired
+	case isTransient(err):
+		// Retrying didn't help, HAC is down.
+		return fiber.StatusServiceUnavailable, repository.ErrorServerUnreachable
+	case errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusTooManyRequests:
+		return fiber.StatusTooManyRequests, repository.ErrorRateLimited
+	case errors.Is(err, repository.ErrorPageLayoutChanged), errors.Is(err, ErrorPageNotAvaliable), errors.Is(err, ErrorBadHTML):
+		// HAC sent back a page which can't be parsed, or redirected elsewhere.
+		return fiber.StatusBadGateway, repository.ErrorPageLayoutChanged
+	default:
+		return fiber.StatusInternalServerError, repository.ErrorInternalError
+	}
+}
@@ -0,0 +1,46 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/Threqt1/HACApi/pkg/repository"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+// testQueryError_Test represents an expected output from QueryErrorResponse().
+type testQueryError_Test struct {
+	Status int
+	Error  error
+}
+
+// TestQueryErrorResponse tests QueryErrorResponse() for timeouts, cancellations, expired sessions, outages, rate limits, unexpected pages and other errors.
+func TestQueryErrorResponse(t *testing.T) {
+	cases := map[error]testQueryError_Test{
+		context.DeadlineExceeded:                                     {Status: fiber.StatusGatewayTimeout, Error: repository.ErrorRequestTimeout},
+		fmt.Errorf("post: %w", context.DeadlineExceeded):             {Status: fiber.StatusGatewayTimeout, Error: repository.ErrorRequestTimeout},
+		context.Canceled:                                             {Status: StatusClientClosedRequest, Error: repository.ErrorRequestCanceled},
+		fmt.Errorf("get: %w", context.Canceled):                      {Status: StatusClientClosedRequest, Error: repository.ErrorRequestCanceled},
+		ErrorSessionExpired:                                          {Status: fiber.StatusUnauthorized, Error: repository.ErrorSessionExpired},
+		HTTPStatusError{StatusCode: fiber.StatusServiceUnavailable}:  {Status: fiber.StatusServiceUnavailable, Error: repository.ErrorServerUnreachable},
+		HTTPStatusError{StatusCode: fiber.StatusInternalServerError}: {Status: fiber.StatusInternalServerError, Error: repository.ErrorInternalError},
+		HTTPStatusError{StatusCode: fiber.StatusTooManyRequests}:     {Status: fiber.StatusTooManyRequests, Error: repository.ErrorRateLimited},
+		ErrorPageNotAvaliable:                                        {Status: fiber.StatusBadGateway, Error: repository.ErrorPageLayoutChanged},
+		ErrorBadHTML:                                                 {Status: fiber.StatusBadGateway, Error: repository.ErrorPageLayoutChanged},
+		repository.ErrorPageLayoutChanged:                            {Status: fiber.StatusBadGateway, Error: repository.ErrorPageLayoutChanged},
+		errors.New("other"):                                          {Status: fiber.StatusInternalServerError, Error: repository.ErrorInternalError},
+	}
+
+	for input, expected := range cases {
+		// Test.
+		status, err := QueryErrorResponse(input)
+
+		if diff := cmp.Diff(expected, testQueryError_Test{Status: status, Error: err}, cmpopts.EquateErrors()); diff != "" {
+			t.Fatalf("Failed for QueryErrorResponse() with error %v (-want, +got)\n%s", input, diff)
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package utils
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jellydator/ttlcache/v3"
+)
+
+// How long the token bucket of a district is kept after its last request.
+const rateLimiterTTL = time.Hour
+
+// The maximum amount of districts token buckets are kept for.
+const rateLimiterCapacity = 1000
+
+// tokenBucket is a token bucket rate limiter, which allows
+// a burst of requests, and then a steady rate of requests.
+type tokenBucket struct {
+	mutex  sync.Mutex
+	rate   float64   // Tokens added per second
+	burst  float64   // The maximum amount of tokens
+	tokens float64   // The tokens currently available
+	last   time.Time // The last time tokens were added
+}
+
+// rateLimiters holds a token bucket for every district, keyed by base, so
+// every request to a district shares the same bucket. Buckets of districts
+// which weren't requested recently are dropped, so any base being accepted
+// can't grow it without bound.
+var rateLimiters = ttlcache.New(
+	ttlcache.WithTTL[string, *tokenBucket](rateLimiterTTL),
+	ttlcache.WithCapacity[string, *tokenBucket](rateLimiterCapacity),
+)
+
+// rateLimitersMutex makes getting or creating a bucket atomic.
+var rateLimitersMutex sync.Mutex
+
+// waitForRateLimit blocks until a request to base is allowed, or ctx is done.
+// A rate of 0 means requests are not limited.
+func waitForRateLimit(ctx context.Context, base string, rate float64, burst int) error {
+	if rate <= 0 {
+		return nil
+	}
+	if burst < 1 {
+		burst = 1
+	}
+
+	key := strings.TrimRight(strings.ToLower(base), "/")
+	return rateLimiter(key, rate, float64(burst)).wait(ctx)
+}
+
+// rateLimiter returns the token bucket for a district, creating it if there is
+// none, and updating its rate and burst if the district's profile changed them.
+func rateLimiter(key string, rate, burst float64) *tokenBucket {
+	rateLimitersMutex.Lock()
+	defer rateLimitersMutex.Unlock()
+
+	if item := rateLimiters.Get(key); item != nil {
+		bucket := item.Value()
+		bucket.configure(rate, burst)
+		return bucket
+	}
+
+	bucket := &tokenBucket{rate: rate, burst: burst, tokens: burst, last: time.Now()}
+	rateLimiters.Set(key, bucket, ttlcache.DefaultTTL)
+	return bucket
+}
+
+// configure sets the rate and burst of the bucket, keeping the tokens within the burst.
+func (bucket *tokenBucket) configure(rate, burst float64) {
+	bucket.mutex.Lock()
+	defer bucket.mutex.Unlock()
+
+	bucket.rate, bucket.burst = rate, burst
+	if bucket.tokens > burst {
+		bucket.tokens = burst
+	}
+}
+
+// wait takes a token from the bucket, waiting for one to be added if needed.
+func (bucket *tokenBucket) wait(ctx context.Context) error {
+	bucket.mutex.Lock()
+
+	// Add the tokens accumulated since the last request.
+	now := time.Now()
+	bucket.tokens += now.Sub(bucket.last).Seconds() * bucket.rate
+	if bucket.tokens > bucket.burst {
+		bucket.tokens = bucket.burst
+	}
+	bucket.last = now
+
+	// Take a token, going into debt if there is none, so waiters are served in order.
+	bucket.tokens--
+	delay := time.Duration(0)
+	if bucket.tokens < 0 {
+		delay = time.Duration(-bucket.tokens / bucket.rate * float64(time.Second))
+	}
+
+	bucket.mutex.Unlock()
+
+	if delay == 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		// Give the token back, since no request was made.
+		bucket.mutex.Lock()
+		bucket.tokens++
+		bucket.mutex.Unlock()
+		return ctx.Err()
+	}
+}
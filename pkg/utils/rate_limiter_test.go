@@ -0,0 +1,91 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Test if waitForRateLimit() allows a burst, and then limits requests to the rate.
+func TestWaitForRateLimit(t *testing.T) {
+	// Use a fresh base, since buckets are shared for the whole process.
+	base := fmt.Sprintf("https://rate-%d.test", time.Now().UnixNano())
+
+	// The burst should be allowed immediately.
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		if err := waitForRateLimit(context.Background(), base, 20, 3); err != nil {
+			t.Fatalf("Failed for waitForRateLimit() burst:\n%v", err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed > 25*time.Millisecond {
+		t.Fatalf("Failed for waitForRateLimit() burst, expected no wait, took %v", elapsed)
+	}
+
+	// Requests after the burst should wait for the rate, 50ms each.
+	for i := 0; i < 2; i++ {
+		if err := waitForRateLimit(context.Background(), base, 20, 3); err != nil {
+			t.Fatalf("Failed for waitForRateLimit() after burst:\n%v", err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 90*time.Millisecond {
+		t.Fatalf("Failed for waitForRateLimit() after burst, expected to wait ~100ms, took %v", elapsed)
+	}
+
+	// The bucket should be shared regardless of trailing slashes or case.
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := waitForRateLimit(ctx, strings.ToUpper(base)+"/", 20, 3); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Failed for waitForRateLimit() with timeout (-want, +got):\n- %v\n+ %v", context.DeadlineExceeded, err)
+	}
+}
+
+// Test if waitForRateLimit() does not limit requests when there is no rate.
+func TestWaitForRateLimit_Unlimited(t *testing.T) {
+	start := time.Now()
+	for i := 0; i < 100; i++ {
+		if err := waitForRateLimit(context.Background(), "https://unlimited.test", 0, 0); err != nil {
+			t.Fatalf("Failed for waitForRateLimit() unlimited:\n%v", err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed > 25*time.Millisecond {
+		t.Fatalf("Failed for waitForRateLimit() unlimited, expected no wait, took %v", elapsed)
+	}
+}
+
+// Test if waitForRateLimit() picks up a changed rate and burst for a district.
+func TestWaitForRateLimit_ChangedProfile(t *testing.T) {
+	base := fmt.Sprintf("https://rate-changed-%d.test", time.Now().UnixNano())
+
+	// Use up the burst at a slow rate.
+	if err := waitForRateLimit(context.Background(), base, 1, 1); err != nil {
+		t.Fatalf("Failed for waitForRateLimit() burst:\n%v", err)
+	}
+
+	// A faster rate should apply right away, instead of waiting for the old one.
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+	for i := 0; i < 5; i++ {
+		if err := waitForRateLimit(ctx, base, 100, 1); err != nil {
+			t.Fatalf("Failed for waitForRateLimit() with changed rate:\n%v", err)
+		}
+	}
+}
+
+// Test if waitForRateLimit() keeps a bounded amount of buckets.
+func TestWaitForRateLimit_Bounded(t *testing.T) {
+	prefix := fmt.Sprintf("https://rate-bounded-%d-", time.Now().UnixNano())
+
+	for i := 0; i < rateLimiterCapacity+10; i++ {
+		if err := waitForRateLimit(context.Background(), fmt.Sprintf("%s%d.test", prefix, i), 1, 1); err != nil {
+			t.Fatalf("Failed for waitForRateLimit():\n%v", err)
+		}
+	}
+
+	if length := rateLimiters.Len(); length > rateLimiterCapacity {
+		t.Fatalf("Failed for waitForRateLimit(), expected at most %d buckets, got %d", rateLimiterCapacity, length)
+	}
+}
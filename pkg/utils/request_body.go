@@ -0,0 +1,15 @@
+package utils
+
+import (
+	"github.com/gofiber/fiber/v2"
+)
+
+// ParseBody parses the body of a request into params. Requests with a
+// bearer token can leave the body out, since the token carries the
+// credentials. Any other required params are still caught by validation.
+func ParseBody(ctx *fiber.Ctx, params interface{}) error {
+	if len(ctx.Body()) == 0 && GetBearerToken(ctx) != "" {
+		return nil
+	}
+	return ctx.BodyParser(params)
+}
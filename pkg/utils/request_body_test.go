@@ -0,0 +1,57 @@
+package utils
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Threqt1/HACApi/app/models"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/go-cmp/cmp"
+)
+
+// testRequestBody_Case represents an individual test case for ParseBody().
+type testRequestBody_Case struct {
+	Name          string
+	Body          string
+	ContentType   string
+	Authorization string
+	Status        int
+}
+
+// TestParseBody tests ParseBody() with and without a body and a bearer token.
+func TestParseBody(t *testing.T) {
+	cases := []testRequestBody_Case{
+		{Name: "Body", Body: `{"username":"ABC"}`, ContentType: fiber.MIMEApplicationJSON, Status: fiber.StatusOK},
+		{Name: "Bearer Token Without Body", Authorization: "Bearer abc123", Status: fiber.StatusOK},
+		{Name: "No Body Or Bearer Token", Status: fiber.StatusBadRequest},
+		{Name: "Bearer Token With Bad Body", Body: `{`, ContentType: fiber.MIMEApplicationJSON, Authorization: "Bearer abc123", Status: fiber.StatusBadRequest},
+	}
+
+	for _, test := range cases {
+		// Create a server which parses the body.
+		app := fiber.New()
+		app.Post("/", func(ctx *fiber.Ctx) error {
+			if err := ParseBody(ctx, new(models.BaseRequestBody)); err != nil {
+				return ctx.SendStatus(fiber.StatusBadRequest)
+			}
+			return ctx.SendStatus(fiber.StatusOK)
+		})
+
+		// Create a test request.
+		req := httptest.NewRequest("POST", "http://fake.url/", strings.NewReader(test.Body))
+		if test.ContentType != "" {
+			req.Header.Set("Content-Type", test.ContentType)
+		}
+		if test.Authorization != "" {
+			req.Header.Set("Authorization", test.Authorization)
+		}
+
+		// Test.
+		resp, _ := app.Test(req)
+
+		if diff := cmp.Diff(test.Status, resp.StatusCode); diff != "" {
+			t.Fatalf("Failed for ParseBody() %s (-want, +got):\n%s", test.Name, diff)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package utils
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+
+	"github.com/gocolly/colly"
+)
+
+// The header used to tie a HAC request to the context of the API request
+// which made it. It is removed before the request is sent.
+const contextHeader = "X-Hacapi-Context"
+
+// requestContexts holds the contexts of in-flight HAC requests, keyed by the ID
+// sent in contextHeader.
+var requestContexts sync.Map
+
+// The last ID handed out to a context.
+var lastContextID uint64
+
+// contextTransport is a transport which cancels HAC requests along with
+// the context of the API request which made them. Colly doesn't support
+// contexts, so the context is looked up from contextHeader.
+type contextTransport struct {
+	Transport http.RoundTripper
+}
+
+func (transport contextTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	id := req.Header.Get(contextHeader)
+	if id == "" {
+		return transport.Transport.RoundTrip(req)
+	}
+
+	// Send the request with the context, without the header.
+	ctx := context.Background()
+	if value, ok := requestContexts.Load(id); ok {
+		ctx = value.(context.Context)
+	}
+	req = req.Clone(ctx)
+	req.Header.Del(contextHeader)
+
+	return transport.Transport.RoundTrip(req)
+}
+
+// bindContext ties every request made by a collector to a context, so requests
+// are aborted once it is done. Collectors using contextTransport also have
+// their in-flight requests cancelled. The returned function must be called
+// once the collector is done.
+func bindContext(ctx context.Context, collector *colly.Collector) func() {
+	id := strconv.FormatUint(atomic.AddUint64(&lastContextID, 1), 10)
+	requestContexts.Store(id, ctx)
+
+	collector.OnRequest(func(req *colly.Request) {
+		if ctx.Err() != nil {
+			req.Abort()
+			return
+		}
+		req.Headers.Set(contextHeader, id)
+	})
+
+	return func() {
+		requestContexts.Delete(id)
+	}
+}
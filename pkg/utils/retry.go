@@ -0,0 +1,103 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/gocolly/colly"
+)
+
+// The longest delay before the first retry, doubled for every retry after.
+const retryBaseDelay = 200 * time.Millisecond
+
+// The longest delay before any retry.
+const retryMaxDelay = 2 * time.Second
+
+// jitter randomizes retry delays, so requests failing together don't retry together.
+var jitter = rand.New(rand.NewSource(time.Now().UnixNano()))
+var jitterMutex sync.Mutex
+
+// HTTPStatusError is the error thrown when HAC responds with an error status code.
+type HTTPStatusError struct {
+	StatusCode int
+}
+
+func (err HTTPStatusError) Error() string {
+	return fmt.Sprintf("%d %s", err.StatusCode, http.StatusText(err.StatusCode))
+}
+
+// responseError returns the error for a failed colly request, keeping its status code if it got a response.
+func responseError(res *colly.Response, err error) error {
+	if res != nil && res.StatusCode >= 400 {
+		return HTTPStatusError{StatusCode: res.StatusCode}
+	}
+	return err
+}
+
+// isTransient returns whether a request failed in a way which is safe to retry. Only
+// gateway errors, timeouts and dropped connections are, so a redirect to the LogOn
+// page is never retried, and stays an expired session.
+func isTransient(err error) bool {
+	var statusErr HTTPStatusError
+	if errors.As(err, &statusErr) {
+		switch statusErr.StatusCode {
+		case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+			return true
+		}
+		return false
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+
+	return errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, io.EOF) ||
+		errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+// retryTransient runs a request, retrying it with jittered exponential backoff
+// up to retries times while it fails transiently. It stops once ctx is done.
+func retryTransient(ctx context.Context, retries int, request func() (*colly.Collector, *goquery.Selection, error)) (*colly.Collector, *goquery.Selection, error) {
+	for attempt := 0; ; attempt++ {
+		collector, html, err := request()
+
+		// Return if the request succeeded, failed for good, or is out of retries.
+		if err == nil || ctx.Err() != nil || !isTransient(err) || attempt >= retries {
+			return collector, html, err
+		}
+
+		// Wait before retrying, unless the context is done first.
+		timer := time.NewTimer(retryDelay(attempt))
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, nil, ctx.Err()
+		}
+	}
+}
+
+// retryDelay returns a random delay before a retry, up to a limit which doubles every attempt.
+func retryDelay(attempt int) time.Duration {
+	limit := retryBaseDelay << attempt
+	if limit > retryMaxDelay || limit <= 0 {
+		limit = retryMaxDelay
+	}
+
+	jitterMutex.Lock()
+	defer jitterMutex.Unlock()
+
+	return time.Duration(jitter.Int63n(int64(limit)))
+}
@@ -0,0 +1,107 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/gocolly/colly"
+	"github.com/google/go-cmp/cmp"
+)
+
+// testRetry_Timeout is a net.Error which timed out.
+type testRetry_Timeout struct{}
+
+func (testRetry_Timeout) Error() string   { return "i/o timeout" }
+func (testRetry_Timeout) Timeout() bool   { return true }
+func (testRetry_Timeout) Temporary() bool { return true }
+
+// Test which errors are retried.
+func TestIsTransient(t *testing.T) {
+	tests := []struct {
+		Name  string
+		Err   error
+		Value bool
+	}{
+		{"bad gateway", HTTPStatusError{StatusCode: http.StatusBadGateway}, true},
+		{"service unavailable", HTTPStatusError{StatusCode: http.StatusServiceUnavailable}, true},
+		{"gateway timeout", HTTPStatusError{StatusCode: http.StatusGatewayTimeout}, true},
+		{"internal server error", HTTPStatusError{StatusCode: http.StatusInternalServerError}, false},
+		{"not found", HTTPStatusError{StatusCode: http.StatusNotFound}, false},
+		{"timeout", &url.Error{Op: "Get", URL: "https://fake.url", Err: testRetry_Timeout{}}, true},
+		{"connection reset", &url.Error{Op: "Get", URL: "https://fake.url", Err: syscall.ECONNRESET}, true},
+		{"unexpected EOF", fmt.Errorf("read: %w", io.ErrUnexpectedEOF), true},
+		{"expired session", ErrorSessionExpired, false},
+		{"page not available", ErrorPageNotAvaliable, false},
+		{"cancelled", context.Canceled, false},
+	}
+
+	for _, test := range tests {
+		if diff := cmp.Diff(test.Value, isTransient(test.Err)); diff != "" {
+			t.Fatalf("Failed for isTransient() with %s (-want, +got)\n%s", test.Name, diff)
+		}
+	}
+}
+
+// Test if retryTransient() stops retrying once the request succeeds.
+func TestRetryTransient(t *testing.T) {
+	attempts := 0
+	_, _, err := retryTransient(context.Background(), 3, func() (*colly.Collector, *goquery.Selection, error) {
+		attempts++
+		if attempts < 3 {
+			return nil, nil, HTTPStatusError{StatusCode: http.StatusBadGateway}
+		}
+		return nil, nil, nil
+	})
+
+	if err != nil {
+		t.Fatalf("Failed for retryTransient():\n%v", err)
+	}
+
+	if diff := cmp.Diff(3, attempts); diff != "" {
+		t.Fatalf("Failed for retryTransient() attempts (-want, +got)\n%s", diff)
+	}
+}
+
+// Test if retryTransient() never retries an expired session.
+func TestRetryTransient_ExpiredSession(t *testing.T) {
+	attempts := 0
+	_, _, err := retryTransient(context.Background(), 3, func() (*colly.Collector, *goquery.Selection, error) {
+		attempts++
+		return nil, nil, ErrorSessionExpired
+	})
+
+	if err != ErrorSessionExpired {
+		t.Fatalf("Failed for retryTransient() with expired session (-want, +got):\n- %v\n+ %v", ErrorSessionExpired, err)
+	}
+
+	if diff := cmp.Diff(1, attempts); diff != "" {
+		t.Fatalf("Failed for retryTransient() with expired session attempts (-want, +got)\n%s", diff)
+	}
+}
+
+// Test if retryTransient() stops waiting to retry once the context is done.
+func TestRetryTransient_ContextTimeout(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, _, err := retryTransient(ctx, 1000, func() (*colly.Collector, *goquery.Selection, error) {
+		return nil, nil, HTTPStatusError{StatusCode: http.StatusServiceUnavailable}
+	})
+
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Failed for retryTransient() with timeout (-want, +got):\n- %v\n+ %v", context.DeadlineExceeded, err)
+	}
+
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("Failed for retryTransient() with timeout, kept retrying (took %v)", elapsed)
+	}
+}
@@ -1,26 +1,94 @@
 package utils
 
 import (
+	"context"
+	"net/http"
+	"time"
+
 	"github.com/PuerkitoBio/goquery"
+	"github.com/Threqt1/HACApi/pkg/repository"
+	"github.com/Threqt1/HACApi/platform/metrics"
 	"github.com/gocolly/colly"
 )
 
 // Scraper is the struct used to inject the HAC scraping
 // dependency into the Server struct.
-type Scraper struct{}
+type Scraper struct {
+	Profiles  repository.ProfileProvider // The district profiles, the default profile is used if nil
+	Transport http.RoundTripper          // The transport HAC requests are sent with, http.DefaultTransport is used if nil
+}
+
+func (scraper Scraper) Login(ctx context.Context, url, username, password string) (*colly.Collector, error) {
+	profile := scraper.profile(url)
+	if err := waitForRateLimit(ctx, url, profile.RequestsPerSecond, profile.RequestBurst); err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+	collector, err := login(ctx, url, username, password, profile, scraper.Transport)
+	observeHACRequest("login", start, err)
+	metrics.Logins.Inc(loginResult(err))
+
+	return collector, err
+}
+
+func (scraper Scraper) Probe(ctx context.Context, url string) error {
+	profile := scraper.profile(url)
+	if err := waitForRateLimit(ctx, url, profile.RequestsPerSecond, profile.RequestBurst); err != nil {
+		return err
+	}
+
+	start := time.Now()
+	err := probe(ctx, url, profile, scraper.Transport)
+	observeHACRequest("probe", start, err)
 
-func (scraper Scraper) Login(url, username, password string) (*colly.Collector, error) {
-	return login(url, username, password)
+	return err
 }
 
-func (scraper Scraper) Navigate(collector *colly.Collector, url, endpoint string) (*colly.Collector, *goquery.Selection, error) {
-	return navigate(collector, url, endpoint)
+func (scraper Scraper) Restore(url string, cookies []*http.Cookie) (*colly.Collector, error) {
+	return restore(url, cookies, scraper.Transport)
+}
+
+func (scraper Scraper) Navigate(ctx context.Context, collector *colly.Collector, url, endpoint string) (*colly.Collector, *goquery.Selection, error) {
+	profile := scraper.profile(url)
+	name := hacEndpoint(profile, endpoint)
+	return retryTransient(ctx, profile.MaxRetries, func() (*colly.Collector, *goquery.Selection, error) {
+		if err := waitForRateLimit(ctx, url, profile.RequestsPerSecond, profile.RequestBurst); err != nil {
+			return nil, nil, err
+		}
+
+		start := time.Now()
+		collector, html, err := navigate(ctx, collector, url, endpoint, profile.Routes.Login)
+		observeHACRequest(name, start, err)
+
+		return collector, html, err
+	})
+}
+
+func (scraper Scraper) Post(ctx context.Context, collector *colly.Collector, url, endpoint string, formData map[string]string) (*colly.Collector, *goquery.Selection, error) {
+	profile := scraper.profile(url)
+	name := hacEndpoint(profile, endpoint)
+	return retryTransient(ctx, profile.MaxRetries, func() (*colly.Collector, *goquery.Selection, error) {
+		if err := waitForRateLimit(ctx, url, profile.RequestsPerSecond, profile.RequestBurst); err != nil {
+			return nil, nil, err
+		}
+
+		start := time.Now()
+		collector, html, err := post(ctx, collector, url, endpoint, profile.Routes.Login, formData)
+		observeHACRequest(name, start, err)
+
+		return collector, html, err
+	})
 }
 
-func (scraper Scraper) Post(collector *colly.Collector, url, endpoint string, formData map[string]string) (*colly.Collector, *goquery.Selection, error) {
-	return post(collector, url, endpoint, formData)
+// profile returns the district profile for a base URL.
+func (scraper Scraper) profile(url string) repository.DistrictProfile {
+	if scraper.Profiles == nil {
+		return repository.DefaultDistrictProfile()
+	}
+	return scraper.Profiles.Profile(url)
 }
 
-func NewScraper() *Scraper {
-	return &Scraper{}
+func NewScraper(profiles repository.ProfileProvider, transport http.RoundTripper) *Scraper {
+	return &Scraper{Profiles: profiles, Transport: transport}
 }
@@ -1,14 +1,20 @@
 package utils
 
 import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/Threqt1/HACApi/pkg/repository"
 	"github.com/gocolly/colly"
 	"github.com/google/go-cmp/cmp"
 )
 
-// scraperTest represents the expected value from NewScraper().
+// scraperTest represents the expected value from NewScraper(nil, nil).
 type scraperTest struct {
 	Value *Scraper
 }
@@ -21,10 +27,10 @@ func TestNewScraper(t *testing.T) {
 	}
 
 	// Test.
-	scraper := NewScraper()
+	scraper := NewScraper(nil, nil)
 
 	if diff := cmp.Diff(expected, scraperTest{Value: scraper}); diff != "" {
-		t.Fatalf("Failed for NewScraper() (-want, +got):\n%s", diff)
+		t.Fatalf("Failed for NewScraper(nil, nil) (-want, +got):\n%s", diff)
 	}
 }
 
@@ -34,10 +40,10 @@ func TestLogin_WithValidCredentials(t *testing.T) {
 	ts := CreateTestingServer()
 	defer ts.Close()
 
-	scraper := NewScraper()
+	scraper := NewScraper(nil, nil)
 
 	// Test.
-	collector, err := scraper.Login(ts.URL, "ABC", "123")
+	collector, err := scraper.Login(context.Background(), ts.URL, "ABC", "123")
 
 	if err != nil || collector == nil {
 		t.Fatalf("Failed for Login() with valid credentials:\n%v", err)
@@ -50,10 +56,10 @@ func TestLogin_WithInvalidCredentials(t *testing.T) {
 	ts := CreateTestingServer()
 	defer ts.Close()
 
-	scraper := NewScraper()
+	scraper := NewScraper(nil, nil)
 
 	// Test.
-	collector, err := scraper.Login(ts.URL, "123", "ABC")
+	collector, err := scraper.Login(context.Background(), ts.URL, "123", "ABC")
 
 	if err != ErrorInvalidCredentials || collector != nil {
 		t.Fatalf("Failed for Login() with invalid credentials")
@@ -63,28 +69,150 @@ func TestLogin_WithInvalidCredentials(t *testing.T) {
 // Test if Login() errors out with an invalid URL.
 func TestLogin_WithInvalidURL(t *testing.T) {
 	// Create scraper.
-	scraper := NewScraper()
+	scraper := NewScraper(nil, nil)
 
 	// Test.
-	collector, err := scraper.Login("https://fake.url", "123", "ABC")
+	collector, err := scraper.Login(context.Background(), "https://fake.url", "123", "ABC")
 
 	if err == nil || collector != nil {
 		t.Fatalf("Failed for Login() with invalid URL")
 	}
 }
 
+// Test if Login() doesn't contact HAC once the context is cancelled.
+func TestLogin_WithCancelledContext(t *testing.T) {
+	// Create testing server and scraper.
+	ts := CreateTestingServer()
+	defer ts.Close()
+
+	scraper := NewScraper(nil, nil)
+
+	// Cancel the context before logging in.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// Test.
+	collector, err := scraper.Login(ctx, ts.URL, "ABC", "123")
+
+	if !errors.Is(err, context.Canceled) || collector != nil {
+		t.Fatalf("Failed for Login() with cancelled context (-want, +got):\n- %v\n+ %v", context.Canceled, err)
+	}
+}
+
+// Test if Login() cancels the in-flight request once the context times out.
+func TestLogin_WithTimeout(t *testing.T) {
+	// Create testing server.
+	ts := CreateTestingServer()
+	defer ts.Close()
+
+	// A profile whose login page takes too long to respond.
+	profile := repository.DefaultDistrictProfile()
+	profile.Routes.Login = "/slow"
+
+	scraper := NewScraper(testScraper_Profiles{Value: profile}, nil)
+
+	// Time out long before the server responds.
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	// Test.
+	start := time.Now()
+	collector, err := scraper.Login(ctx, ts.URL, "ABC", "123")
+
+	if !errors.Is(err, context.DeadlineExceeded) || collector != nil {
+		t.Fatalf("Failed for Login() with timeout (-want, +got):\n- %v\n+ %v", context.DeadlineExceeded, err)
+	}
+
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("Failed for Login() with timeout, the request was not cancelled (took %v)", elapsed)
+	}
+}
+
+// testScraper_Profiles represents district profiles which use one profile for every district.
+type testScraper_Profiles struct {
+	Value repository.DistrictProfile
+}
+
+func (profiles testScraper_Profiles) Profile(base string) repository.DistrictProfile {
+	return profiles.Value
+}
+
+// Test if Login() posts the login form fields of the district's profile.
+func TestLogin_WithDistrictProfile(t *testing.T) {
+	// Create testing server.
+	ts := CreateTestingServer()
+	defer ts.Close()
+
+	// A profile with a different database, which the testing server rejects.
+	profile := repository.DefaultDistrictProfile()
+	profile.LoginFields["Database"] = "11"
+
+	scraper := NewScraper(testScraper_Profiles{Value: profile}, nil)
+
+	// Test.
+	collector, err := scraper.Login(context.Background(), ts.URL, "ABC", "123")
+
+	if err != ErrorInvalidCredentials || collector != nil {
+		t.Fatalf("Failed for Login() with district profile, expected the profile's login fields to be posted, got %v", err)
+	}
+}
+
+// Test if Login() tells apart the ways HAC can reject a login.
+func TestLogin_WithRejectedLogin(t *testing.T) {
+	// Create testing server and scraper.
+	ts := CreateTestingServer()
+	defer ts.Close()
+
+	scraper := NewScraper(nil, nil)
+
+	// The usernames the testing server rejects, and the errors they should cause.
+	cases := map[string]error{
+		"LOCKED": ErrorAccountLocked,
+		"MOVED":  ErrorUnexpectedLoginPage,
+		"OUTAGE": ErrorServerUnreachable,
+	}
+
+	for username, expected := range cases {
+		// Test.
+		collector, err := scraper.Login(context.Background(), ts.URL, username, "123")
+
+		if !errors.Is(err, expected) || collector != nil {
+			t.Fatalf("Failed for Login() with username %s, expected %v, got %v", username, expected, err)
+		}
+	}
+}
+
+// Test if Login() errors out when the login page has no request verification token.
+func TestLogin_WithUnexpectedLoginPage(t *testing.T) {
+	// Create a server with a login page that isn't HAC's.
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(`<!doctype html><html></html>`))
+	}))
+	defer ts.Close()
+
+	scraper := NewScraper(nil, nil)
+
+	// Test.
+	collector, err := scraper.Login(context.Background(), ts.URL, "ABC", "123")
+
+	if !errors.Is(err, ErrorUnexpectedLoginPage) || collector != nil {
+		t.Fatalf("Failed for Login() with unexpected login page, got %v", err)
+	}
+}
+
 // Test if Navigate() works with a valid URL.
 func TestNavigate_WithValidURL(t *testing.T) {
 	// Create testing server and scraper.
 	ts := CreateTestingServer()
 	defer ts.Close()
 
-	scraper := NewScraper()
+	scraper := NewScraper(nil, nil)
 
 	// Test.
 	initialCollector := colly.NewCollector(colly.Async(true), colly.AllowedDomains(strings.Split(ts.URL, "//")[1]), colly.AllowURLRevisit())
 
-	_, html, err := scraper.Navigate(initialCollector, ts.URL, "/default")
+	_, html, err := scraper.Navigate(context.Background(), initialCollector, ts.URL, "/default")
 
 	if err != nil {
 		t.Fatalf("Failed for Navigate() with valid url:\n%v", err)
@@ -104,12 +232,12 @@ func TestNavigate_WithInvalidURL(t *testing.T) {
 	ts := CreateTestingServer()
 	defer ts.Close()
 
-	scraper := NewScraper()
+	scraper := NewScraper(nil, nil)
 
 	// Test.
 	initialCollector := colly.NewCollector(colly.Async(true), colly.AllowedDomains(strings.Split(ts.URL, "//")[1]), colly.AllowURLRevisit())
 
-	_, _, err := scraper.Navigate(initialCollector, ts.URL, "/invalid")
+	_, _, err := scraper.Navigate(context.Background(), initialCollector, ts.URL, "/invalid")
 
 	if err == nil {
 		t.Fatalf("Failed for Navigate() with invalid url")
@@ -122,25 +250,111 @@ func TestNavigate_WithRedirectBack(t *testing.T) {
 	ts := CreateTestingServer()
 	defer ts.Close()
 
-	scraper := NewScraper()
+	scraper := NewScraper(nil, nil)
 
 	// Test.
 	initialCollector := colly.NewCollector(colly.Async(true), colly.AllowedDomains(strings.Split(ts.URL, "//")[1]), colly.AllowURLRevisit())
 
-	_, _, err := scraper.Navigate(initialCollector, ts.URL, "/redirect")
+	_, _, err := scraper.Navigate(context.Background(), initialCollector, ts.URL, "/redirect")
 
 	if err == nil {
 		t.Fatalf("Failed for Navigate() with redirect back")
 	}
 }
 
+// Test if Navigate() errors out with an expired session if redirected to the LogOn page.
+func TestNavigate_WithExpiredSession(t *testing.T) {
+	// Create testing server and scraper.
+	ts := CreateTestingServer()
+	defer ts.Close()
+
+	scraper := NewScraper(nil, nil)
+
+	// Test.
+	initialCollector := colly.NewCollector(colly.Async(true), colly.AllowedDomains(strings.Split(ts.URL, "//")[1]), colly.AllowURLRevisit())
+
+	_, _, err := scraper.Navigate(context.Background(), initialCollector, ts.URL, "/expired")
+
+	if err != ErrorSessionExpired {
+		t.Fatalf("Failed for Navigate() with expired session (-want, +got):\n- %v\n+ %v", ErrorSessionExpired, err)
+	}
+}
+
+// Test if Navigate() errors out with an expired session if redirected to
+// the LogOn page of a district whose profile overrides the login route.
+func TestNavigate_WithExpiredSession_ProfileLoginRoute(t *testing.T) {
+	// Create testing server and scraper.
+	ts := CreateTestingServer()
+	defer ts.Close()
+
+	// A profile whose LogOn page is where /redirect sends requests.
+	profile := repository.DefaultDistrictProfile()
+	profile.Routes.Login = "/HomeAccess/Classes/Classwork"
+
+	scraper := NewScraper(testScraper_Profiles{Value: profile}, nil)
+
+	// Test.
+	initialCollector := colly.NewCollector(colly.Async(true), colly.AllowedDomains(strings.Split(ts.URL, "//")[1]), colly.AllowURLRevisit())
+
+	_, _, err := scraper.Navigate(context.Background(), initialCollector, ts.URL, "/redirect")
+
+	if err != ErrorSessionExpired {
+		t.Fatalf("Failed for Navigate() with expired session and profile login route (-want, +got):\n- %v\n+ %v", ErrorSessionExpired, err)
+	}
+}
+
+// Test if Navigate() errors out without making a request if the context was cancelled.
+func TestNavigate_WithCancelledContext(t *testing.T) {
+	// Create testing server and scraper.
+	ts := CreateTestingServer()
+	defer ts.Close()
+
+	scraper := NewScraper(nil, nil)
+
+	// Cancel the context before navigating.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// Test.
+	_, _, err := scraper.Navigate(ctx, newCollector(ts.URL, nil), ts.URL, "/default")
+
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Failed for Navigate() with cancelled context (-want, +got):\n- %v\n+ %v", context.Canceled, err)
+	}
+}
+
+// Test if Navigate() cancels the in-flight request once the context times out.
+func TestNavigate_WithTimeout(t *testing.T) {
+	// Create testing server and scraper.
+	ts := CreateTestingServer()
+	defer ts.Close()
+
+	scraper := NewScraper(nil, nil)
+
+	// Time out long before the server responds.
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	// Test.
+	start := time.Now()
+	_, _, err := scraper.Navigate(ctx, newCollector(ts.URL, nil), ts.URL, "/slow")
+
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Failed for Navigate() with timeout (-want, +got):\n- %v\n+ %v", context.DeadlineExceeded, err)
+	}
+
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("Failed for Navigate() with timeout, the request was not cancelled (took %v)", elapsed)
+	}
+}
+
 // Test if Post() works with valid form data and URL.
 func TestPost_WithValidFormdata(t *testing.T) {
 	// Create testing server and scraper.
 	ts := CreateTestingServer()
 	defer ts.Close()
 
-	scraper := NewScraper()
+	scraper := NewScraper(nil, nil)
 
 	// Create dummy collector and form data.
 	initialCollector := colly.NewCollector(colly.Async(true), colly.AllowedDomains(strings.Split(ts.URL, "//")[1]), colly.AllowURLRevisit())
@@ -151,7 +365,7 @@ func TestPost_WithValidFormdata(t *testing.T) {
 	}
 
 	// Test.
-	_, html, err := scraper.Post(initialCollector, ts.URL, "/post", formData)
+	_, html, err := scraper.Post(context.Background(), initialCollector, ts.URL, "/post", formData)
 
 	if err != nil {
 		t.Fatalf("Failed for TestPost() with valid form data:\n%v", err)
@@ -171,7 +385,7 @@ func TestPost_WithInvalidFormData(t *testing.T) {
 	ts := CreateTestingServer()
 	defer ts.Close()
 
-	scraper := NewScraper()
+	scraper := NewScraper(nil, nil)
 
 	// Create dummy collector and form data.
 	initialCollector := colly.NewCollector(colly.Async(true), colly.AllowedDomains(strings.Split(ts.URL, "//")[1]), colly.AllowURLRevisit())
@@ -181,11 +395,16 @@ func TestPost_WithInvalidFormData(t *testing.T) {
 		"C": "1",
 	}
 
-	_, _, err := scraper.Post(initialCollector, ts.URL, "/post", formData)
+	_, _, err := scraper.Post(context.Background(), initialCollector, ts.URL, "/post", formData)
 
 	if err == nil {
 		t.Fatalf("Failed for Post() with invalid form data:\n%v", err)
 	}
+
+	// A redirect to the error page is not an expired session.
+	if err == ErrorSessionExpired {
+		t.Fatalf("Failed for Post() with invalid form data, mistaken for an expired session")
+	}
 }
 
 // Test if Post() errors out with an invalid URL.
@@ -194,7 +413,7 @@ func TestPost_WithInvalidURL(t *testing.T) {
 	ts := CreateTestingServer()
 	defer ts.Close()
 
-	scraper := NewScraper()
+	scraper := NewScraper(nil, nil)
 
 	// Create dummy collector and form data.
 	initialCollector := colly.NewCollector(colly.Async(true), colly.AllowedDomains(strings.Split(ts.URL, "//")[1]), colly.AllowURLRevisit())
@@ -204,9 +423,143 @@ func TestPost_WithInvalidURL(t *testing.T) {
 		"C": "3",
 	}
 
-	_, _, err := scraper.Post(initialCollector, ts.URL, "/invalid", formData)
+	_, _, err := scraper.Post(context.Background(), initialCollector, ts.URL, "/invalid", formData)
 
 	if err == nil {
 		t.Fatalf("Failed for Post() with an invalid URL:\n%v", err)
 	}
 }
+
+// Test if Navigate() retries requests failing transiently.
+func TestNavigate_WithTransientErrors(t *testing.T) {
+	// Create testing server and scraper.
+	ts := CreateTestingServer()
+	defer ts.Close()
+
+	scraper := NewScraper(nil, nil)
+
+	// Test.
+	_, html, err := scraper.Navigate(context.Background(), newCollector(ts.URL, nil), ts.URL, "/flaky")
+
+	if err != nil {
+		t.Fatalf("Failed for Navigate() with transient errors:\n%v", err)
+	}
+
+	// Confirm correct page was returned.
+	pageType, _ := html.Find("input[name='type']").First().Attr("value")
+
+	if diff := cmp.Diff("default", pageType); diff != "" {
+		t.Fatalf("Failed for Navigate() with transient errors (-want, +got):\n%s", diff)
+	}
+}
+
+// Test if Navigate() gives up once the district's retry budget is spent.
+func TestNavigate_WithExhaustedRetries(t *testing.T) {
+	// Create testing server and scraper.
+	ts := CreateTestingServer()
+	defer ts.Close()
+
+	// A profile which only retries once, while the testing server fails twice.
+	profile := repository.DefaultDistrictProfile()
+	profile.MaxRetries = 1
+
+	scraper := NewScraper(testScraper_Profiles{Value: profile}, nil)
+
+	// Test.
+	_, _, err := scraper.Navigate(context.Background(), newCollector(ts.URL, nil), ts.URL, "/flaky")
+
+	want := HTTPStatusError{StatusCode: http.StatusServiceUnavailable}
+	if err != want {
+		t.Fatalf("Failed for Navigate() with exhausted retries (-want, +got):\n- %v\n+ %v", want, err)
+	}
+}
+
+// Test if Navigate() doesn't retry requests failing for good.
+func TestNavigate_WithServerError(t *testing.T) {
+	// Create testing server and scraper.
+	ts := CreateTestingServer()
+	defer ts.Close()
+
+	scraper := NewScraper(nil, nil)
+
+	// Test.
+	_, _, err := scraper.Navigate(context.Background(), newCollector(ts.URL, nil), ts.URL, "/broken")
+
+	want := HTTPStatusError{StatusCode: http.StatusInternalServerError}
+	if err != want {
+		t.Fatalf("Failed for Navigate() with server error (-want, +got):\n- %v\n+ %v", want, err)
+	}
+}
+
+// Test if Post() retries requests failing transiently.
+func TestPost_WithTransientErrors(t *testing.T) {
+	// Create testing server and scraper.
+	ts := CreateTestingServer()
+	defer ts.Close()
+
+	scraper := NewScraper(nil, nil)
+
+	// Test.
+	_, html, err := scraper.Post(context.Background(), newCollector(ts.URL, nil), ts.URL, "/flaky", map[string]string{"A": "1"})
+
+	if err != nil {
+		t.Fatalf("Failed for Post() with transient errors:\n%v", err)
+	}
+
+	// Confirm correct page was returned.
+	pageType, _ := html.Find("input[name='type']").First().Attr("value")
+
+	if diff := cmp.Diff("default", pageType); diff != "" {
+		t.Fatalf("Failed for Post() with transient errors (-want, +got):\n%s", diff)
+	}
+}
+
+// Test if Probe() works with a district whose LogOn page is up.
+func TestProbe_WithValidURL(t *testing.T) {
+	// Create testing server and scraper.
+	ts := CreateTestingServer()
+	defer ts.Close()
+
+	scraper := NewScraper(nil, nil)
+
+	// Test.
+	if err := scraper.Probe(context.Background(), ts.URL); err != nil {
+		t.Fatalf("Failed for Probe() with valid URL:\n%v", err)
+	}
+}
+
+// Test if Probe() errors out when the LogOn page has no request verification token.
+func TestProbe_WithUnexpectedLoginPage(t *testing.T) {
+	// Create testing server.
+	ts := CreateTestingServer()
+	defer ts.Close()
+
+	// A profile whose LogOn page is a page without a token.
+	profile := repository.DefaultDistrictProfile()
+	profile.Routes.Login = "/default"
+
+	scraper := NewScraper(testScraper_Profiles{Value: profile}, nil)
+
+	// Test.
+	if err := scraper.Probe(context.Background(), ts.URL); err != ErrorUnexpectedLoginPage {
+		t.Fatalf("Failed for Probe() with unexpected login page, expected %v, got %v", ErrorUnexpectedLoginPage, err)
+	}
+}
+
+// Test if Probe() errors out when the LogOn page is down.
+func TestProbe_WithBrokenPage(t *testing.T) {
+	// Create testing server.
+	ts := CreateTestingServer()
+	defer ts.Close()
+
+	// A profile whose LogOn page errors out.
+	profile := repository.DefaultDistrictProfile()
+	profile.Routes.Login = "/broken"
+
+	scraper := NewScraper(testScraper_Profiles{Value: profile}, nil)
+
+	// Test.
+	if err := scraper.Probe(context.Background(), ts.URL); !errors.Is(err, ErrorServerUnreachable) {
+		t.Fatalf("Failed for Probe() with broken page, expected %v, got %v", ErrorServerUnreachable, err)
+	}
+}
@@ -0,0 +1,47 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strings"
+
+	"github.com/Threqt1/HACApi/pkg/repository"
+	"github.com/gocolly/colly"
+)
+
+// ErrorSessionExpired is the error thrown when HAC redirects a request back to the LogOn page.
+var ErrorSessionExpired = errors.New("session expired")
+
+// isLoginRedirect checks if a request was redirected to the HAC LogOn page,
+// at the login route of the district's profile.
+func isLoginRedirect(requestURL *url.URL, loginRoute string) bool {
+	loginURL, err := url.Parse(loginRoute)
+	if err != nil {
+		return false
+	}
+
+	return strings.EqualFold(requestURL.Path, loginURL.Path)
+}
+
+// RetryOnExpiredSession runs a query with a logged-in collector. If the HAC session behind
+// the collector expired, the stale collector cached under key is evicted, the user is logged
+// in again with ctx and the query is retried once.
+func RetryOnExpiredSession[T any](ctx context.Context, cache repository.CacheProvider, key string, collector *colly.Collector, query func(*colly.Collector) (T, error)) (T, error) {
+	// Try the query with the cached collector.
+	res, err := query(collector)
+
+	if !errors.Is(err, ErrorSessionExpired) {
+		return res, err
+	}
+
+	// Log in again, replacing the stale collector.
+	collector, err = cache.Relogin(ctx, key)
+
+	if err != nil {
+		return res, err
+	}
+
+	// Retry the query once.
+	return query(collector)
+}
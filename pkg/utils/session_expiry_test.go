@@ -0,0 +1,116 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Threqt1/HACApi/app/models"
+	"github.com/gocolly/colly"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+// Represents a dummy cache which counts how many times it logged in again.
+type testSessionExpiry_DummyCache struct {
+	Relogins *int  // The amount of times Relogin() was called.
+	Err      error // The error to log in again with, if any.
+}
+
+// Represents the GetOrLogin method for a dummy cache (not needed).
+func (cache testSessionExpiry_DummyCache) GetOrLogin(ctx context.Context, key string) (*colly.Collector, error) {
+	return nil, nil
+}
+
+// Represents the Relogin method for a dummy cache.
+func (cache testSessionExpiry_DummyCache) Relogin(ctx context.Context, key string) (*colly.Collector, error) {
+	*cache.Relogins++
+	return colly.NewCollector(), cache.Err
+}
+
+// Represents the NewSession method for a dummy cache (not needed).
+func (cache testSessionExpiry_DummyCache) NewSession(credentials models.BaseRequestBody) (models.Session, error) {
+	return models.Session{}, nil
+}
+
+// Represents the GetSession method for a dummy cache (not needed).
+func (cache testSessionExpiry_DummyCache) GetSession(token string) (models.BaseRequestBody, error) {
+	return models.BaseRequestBody{}, nil
+}
+
+// Represents the DeleteSession method for a dummy cache (not needed).
+func (cache testSessionExpiry_DummyCache) DeleteSession(token string) error {
+	return nil
+}
+
+// Represents the NewCalendarSession method for a dummy cache (not needed).
+func (cache testSessionExpiry_DummyCache) NewCalendarSession(credentials models.BaseRequestBody) (models.Session, error) {
+	return models.Session{}, nil
+}
+
+// Represents the GetCalendarSession method for a dummy cache (not needed).
+func (cache testSessionExpiry_DummyCache) GetCalendarSession(token string) (models.BaseRequestBody, error) {
+	return models.BaseRequestBody{}, nil
+}
+
+// Represents the DeleteCalendarSession method for a dummy cache (not needed).
+func (cache testSessionExpiry_DummyCache) DeleteCalendarSession(token string) error {
+	return nil
+}
+
+// Represents the Run method for a dummy cache (not needed).
+func (cache testSessionExpiry_DummyCache) Run(ctx context.Context) {
+}
+
+// testSessionExpiry_Test represents an expected output from RetryOnExpiredSession().
+type testSessionExpiry_Test struct {
+	Queries  int   // The amount of times the query ran.
+	Relogins int   // The amount of times the user was logged in again.
+	Error    error // The returned error, if any.
+}
+
+// testSessionExpiry_Case represents an individual test case for RetryOnExpiredSession().
+type testSessionExpiry_Case struct {
+	Name       string
+	Test       testSessionExpiry_Test
+	QueryErrs  []error // The errors the query returns, in order of calls.
+	ReloginErr error   // The error logging in again returns, if any.
+}
+
+// TestRetryOnExpiredSession tests RetryOnExpiredSession() for different query results.
+func TestRetryOnExpiredSession(t *testing.T) {
+	errOther := errors.New("other")
+
+	// Set up all test cases.
+	cases := []testSessionExpiry_Case{
+		// Successful queries should not log in again.
+		{Name: "Success", QueryErrs: []error{nil}, Test: testSessionExpiry_Test{Queries: 1, Relogins: 0, Error: nil}},
+		// Other errors should not log in again.
+		{Name: "Other Error", QueryErrs: []error{errOther}, Test: testSessionExpiry_Test{Queries: 1, Relogins: 0, Error: errOther}},
+		// Expired sessions should log in again and retry.
+		{Name: "Expired Session", QueryErrs: []error{ErrorSessionExpired, nil}, Test: testSessionExpiry_Test{Queries: 2, Relogins: 1, Error: nil}},
+		// Expired sessions should only be retried once.
+		{Name: "Expired Session Twice", QueryErrs: []error{ErrorSessionExpired, ErrorSessionExpired}, Test: testSessionExpiry_Test{Queries: 2, Relogins: 1, Error: ErrorSessionExpired}},
+		// Failing to log in again should not retry.
+		{Name: "Relogin Failure", QueryErrs: []error{ErrorSessionExpired}, ReloginErr: ErrorInvalidCredentials, Test: testSessionExpiry_Test{Queries: 1, Relogins: 1, Error: ErrorInvalidCredentials}},
+	}
+
+	for _, test := range cases {
+		relogins := 0
+		queries := 0
+		cache := testSessionExpiry_DummyCache{Relogins: &relogins, Err: test.ReloginErr}
+
+		// Test.
+		_, err := RetryOnExpiredSession(context.Background(), cache, "key", nil, func(collector *colly.Collector) (int, error) {
+			err := test.QueryErrs[queries]
+			queries++
+			return queries, err
+		})
+
+		got := testSessionExpiry_Test{Queries: queries, Relogins: relogins, Error: err}
+
+		if diff := cmp.Diff(test.Test, got, cmpopts.EquateErrors()); diff != "" {
+			t.Fatalf("Failed for RetryOnExpiredSession() %s (-want, +got):\n%s", test.Name, diff)
+		}
+	}
+}
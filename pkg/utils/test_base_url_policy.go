@@ -0,0 +1,14 @@
+package utils
+
+import (
+	"github.com/Threqt1/HACApi/pkg/repository"
+)
+
+// NewTestBaseURLPolicy makes a base URL policy meant to be
+// used during testing. Only the host of the fake base is
+// allowed.
+func NewTestBaseURLPolicy() *BaseURLPolicy {
+	return &BaseURLPolicy{
+		AllowedHosts: []string{repository.FakeBase[len("https://"):]},
+	}
+}
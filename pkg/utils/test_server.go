@@ -7,6 +7,10 @@ import (
 	"os"
 	"reflect"
 	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/Threqt1/HACApi/pkg/repository"
 )
 
 // ExpectedServerResponse represents a response to a
@@ -54,9 +58,27 @@ func CreateTestingServer() *httptest.Server {
 			}
 			// Get the request body params.
 			got, err := io.ReadAll(r.Body)
+			gotParams := ParseRequestBody(string(got))
+
+			// Simulate the other ways a login can fail.
+			switch gotParams["LogOnDetails.UserName"] {
+			case "LOCKED":
+				// Send back login HTML with a locked account message.
+				w.Header().Set("Content-Type", "text/html")
+				w.Write([]byte(strings.Replace(string(html), "</body>", `<div class="validation-summary-errors">Your account has been locked.</div></body>`, 1)))
+				return
+			case "MOVED":
+				// Redirect to a page which isn't the login page.
+				http.Redirect(w, r, "/default", http.StatusSeeOther)
+				return
+			case "OUTAGE":
+				// Error out like an unavailable server.
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
 
 			// Compare them, if possible.
-			if err == nil && reflect.DeepEqual(expected, ParseRequestBody(string(got))) {
+			if err == nil && reflect.DeepEqual(expected, gotParams) {
 				// Redirect to classwork endpoint.
 				http.Redirect(w, r, "/HomeAccess/Classes/Classwork", http.StatusSeeOther)
 			} else {
@@ -90,11 +112,74 @@ func CreateTestingServer() *httptest.Server {
 		}
 	})
 
+	// Handle calls to repository.WEEK_VIEW_ROUTE.
+	mux.HandleFunc(repository.WEEK_VIEW_ROUTE, func(w http.ResponseWriter, r *http.Request) {
+		// Get the static week view page, for the week of 10/03/2022.
+		html, err := os.ReadFile("../../test/weekview.html")
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		page := string(html)
+
+		// Move the page to the week containing startDate, if one was passed.
+		if startDate := r.URL.Query().Get("startDate"); startDate != "" {
+			date, err := time.Parse("01/02/2006", startDate)
+			if err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			monday := date.AddDate(0, 0, -(int(date.Weekday())+6)%7)
+
+			week := time.Date(2022, time.October, 3, 0, 0, 0, 0, time.UTC)
+			for day := 0; day < 5; day++ {
+				page = strings.Replace(page, week.AddDate(0, 0, day).Format("01/02/2006"), monday.AddDate(0, 0, day).Format("01/02/2006"), 1)
+			}
+		}
+
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(page))
+	})
+
 	// Dummy handler to test redirects.
 	mux.HandleFunc("/redirect", func(w http.ResponseWriter, r *http.Request) {
 		http.Redirect(w, r, "/HomeAccess/Classes/Classwork", http.StatusSeeOther)
 	})
 
+	// Dummy handler to test redirects to the LogOn page, as if the session expired.
+	mux.HandleFunc("/expired", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, repository.LOGIN_ROUTE, http.StatusFound)
+	})
+
+	// Dummy handler which hangs, as if the server was slow.
+	mux.HandleFunc("/slow", func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(5 * time.Second):
+		}
+	})
+
+	// Dummy handler which is unavailable for the first two requests, as if HAC was restarting.
+	var flakyRequests int32
+	mux.HandleFunc("/flaky", func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&flakyRequests, 1) <= 2 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		html, err := os.ReadFile("../../test/default.html")
+
+		if err == nil {
+			w.Header().Set("Content-Type", "text/html")
+			w.Write([]byte(html))
+		}
+	})
+
+	// Dummy handler which always fails, in a way which is not worth retrying.
+	mux.HandleFunc("/broken", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+
 	// Handle a default post request.
 	mux.HandleFunc("/post", func(w http.ResponseWriter, r *http.Request) {
 		if r.Method == "POST" {
@@ -16,6 +16,8 @@ func BuildConnectionURL(n string) (string, error) {
 	switch n {
 	case "fiber":
 		url = fmt.Sprintf("%s:%s", os.Getenv("SERVER_HOST"), os.Getenv("SERVER_PORT"))
+	case "redis":
+		url = fmt.Sprintf("%s:%s", os.Getenv("REDIS_HOST"), os.Getenv("REDIS_PORT"))
 	default:
 		return "", ErrorURLConnection
 	}
@@ -33,6 +33,8 @@ func TestBuildConnectionURL(t *testing.T) {
 	cases := []testUrl_Case{
 		// Check for host URL.
 		{Input: "fiber", Test: testUrl_Test{Value: "127.0.0.1:3000", Error: nil}, Env: []testUrl_EnvVar{{Key: "SERVER_HOST", Value: "127.0.0.1"}, {Key: "SERVER_PORT", Value: "3000"}}},
+		// Check for Redis URL.
+		{Input: "redis", Test: testUrl_Test{Value: "127.0.0.1:6379", Error: nil}, Env: []testUrl_EnvVar{{Key: "REDIS_HOST", Value: "127.0.0.1"}, {Key: "REDIS_PORT", Value: "6379"}}},
 		// Check for error on empty/invalid input.
 		{Input: "", Test: testUrl_Test{Value: "", Error: ErrorURLConnection}},
 	}
@@ -0,0 +1,45 @@
+package utils
+
+import (
+	"fmt"
+
+	"github.com/Threqt1/HACApi/app/models"
+	"github.com/Threqt1/HACApi/platform/export"
+	"github.com/gofiber/fiber/v2"
+)
+
+// The MIME types of the formats a response can be sent back in.
+const (
+	csvMIMEType  = "text/csv"
+	xlsxMIMEType = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+)
+
+// ResponseFormat returns the format to send a response back in: the format
+// param if one was passed, otherwise whichever the Accept header prefers.
+func ResponseFormat(ctx *fiber.Ctx, format string) string {
+	if format != "" {
+		return format
+	}
+
+	switch ctx.Accepts(fiber.MIMEApplicationJSON, csvMIMEType, xlsxMIMEType) {
+	case csvMIMEType:
+		return models.FormatCSV
+	case xlsxMIMEType:
+		return models.FormatXLSX
+	}
+	return models.FormatJSON
+}
+
+// SendWorkbook sends back a workbook as a CSV file or a spreadsheet,
+// named filename with the extension of the format.
+func SendWorkbook(ctx *fiber.Ctx, workbook export.Workbook, format, filename string) error {
+	ctx.Set(fiber.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", filename+"."+format))
+
+	if format == models.FormatXLSX {
+		ctx.Set(fiber.HeaderContentType, xlsxMIMEType)
+		return workbook.WriteXLSX(ctx)
+	}
+
+	ctx.Set(fiber.HeaderContentType, csvMIMEType+"; charset=utf-8")
+	return workbook.WriteCSV(ctx)
+}
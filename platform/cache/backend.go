@@ -0,0 +1,83 @@
+package cache
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"time"
+)
+
+// ErrorNotFound is the error thrown when a key is not stored in a backend.
+var ErrorNotFound = errors.New("key not found")
+
+// ErrorCorruptEntry is the error thrown when a stored entry can't be decrypted.
+var ErrorCorruptEntry = errors.New("corrupt cache entry")
+
+// Backend is a key-value store which persists logged-in
+// sessions, so they can be shared between restarts and instances.
+// Run removes expired entries until ctx is done.
+type Backend interface {
+	Get(key string) ([]byte, error)
+	Set(key string, value []byte, ttl time.Duration) error
+	Delete(key string) error
+	Run(ctx context.Context)
+}
+
+// backendKey hashes a secret into a key safe to store in a backend,
+// so neither credentials nor session tokens are ever stored as keys.
+func backendKey(prefix, secret string) string {
+	hash := sha256.Sum256([]byte("id\n" + secret))
+	return prefix + hex.EncodeToString(hash[:])
+}
+
+// seal encrypts a value with a key derived from a secret, so only
+// someone who knows the secret can read the stored value.
+func seal(secret string, value []byte) ([]byte, error) {
+	gcm, err := newGCM(secret)
+	if err != nil {
+		return nil, err
+	}
+
+	// Prepend a random nonce to the ciphertext.
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	return gcm.Seal(nonce, nonce, value, nil), nil
+}
+
+// unseal decrypts a value encrypted by seal.
+func unseal(secret string, sealed []byte) ([]byte, error) {
+	gcm, err := newGCM(secret)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(sealed) < gcm.NonceSize() {
+		return nil, ErrorCorruptEntry
+	}
+
+	value, err := gcm.Open(nil, sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():], nil)
+	if err != nil {
+		return nil, ErrorCorruptEntry
+	}
+
+	return value, nil
+}
+
+// newGCM creates an AES-GCM cipher with a key derived from a secret.
+func newGCM(secret string) (cipher.AEAD, error) {
+	key := sha256.Sum256([]byte("key\n" + secret))
+
+	block, err := aes.NewCipher(key[:])
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
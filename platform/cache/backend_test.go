@@ -0,0 +1,173 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+// testBackend_Case represents an individual backend to test.
+type testBackend_Case struct {
+	Name    string
+	Backend Backend
+}
+
+// TestBackends tests Get(), Set() and Delete() for every backend.
+func TestBackends(t *testing.T) {
+	// Set up the backends.
+	fileBackend, err := NewFileBackend(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to create file backend: %v", err)
+	}
+
+	redisServer, err := NewTestRedisServer("password")
+	if err != nil {
+		t.Fatalf("Failed to start test redis server: %v", err)
+	}
+	defer redisServer.Close()
+
+	cases := []testBackend_Case{
+		{Name: "Memory", Backend: NewMemoryBackend()},
+		{Name: "File", Backend: fileBackend},
+		{Name: "Redis", Backend: NewRedisBackend(redisServer.Addr, redisServer.Password)},
+	}
+
+	for _, test := range cases {
+		// Missing keys should not be found.
+		_, err := test.Backend.Get("missing")
+		if diff := cmp.Diff(ErrorNotFound, err, cmpopts.EquateErrors()); diff != "" {
+			t.Fatalf("Failed for %s Get() missing key (-want, +got)\n%s", test.Name, diff)
+		}
+
+		// Stored values should be returned as is.
+		value := []byte("value\r\nwith\x00binary")
+		if err := test.Backend.Set("key", value, time.Minute); err != nil {
+			t.Fatalf("Failed for %s Set(): %v", test.Name, err)
+		}
+		got, err := test.Backend.Get("key")
+		if err != nil {
+			t.Fatalf("Failed for %s Get(): %v", test.Name, err)
+		}
+		if diff := cmp.Diff(value, got); diff != "" {
+			t.Fatalf("Failed for %s Get() stored key (-want, +got)\n%s", test.Name, diff)
+		}
+
+		// Deleted values should not be found.
+		if err := test.Backend.Delete("key"); err != nil {
+			t.Fatalf("Failed for %s Delete(): %v", test.Name, err)
+		}
+		_, err = test.Backend.Get("key")
+		if diff := cmp.Diff(ErrorNotFound, err, cmpopts.EquateErrors()); diff != "" {
+			t.Fatalf("Failed for %s Get() deleted key (-want, +got)\n%s", test.Name, diff)
+		}
+
+		// Expired values should not be found.
+		if err := test.Backend.Set("expiring", value, 10*time.Millisecond); err != nil {
+			t.Fatalf("Failed for %s Set(): %v", test.Name, err)
+		}
+		time.Sleep(20 * time.Millisecond)
+		_, err = test.Backend.Get("expiring")
+		if diff := cmp.Diff(ErrorNotFound, err, cmpopts.EquateErrors()); diff != "" {
+			t.Fatalf("Failed for %s Get() expired key (-want, +got)\n%s", test.Name, diff)
+		}
+	}
+}
+
+// TestRedisBackend_WrongPassword tests that the Redis backend surfaces authentication errors.
+func TestRedisBackend_WrongPassword(t *testing.T) {
+	redisServer, err := NewTestRedisServer("password")
+	if err != nil {
+		t.Fatalf("Failed to start test redis server: %v", err)
+	}
+	defer redisServer.Close()
+
+	backend := NewRedisBackend(redisServer.Addr, "wrong")
+	_, err = backend.Get("key")
+
+	var replyErr redisError
+	if !errors.As(err, &replyErr) {
+		t.Fatalf("Failed for Get() with wrong password, expected a redis error, got %v", err)
+	}
+}
+
+// TestMemoryBackend_Run tests that the memory backend deletes expired entries which are never read.
+func TestMemoryBackend_Run(t *testing.T) {
+	backend := NewMemoryBackend()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go backend.Run(ctx)
+
+	if err := backend.Set("expiring", []byte("value"), 10*time.Millisecond); err != nil {
+		t.Fatalf("Failed for Set(): %v", err)
+	}
+
+	// Wait for the janitor to delete the entry.
+	deadline := time.Now().Add(time.Second)
+	for backend.Cache.Len() != 0 {
+		if time.Now().After(deadline) {
+			t.Fatalf("Failed for Run(), expired entry was not deleted")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// TestFileBackend_Sweep tests that the file backend deletes expired entries which are never read.
+func TestFileBackend_Sweep(t *testing.T) {
+	backend, err := NewFileBackend(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to create file backend: %v", err)
+	}
+
+	if err := backend.Set("expiring", []byte("value"), 10*time.Millisecond); err != nil {
+		t.Fatalf("Failed for Set(): %v", err)
+	}
+	if err := backend.Set("fresh", []byte("value"), time.Minute); err != nil {
+		t.Fatalf("Failed for Set(): %v", err)
+	}
+	time.Sleep(20 * time.Millisecond)
+
+	// Test.
+	backend.sweep()
+
+	files, err := os.ReadDir(backend.Dir)
+	if err != nil {
+		t.Fatalf("Failed to read file backend directory: %v", err)
+	}
+	names := []string{}
+	for _, file := range files {
+		names = append(names, file.Name())
+	}
+
+	if diff := cmp.Diff([]string{"fresh"}, names); diff != "" {
+		t.Fatalf("Failed for sweep() (-want, +got)\n%s", diff)
+	}
+}
+
+// TestSeal tests that sealed values can only be unsealed with the same secret.
+func TestSeal(t *testing.T) {
+	value := []byte("cookies")
+
+	sealed, err := seal("secret", value)
+	if err != nil {
+		t.Fatalf("Failed for seal(): %v", err)
+	}
+
+	got, err := unseal("secret", sealed)
+	if err != nil {
+		t.Fatalf("Failed for unseal(): %v", err)
+	}
+	if diff := cmp.Diff(value, got); diff != "" {
+		t.Fatalf("Failed for unseal() with the right secret (-want, +got)\n%s", diff)
+	}
+
+	_, err = unseal("wrong", sealed)
+	if diff := cmp.Diff(ErrorCorruptEntry, err, cmpopts.EquateErrors()); diff != "" {
+		t.Fatalf("Failed for unseal() with the wrong secret (-want, +got)\n%s", diff)
+	}
+}
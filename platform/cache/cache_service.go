@@ -1,58 +1,305 @@
 package cache
 
 import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"net/http"
 	"strings"
 	"time"
 
+	"github.com/Threqt1/HACApi/app/models"
 	"github.com/Threqt1/HACApi/pkg/repository"
+	"github.com/Threqt1/HACApi/platform/metrics"
 	"github.com/gocolly/colly"
 	"github.com/jellydator/ttlcache/v3"
+	"golang.org/x/sync/singleflight"
 )
 
+// How long a logged-in cookie jar stays cached.
+const collectorTTL = 10 * time.Minute
+
+// How long a minted session token stays valid.
+const sessionTTL = 24 * time.Hour
+
+// How long a minted calendar token stays valid, unless it is revoked.
+const calendarTTL = 365 * 24 * time.Hour
+
+// The amount of random bytes in a session token.
+const sessionTokenBytes = 32
+
 // cache format -
 // key: username\npassword\nbase
 // val: logged-in colly.Collector
+//
+// backend format -
+// key: collector-sha256(username\npassword\nbase)
+// val: cookie jar of the logged-in collector, sealed with the cache key
+// key: session-sha256(token)
+// val: credentials the session was minted for, sealed with the token
+// key: calendar-sha256(token)
+// val: credentials the calendar token was minted for, sealed with the token
 type TTLCache struct {
-	Cache *ttlcache.Cache[string, *colly.Collector]
+	Cache   *ttlcache.Cache[string, *colly.Collector]
+	Backend Backend
+	Scraper repository.ScraperProvider
+	Logins  *singleflight.Group // Coalesces concurrent logins for the same key
 }
 
 // NewCache creates a new TTL cache which stores
 // logged-in collectors for username/password combinations.
-func NewCache(scraper repository.ScraperProvider) *TTLCache {
-	// Loader to recache username/password combos if they expired and were requested again
-	loader := ttlcache.LoaderFunc[string, *colly.Collector](
-		func(cache *ttlcache.Cache[string, *colly.Collector], key string) *ttlcache.Item[string, *colly.Collector] {
-			// Get username/password
-			splitKey := strings.Split(key, "\n")
-			username, password, base := splitKey[0], splitKey[1], splitKey[2]
+// Cookie jars and sessions are persisted in the backend, so
+// they survive restarts and can be shared between instances.
+func NewCache(scraper repository.ScraperProvider, backend Backend) *TTLCache {
+	cache := ttlcache.New(
+		ttlcache.WithTTL[string, *colly.Collector](collectorTTL),
+		ttlcache.WithCapacity[string, *colly.Collector](100),
+	)
 
-			// Login
-			collector, err := scraper.Login(base, username, password)
+	// Keep the cache metrics up to date.
+	cache.OnInsertion(func(context.Context, *ttlcache.Item[string, *colly.Collector]) {
+		metrics.CacheSize.Add(1)
+	})
+	cache.OnEviction(func(_ context.Context, reason ttlcache.EvictionReason, _ *ttlcache.Item[string, *colly.Collector]) {
+		metrics.CacheSize.Add(-1)
+		metrics.CacheEvictions.Inc(evictionReason(reason))
+	})
 
-			if err != nil {
-				return nil
-			}
+	return &TTLCache{Cache: cache, Backend: backend, Scraper: scraper, Logins: &singleflight.Group{}}
+}
 
-			item := cache.Set(key, collector, 10*time.Minute)
+// evictionReason names why a collector was evicted from the cache.
+func evictionReason(reason ttlcache.EvictionReason) string {
+	switch reason {
+	case ttlcache.EvictionReasonDeleted:
+		return "deleted"
+	case ttlcache.EvictionReasonCapacityReached:
+		return "capacity"
+	case ttlcache.EvictionReasonExpired:
+		return "expired"
+	}
+	return "unknown"
+}
 
-			return item
-		},
-	)
+// load recaches a username/password combo which expired or was never cached,
+// logging in with ctx if its cookie jar can't be restored.
+func (cache TTLCache) load(ctx context.Context, key string) (*colly.Collector, error) {
+	// Another caller might have finished loading while this one waited.
+	if res := cache.Cache.Get(key); res != nil {
+		return res.Value(), nil
+	}
 
-	cache := ttlcache.New(
-		ttlcache.WithTTL[string, *colly.Collector](10*time.Minute),
-		ttlcache.WithCapacity[string, *colly.Collector](100),
-		ttlcache.WithLoader[string, *colly.Collector](loader),
-	)
+	// Get username/password
+	splitKey := strings.Split(key, "\n")
+	if len(splitKey) != 3 {
+		return nil, repository.ErrorInvalidAuthentication
+	}
+	username, password, base := splitKey[0], splitKey[1], splitKey[2]
+
+	// Restore the cookie jar of a previous login, if it is stored
+	collector, err := restoreCollector(cache.Scraper, cache.Backend, key, base)
+	if err == nil {
+		cache.Cache.Set(key, collector, ttlcache.DefaultTTL)
+		return collector, nil
+	}
+
+	// Login
+	collector, err = cache.Scraper.Login(ctx, base, username, password)
+	if err != nil {
+		return nil, err
+	}
+
+	// Persist the cookie jar, failing to do so only costs a login later
+	storeCollector(cache.Backend, key, base, collector)
+
+	cache.Cache.Set(key, collector, ttlcache.DefaultTTL)
+
+	return collector, nil
+}
+
+// restoreCollector recreates a logged-in collector from the cookie jar stored in the backend.
+func restoreCollector(scraper repository.ScraperProvider, backend Backend, key, base string) (*colly.Collector, error) {
+	sealed, err := backend.Get(backendKey("collector-", key))
+	if err != nil {
+		return nil, err
+	}
+
+	jar, err := unseal(key, sealed)
+	if err != nil {
+		return nil, err
+	}
+
+	var cookies []*http.Cookie
+	if err := json.Unmarshal(jar, &cookies); err != nil {
+		return nil, err
+	}
+
+	return scraper.Restore(base, cookies)
+}
+
+// storeCollector persists the cookie jar of a logged-in collector in the backend.
+func storeCollector(backend Backend, key, base string, collector *colly.Collector) error {
+	jar, err := json.Marshal(collector.Cookies(base))
+	if err != nil {
+		return err
+	}
+
+	sealed, err := seal(key, jar)
+	if err != nil {
+		return err
+	}
+
+	return backend.Set(backendKey("collector-", key), sealed, collectorTTL)
+}
+
+// GetOrLogin returns the collector cached for key, logging in if there is none.
+// Concurrent callers missing the same key wait on a single login, and share
+// its result or error. The login is cancelled along with the context of the
+// caller which started it, in which case the other callers start another.
+func (cache TTLCache) GetOrLogin(ctx context.Context, key string) (*colly.Collector, error) {
+	if res := cache.Cache.Get(key); res != nil {
+		metrics.CacheHits.Inc()
+		return res.Value(), nil
+	}
+	metrics.CacheMisses.Inc()
+
+	for {
+		started := false
+		res, err, _ := cache.Logins.Do(key, func() (interface{}, error) {
+			started = true
+			return cache.load(ctx, key)
+		})
 
-	return &TTLCache{Cache: cache}
+		// Another caller's login was cancelled, so try again with this caller's context.
+		if !started && ctx.Err() == nil && (errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		return res.(*colly.Collector), nil
+	}
 }
 
-func (cache TTLCache) GetOrLogin(key string) (*colly.Collector, error) {
-	res := cache.Cache.Get(key)
-	if res == nil {
-		return nil, errors.New("not found")
+// Relogin evicts the collector cached for key, and logs in again with ctx.
+func (cache TTLCache) Relogin(ctx context.Context, key string) (*colly.Collector, error) {
+	if err := cache.evict(key); err != nil {
+		return nil, err
 	}
-	return res.Value(), nil
+	return cache.GetOrLogin(ctx, key)
+}
+
+// Run deletes expired collectors and backend entries until ctx is done.
+func (cache TTLCache) Run(ctx context.Context) {
+	go cache.Cache.Start()
+	defer cache.Cache.Stop()
+
+	cache.Backend.Run(ctx)
+}
+
+// evict removes the collector cached for key from memory and from the backend.
+func (cache TTLCache) evict(key string) error {
+	cache.Cache.Delete(key)
+	return cache.Backend.Delete(backendKey("collector-", key))
+}
+
+// NewSession mints a new opaque session token for the given credentials.
+func (cache TTLCache) NewSession(credentials models.BaseRequestBody) (models.Session, error) {
+	return cache.mint("session-", sessionTTL, credentials)
+}
+
+// GetSession returns the credentials bound to a session token.
+func (cache TTLCache) GetSession(token string) (models.BaseRequestBody, error) {
+	return cache.resolve("session-", token)
+}
+
+// NewCalendarSession mints a new opaque calendar token for the given credentials.
+// Calendar tokens are only accepted by the calendar endpoints, and last far longer
+// than sessions, so calendar apps can stay subscribed.
+func (cache TTLCache) NewCalendarSession(credentials models.BaseRequestBody) (models.Session, error) {
+	return cache.mint("calendar-", calendarTTL, credentials)
+}
+
+// GetCalendarSession returns the credentials bound to a calendar token.
+func (cache TTLCache) GetCalendarSession(token string) (models.BaseRequestBody, error) {
+	return cache.resolve("calendar-", token)
+}
+
+// DeleteCalendarSession revokes a calendar token.
+func (cache TTLCache) DeleteCalendarSession(token string) error {
+	if _, err := cache.GetCalendarSession(token); err != nil {
+		return err
+	}
+	return cache.Backend.Delete(backendKey("calendar-", token))
+}
+
+// mint stores credentials in the backend under the prefix, keyed with a new
+// random token they are sealed with, for ttl.
+func (cache TTLCache) mint(prefix string, ttl time.Duration, credentials models.BaseRequestBody) (models.Session, error) {
+	// Generate a random token.
+	tokenBytes := make([]byte, sessionTokenBytes)
+	if _, err := rand.Read(tokenBytes); err != nil {
+		return models.Session{}, err
+	}
+	token := hex.EncodeToString(tokenBytes)
+
+	// Seal the credentials with the token, so they can't be read without it.
+	credentialsJSON, err := json.Marshal(credentials)
+	if err != nil {
+		return models.Session{}, err
+	}
+	sealed, err := seal(token, credentialsJSON)
+	if err != nil {
+		return models.Session{}, err
+	}
+
+	// Store the credentials under the token.
+	expires := time.Now().Add(ttl)
+	if err := cache.Backend.Set(backendKey(prefix, token), sealed, ttl); err != nil {
+		return models.Session{}, err
+	}
+
+	return models.Session{Token: token, Expires: expires}, nil
+}
+
+// resolve returns the credentials stored under the prefix for a token.
+func (cache TTLCache) resolve(prefix, token string) (models.BaseRequestBody, error) {
+	sealed, err := cache.Backend.Get(backendKey(prefix, token))
+	if errors.Is(err, ErrorNotFound) {
+		return models.BaseRequestBody{}, repository.ErrorInvalidSession
+	}
+	if err != nil {
+		return models.BaseRequestBody{}, err
+	}
+
+	credentialsJSON, err := unseal(token, sealed)
+	if err != nil {
+		return models.BaseRequestBody{}, repository.ErrorInvalidSession
+	}
+
+	var credentials models.BaseRequestBody
+	if err := json.Unmarshal(credentialsJSON, &credentials); err != nil {
+		return models.BaseRequestBody{}, repository.ErrorInvalidSession
+	}
+
+	return credentials, nil
+}
+
+// DeleteSession evicts a session token along with the collector
+// cached for its credentials.
+func (cache TTLCache) DeleteSession(token string) error {
+	credentials, err := cache.GetSession(token)
+	if err != nil {
+		return err
+	}
+
+	// Evict the logged-in collector and the session.
+	if err := cache.evict(fmt.Sprintf("%s\n%s\n%s", credentials.Username, credentials.Password, credentials.Base)); err != nil {
+		return err
+	}
+
+	return cache.Backend.Delete(backendKey("session-", token))
 }
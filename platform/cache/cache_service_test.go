@@ -0,0 +1,226 @@
+package cache
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/Threqt1/HACApi/app/models"
+	"github.com/Threqt1/HACApi/pkg/repository"
+	"github.com/gocolly/colly"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+// The base used for logging in during testing.
+const testCache_Base = "https://hac.example.com"
+
+// Represents a dummy scraper which counts how many times it logged in and restored cookies.
+type testCache_DummyScraper struct {
+	Logins   *int32        // The amount of times Login() was called.
+	Restores *int32        // The amount of times Restore() was called.
+	Delay    time.Duration // How long logging in takes.
+	Err      error         // The error to log in with, if any.
+}
+
+// Represents the Login method for a dummy scraper, which sets a session cookie.
+func (scraper testCache_DummyScraper) Login(ctx context.Context, base, username, password string) (*colly.Collector, error) {
+	atomic.AddInt32(scraper.Logins, 1)
+	time.Sleep(scraper.Delay)
+	if scraper.Err != nil {
+		return nil, scraper.Err
+	}
+	collector := colly.NewCollector()
+	collector.SetCookies(base, []*http.Cookie{{Name: "session", Value: username}})
+	return collector, nil
+}
+
+// Represents the Restore method for a dummy scraper.
+func (scraper testCache_DummyScraper) Restore(base string, cookies []*http.Cookie) (*colly.Collector, error) {
+	atomic.AddInt32(scraper.Restores, 1)
+	collector := colly.NewCollector()
+	collector.SetCookies(base, cookies)
+	return collector, nil
+}
+
+// Represents the Probe method for a dummy scraper (not needed).
+func (scraper testCache_DummyScraper) Probe(ctx context.Context, base string) error {
+	return nil
+}
+
+// Represents the Navigate method for a dummy scraper (not needed).
+func (scraper testCache_DummyScraper) Navigate(ctx context.Context, collector *colly.Collector, url, endpoint string) (*colly.Collector, *goquery.Selection, error) {
+	return collector, nil, nil
+}
+
+// Represents the Post method for a dummy scraper (not needed).
+func (scraper testCache_DummyScraper) Post(ctx context.Context, collector *colly.Collector, url, endpoint string, formData map[string]string) (*colly.Collector, *goquery.Selection, error) {
+	return collector, nil, nil
+}
+
+// TestTTLCache_Restart tests that logins and sessions survive a restart when the backend is shared.
+func TestTTLCache_Restart(t *testing.T) {
+	var logins, restores int32
+	scraper := testCache_DummyScraper{Logins: &logins, Restores: &restores}
+	backend := NewMemoryBackend()
+	credentials := models.BaseRequestBody{Username: "user", Password: "pass", Base: testCache_Base}
+	key := fmt.Sprintf("%s\n%s\n%s", credentials.Username, credentials.Password, credentials.Base)
+
+	// Log in and mint a session on the first instance.
+	first := NewCache(scraper, backend)
+	if _, err := first.GetOrLogin(context.Background(), key); err != nil {
+		t.Fatalf("Failed for GetOrLogin() on first instance: %v", err)
+	}
+	session, err := first.NewSession(credentials)
+	if err != nil {
+		t.Fatalf("Failed for NewSession(): %v", err)
+	}
+
+	// Resolve the session and the login on a second instance.
+	second := NewCache(scraper, backend)
+	got, err := second.GetSession(session.Token)
+	if err != nil {
+		t.Fatalf("Failed for GetSession() on second instance: %v", err)
+	}
+	if diff := cmp.Diff(credentials, got); diff != "" {
+		t.Fatalf("Failed for GetSession() on second instance (-want, +got)\n%s", diff)
+	}
+
+	collector, err := second.GetOrLogin(context.Background(), key)
+	if err != nil {
+		t.Fatalf("Failed for GetOrLogin() on second instance: %v", err)
+	}
+	if diff := cmp.Diff([]int32{1, 1}, []int32{logins, restores}); diff != "" {
+		t.Fatalf("Failed for GetOrLogin() on second instance, expected to restore instead of log in (-want, +got)\n%s", diff)
+	}
+	if cookies := collector.Cookies(testCache_Base); len(cookies) != 1 || cookies[0].Value != "user" {
+		t.Fatalf("Failed for GetOrLogin() on second instance, cookies were not restored: %v", cookies)
+	}
+
+	// Logging out on one instance should log out everywhere.
+	if err := second.DeleteSession(session.Token); err != nil {
+		t.Fatalf("Failed for DeleteSession(): %v", err)
+	}
+	_, err = first.GetSession(session.Token)
+	if diff := cmp.Diff(repository.ErrorInvalidSession, err, cmpopts.EquateErrors()); diff != "" {
+		t.Fatalf("Failed for GetSession() after DeleteSession() (-want, +got)\n%s", diff)
+	}
+}
+
+// TestTTLCache_CalendarSessions tests that calendar tokens outlive sessions, are only
+// resolved as calendar tokens, and can be revoked without logging the user out.
+func TestTTLCache_CalendarSessions(t *testing.T) {
+	var logins, restores int32
+	cache := NewCache(testCache_DummyScraper{Logins: &logins, Restores: &restores}, NewMemoryBackend())
+	credentials := models.BaseRequestBody{Username: "user", Password: "pass", Base: testCache_Base}
+
+	session, err := cache.NewSession(credentials)
+	if err != nil {
+		t.Fatalf("Failed for NewSession(): %v", err)
+	}
+	calendar, err := cache.NewCalendarSession(credentials)
+	if err != nil {
+		t.Fatalf("Failed for NewCalendarSession(): %v", err)
+	}
+	if !calendar.Expires.After(session.Expires.Add(300 * 24 * time.Hour)) {
+		t.Fatalf("Failed for NewCalendarSession(), expected it to last far longer than a session, expires %v", calendar.Expires)
+	}
+
+	// The calendar token resolves, but not as a session.
+	got, err := cache.GetCalendarSession(calendar.Token)
+	if diff := cmp.Diff(credentials, got); err != nil || diff != "" {
+		t.Fatalf("Failed for GetCalendarSession() (-want, +got)\n%s%v", diff, err)
+	}
+	if _, err := cache.GetSession(calendar.Token); !errors.Is(err, repository.ErrorInvalidSession) {
+		t.Fatalf("Failed for GetSession() with a calendar token, expected %v, got %v", repository.ErrorInvalidSession, err)
+	}
+
+	// Revoking the calendar token leaves the session working.
+	if err := cache.DeleteCalendarSession(calendar.Token); err != nil {
+		t.Fatalf("Failed for DeleteCalendarSession(): %v", err)
+	}
+	if _, err := cache.GetCalendarSession(calendar.Token); !errors.Is(err, repository.ErrorInvalidSession) {
+		t.Fatalf("Failed for GetCalendarSession() after DeleteCalendarSession(), expected %v, got %v", repository.ErrorInvalidSession, err)
+	}
+	if _, err := cache.GetSession(session.Token); err != nil {
+		t.Fatalf("Failed for GetSession() after DeleteCalendarSession(): %v", err)
+	}
+}
+
+// TestTTLCache_SealedEntries tests that credentials and tokens are never stored in plain text.
+func TestTTLCache_SealedEntries(t *testing.T) {
+	var logins, restores int32
+	backend, err := NewFileBackend(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to create file backend: %v", err)
+	}
+	cache := NewCache(testCache_DummyScraper{Logins: &logins, Restores: &restores}, backend)
+
+	session, err := cache.NewSession(models.BaseRequestBody{Username: "user", Password: "secret-password", Base: testCache_Base})
+	if err != nil {
+		t.Fatalf("Failed for NewSession(): %v", err)
+	}
+
+	// The session should only be stored under the hashed token.
+	if _, err := backend.Get(session.Token); err == nil {
+		t.Fatalf("Failed for NewSession(), session was stored under the plain token")
+	}
+	sealed, err := backend.Get(backendKey("session-", session.Token))
+	if err != nil {
+		t.Fatalf("Failed for NewSession(), session was not stored: %v", err)
+	}
+	if bytes.Contains(sealed, []byte("secret-password")) {
+		t.Fatalf("Failed for NewSession(), password was stored in plain text")
+	}
+}
+
+// TestTTLCache_ConcurrentLogins tests that concurrent cache misses for the same key share a single login.
+func TestTTLCache_ConcurrentLogins(t *testing.T) {
+	errorLogin := errors.New("login failed")
+	cases := []struct {
+		Name  string
+		Error error // The error logging in returns, if any.
+	}{
+		{Name: "Success", Error: nil},
+		{Name: "Error", Error: errorLogin},
+	}
+
+	for _, test := range cases {
+		var logins, restores int32
+		scraper := testCache_DummyScraper{Logins: &logins, Restores: &restores, Delay: 50 * time.Millisecond, Err: test.Error}
+		cache := NewCache(scraper, NewMemoryBackend())
+		key := fmt.Sprintf("%s\n%s\n%s", "user", "pass", testCache_Base)
+
+		// Miss the cache from several goroutines at once.
+		collectors := make([]*colly.Collector, 10)
+		errs := make([]error, 10)
+		var wg sync.WaitGroup
+		for i := range collectors {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				collectors[i], errs[i] = cache.GetOrLogin(context.Background(), key)
+			}(i)
+		}
+		wg.Wait()
+
+		if logins != 1 {
+			t.Fatalf("Failed for GetOrLogin() %s, expected 1 login, got %d", test.Name, logins)
+		}
+		for i := range collectors {
+			if diff := cmp.Diff(test.Error, errs[i], cmpopts.EquateErrors()); diff != "" {
+				t.Fatalf("Failed for GetOrLogin() %s error (-want, +got)\n%s", test.Name, diff)
+			}
+			if collectors[i] != collectors[0] {
+				t.Fatalf("Failed for GetOrLogin() %s, waiters did not share the same collector", test.Name)
+			}
+		}
+	}
+}
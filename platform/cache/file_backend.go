@@ -0,0 +1,143 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// How often the file backend deletes expired entries.
+const fileSweepInterval = 10 * time.Minute
+
+// fileEntry represents an entry stored on disk by the file backend.
+type fileEntry struct {
+	Value   []byte    `json:"value"`   // The stored value
+	Expires time.Time `json:"expires"` // When the entry expires
+}
+
+// FileBackend is a backend which stores every entry as a file in a
+// directory, so entries survive restarts.
+type FileBackend struct {
+	Dir string // The directory entries are stored in
+}
+
+// NewFileBackend creates a new file backend, creating the directory
+// if it does not exist yet.
+func NewFileBackend(dir string) (*FileBackend, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, err
+	}
+	return &FileBackend{Dir: dir}, nil
+}
+
+func (backend *FileBackend) Get(key string) ([]byte, error) {
+	// Read the entry.
+	data, err := os.ReadFile(backend.path(key))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, ErrorNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var entry fileEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil, ErrorCorruptEntry
+	}
+
+	// Remove the entry if it expired.
+	if time.Now().After(entry.Expires) {
+		backend.Delete(key)
+		return nil, ErrorNotFound
+	}
+
+	return entry.Value, nil
+}
+
+func (backend *FileBackend) Set(key string, value []byte, ttl time.Duration) error {
+	data, err := json.Marshal(fileEntry{Value: value, Expires: time.Now().Add(ttl)})
+	if err != nil {
+		return err
+	}
+
+	// Write to a temporary file first, so readers never see a partial entry.
+	tmp, err := os.CreateTemp(backend.Dir, ".tmp-")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), backend.path(key))
+}
+
+func (backend *FileBackend) Delete(key string) error {
+	err := os.Remove(backend.path(key))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+// Run deletes expired entries every fileSweepInterval until ctx is done.
+func (backend *FileBackend) Run(ctx context.Context) {
+	ticker := time.NewTicker(fileSweepInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			backend.sweep()
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// sweep deletes every entry which expired, as entries are otherwise
+// only deleted once they are read after expiring.
+func (backend *FileBackend) sweep() {
+	files, err := os.ReadDir(backend.Dir)
+	if err != nil {
+		return
+	}
+
+	now := time.Now()
+	for _, file := range files {
+		// Skip temporary files which are still being written.
+		if file.IsDir() || strings.HasPrefix(file.Name(), ".tmp-") {
+			continue
+		}
+
+		data, err := os.ReadFile(filepath.Join(backend.Dir, file.Name()))
+		if err != nil {
+			continue
+		}
+
+		var entry fileEntry
+		if err := json.Unmarshal(data, &entry); err != nil {
+			continue
+		}
+
+		if now.After(entry.Expires) {
+			backend.Delete(file.Name())
+		}
+	}
+}
+
+// path returns the path of the file for a key.
+func (backend *FileBackend) path(key string) string {
+	return filepath.Join(backend.Dir, filepath.Base(key))
+}
@@ -0,0 +1,54 @@
+package cache
+
+import (
+	"context"
+	"time"
+
+	"github.com/jellydator/ttlcache/v3"
+)
+
+// The maximum amount of entries kept by the memory backend.
+const memoryBackendCapacity = 10000
+
+// MemoryBackend is a backend which keeps entries in memory. Entries
+// do not survive restarts, and are not shared between instances.
+type MemoryBackend struct {
+	Cache *ttlcache.Cache[string, []byte]
+}
+
+// NewMemoryBackend creates a new in-memory backend. When full,
+// the least recently used entries are evicted first.
+func NewMemoryBackend() *MemoryBackend {
+	return &MemoryBackend{
+		Cache: ttlcache.New(
+			ttlcache.WithDisableTouchOnHit[string, []byte](),
+			ttlcache.WithCapacity[string, []byte](memoryBackendCapacity),
+		),
+	}
+}
+
+func (backend *MemoryBackend) Get(key string) ([]byte, error) {
+	res := backend.Cache.Get(key)
+	if res == nil {
+		return nil, ErrorNotFound
+	}
+	return res.Value(), nil
+}
+
+func (backend *MemoryBackend) Set(key string, value []byte, ttl time.Duration) error {
+	backend.Cache.Set(key, value, ttl)
+	return nil
+}
+
+func (backend *MemoryBackend) Delete(key string) error {
+	backend.Cache.Delete(key)
+	return nil
+}
+
+// Run deletes expired entries until ctx is done.
+func (backend *MemoryBackend) Run(ctx context.Context) {
+	go backend.Cache.Start()
+	defer backend.Cache.Stop()
+
+	<-ctx.Done()
+}
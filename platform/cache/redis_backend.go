@@ -0,0 +1,209 @@
+package cache
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"time"
+)
+
+// The maximum amount of idle connections kept open to Redis.
+const redisMaxIdleConns = 8
+
+// The timeout for dialing and for every command sent to Redis.
+const redisTimeout = 5 * time.Second
+
+// ErrorRedisProtocol is the error thrown when Redis sends back an unexpected reply.
+var ErrorRedisProtocol = errors.New("unexpected redis reply")
+
+// redisError represents an error reply sent back by Redis.
+type redisError string
+
+func (err redisError) Error() string {
+	return "redis: " + string(err)
+}
+
+// redisConn represents a connection to Redis.
+type redisConn struct {
+	net.Conn
+	Reader *bufio.Reader
+}
+
+// RedisBackend is a backend which stores entries in any server speaking
+// the Redis protocol, so entries are shared between instances.
+type RedisBackend struct {
+	Addr     string // The address of the server
+	Password string // The password to authenticate with, if any
+
+	idle chan *redisConn // Idle connections ready for reuse
+}
+
+// NewRedisBackend creates a new Redis backend. Connections are
+// opened lazily.
+func NewRedisBackend(addr, password string) *RedisBackend {
+	return &RedisBackend{
+		Addr:     addr,
+		Password: password,
+		idle:     make(chan *redisConn, redisMaxIdleConns),
+	}
+}
+
+func (backend *RedisBackend) Get(key string) ([]byte, error) {
+	res, err := backend.do("GET", key)
+	if err != nil {
+		return nil, err
+	}
+	if res == nil {
+		return nil, ErrorNotFound
+	}
+
+	value, ok := res.([]byte)
+	if !ok {
+		return nil, ErrorRedisProtocol
+	}
+	return value, nil
+}
+
+func (backend *RedisBackend) Set(key string, value []byte, ttl time.Duration) error {
+	_, err := backend.do("SET", key, string(value), "PX", strconv.FormatInt(ttl.Milliseconds(), 10))
+	return err
+}
+
+func (backend *RedisBackend) Delete(key string) error {
+	_, err := backend.do("DEL", key)
+	return err
+}
+
+// Run waits until ctx is done, as Redis removes expired entries by itself.
+func (backend *RedisBackend) Run(ctx context.Context) {
+	<-ctx.Done()
+}
+
+// do sends a command to Redis, and returns the reply.
+func (backend *RedisBackend) do(args ...string) (interface{}, error) {
+	conn, err := backend.conn()
+	if err != nil {
+		return nil, err
+	}
+
+	// Send the command and read the reply.
+	conn.SetDeadline(time.Now().Add(redisTimeout))
+	res, err := sendRedisCommand(conn, args...)
+
+	// Drop the connection on network or protocol errors.
+	var replyErr redisError
+	if err != nil && !errors.As(err, &replyErr) {
+		conn.Close()
+		return nil, err
+	}
+
+	backend.release(conn)
+	return res, err
+}
+
+// conn returns an idle connection, or opens a new one.
+func (backend *RedisBackend) conn() (*redisConn, error) {
+	select {
+	case conn := <-backend.idle:
+		return conn, nil
+	default:
+	}
+
+	netConn, err := net.DialTimeout("tcp", backend.Addr, redisTimeout)
+	if err != nil {
+		return nil, err
+	}
+	conn := &redisConn{Conn: netConn, Reader: bufio.NewReader(netConn)}
+
+	// Authenticate if needed.
+	if backend.Password != "" {
+		conn.SetDeadline(time.Now().Add(redisTimeout))
+		if _, err := sendRedisCommand(conn, "AUTH", backend.Password); err != nil {
+			conn.Close()
+			return nil, err
+		}
+	}
+
+	return conn, nil
+}
+
+// release puts a connection back into the idle pool, or closes it if the pool is full.
+func (backend *RedisBackend) release(conn *redisConn) {
+	select {
+	case backend.idle <- conn:
+	default:
+		conn.Close()
+	}
+}
+
+// sendRedisCommand writes a command as an array of bulk strings, and reads the reply.
+func sendRedisCommand(conn *redisConn, args ...string) (interface{}, error) {
+	command := fmt.Sprintf("*%d\r\n", len(args))
+	for _, arg := range args {
+		command += fmt.Sprintf("$%d\r\n%s\r\n", len(arg), arg)
+	}
+
+	if _, err := io.WriteString(conn, command); err != nil {
+		return nil, err
+	}
+
+	return readRedisReply(conn.Reader)
+}
+
+// readRedisReply reads a single reply. Simple strings and integers are returned as strings,
+// bulk strings as bytes, nil replies as nil and arrays as slices.
+func readRedisReply(reader *bufio.Reader) (interface{}, error) {
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	if len(line) < 3 || line[len(line)-2] != '\r' {
+		return nil, ErrorRedisProtocol
+	}
+	kind, line := line[0], line[1:len(line)-2]
+
+	switch kind {
+	// Simple string or integer.
+	case '+', ':':
+		return line, nil
+	// Error.
+	case '-':
+		return nil, redisError(line)
+	// Bulk string.
+	case '$':
+		length, err := strconv.Atoi(line)
+		if err != nil {
+			return nil, ErrorRedisProtocol
+		}
+		if length < 0 {
+			return nil, nil
+		}
+		data := make([]byte, length+2)
+		if _, err := io.ReadFull(reader, data); err != nil {
+			return nil, err
+		}
+		return data[:length], nil
+	// Array.
+	case '*':
+		length, err := strconv.Atoi(line)
+		if err != nil {
+			return nil, ErrorRedisProtocol
+		}
+		if length < 0 {
+			return nil, nil
+		}
+		elements := make([]interface{}, length)
+		for i := range elements {
+			if elements[i], err = readRedisReply(reader); err != nil {
+				return nil, err
+			}
+		}
+		return elements, nil
+	}
+
+	return nil, ErrorRedisProtocol
+}
@@ -1,8 +1,11 @@
 package cache
 
 import (
+	"context"
 	"fmt"
+	"time"
 
+	"github.com/Threqt1/HACApi/app/models"
 	"github.com/Threqt1/HACApi/pkg/repository"
 	"github.com/gocolly/colly"
 )
@@ -16,7 +19,7 @@ import (
 type TestCache struct {
 }
 
-func (TestCache) GetOrLogin(key string) (*colly.Collector, error) {
+func (TestCache) GetOrLogin(ctx context.Context, key string) (*colly.Collector, error) {
 	// Confirm the credentials match the fake ones.
 	fakeCredentials := fmt.Sprintf("%s\n%s\n%s", repository.FakeUsername, repository.FakePassword, repository.FakeBase)
 	if key == fakeCredentials {
@@ -25,7 +28,67 @@ func (TestCache) GetOrLogin(key string) (*colly.Collector, error) {
 	return nil, repository.ErrorInvalidAuthentication
 }
 
+// Log in again, the same way as GetOrLogin.
+func (cache TestCache) Relogin(ctx context.Context, key string) (*colly.Collector, error) {
+	return cache.GetOrLogin(ctx, key)
+}
+
+// Always mint the fake token.
+func (TestCache) NewSession(credentials models.BaseRequestBody) (models.Session, error) {
+	return models.Session{Token: repository.FakeToken, Expires: time.Time{}}, nil
+}
+
+// Only the fake token resolves, to the fake credentials.
+func (TestCache) GetSession(token string) (models.BaseRequestBody, error) {
+	if token == repository.FakeToken {
+		return models.BaseRequestBody{
+			Username: repository.FakeUsername,
+			Password: repository.FakePassword,
+			Base:     repository.FakeBase,
+		}, nil
+	}
+	return models.BaseRequestBody{}, repository.ErrorInvalidSession
+}
+
+// Only the fake token can be deleted.
+func (TestCache) DeleteSession(token string) error {
+	if token == repository.FakeToken {
+		return nil
+	}
+	return repository.ErrorInvalidSession
+}
+
+// Always mint the fake calendar token.
+func (TestCache) NewCalendarSession(credentials models.BaseRequestBody) (models.Session, error) {
+	return models.Session{Token: repository.FakeCalendarToken, Expires: time.Time{}}, nil
+}
+
+// Only the fake calendar token resolves, to the fake credentials.
+func (TestCache) GetCalendarSession(token string) (models.BaseRequestBody, error) {
+	if token == repository.FakeCalendarToken {
+		return models.BaseRequestBody{
+			Username: repository.FakeUsername,
+			Password: repository.FakePassword,
+			Base:     repository.FakeBase,
+		}, nil
+	}
+	return models.BaseRequestBody{}, repository.ErrorInvalidSession
+}
+
+// Only the fake calendar token can be deleted.
+func (TestCache) DeleteCalendarSession(token string) error {
+	if token == repository.FakeCalendarToken {
+		return nil
+	}
+	return repository.ErrorInvalidSession
+}
+
 // NewTestCache makes a new Test Cache.
 func NewTestCache() TestCache {
 	return TestCache{}
 }
+
+// Nothing expires, so wait until ctx is done.
+func (TestCache) Run(ctx context.Context) {
+	<-ctx.Done()
+}
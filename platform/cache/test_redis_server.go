@@ -0,0 +1,169 @@
+package cache
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// TestRedisServer is a stand-in for a Redis server meant
+// to be used during testing. It supports the commands
+// used by the Redis backend.
+type TestRedisServer struct {
+	Addr     string // The address the server listens on
+	Password string // The password clients must authenticate with, if any
+
+	listener net.Listener
+	mutex    sync.Mutex
+	values   map[string]string
+	expires  map[string]time.Time
+}
+
+// NewTestRedisServer starts a new stand-in Redis server on a random local port.
+func NewTestRedisServer(password string) (*TestRedisServer, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+
+	server := &TestRedisServer{
+		Addr:     listener.Addr().String(),
+		Password: password,
+		listener: listener,
+		values:   map[string]string{},
+		expires:  map[string]time.Time{},
+	}
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go server.serve(conn)
+		}
+	}()
+
+	return server, nil
+}
+
+// Close stops the server.
+func (server *TestRedisServer) Close() error {
+	return server.listener.Close()
+}
+
+// Len returns the amount of unexpired keys stored.
+func (server *TestRedisServer) Len() int {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+
+	count := 0
+	for key := range server.values {
+		if expires, ok := server.expires[key]; !ok || time.Now().Before(expires) {
+			count++
+		}
+	}
+	return count
+}
+
+// serve handles the commands sent over a connection.
+func (server *TestRedisServer) serve(conn net.Conn) {
+	defer conn.Close()
+
+	reader := bufio.NewReader(conn)
+	authenticated := server.Password == ""
+
+	for {
+		// Read the command.
+		res, err := readRedisReply(reader)
+		if err != nil {
+			return
+		}
+		elements, ok := res.([]interface{})
+		if !ok || len(elements) == 0 {
+			return
+		}
+		args := make([]string, len(elements))
+		for i, element := range elements {
+			arg, _ := element.([]byte)
+			args[i] = string(arg)
+		}
+
+		// Handle the command.
+		var reply string
+		switch command := strings.ToUpper(args[0]); {
+		case command == "AUTH":
+			authenticated = len(args) == 2 && args[1] == server.Password
+			reply = "+OK\r\n"
+			if !authenticated {
+				reply = "-WRONGPASS invalid password\r\n"
+			}
+		case !authenticated:
+			reply = "-NOAUTH Authentication required.\r\n"
+		case command == "PING":
+			reply = "+PONG\r\n"
+		case command == "GET" && len(args) == 2:
+			reply = server.get(args[1])
+		case command == "SET" && len(args) >= 3:
+			reply = server.set(args[1], args[2], args[3:])
+		case command == "DEL" && len(args) >= 2:
+			reply = server.del(args[1:])
+		default:
+			reply = "-ERR unknown command\r\n"
+		}
+
+		if _, err := conn.Write([]byte(reply)); err != nil {
+			return
+		}
+	}
+}
+
+// get handles the GET command.
+func (server *TestRedisServer) get(key string) string {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+
+	value, ok := server.values[key]
+	if expires, hasExpiry := server.expires[key]; !ok || hasExpiry && time.Now().After(expires) {
+		return "$-1\r\n"
+	}
+	return fmt.Sprintf("$%d\r\n%s\r\n", len(value), value)
+}
+
+// set handles the SET command, with an optional PX expiry.
+func (server *TestRedisServer) set(key, value string, options []string) string {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+
+	server.values[key] = value
+	delete(server.expires, key)
+
+	if len(options) == 2 && strings.EqualFold(options[0], "PX") {
+		ms, err := strconv.ParseInt(options[1], 10, 64)
+		if err != nil {
+			return "-ERR value is not an integer or out of range\r\n"
+		}
+		server.expires[key] = time.Now().Add(time.Duration(ms) * time.Millisecond)
+	}
+	return "+OK\r\n"
+}
+
+// del handles the DEL command.
+func (server *TestRedisServer) del(keys []string) string {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+
+	deleted := 0
+	for _, key := range keys {
+		if _, ok := server.values[key]; ok {
+			deleted++
+		}
+		delete(server.values, key)
+		delete(server.expires, key)
+	}
+	return fmt.Sprintf(":%d\r\n", deleted)
+}
@@ -0,0 +1,105 @@
+package calendar
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Threqt1/HACApi/app/models"
+)
+
+// The format HAC writes dates in.
+const hacDateFormat = "01/02/2006"
+
+// Assignments makes a calendar with an all-day event, or a to-do if kind is
+// models.CalendarKindTodo, on the due date of every assignment in the classwork.
+// Assignments without a valid due date are left out.
+//
+// UIDs are derived from the owner, the class and the assignment's name, but
+// not its dates, so calendar apps update the same entry when an assignment is
+// graded or moved.
+func Assignments(classwork []models.Classwork, kind string, owner string, stamp time.Time) Calendar {
+	calendar := Calendar{Name: "HAC Assignments"}
+
+	// Tell apart identical assignments in the same class.
+	occurrences := make(map[string]int)
+
+	for _, markingPeriod := range classwork {
+		for _, entry := range markingPeriod.Entries {
+			for _, assignment := range entry.Assignments {
+				// Count every assignment, so leaving one out doesn't change the UIDs of the rest.
+				key := strings.Join([]string{entry.Class.Course, entry.Class.Name, assignment.Name}, "\n")
+				occurrence := occurrences[key]
+				occurrences[key]++
+
+				due, err := time.Parse(hacDateFormat, assignment.DueDate)
+				if err != nil {
+					continue
+				}
+
+				properties := []Property{
+					{Name: "UID", Value: uid(owner, key, fmt.Sprint(occurrence))},
+					stampProperty(stamp),
+					{Name: "SUMMARY", Value: escapeText(fmt.Sprintf("%s (%s)", assignment.Name, entry.Class.Name))},
+					{Name: "DESCRIPTION", Value: escapeText(describeAssignment(entry.Class, assignment))},
+				}
+				if assignment.Category != "" {
+					properties = append(properties, Property{Name: "CATEGORIES", Value: escapeText(assignment.Category)})
+				}
+
+				if kind == models.CalendarKindTodo {
+					properties = append(properties, todoProperties(assignment, due)...)
+					calendar.Components = append(calendar.Components, Component{Kind: "VTODO", Properties: properties})
+					continue
+				}
+
+				properties = append(properties,
+					Property{Name: "DTSTART;VALUE=DATE", Value: due.Format(dateFormat)},
+					Property{Name: "DTEND;VALUE=DATE", Value: due.AddDate(0, 0, 1).Format(dateFormat)},
+					Property{Name: "TRANSP", Value: "TRANSPARENT"},
+				)
+				calendar.Components = append(calendar.Components, Component{Kind: "VEVENT", Properties: properties})
+			}
+		}
+	}
+
+	return calendar
+}
+
+// todoProperties are the dates and status of a to-do for an assignment,
+// which counts as completed once it has a grade.
+func todoProperties(assignment models.Assignment, due time.Time) []Property {
+	var properties []Property
+
+	if assigned, err := time.Parse(hacDateFormat, assignment.AssignedDate); err == nil && !assigned.After(due) {
+		properties = append(properties, Property{Name: "DTSTART;VALUE=DATE", Value: assigned.Format(dateFormat)})
+	}
+	properties = append(properties, Property{Name: "DUE;VALUE=DATE", Value: due.Format(dateFormat)})
+
+	if assignment.Grade != "" {
+		return append(properties, Property{Name: "STATUS", Value: "COMPLETED"})
+	}
+	return append(properties, Property{Name: "STATUS", Value: "NEEDS-ACTION"})
+}
+
+// describeAssignment lists the details of an assignment, one per line.
+func describeAssignment(class models.Class, assignment models.Assignment) string {
+	lines := []string{"Class: " + class.Name}
+
+	if assignment.Category != "" {
+		lines = append(lines, "Category: "+assignment.Category)
+	}
+	if assignment.AssignedDate != "" {
+		lines = append(lines, "Assigned: "+assignment.AssignedDate)
+	}
+	if assignment.Grade != "" && assignment.TotalPoints != "" {
+		lines = append(lines, fmt.Sprintf("Grade: %s/%s", assignment.Grade, assignment.TotalPoints))
+	} else if assignment.Grade != "" {
+		lines = append(lines, "Grade: "+assignment.Grade)
+	}
+	if assignment.Dropped {
+		lines = append(lines, "Dropped")
+	}
+
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,111 @@
+package calendar
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// The identifier of the product which made the calendars.
+const productID = "-//Threqt1//HACApi//EN"
+
+// How often subscribed calendar apps should check for changes.
+const refreshInterval = "PT1H"
+
+// The longest a line can be before it is folded, in octets.
+const maxLineLength = 75
+
+// The formats dates and times are written in.
+const (
+	dateFormat     = "20060102"
+	dateTimeFormat = "20060102T150405"
+	stampFormat    = "20060102T150405Z"
+)
+
+// Property is a single property of a component, like SUMMARY or DTSTART.
+// The name can carry parameters, like "DTSTART;VALUE=DATE".
+type Property struct {
+	Name  string
+	Value string
+}
+
+// Component is a VEVENT or a VTODO, with its properties in order.
+type Component struct {
+	Kind       string
+	Properties []Property
+}
+
+// Calendar is an iCalendar (RFC 5545) document.
+type Calendar struct {
+	Name       string
+	Components []Component
+}
+
+// Write writes the calendar in the iCalendar format.
+func (calendar Calendar) Write(w io.Writer) error {
+	writer := bufio.NewWriter(w)
+
+	writeLine(writer, "BEGIN", "VCALENDAR")
+	writeLine(writer, "VERSION", "2.0")
+	writeLine(writer, "PRODID", productID)
+	writeLine(writer, "CALSCALE", "GREGORIAN")
+	writeLine(writer, "METHOD", "PUBLISH")
+	writeLine(writer, "X-WR-CALNAME", escapeText(calendar.Name))
+	writeLine(writer, "REFRESH-INTERVAL;VALUE=DURATION", refreshInterval)
+	writeLine(writer, "X-PUBLISHED-TTL", refreshInterval)
+
+	for _, component := range calendar.Components {
+		writeLine(writer, "BEGIN", component.Kind)
+		for _, property := range component.Properties {
+			writeLine(writer, property.Name, property.Value)
+		}
+		writeLine(writer, "END", component.Kind)
+	}
+
+	writeLine(writer, "END", "VCALENDAR")
+
+	return writer.Flush()
+}
+
+// writeLine writes a content line, folding it so no line is longer than 75 octets.
+func writeLine(writer *bufio.Writer, name, value string) {
+	line := name + ":" + value
+
+	for length := maxLineLength; len(line) > length; length = maxLineLength - 1 {
+		// Don't split a multi-byte character.
+		cut := length
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+
+		writer.WriteString(line[:cut])
+		writer.WriteString("\r\n ")
+		line = line[cut:]
+	}
+
+	writer.WriteString(line)
+	writer.WriteString("\r\n")
+}
+
+// textEscaper escapes the characters which have a meaning in text values.
+var textEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+
+// escapeText escapes a text value.
+func escapeText(text string) string {
+	return textEscaper.Replace(text)
+}
+
+// uid makes a globally unique, stable identifier from the parts identifying a component.
+func uid(parts ...string) string {
+	sum := sha256.Sum256([]byte(strings.Join(parts, "\n")))
+	return hex.EncodeToString(sum[:16]) + "@hacapi"
+}
+
+// stampProperty is the DTSTAMP property, which is written in UTC.
+func stampProperty(stamp time.Time) Property {
+	return Property{Name: "DTSTAMP", Value: stamp.UTC().Format(stampFormat)}
+}
@@ -0,0 +1,196 @@
+package calendar
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/Threqt1/HACApi/app/models"
+	"github.com/google/go-cmp/cmp"
+)
+
+// The time calendars are stamped with in the tests.
+var testCalendar_Stamp = time.Date(2022, 10, 3, 12, 0, 0, 0, time.UTC)
+
+// testCalendar_Components writes a calendar, and splits out
+// the lines of its components.
+func testCalendar_Components(t *testing.T, calendar Calendar) []string {
+	var buffer bytes.Buffer
+	if err := calendar.Write(&buffer); err != nil {
+		t.Fatalf("Failed for Write():\n%v", err)
+	}
+
+	// Unfold the lines, and drop the calendar properties.
+	lines := strings.Split(strings.ReplaceAll(strings.TrimSuffix(buffer.String(), "\r\n"), "\r\n ", ""), "\r\n")
+	for i, line := range lines {
+		if strings.HasPrefix(line, "BEGIN:V") && line != "BEGIN:VCALENDAR" {
+			return lines[i : len(lines)-1]
+		}
+	}
+	return nil
+}
+
+// Test if Write() folds long lines without splitting characters, and escapes text.
+func TestWrite(t *testing.T) {
+	summary := strings.Repeat("é", 50) + "; a, b\\c\nd"
+	calendar := Calendar{
+		Name: "Test",
+		Components: []Component{{
+			Kind:       "VEVENT",
+			Properties: []Property{{Name: "SUMMARY", Value: escapeText(summary)}},
+		}},
+	}
+
+	var buffer bytes.Buffer
+	calendar.Write(&buffer)
+
+	for _, line := range strings.Split(buffer.String(), "\r\n") {
+		if len(line) > maxLineLength || !utf8.ValidString(line) {
+			t.Fatalf("Failed for Write(), line %q is too long or splits a character", line)
+		}
+	}
+
+	expected := []string{"BEGIN:VEVENT", "SUMMARY:" + strings.Repeat("é", 50) + `\; a\, b\\c\nd`, "END:VEVENT"}
+	if diff := cmp.Diff(expected, testCalendar_Components(t, calendar)); diff != "" {
+		t.Fatalf("Failed for Write() (-want, +got)\n%s", diff)
+	}
+}
+
+// Test if Assignments() makes an all-day event on the due date of each assignment.
+func TestAssignments(t *testing.T) {
+	classwork := []models.Classwork{{
+		MarkingPeriod: 1,
+		Entries: []models.ClassworkEntry{{
+			Class: models.Class{Name: "Algebra", Course: "MTH101"},
+			Assignments: []models.Assignment{
+				{Name: "Quiz", Category: "Minor", AssignedDate: "09/01/2022", DueDate: "09/02/2022", Grade: "95.00", TotalPoints: "100.00"},
+				{Name: "Undated", DueDate: ""},
+			},
+		}},
+	}}
+
+	lines := testCalendar_Components(t, Assignments(classwork, models.CalendarKindEvent, "user\nbase", testCalendar_Stamp))
+
+	expected := []string{
+		"BEGIN:VEVENT",
+		"UID:" + uid("user\nbase", "MTH101\nAlgebra\nQuiz", "0"),
+		"DTSTAMP:20221003T120000Z",
+		"SUMMARY:Quiz (Algebra)",
+		`DESCRIPTION:Class: Algebra\nCategory: Minor\nAssigned: 09/01/2022\nGrade: 95.00/100.00`,
+		"CATEGORIES:Minor",
+		"DTSTART;VALUE=DATE:20220902",
+		"DTEND;VALUE=DATE:20220903",
+		"TRANSP:TRANSPARENT",
+		"END:VEVENT",
+	}
+	if diff := cmp.Diff(expected, lines); diff != "" {
+		t.Fatalf("Failed for Assignments() (-want, +got)\n%s", diff)
+	}
+}
+
+// Test if Assignments() keeps the UID of an assignment once it is moved.
+func TestAssignments_Moved(t *testing.T) {
+	classwork := func(dueDate string) []models.Classwork {
+		return []models.Classwork{{
+			Entries: []models.ClassworkEntry{{
+				Class:       models.Class{Name: "English", Course: "ENG101"},
+				Assignments: []models.Assignment{{Name: "Essay", AssignedDate: "09/05/2022", DueDate: dueDate}},
+			}},
+		}}
+	}
+
+	before := Assignments(classwork("09/09/2022"), models.CalendarKindEvent, "user\nbase", testCalendar_Stamp)
+	after := Assignments(classwork("09/16/2022"), models.CalendarKindEvent, "user\nbase", testCalendar_Stamp)
+
+	if diff := cmp.Diff(before.Components[0].Properties[0], after.Components[0].Properties[0]); diff != "" {
+		t.Fatalf("Failed for Assignments() Moved, expected the UID to stay the same (-want, +got)\n%s", diff)
+	}
+}
+
+// Test if Assignments() makes to-dos with stable, distinct UIDs.
+func TestAssignments_Todo(t *testing.T) {
+	assignment := models.Assignment{Name: "Essay", AssignedDate: "09/05/2022", DueDate: "09/09/2022"}
+	classwork := []models.Classwork{{
+		Entries: []models.ClassworkEntry{{
+			Class:       models.Class{Name: "English", Course: "ENG101"},
+			Assignments: []models.Assignment{assignment, assignment},
+		}},
+	}}
+
+	first := Assignments(classwork, models.CalendarKindTodo, "user\nbase", testCalendar_Stamp)
+	second := Assignments(classwork, models.CalendarKindTodo, "user\nbase", testCalendar_Stamp.Add(time.Hour))
+
+	if len(first.Components) != 2 || first.Components[0].Kind != "VTODO" {
+		t.Fatalf("Failed for Assignments() To-Do, expected 2 to-dos, got %+v", first.Components)
+	}
+	if first.Components[0].Properties[0] == first.Components[1].Properties[0] {
+		t.Fatalf("Failed for Assignments() To-Do, expected distinct UIDs for identical assignments")
+	}
+	if first.Components[0].Properties[0] != second.Components[0].Properties[0] {
+		t.Fatalf("Failed for Assignments() To-Do, expected UIDs to be stable")
+	}
+
+	expected := []Property{
+		{Name: "DTSTART;VALUE=DATE", Value: "20220905"},
+		{Name: "DUE;VALUE=DATE", Value: "20220909"},
+		{Name: "STATUS", Value: "NEEDS-ACTION"},
+	}
+	if diff := cmp.Diff(expected, first.Components[0].Properties[4:]); diff != "" {
+		t.Fatalf("Failed for Assignments() To-Do (-want, +got)\n%s", diff)
+	}
+}
+
+// Test if Schedule() makes a weekly event for every active class with a known period and weekdays.
+func TestSchedule(t *testing.T) {
+	periods, err := ParsePeriods([]models.PeriodTime{{Period: "1", Start: "08:45", End: "09:35"}, {Period: "2", Start: "09:40", End: "10:30"}})
+	if err != nil {
+		t.Fatalf("Failed for ParsePeriods():\n%v", err)
+	}
+
+	schedule := []models.Schedule{{
+		Entries: []models.ScheduleEntry{
+			{Class: models.Class{Name: "Algebra", Course: "MTH101", Period: "01", Teacher: "Smith", Room: "204"}, Days: []string{"W", "M"}, Building: "Main", Active: true},
+			{Class: models.Class{Name: "Rotating", Period: "2"}, Days: []string{"A"}, Active: true},
+			{Class: models.Class{Name: "No Times", Period: "3"}, Days: []string{"M"}, Active: true},
+			{Class: models.Class{Name: "Inactive", Period: "1"}, Days: []string{"M"}, Active: false},
+		},
+	}}
+
+	// 08/17/2022 is a Wednesday.
+	start := time.Date(2022, 8, 17, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2022, 12, 16, 0, 0, 0, 0, time.UTC)
+
+	lines := testCalendar_Components(t, Schedule(schedule, periods, start, end, "user\nbase", testCalendar_Stamp))
+
+	expected := []string{
+		"BEGIN:VEVENT",
+		"UID:" + uid("user\nbase", "MTH101", "Algebra", "01", "MO,WE"),
+		"DTSTAMP:20221003T120000Z",
+		"SUMMARY:Algebra",
+		`DESCRIPTION:Course: MTH101\nPeriod: 01\nTeacher: Smith`,
+		"DTSTART:20220817T084500",
+		"DTEND:20220817T093500",
+		"RRULE:FREQ=WEEKLY;BYDAY=MO,WE;UNTIL=20221216T235959",
+		`LOCATION:204\, Main`,
+		"END:VEVENT",
+	}
+	if diff := cmp.Diff(expected, lines); diff != "" {
+		t.Fatalf("Failed for Schedule() (-want, +got)\n%s", diff)
+	}
+}
+
+// Test if ParsePeriods() rejects invalid period times.
+func TestParsePeriods_Invalid(t *testing.T) {
+	tests := map[string]models.PeriodTime{
+		"Bad Start":        {Period: "1", Start: "8:45am", End: "09:35"},
+		"End Before Start": {Period: "1", Start: "09:35", End: "08:45"},
+	}
+
+	for name, periodTime := range tests {
+		if _, err := ParsePeriods([]models.PeriodTime{periodTime}); err == nil {
+			t.Fatalf("Failed for ParsePeriods() %s, expected an error", name)
+		}
+	}
+}
@@ -0,0 +1,170 @@
+package calendar
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Threqt1/HACApi/app/models"
+)
+
+// The format period times are passed in.
+const periodTimeFormat = "15:04"
+
+// ErrorInvalidPeriodTime is returned when a period's times can't be parsed,
+// or it ends before it starts.
+var ErrorInvalidPeriodTime = errors.New("period times must be formatted as HH:MM, with the end after the start")
+
+// Period is when a period starts and ends, as the time since midnight.
+type Period struct {
+	Start time.Duration
+	End   time.Duration
+}
+
+// Periods maps period numbers to their times.
+type Periods map[string]Period
+
+// ParsePeriods parses the times of each period.
+func ParsePeriods(periodTimes []models.PeriodTime) (Periods, error) {
+	periods := make(Periods, len(periodTimes))
+
+	for _, periodTime := range periodTimes {
+		start, startErr := time.Parse(periodTimeFormat, periodTime.Start)
+		end, endErr := time.Parse(periodTimeFormat, periodTime.End)
+		if startErr != nil || endErr != nil || !end.After(start) {
+			return nil, fmt.Errorf("%w: period %s", ErrorInvalidPeriodTime, periodTime.Period)
+		}
+
+		midnight := time.Date(0, 1, 1, 0, 0, 0, 0, time.UTC)
+		periods[normalizePeriod(periodTime.Period)] = Period{Start: start.Sub(midnight), End: end.Sub(midnight)}
+	}
+
+	return periods, nil
+}
+
+// normalizePeriod trims a period number, so "01" and "1" are the same period.
+func normalizePeriod(period string) string {
+	period = strings.TrimSpace(period)
+	if trimmed := strings.TrimLeft(period, "0"); trimmed != "" || period == "" {
+		return trimmed
+	}
+	return "0"
+}
+
+// weekdays maps the ways HAC writes days to iCalendar weekdays.
+var weekdays = map[string]time.Weekday{
+	"M": time.Monday, "MO": time.Monday, "MON": time.Monday, "MONDAY": time.Monday,
+	"T": time.Tuesday, "TU": time.Tuesday, "TUE": time.Tuesday, "TUES": time.Tuesday, "TUESDAY": time.Tuesday,
+	"W": time.Wednesday, "WE": time.Wednesday, "WED": time.Wednesday, "WEDNESDAY": time.Wednesday,
+	"R": time.Thursday, "TH": time.Thursday, "THU": time.Thursday, "THUR": time.Thursday, "THURS": time.Thursday, "THURSDAY": time.Thursday,
+	"F": time.Friday, "FR": time.Friday, "FRI": time.Friday, "FRIDAY": time.Friday,
+	"SA": time.Saturday, "SAT": time.Saturday, "SATURDAY": time.Saturday,
+	"SU": time.Sunday, "SUN": time.Sunday, "SUNDAY": time.Sunday,
+}
+
+// byDay is the iCalendar name of each weekday.
+var byDay = [...]string{"SU", "MO", "TU", "WE", "TH", "FR", "SA"}
+
+// parseDays parses the days a class meets on into weekdays, sorted from Sunday.
+// Days which aren't weekdays, like the A and B days of a rotating schedule,
+// can't be turned into a weekly recurrence, so they are left out.
+func parseDays(days []string) []time.Weekday {
+	seen := make(map[time.Weekday]bool)
+	var parsed []time.Weekday
+
+	for _, day := range days {
+		weekday, ok := weekdays[strings.ToUpper(strings.TrimSpace(day))]
+		if !ok || seen[weekday] {
+			continue
+		}
+		seen[weekday] = true
+		parsed = append(parsed, weekday)
+	}
+
+	sort.Slice(parsed, func(i, j int) bool { return parsed[i] < parsed[j] })
+	return parsed
+}
+
+// Schedule makes a calendar with an event for every active class in the schedule,
+// repeating weekly on the days the class meets from start until end. Times are
+// written without a time zone, so calendar apps show them in the local time.
+// Classes meeting on days which aren't weekdays, or in periods without times,
+// are left out.
+func Schedule(schedules []models.Schedule, periods Periods, start, end time.Time, owner string, stamp time.Time) Calendar {
+	calendar := Calendar{Name: "HAC Schedule"}
+
+	for _, schedule := range schedules {
+		for _, entry := range schedule.Entries {
+			period, ok := periods[normalizePeriod(entry.Class.Period)]
+			days := parseDays(entry.Days)
+			if !entry.Active || !ok || len(days) == 0 {
+				continue
+			}
+
+			// The event starts on the first day the class meets.
+			first := firstMeeting(start, days)
+			if first.After(end) {
+				continue
+			}
+
+			names := make([]string, len(days))
+			for i, day := range days {
+				names[i] = byDay[day]
+			}
+
+			properties := []Property{
+				{Name: "UID", Value: uid(owner, entry.Class.Course, entry.Class.Name, entry.Class.Period, strings.Join(names, ","))},
+				stampProperty(stamp),
+				{Name: "SUMMARY", Value: escapeText(entry.Class.Name)},
+				{Name: "DESCRIPTION", Value: escapeText(describeClass(entry))},
+				{Name: "DTSTART", Value: first.Add(period.Start).Format(dateTimeFormat)},
+				{Name: "DTEND", Value: first.Add(period.End).Format(dateTimeFormat)},
+				{Name: "RRULE", Value: fmt.Sprintf("FREQ=WEEKLY;BYDAY=%s;UNTIL=%s", strings.Join(names, ","), end.Add(24*time.Hour-time.Second).Format(dateTimeFormat))},
+			}
+			if location := describeLocation(entry); location != "" {
+				properties = append(properties, Property{Name: "LOCATION", Value: escapeText(location)})
+			}
+
+			calendar.Components = append(calendar.Components, Component{Kind: "VEVENT", Properties: properties})
+		}
+	}
+
+	return calendar
+}
+
+// firstMeeting returns the first day on or after start which falls on one of the days.
+func firstMeeting(start time.Time, days []time.Weekday) time.Time {
+	for offset := 0; offset < 7; offset++ {
+		day := start.AddDate(0, 0, offset)
+		for _, weekday := range days {
+			if day.Weekday() == weekday {
+				return day
+			}
+		}
+	}
+	return start
+}
+
+// describeClass lists the details of a class, one per line.
+func describeClass(entry models.ScheduleEntry) string {
+	lines := []string{"Course: " + entry.Class.Course, "Period: " + entry.Class.Period}
+
+	if entry.Class.Teacher != "" {
+		lines = append(lines, "Teacher: "+entry.Class.Teacher)
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// describeLocation is the room of a class, along with its building if there is one.
+func describeLocation(entry models.ScheduleEntry) string {
+	switch {
+	case entry.Class.Room != "" && entry.Building != "":
+		return fmt.Sprintf("%s, %s", entry.Class.Room, entry.Building)
+	case entry.Building != "":
+		return entry.Building
+	}
+	return entry.Class.Room
+}
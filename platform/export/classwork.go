@@ -0,0 +1,62 @@
+package export
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/Threqt1/HACApi/app/models"
+)
+
+// The columns of exported classwork.
+var classworkColumns = []Column{
+	{Name: "Marking Period", Numeric: true},
+	{Name: "Course"},
+	{Name: "Class"},
+	{Name: "Period"},
+	{Name: "Teacher"},
+	{Name: "Average", Numeric: true},
+	{Name: "Assignment"},
+	{Name: "Category"},
+	{Name: "Assigned Date"},
+	{Name: "Due Date"},
+	{Name: "Grade", Numeric: true},
+	{Name: "Total Points", Numeric: true},
+	{Name: "Dropped"},
+}
+
+// Classwork flattens classwork into a row per assignment, with a sheet per
+// marking period. Classes without assignments get a row of their own, so
+// their average isn't lost.
+func Classwork(classwork []models.Classwork) Workbook {
+	workbook := Workbook{Name: "Classwork", Columns: classworkColumns}
+
+	for _, markingPeriod := range classwork {
+		sheet := Sheet{Name: fmt.Sprintf("Marking Period %d", markingPeriod.MarkingPeriod)}
+		number := strconv.Itoa(markingPeriod.MarkingPeriod)
+
+		for _, entry := range markingPeriod.Entries {
+			class := []string{number, entry.Class.Course, entry.Class.Name, entry.Class.Period, entry.Class.Teacher, entry.Average}
+
+			if len(entry.Assignments) == 0 {
+				sheet.Rows = append(sheet.Rows, append(class, "", "", "", "", "", "", ""))
+				continue
+			}
+
+			for _, assignment := range entry.Assignments {
+				sheet.Rows = append(sheet.Rows, append(class[:len(class):len(class)],
+					assignment.Name,
+					assignment.Category,
+					assignment.AssignedDate,
+					assignment.DueDate,
+					assignment.Grade,
+					assignment.TotalPoints,
+					strconv.FormatBool(assignment.Dropped),
+				))
+			}
+		}
+
+		workbook.Sheets = append(workbook.Sheets, sheet)
+	}
+
+	return workbook
+}
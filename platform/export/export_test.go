@@ -0,0 +1,127 @@
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/Threqt1/HACApi/app/models"
+	"github.com/google/go-cmp/cmp"
+)
+
+// testExport_Classwork is classwork with one assignment, and one class without any.
+var testExport_Classwork = []models.Classwork{{
+	MarkingPeriod: 2,
+	Entries: []models.ClassworkEntry{
+		{
+			Class:       models.Class{Name: "Algebra", Course: "MTH101", Period: "1", Teacher: "Smith"},
+			Average:     "92.50",
+			Assignments: []models.Assignment{{Name: "=HYPERLINK(\"x\")", Category: "Minor", AssignedDate: "09/01/2022", DueDate: "09/02/2022", Grade: "95.00", TotalPoints: "100.00"}},
+		},
+		{
+			Class:   models.Class{Name: "Art", Course: "ART101", Period: "2", Teacher: "Jones"},
+			Average: "",
+		},
+	},
+}}
+
+// Test if WriteCSV() writes a row per assignment under one header,
+// and escapes text which would run as a formula.
+func TestWriteCSV_Classwork(t *testing.T) {
+	var buffer bytes.Buffer
+	if err := Classwork(testExport_Classwork).WriteCSV(&buffer); err != nil {
+		t.Fatalf("Failed for WriteCSV():\n%v", err)
+	}
+
+	expected := strings.Join([]string{
+		"Marking Period,Course,Class,Period,Teacher,Average,Assignment,Category,Assigned Date,Due Date,Grade,Total Points,Dropped",
+		`2,MTH101,Algebra,1,Smith,92.50,"'=HYPERLINK(""x"")",Minor,09/01/2022,09/02/2022,95.00,100.00,false`,
+		"2,ART101,Art,2,Jones,,,,,,,,",
+		"",
+	}, "\n")
+	if diff := cmp.Diff(expected, buffer.String()); diff != "" {
+		t.Fatalf("Failed for WriteCSV() (-want, +got)\n%s", diff)
+	}
+}
+
+// testExport_ReadXLSX reads the files of a spreadsheet.
+func testExport_ReadXLSX(t *testing.T, workbook Workbook) map[string]string {
+	var buffer bytes.Buffer
+	if err := workbook.WriteXLSX(&buffer); err != nil {
+		t.Fatalf("Failed for WriteXLSX():\n%v", err)
+	}
+
+	archive, err := zip.NewReader(bytes.NewReader(buffer.Bytes()), int64(buffer.Len()))
+	if err != nil {
+		t.Fatalf("Failed for WriteXLSX(), not a zip archive:\n%v", err)
+	}
+
+	files := make(map[string]string)
+	for _, file := range archive.File {
+		reader, _ := file.Open()
+		content, _ := io.ReadAll(reader)
+		reader.Close()
+		files[file.Name] = string(content)
+	}
+	return files
+}
+
+// Test if WriteXLSX() puts each transcript year on its own sheet,
+// and writes numeric columns as numbers.
+func TestWriteXLSX_Transcript(t *testing.T) {
+	transcripts := []models.Transcript{{
+		Entries: []models.TranscriptGroup{
+			{Year: "2021-2022", Semester: "1", Entries: []models.TranscriptGroupEntry{{Class: models.Class{Name: "Algebra & Geometry"}, Average: "95", Credit: "0.5"}}},
+			{Year: "2021-2022", Semester: "2", Entries: []models.TranscriptGroupEntry{{Class: models.Class{Name: "Algebra"}, Average: "P", Credit: "0.5"}}},
+			{Year: "2022-2023", Semester: "1"},
+		},
+	}}
+
+	files := testExport_ReadXLSX(t, Transcript(transcripts))
+
+	for _, name := range []string{"[Content_Types].xml", "_rels/.rels", "xl/workbook.xml", "xl/_rels/workbook.xml.rels", "xl/worksheets/sheet1.xml", "xl/worksheets/sheet2.xml"} {
+		if _, ok := files[name]; !ok {
+			t.Fatalf("Failed for WriteXLSX(), missing %s", name)
+		}
+	}
+	if _, ok := files["xl/worksheets/sheet3.xml"]; ok {
+		t.Fatalf("Failed for WriteXLSX(), expected a sheet per year")
+	}
+
+	if !strings.Contains(files["xl/workbook.xml"], `<sheet name="2021-2022" sheetId="1" r:id="rId1"/><sheet name="2022-2023" sheetId="2" r:id="rId2"/>`) {
+		t.Fatalf("Failed for WriteXLSX(), unexpected sheets\n%s", files["xl/workbook.xml"])
+	}
+
+	sheet := files["xl/worksheets/sheet1.xml"]
+	for _, cell := range []string{
+		`<c r="F2" t="inlineStr"><is><t xml:space="preserve">Algebra &amp; Geometry</t></is></c>`,
+		`<c r="G2"><v>95</v></c>`,
+		`<c r="G3" t="inlineStr"><is><t xml:space="preserve">P</t></is></c>`,
+		`<c r="H3"><v>0.5</v></c>`,
+	} {
+		if !strings.Contains(sheet, cell) {
+			t.Fatalf("Failed for WriteXLSX(), expected %s in\n%s", cell, sheet)
+		}
+	}
+}
+
+// Test if sheetNames() makes valid, unique sheet names.
+func TestSheetNames(t *testing.T) {
+	sheets := []Sheet{{Name: "2021/2022"}, {Name: "2021/2022"}, {Name: strings.Repeat("a", 40)}, {Name: "'?'"}}
+
+	expected := []string{"2021-2022", "2021-2022 (2)", strings.Repeat("a", 31), "Sheet 4"}
+	if diff := cmp.Diff(expected, sheetNames(sheets)); diff != "" {
+		t.Fatalf("Failed for sheetNames() (-want, +got)\n%s", diff)
+	}
+}
+
+// Test if columnName() names columns like spreadsheets do.
+func TestColumnName(t *testing.T) {
+	for index, expected := range map[int]string{0: "A", 25: "Z", 26: "AA", 27: "AB", 701: "ZZ", 702: "AAA"} {
+		if got := columnName(index); got != expected {
+			t.Fatalf("Failed for columnName(%d), expected %s, got %s", index, expected, got)
+		}
+	}
+}
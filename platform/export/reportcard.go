@@ -0,0 +1,61 @@
+package export
+
+import "github.com/Threqt1/HACApi/app/models"
+
+// The columns of an exported report card.
+var reportCardColumns = []Column{
+	{Name: "Course"},
+	{Name: "Class"},
+	{Name: "Period"},
+	{Name: "Teacher"},
+	{Name: "Room"},
+	{Name: "Attempted Credit", Numeric: true},
+	{Name: "Earned Credit", Numeric: true},
+	{Name: "1st", Numeric: true},
+	{Name: "2nd", Numeric: true},
+	{Name: "3rd", Numeric: true},
+	{Name: "Exam 1", Numeric: true},
+	{Name: "Sem 1", Numeric: true},
+	{Name: "4th", Numeric: true},
+	{Name: "5th", Numeric: true},
+	{Name: "6th", Numeric: true},
+	{Name: "Exam 2", Numeric: true},
+	{Name: "Sem 2", Numeric: true},
+}
+
+// ReportCard flattens report cards into a row per class, with the average
+// of every marking period, exam and semester in its own column.
+func ReportCard(reportCards []models.ReportCard) Workbook {
+	workbook := Workbook{Name: "Report Card", Columns: reportCardColumns}
+
+	for _, reportCard := range reportCards {
+		sheet := Sheet{Name: "Report Card"}
+
+		for _, entry := range reportCard.Entries {
+			averages := entry.Averages
+			sheet.Rows = append(sheet.Rows, []string{
+				entry.Class.Course,
+				entry.Class.Name,
+				entry.Class.Period,
+				entry.Class.Teacher,
+				entry.Class.Room,
+				entry.AttemptedCredit,
+				entry.EarnedCredit,
+				averages.First,
+				averages.Second,
+				averages.Third,
+				averages.Exam1,
+				averages.Sem1,
+				averages.Fourth,
+				averages.Fifth,
+				averages.Sixth,
+				averages.Exam2,
+				averages.Sem2,
+			})
+		}
+
+		workbook.Sheets = append(workbook.Sheets, sheet)
+	}
+
+	return workbook
+}
@@ -0,0 +1,49 @@
+package export
+
+import "github.com/Threqt1/HACApi/app/models"
+
+// The columns of an exported transcript.
+var transcriptColumns = []Column{
+	{Name: "Year"},
+	{Name: "Semester"},
+	{Name: "Grade Level"},
+	{Name: "Building"},
+	{Name: "Course"},
+	{Name: "Class"},
+	{Name: "Average", Numeric: true},
+	{Name: "Credit", Numeric: true},
+}
+
+// Transcript flattens transcripts into a row per class, with a sheet per school year.
+func Transcript(transcripts []models.Transcript) Workbook {
+	workbook := Workbook{Name: "Transcript", Columns: transcriptColumns}
+
+	// Semesters of the same year share a sheet.
+	sheets := make(map[string]int)
+
+	for _, transcript := range transcripts {
+		for _, group := range transcript.Entries {
+			index, ok := sheets[group.Year]
+			if !ok {
+				index = len(workbook.Sheets)
+				sheets[group.Year] = index
+				workbook.Sheets = append(workbook.Sheets, Sheet{Name: group.Year})
+			}
+
+			for _, entry := range group.Entries {
+				workbook.Sheets[index].Rows = append(workbook.Sheets[index].Rows, []string{
+					group.Year,
+					group.Semester,
+					group.GradeLevel,
+					group.Building,
+					entry.Class.Course,
+					entry.Class.Name,
+					entry.Average,
+					entry.Credit,
+				})
+			}
+		}
+	}
+
+	return workbook
+}
@@ -0,0 +1,222 @@
+package export
+
+import (
+	"archive/zip"
+	"encoding/csv"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// The longest a sheet name can be, in characters.
+const maxSheetNameLength = 31
+
+// Column is a column of a workbook. Numeric columns are written
+// as numbers in spreadsheets, where their values are numbers.
+type Column struct {
+	Name    string
+	Numeric bool
+}
+
+// Sheet is a named group of rows, like a marking period.
+type Sheet struct {
+	Name string
+	Rows [][]string
+}
+
+// Workbook is a resource flattened into rows, with the same columns on every sheet.
+type Workbook struct {
+	Name    string
+	Columns []Column
+	Sheets  []Sheet
+}
+
+// WriteCSV writes the rows of every sheet in a single CSV file, under one header.
+func (workbook Workbook) WriteCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+
+	header := make([]string, len(workbook.Columns))
+	for i, column := range workbook.Columns {
+		header[i] = column.Name
+	}
+	writer.Write(header)
+
+	for _, sheet := range workbook.Sheets {
+		for _, row := range sheet.Rows {
+			cells := make([]string, len(row))
+			for i, cell := range row {
+				cells[i] = escapeFormula(cell)
+			}
+			writer.Write(cells)
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// escapeFormula stops spreadsheet apps from running text cells as formulas,
+// since assignment names and comments are written by anyone.
+func escapeFormula(cell string) string {
+	if cell == "" || !strings.ContainsAny(cell[:1], "=+-@\t\r") {
+		return cell
+	}
+	if _, err := strconv.ParseFloat(cell, 64); err == nil {
+		return cell
+	}
+	return "'" + cell
+}
+
+// The parts of a spreadsheet which don't depend on its sheets.
+const (
+	xmlHeader     = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` + "\n"
+	rootRels      = xmlHeader + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/></Relationships>`
+	sheetMIMEType = "application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"
+)
+
+// WriteXLSX writes the workbook as a spreadsheet, with every sheet under the header.
+func (workbook Workbook) WriteXLSX(w io.Writer) error {
+	archive := zip.NewWriter(w)
+
+	// A spreadsheet needs at least one sheet.
+	sheets := workbook.Sheets
+	if len(sheets) == 0 {
+		sheets = []Sheet{{Name: workbook.Name}}
+	}
+	names := sheetNames(sheets)
+
+	var contentTypes, workbookSheets, workbookRels strings.Builder
+	contentTypes.WriteString(xmlHeader + `<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types"><Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/><Default Extension="xml" ContentType="application/xml"/><Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>`)
+	workbookRels.WriteString(xmlHeader + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">`)
+
+	for i := range sheets {
+		fmt.Fprintf(&contentTypes, `<Override PartName="/xl/worksheets/sheet%d.xml" ContentType="%s"/>`, i+1, sheetMIMEType)
+		fmt.Fprintf(&workbookSheets, `<sheet name="%s" sheetId="%d" r:id="rId%d"/>`, escapeXML(names[i]), i+1, i+1)
+		fmt.Fprintf(&workbookRels, `<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet%d.xml"/>`, i+1, i+1)
+	}
+
+	contentTypes.WriteString(`</Types>`)
+	workbookRels.WriteString(`</Relationships>`)
+
+	files := []struct {
+		Name    string
+		Content string
+	}{
+		{"[Content_Types].xml", contentTypes.String()},
+		{"_rels/.rels", rootRels},
+		{"xl/workbook.xml", xmlHeader + `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets>` + workbookSheets.String() + `</sheets></workbook>`},
+		{"xl/_rels/workbook.xml.rels", workbookRels.String()},
+	}
+	for _, file := range files {
+		if err := writeZipFile(archive, file.Name, file.Content); err != nil {
+			return err
+		}
+	}
+
+	for i, sheet := range sheets {
+		if err := writeZipFile(archive, fmt.Sprintf("xl/worksheets/sheet%d.xml", i+1), workbook.sheetXML(sheet)); err != nil {
+			return err
+		}
+	}
+
+	return archive.Close()
+}
+
+// writeZipFile adds a file to a zip archive.
+func writeZipFile(archive *zip.Writer, name, content string) error {
+	file, err := archive.Create(name)
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(file, content)
+	return err
+}
+
+// sheetXML makes the worksheet of a sheet, with the header as its first row.
+func (workbook Workbook) sheetXML(sheet Sheet) string {
+	var builder strings.Builder
+	builder.WriteString(xmlHeader + `<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`)
+
+	header := make([]string, len(workbook.Columns))
+	for i, column := range workbook.Columns {
+		header[i] = column.Name
+	}
+
+	for i, row := range append([][]string{header}, sheet.Rows...) {
+		fmt.Fprintf(&builder, `<row r="%d">`, i+1)
+		for j, cell := range row {
+			if cell == "" {
+				continue
+			}
+
+			ref := columnName(j) + strconv.Itoa(i+1)
+
+			// Write numbers as numbers, so they can be calculated with.
+			if number, err := strconv.ParseFloat(cell, 64); i > 0 && j < len(workbook.Columns) && workbook.Columns[j].Numeric && err == nil {
+				fmt.Fprintf(&builder, `<c r="%s"><v>%s</v></c>`, ref, strconv.FormatFloat(number, 'f', -1, 64))
+				continue
+			}
+
+			fmt.Fprintf(&builder, `<c r="%s" t="inlineStr"><is><t xml:space="preserve">%s</t></is></c>`, ref, escapeXML(cell))
+		}
+		builder.WriteString(`</row>`)
+	}
+
+	builder.WriteString(`</sheetData></worksheet>`)
+	return builder.String()
+}
+
+// columnName names a column the way spreadsheets do, A through Z, then AA and on.
+func columnName(index int) string {
+	name := ""
+	for index++; index > 0; index = (index - 1) / 26 {
+		name = string(rune('A'+(index-1)%26)) + name
+	}
+	return name
+}
+
+// escapeXML escapes text for XML, replacing characters XML can't hold.
+func escapeXML(text string) string {
+	var builder strings.Builder
+	xml.EscapeText(&builder, []byte(text))
+	return builder.String()
+}
+
+// sheetNameReplacer replaces the characters sheet names can't hold.
+var sheetNameReplacer = strings.NewReplacer("[", "(", "]", ")", ":", "-", "*", "-", "?", "", "/", "-", `\`, "-")
+
+// sheetNames makes names spreadsheet apps accept for the sheets: short,
+// without special characters and unique.
+func sheetNames(sheets []Sheet) []string {
+	names := make([]string, len(sheets))
+	seen := make(map[string]bool)
+
+	for i, sheet := range sheets {
+		base := strings.Trim(sheetNameReplacer.Replace(sheet.Name), " '")
+		if base == "" {
+			base = fmt.Sprintf("Sheet %d", i+1)
+		}
+
+		name := truncate(base, maxSheetNameLength)
+		for n := 2; seen[strings.ToLower(name)]; n++ {
+			suffix := fmt.Sprintf(" (%d)", n)
+			name = truncate(base, maxSheetNameLength-len(suffix)) + suffix
+		}
+
+		seen[strings.ToLower(name)] = true
+		names[i] = name
+	}
+
+	return names
+}
+
+// truncate shortens text to at most length characters.
+func truncate(text string, length int) string {
+	if utf8.RuneCountInString(text) <= length {
+		return text
+	}
+	return string([]rune(text)[:length])
+}
@@ -0,0 +1,280 @@
+package grades
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Threqt1/HACApi/app/models"
+	"github.com/Threqt1/HACApi/pkg/repository"
+)
+
+// The defaults of a GPA scale.
+const (
+	defaultBase    = 4
+	defaultPassing = 70
+)
+
+// The credit of a semester, if HAC doesn't list one.
+const defaultSemesterCredit = 0.5
+
+// weightRule is a compiled GPAWeightRule.
+type weightRule struct {
+	Pattern *regexp.Regexp
+	Bonus   float64
+}
+
+// Scale converts averages into grade points.
+type Scale struct {
+	Base    float64
+	Linear  bool
+	Passing float64
+	Rules   []weightRule
+}
+
+// NewScale makes a scale, filling in the defaults and compiling the weight rules.
+func NewScale(params models.GPAScale, rules []models.GPAWeightRule) (Scale, error) {
+	scale := Scale{Base: params.Base, Linear: params.Method == models.GPAMethodLinear, Passing: params.Passing}
+	if scale.Base == 0 {
+		scale.Base = defaultBase
+	}
+	if scale.Passing == 0 {
+		scale.Passing = defaultPassing
+	}
+
+	for i, rule := range rules {
+		pattern, err := regexp.Compile(rule.Pattern)
+		if err != nil {
+			return Scale{}, fmt.Errorf("%w: rules[%d]: %s", repository.ErrorBadBodyParams, i, err)
+		}
+		scale.Rules = append(scale.Rules, weightRule{Pattern: pattern, Bonus: rule.Bonus})
+	}
+
+	return scale, nil
+}
+
+// Points converts the average of a class into grade points. Passing averages earn the bonus
+// of the first weight rule matching the course code, and failing ones earn nothing.
+func (scale Scale) Points(average float64, course string) float64 {
+	if average < scale.Passing {
+		return 0
+	}
+
+	var points float64
+	switch {
+	case scale.Linear:
+		points = scale.Base - math.Max(0, 100-average)/10
+	case average >= 90:
+		points = scale.Base
+	case average >= 80:
+		points = scale.Base - 1
+	case average >= 70:
+		points = scale.Base - 2
+	default:
+		points = scale.Base - 3
+	}
+
+	for _, rule := range scale.Rules {
+		if rule.Pattern.MatchString(course) {
+			points += rule.Bonus
+			break
+		}
+	}
+
+	return math.Max(0, points)
+}
+
+// SchoolYear returns the school year a time falls in, written like HAC does ("2023-2024").
+// School years start in August.
+func SchoolYear(now time.Time) string {
+	start := now.Year()
+	if now.Month() < time.August {
+		start--
+	}
+	return fmt.Sprintf("%d-%d", start, start+1)
+}
+
+// CalculateGPA calculates the GPA of the completed semesters on the transcripts, the GPA
+// projected with the semester averages of the report cards, and the projected GPA with
+// the hypothetical averages, if any. The report cards are of the school year passed, and
+// semesters of it already on the transcript aren't counted again from the report card.
+// The transcripts and report cards have to be normalized.
+func CalculateGPA(transcripts []models.Transcript, reportCards []models.ReportCard, year string, whatIf []models.GPAWhatIf, scale Scale) models.GPA {
+	gpa := models.GPA{District: []models.TranscriptGPA{}, Classes: []models.GPAClass{}}
+
+	// Count the completed semesters.
+	completed := make(map[string]bool)
+	for _, transcript := range transcripts {
+		gpa.District = append(gpa.District, transcript.Weighted, transcript.Unweighted)
+
+		for _, group := range transcript.Entries {
+			for _, entry := range group.Entries {
+				if entry.Normalized == nil || entry.Normalized.Average.Score == nil || entry.Normalized.Credit == nil || *entry.Normalized.Credit <= 0 {
+					continue
+				}
+				average, credit := *entry.Normalized.Average.Score, *entry.Normalized.Credit
+
+				completed[semesterKey(group.Year, entry.Class.Course, group.Semester)] = true
+				gpa.Classes = append(gpa.Classes, models.GPAClass{
+					Class:    entry.Class,
+					Year:     group.Year,
+					Semester: group.Semester,
+					Average:  average,
+					Credit:   credit,
+					Points:   scale.Points(average, entry.Class.Course),
+					Source:   models.GPASourceTranscript,
+				})
+			}
+		}
+	}
+	gpa.Current = weightedGPA(gpa.Classes)
+
+	// Add the semesters in progress.
+	for _, reportCard := range reportCards {
+		for _, entry := range reportCard.Entries {
+			for semester := 1; semester <= 2; semester++ {
+				average, ok := semesterAverage(entry.Averages.Normalized, semester)
+				if !ok || completed[semesterKey(year, entry.Class.Course, strconv.Itoa(semester))] {
+					continue
+				}
+
+				gpa.Classes = append(gpa.Classes, models.GPAClass{
+					Class:    entry.Class,
+					Year:     year,
+					Semester: strconv.Itoa(semester),
+					Average:  average,
+					Credit:   semesterCredit(entry.Normalized),
+					Points:   scale.Points(average, entry.Class.Course),
+					Source:   models.GPASourceReportCard,
+				})
+			}
+		}
+	}
+	gpa.Projected = weightedGPA(gpa.Classes)
+
+	if len(whatIf) == 0 {
+		return gpa
+	}
+
+	// Apply the hypothetical averages.
+	for _, change := range whatIf {
+		gpa.Classes = applyGPAWhatIf(gpa.Classes, change, scale)
+	}
+	result := weightedGPA(gpa.Classes)
+	gpa.WhatIf = &result
+
+	return gpa
+}
+
+// applyGPAWhatIf replaces the average of the class's semester in the current
+// year, or adds the class if there is none.
+func applyGPAWhatIf(classes []models.GPAClass, change models.GPAWhatIf, scale Scale) []models.GPAClass {
+	semester := strconv.Itoa(change.Semester)
+
+	for i, class := range classes {
+		if class.Source == models.GPASourceTranscript || class.Semester != semester || !strings.EqualFold(strings.TrimSpace(class.Class.Course), strings.TrimSpace(change.Course)) {
+			continue
+		}
+
+		classes[i].Average = *change.Average
+		classes[i].Points = scale.Points(*change.Average, class.Class.Course)
+		classes[i].Source = models.GPASourceWhatIf
+		if change.Credit != nil {
+			classes[i].Credit = *change.Credit
+		}
+		return classes
+	}
+
+	added := models.GPAClass{
+		Class:    models.Class{Course: change.Course},
+		Semester: semester,
+		Average:  *change.Average,
+		Credit:   defaultSemesterCredit,
+		Points:   scale.Points(*change.Average, change.Course),
+		Source:   models.GPASourceWhatIf,
+	}
+	if change.Credit != nil {
+		added.Credit = *change.Credit
+	}
+	return append(classes, added)
+}
+
+// weightedGPA averages the points of the classes, weighted by their credit.
+func weightedGPA(classes []models.GPAClass) models.GPAResult {
+	var result models.GPAResult
+	var points float64
+
+	for _, class := range classes {
+		points += class.Points * class.Credit
+		result.Credits += class.Credit
+	}
+
+	if result.Credits > 0 {
+		gpa := math.Round(points/result.Credits*10000) / 10000
+		result.GPA = &gpa
+	}
+	return result
+}
+
+// semesterAverage returns the final average of a semester, or the average of its
+// marking periods and exam so far if it isn't over. It returns false if the
+// semester has no averages.
+func semesterAverage(averages *models.NormalizedSixWeeksGrades, semester int) (float64, bool) {
+	if averages == nil {
+		return 0, false
+	}
+
+	final, cycles := averages.Sem1, []models.NormalizedGrade{averages.First, averages.Second, averages.Third, averages.Exam1}
+	if semester == 2 {
+		final, cycles = averages.Sem2, []models.NormalizedGrade{averages.Fourth, averages.Fifth, averages.Sixth, averages.Exam2}
+	}
+
+	if final.Score != nil {
+		return *final.Score, true
+	}
+
+	var sum float64
+	var count int
+	for _, cycle := range cycles {
+		if cycle.Score != nil {
+			sum += *cycle.Score
+			count++
+		}
+	}
+
+	if count == 0 {
+		return 0, false
+	}
+	return sum / float64(count), true
+}
+
+// semesterCredit is the credit of one semester of a class, from the credit attempted.
+// Classes worth a full credit or more span both semesters, so it is split between them.
+func semesterCredit(entry *models.NormalizedReportCardEntry) float64 {
+	switch {
+	case entry == nil || entry.AttemptedCredit == nil || *entry.AttemptedCredit <= 0:
+		return defaultSemesterCredit
+	case *entry.AttemptedCredit >= 1:
+		return *entry.AttemptedCredit / 2
+	}
+	return *entry.AttemptedCredit
+}
+
+// semesterKey identifies a semester of a course in a school year, whether the
+// year is written as "2023-2024" or "2023 - 2024", and the semester as "1" or "S1".
+func semesterKey(year, course, semester string) string {
+	return digits(year) + "\n" + strings.ToUpper(strings.TrimSpace(course)) + "\n" + digits(semester)
+}
+
+// digits returns only the digits of text.
+func digits(text string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return r
+		}
+		return -1
+	}, text)
+}
@@ -0,0 +1,168 @@
+package grades
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Threqt1/HACApi/app/models"
+	"github.com/Threqt1/HACApi/pkg/repository"
+	"github.com/google/go-cmp/cmp"
+)
+
+// testGPA_TranscriptEntry returns a normalized transcript entry.
+func testGPA_TranscriptEntry(name, course string, average *float64, credit float64) models.TranscriptGroupEntry {
+	return models.TranscriptGroupEntry{
+		Class:      models.Class{Name: name, Course: course},
+		Normalized: &models.NormalizedTranscriptEntry{Average: models.NormalizedGrade{Score: average}, Credit: testWhatIf_Float(credit)},
+	}
+}
+
+// testGPA_Transcript has a failed semester of a class being retaken, and a
+// completed year, with an AP class and a failed semester.
+var testGPA_Transcript = []models.Transcript{{
+	Entries: []models.TranscriptGroup{
+		{
+			Year:     "2021-2022",
+			Semester: "1",
+			Entries: []models.TranscriptGroupEntry{
+				testGPA_TranscriptEntry("Geometry", "0402A - 1", testWhatIf_Float(60), 0.5),
+			},
+		},
+		{
+			Year:     "2022-2023",
+			Semester: "1",
+			Entries: []models.TranscriptGroupEntry{
+				testGPA_TranscriptEntry("Algebra", "0401A - 1", testWhatIf_Float(95), 0.5),
+				testGPA_TranscriptEntry("AP Biology", "AP0101 - 1", testWhatIf_Float(85), 0.5),
+				testGPA_TranscriptEntry("Advisory", "9999 - 1", nil, 0),
+			},
+		},
+		{
+			Year:     "2022-2023",
+			Semester: "2",
+			Entries: []models.TranscriptGroupEntry{
+				testGPA_TranscriptEntry("Algebra", "0401A - 1", testWhatIf_Float(65), 0.5),
+			},
+		},
+	},
+	Weighted:   models.TranscriptGPA{Type: "Weighted", GPA: "3.5000"},
+	Unweighted: models.TranscriptGPA{Type: "Unweighted", GPA: "3.0000"},
+}}
+
+// testGPA_ReportCard has a class in progress, and one already on the transcript.
+var testGPA_ReportCard = []models.ReportCard{{
+	Entries: []models.ReportCardEntry{
+		{
+			Class:      models.Class{Name: "Geometry", Course: "0402A - 1"},
+			Averages:   models.SixWeeksGrades{Normalized: &models.NormalizedSixWeeksGrades{First: models.NormalizedGrade{Score: testWhatIf_Float(90)}, Second: models.NormalizedGrade{Score: testWhatIf_Float(80)}}},
+			Normalized: &models.NormalizedReportCardEntry{AttemptedCredit: testWhatIf_Float(1)},
+		},
+		{
+			Class:    models.Class{Name: "Algebra", Course: "0401A - 1"},
+			Averages: models.SixWeeksGrades{Normalized: &models.NormalizedSixWeeksGrades{Sem1: models.NormalizedGrade{Score: testWhatIf_Float(95)}}},
+		},
+	},
+}}
+
+// Test if CalculateGPA() calculates the current, projected
+// and what-if GPA, weighting classes by credit. Classes
+// retaken from a previous year are counted again.
+func TestCalculateGPA(t *testing.T) {
+	scale, err := NewScale(models.GPAScale{}, []models.GPAWeightRule{{Pattern: "^AP", Bonus: 1}})
+	if err != nil {
+		t.Fatalf("Failed for NewScale(): %s", err)
+	}
+
+	got := CalculateGPA(testGPA_Transcript, testGPA_ReportCard, "2022 - 2023", []models.GPAWhatIf{
+		{Course: "0402a - 1", Semester: 1, Average: testWhatIf_Float(95)},
+		{Course: "CHEM - 1", Semester: 2, Average: testWhatIf_Float(75)},
+	}, scale)
+
+	// Make expected output.
+	expected := models.GPA{
+		Current:   models.GPAResult{GPA: testWhatIf_Float(2), Credits: 2},
+		Projected: models.GPAResult{GPA: testWhatIf_Float(2.2), Credits: 2.5},
+		WhatIf:    &models.GPAResult{GPA: testWhatIf_Float(2.3333), Credits: 3},
+		District:  []models.TranscriptGPA{testGPA_Transcript[0].Weighted, testGPA_Transcript[0].Unweighted},
+		Classes: []models.GPAClass{
+			{Class: models.Class{Name: "Geometry", Course: "0402A - 1"}, Year: "2021-2022", Semester: "1", Average: 60, Credit: 0.5, Points: 0, Source: models.GPASourceTranscript},
+			{Class: models.Class{Name: "Algebra", Course: "0401A - 1"}, Year: "2022-2023", Semester: "1", Average: 95, Credit: 0.5, Points: 4, Source: models.GPASourceTranscript},
+			{Class: models.Class{Name: "AP Biology", Course: "AP0101 - 1"}, Year: "2022-2023", Semester: "1", Average: 85, Credit: 0.5, Points: 4, Source: models.GPASourceTranscript},
+			{Class: models.Class{Name: "Algebra", Course: "0401A - 1"}, Year: "2022-2023", Semester: "2", Average: 65, Credit: 0.5, Points: 0, Source: models.GPASourceTranscript},
+			{Class: models.Class{Name: "Geometry", Course: "0402A - 1"}, Year: "2022 - 2023", Semester: "1", Average: 95, Credit: 0.5, Points: 4, Source: models.GPASourceWhatIf},
+			{Class: models.Class{Course: "CHEM - 1"}, Semester: "2", Average: 75, Credit: 0.5, Points: 2, Source: models.GPASourceWhatIf},
+		},
+	}
+
+	// Test.
+	if diff := cmp.Diff(expected, got); diff != "" {
+		t.Fatalf("Failed for CalculateGPA() (-want, +got)\n%s", diff)
+	}
+}
+
+// Test if CalculateGPA() leaves out the what-if GPA, and the
+// GPA itself, when there is nothing to calculate it over.
+func TestCalculateGPA_Empty(t *testing.T) {
+	scale, _ := NewScale(models.GPAScale{}, nil)
+
+	got := CalculateGPA(nil, nil, "2022-2023", nil, scale)
+
+	// Make expected output.
+	expected := models.GPA{District: []models.TranscriptGPA{}, Classes: []models.GPAClass{}}
+
+	// Test.
+	if diff := cmp.Diff(expected, got); diff != "" {
+		t.Fatalf("Failed for CalculateGPA() Empty (-want, +got)\n%s", diff)
+	}
+}
+
+// Test if Scale.Points() converts averages on a linear scale.
+func TestScale_Points_Linear(t *testing.T) {
+	scale, _ := NewScale(models.GPAScale{Base: 5, Method: models.GPAMethodLinear}, []models.GPAWeightRule{{Pattern: "^AP", Bonus: 1}})
+
+	tests := []struct {
+		average  float64
+		course   string
+		expected float64
+	}{
+		{100, "0401A - 1", 5},
+		{105, "0401A - 1", 5},
+		{85, "0401A - 1", 3.5},
+		{85, "AP0101 - 1", 4.5},
+		{69, "AP0101 - 1", 0},
+	}
+
+	// Test.
+	for _, test := range tests {
+		if got := scale.Points(test.average, test.course); got != test.expected {
+			t.Fatalf("Failed for Scale.Points(%v, %q): expected %v, got %v", test.average, test.course, test.expected, got)
+		}
+	}
+}
+
+// Test if SchoolYear() starts school years in August.
+func TestSchoolYear(t *testing.T) {
+	// Set up all test cases, mapping the input to the expected output.
+	cases := map[time.Time]string{
+		time.Date(2023, time.July, 31, 0, 0, 0, 0, time.UTC):   "2022-2023",
+		time.Date(2023, time.August, 1, 0, 0, 0, 0, time.UTC):  "2023-2024",
+		time.Date(2024, time.January, 8, 0, 0, 0, 0, time.UTC): "2023-2024",
+	}
+
+	for input, expected := range cases {
+		if diff := cmp.Diff(expected, SchoolYear(input)); diff != "" {
+			t.Fatalf("Failed for SchoolYear() with input %v (-want, +got):\n%s", input, diff)
+		}
+	}
+}
+
+// Test if NewScale() errors out on invalid weight rules.
+func TestNewScale_InvalidRule(t *testing.T) {
+	_, err := NewScale(models.GPAScale{}, []models.GPAWeightRule{{Pattern: "(AP"}})
+
+	// Test.
+	if !errors.Is(err, repository.ErrorBadBodyParams) {
+		t.Fatalf("Failed for NewScale() Invalid Rule: expected %v, got %v", repository.ErrorBadBodyParams, err)
+	}
+}
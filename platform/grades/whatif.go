@@ -0,0 +1,235 @@
+package grades
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/Threqt1/HACApi/app/models"
+	"github.com/Threqt1/HACApi/pkg/repository"
+)
+
+// The points an assignment is out of, if HAC doesn't list any.
+const defaultTotalPoints = 100
+
+// gradedAssignment is an assignment reduced to what counts towards an average.
+type gradedAssignment struct {
+	Name        string
+	Category    string
+	Score       *float64
+	TotalPoints float64
+	Mark        models.SpecialMark
+	Dropped     bool
+}
+
+// counts returns whether the assignment counts towards the average,
+// and the points it scored. Missing assignments count as zero, while
+// excused, ungraded and dropped ones are left out.
+func (assignment gradedAssignment) counts() (bool, float64) {
+	switch {
+	case assignment.Dropped || assignment.Mark == models.SpecialMarkDropped:
+		return false, 0
+	case assignment.Score != nil:
+		return true, *assignment.Score
+	case assignment.Mark == models.SpecialMarkMissing:
+		return true, 0
+	}
+	return false, 0
+}
+
+// WhatIf recalculates the averages of the classes the hypothetical assignments
+// are in, with them added or edited. The classwork has to be normalized.
+//
+// Classes with a category breakdown are averaged like HAC does: the percentage
+// of each category which has anything counting towards it, weighted by the
+// category's weight. Classes without one are averaged by total points.
+func WhatIf(classwork models.Classwork, changes []models.WhatIfAssignment) ([]models.WhatIfClass, error) {
+	var classes []models.WhatIfClass
+	assignments := make(map[int][]gradedAssignment)
+	order := make(map[int]int)
+
+	for _, change := range changes {
+		// Find the class.
+		index := findClass(classwork.Entries, change.Course)
+		if index < 0 {
+			return nil, fmt.Errorf("%w: no class has the course %q", repository.ErrorInvalidWhatIf, change.Course)
+		}
+		entry := classwork.Entries[index]
+
+		if _, ok := order[index]; !ok {
+			order[index] = len(classes)
+			assignments[index] = gradedAssignments(entry.Assignments)
+			classes = append(classes, models.WhatIfClass{Class: entry.Class, Average: entry.Average})
+		}
+
+		// Apply the change.
+		updated, err := applyChange(entry, assignments[index], change)
+		if err != nil {
+			return nil, err
+		}
+		assignments[index] = updated
+	}
+
+	// Recalculate the averages.
+	for index, position := range order {
+		entry := classwork.Entries[index]
+
+		classes[position].Current, _ = average(entry.Categories, gradedAssignments(entry.Assignments))
+		classes[position].WhatIf, classes[position].Categories = average(entry.Categories, assignments[index])
+	}
+
+	return classes, nil
+}
+
+// applyChange edits or adds the hypothetical assignment.
+func applyChange(entry models.ClassworkEntry, assignments []gradedAssignment, change models.WhatIfAssignment) ([]gradedAssignment, error) {
+	// Confirm the category is one of the class's.
+	category := strings.TrimSpace(change.Category)
+	if category != "" && len(entry.Categories) > 0 && findCategory(entry.Categories, category) < 0 {
+		return nil, fmt.Errorf("%w: %q has no category %q", repository.ErrorInvalidWhatIf, entry.Class.Name, category)
+	}
+
+	if change.New {
+		if category == "" && len(entry.Categories) > 0 {
+			return nil, fmt.Errorf("%w: added assignment %q needs a category", repository.ErrorInvalidWhatIf, change.Name)
+		}
+
+		added := gradedAssignment{Name: change.Name, Category: category, Score: change.Score, TotalPoints: defaultTotalPoints, Dropped: change.Dropped}
+		if change.TotalPoints != nil {
+			added.TotalPoints = *change.TotalPoints
+		}
+		return append(assignments, added), nil
+	}
+
+	// Find the assignment to edit.
+	for i := range assignments {
+		if !strings.EqualFold(strings.TrimSpace(assignments[i].Name), strings.TrimSpace(change.Name)) {
+			continue
+		}
+
+		edited := make([]gradedAssignment, len(assignments))
+		copy(edited, assignments)
+
+		edited[i].Score, edited[i].Mark, edited[i].Dropped = change.Score, models.SpecialMarkNone, change.Dropped
+		if change.TotalPoints != nil {
+			edited[i].TotalPoints = *change.TotalPoints
+		}
+		if category != "" {
+			edited[i].Category = category
+		}
+		return edited, nil
+	}
+
+	return nil, fmt.Errorf("%w: %q has no assignment %q", repository.ErrorInvalidWhatIf, entry.Class.Name, change.Name)
+}
+
+// average calculates the average of the assignments, along with the totals of each category.
+// It returns nil if nothing counts towards the average.
+func average(categories []models.ClassworkCategory, assignments []gradedAssignment) (*float64, []models.WhatIfCategory) {
+	// Without weighted categories, everything is in one category.
+	totals := make([]models.WhatIfCategory, len(categories))
+	weighted := false
+	for i, category := range categories {
+		totals[i].Name = category.Name
+		if category.Normalized != nil && category.Normalized.Weight != nil {
+			totals[i].Weight = *category.Normalized.Weight
+			weighted = weighted || totals[i].Weight > 0
+		}
+	}
+	if !weighted {
+		totals = []models.WhatIfCategory{{Name: "Total", Weight: 100}}
+	}
+
+	// Add up the points of each category.
+	counted := make([]bool, len(totals))
+	for _, assignment := range assignments {
+		counts, score := assignment.counts()
+		if !counts {
+			continue
+		}
+
+		index := 0
+		if weighted {
+			if index = findCategory(categories, assignment.Category); index < 0 {
+				continue
+			}
+		}
+
+		totals[index].StudentPoints += score
+		totals[index].MaximumPoints += assignment.TotalPoints
+		counted[index] = true
+	}
+
+	// Weight the percentage of each category which has anything counting towards it.
+	var points, weights float64
+	for i := range totals {
+		if !counted[i] || totals[i].MaximumPoints <= 0 {
+			continue
+		}
+
+		percent := totals[i].StudentPoints / totals[i].MaximumPoints * 100
+		points += percent * totals[i].Weight
+		weights += totals[i].Weight
+
+		rounded := round(percent)
+		totals[i].Percent = &rounded
+	}
+
+	if weights <= 0 {
+		return nil, totals
+	}
+
+	result := round(points / weights)
+	return &result, totals
+}
+
+// gradedAssignments reduces normalized assignments to what counts towards an average.
+func gradedAssignments(assignments []models.Assignment) []gradedAssignment {
+	graded := make([]gradedAssignment, len(assignments))
+
+	for i, assignment := range assignments {
+		graded[i] = gradedAssignment{
+			Name:        assignment.Name,
+			Category:    assignment.Category,
+			TotalPoints: defaultTotalPoints,
+			Dropped:     assignment.Dropped,
+		}
+
+		if assignment.Normalized == nil {
+			continue
+		}
+
+		grade := assignment.Normalized.Grade
+		graded[i].Score, graded[i].Mark = grade.Score, grade.Mark
+		if grade.MaxPoints != nil {
+			graded[i].TotalPoints = *grade.MaxPoints
+		}
+	}
+
+	return graded
+}
+
+// findClass returns the index of the class with the course ID, or -1 if there is none.
+func findClass(entries []models.ClassworkEntry, course string) int {
+	for i, entry := range entries {
+		if strings.EqualFold(strings.TrimSpace(entry.Class.Course), strings.TrimSpace(course)) {
+			return i
+		}
+	}
+	return -1
+}
+
+// findCategory returns the index of the category with the name, or -1 if there is none.
+func findCategory(categories []models.ClassworkCategory, name string) int {
+	for i, category := range categories {
+		if strings.EqualFold(strings.TrimSpace(category.Name), strings.TrimSpace(name)) {
+			return i
+		}
+	}
+	return -1
+}
+
+// round rounds to two decimals, like HAC shows averages.
+func round(number float64) float64 {
+	return math.Round(number*100) / 100
+}
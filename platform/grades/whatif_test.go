@@ -0,0 +1,131 @@
+package grades
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Threqt1/HACApi/app/models"
+	"github.com/Threqt1/HACApi/pkg/repository"
+	"github.com/google/go-cmp/cmp"
+)
+
+// testWhatIf_Float returns a pointer to a number.
+func testWhatIf_Float(number float64) *float64 {
+	return &number
+}
+
+// testWhatIf_Assignment makes a normalized assignment.
+func testWhatIf_Assignment(name, category string, score *float64, mark models.SpecialMark) models.Assignment {
+	return models.Assignment{
+		Name:       name,
+		Category:   category,
+		Dropped:    mark == models.SpecialMarkDropped,
+		Normalized: &models.NormalizedAssignment{Grade: models.NormalizedGrade{Score: score, MaxPoints: testWhatIf_Float(100), Mark: mark}},
+	}
+}
+
+// testWhatIf_Category makes a normalized category with a weight.
+func testWhatIf_Category(name string, weight float64) models.ClassworkCategory {
+	return models.ClassworkCategory{Name: name, Normalized: &models.NormalizedCategory{Weight: testWhatIf_Float(weight)}}
+}
+
+// testWhatIf_Classwork has a class with weighted categories, and one without.
+var testWhatIf_Classwork = models.Classwork{
+	MarkingPeriod: 1,
+	Entries: []models.ClassworkEntry{
+		{
+			Class:   models.Class{Name: "Algebra", Course: "0401A - 1"},
+			Average: "86.00",
+			Assignments: []models.Assignment{
+				testWhatIf_Assignment("Test 1", "Major Grades", testWhatIf_Float(80), models.SpecialMarkNone),
+				testWhatIf_Assignment("Test 2", "Major Grades", testWhatIf_Float(20), models.SpecialMarkDropped),
+				testWhatIf_Assignment("Quiz 1", "Minor Grades", testWhatIf_Float(95), models.SpecialMarkNone),
+				testWhatIf_Assignment("Quiz 2", "Minor Grades", nil, models.SpecialMarkExcused),
+			},
+			Categories: []models.ClassworkCategory{testWhatIf_Category("Major Grades", 60), testWhatIf_Category("Minor Grades", 40)},
+		},
+		{
+			Class:   models.Class{Name: "Art", Course: "0501A - 1"},
+			Average: "90.00",
+			Assignments: []models.Assignment{
+				testWhatIf_Assignment("Sketch", "Daily", testWhatIf_Float(90), models.SpecialMarkNone),
+				testWhatIf_Assignment("Portfolio", "Daily", nil, models.SpecialMarkMissing),
+			},
+		},
+	},
+}
+
+// Test if WhatIf() recalculates averages with edited and added assignments,
+// leaving out dropped and excused ones.
+func TestWhatIf(t *testing.T) {
+	changes := []models.WhatIfAssignment{
+		{Course: "0401a - 1", Name: "Test 3", New: true, Category: "major grades", Score: testWhatIf_Float(100)},
+		{Course: "0401A - 1", Name: "quiz 1", Score: testWhatIf_Float(85)},
+		{Course: "0501A - 1", Name: "Portfolio", Score: testWhatIf_Float(45), TotalPoints: testWhatIf_Float(50)},
+	}
+
+	got, err := WhatIf(testWhatIf_Classwork, changes)
+	if err != nil {
+		t.Fatalf("Failed for WhatIf():\n%v", err)
+	}
+
+	expected := []models.WhatIfClass{
+		{
+			Class:   models.Class{Name: "Algebra", Course: "0401A - 1"},
+			Average: "86.00",
+			// 80 * 0.6 + 95 * 0.4
+			Current: testWhatIf_Float(86),
+			// 90 * 0.6 + 85 * 0.4
+			WhatIf: testWhatIf_Float(88),
+			Categories: []models.WhatIfCategory{
+				{Name: "Major Grades", Weight: 60, StudentPoints: 180, MaximumPoints: 200, Percent: testWhatIf_Float(90)},
+				{Name: "Minor Grades", Weight: 40, StudentPoints: 85, MaximumPoints: 100, Percent: testWhatIf_Float(85)},
+			},
+		},
+		{
+			Class:   models.Class{Name: "Art", Course: "0501A - 1"},
+			Average: "90.00",
+			// The missing portfolio counts as zero.
+			Current: testWhatIf_Float(45),
+			WhatIf:  testWhatIf_Float(90),
+			Categories: []models.WhatIfCategory{
+				{Name: "Total", Weight: 100, StudentPoints: 135, MaximumPoints: 150, Percent: testWhatIf_Float(90)},
+			},
+		},
+	}
+
+	if diff := cmp.Diff(expected, got); diff != "" {
+		t.Fatalf("Failed for WhatIf() (-want, +got)\n%s", diff)
+	}
+}
+
+// Test if WhatIf() leaves out categories with nothing counting towards them,
+// and assignments dropped hypothetically.
+func TestWhatIf_Dropped(t *testing.T) {
+	changes := []models.WhatIfAssignment{{Course: "0401A - 1", Name: "Quiz 1", Dropped: true}}
+
+	got, err := WhatIf(testWhatIf_Classwork, changes)
+	if err != nil {
+		t.Fatalf("Failed for WhatIf() Dropped:\n%v", err)
+	}
+
+	if got[0].WhatIf == nil || *got[0].WhatIf != 80 || got[0].Categories[1].Percent != nil {
+		t.Fatalf("Failed for WhatIf() Dropped, expected only the major grades to count, got %+v", got[0])
+	}
+}
+
+// Test if WhatIf() rejects changes which don't match the classwork.
+func TestWhatIf_Invalid(t *testing.T) {
+	tests := map[string]models.WhatIfAssignment{
+		"Unknown Class":          {Course: "9999A - 1", Name: "Test 1", Score: testWhatIf_Float(90)},
+		"Unknown Assignment":     {Course: "0401A - 1", Name: "Test 9", Score: testWhatIf_Float(90)},
+		"Unknown Category":       {Course: "0401A - 1", Name: "Test 3", New: true, Category: "Homework", Score: testWhatIf_Float(90)},
+		"Added Without Category": {Course: "0401A - 1", Name: "Test 3", New: true, Score: testWhatIf_Float(90)},
+	}
+
+	for name, change := range tests {
+		if _, err := WhatIf(testWhatIf_Classwork, []models.WhatIfAssignment{change}); !errors.Is(err, repository.ErrorInvalidWhatIf) {
+			t.Fatalf("Failed for WhatIf() %s, expected %v, got %v", name, repository.ErrorInvalidWhatIf, err)
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package health
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/Threqt1/HACApi/app/models"
+	"github.com/Threqt1/HACApi/pkg/repository"
+	"golang.org/x/sync/singleflight"
+)
+
+// How long a district is given to respond to a probe.
+const probeTimeout = 10 * time.Second
+
+// Checker probes the LogOn pages of the configured districts, caching
+// the results so readiness checks don't hit HAC on every request.
+//
+// cache format -
+// key: base URL of the district's HAC
+// val: result of the last probe
+type Checker struct {
+	Bases   []string                   // The base URLs of the districts to probe
+	Scraper repository.ScraperProvider // The scraper probing the districts
+	TTL     time.Duration              // How long a probe result is reused
+	Probes  *singleflight.Group        // Coalesces concurrent probes of the same district
+
+	mutex   sync.Mutex
+	results map[string]models.DistrictHealth
+}
+
+// NewChecker creates a new checker for the districts at bases,
+// reusing probe results for ttl.
+func NewChecker(scraper repository.ScraperProvider, bases []string, ttl time.Duration) *Checker {
+	return &Checker{
+		Bases:   bases,
+		Scraper: scraper,
+		TTL:     ttl,
+		Probes:  &singleflight.Group{},
+		results: map[string]models.DistrictHealth{},
+	}
+}
+
+// Readiness returns the status of every configured district, probing
+// the ones whose last result is older than the TTL.
+func (checker *Checker) Readiness(ctx context.Context) models.ReadinessResponse {
+	districts := make([]models.DistrictHealth, len(checker.Bases))
+
+	var wg sync.WaitGroup
+	for i, base := range checker.Bases {
+		wg.Add(1)
+		go func(i int, base string) {
+			defer wg.Done()
+			districts[i] = checker.district(ctx, base)
+		}(i, base)
+	}
+	wg.Wait()
+
+	return models.ReadinessResponse{Status: readinessStatus(districts), Districts: districts}
+}
+
+// district returns the cached status of the district at base, probing it if the status is stale.
+func (checker *Checker) district(ctx context.Context, base string) models.DistrictHealth {
+	checker.mutex.Lock()
+	result, ok := checker.results[base]
+	checker.mutex.Unlock()
+
+	if ok && time.Since(result.CheckedAt) < checker.TTL {
+		return result
+	}
+
+	res := checker.Probes.DoChan(base, func() (interface{}, error) {
+		return checker.probe(base), nil
+	})
+
+	select {
+	case res := <-res:
+		return res.Val.(models.DistrictHealth)
+	// The caller gave up, so report the last result if there is one.
+	case <-ctx.Done():
+		if ok {
+			return result
+		}
+		return models.DistrictHealth{Base: base, Status: models.HealthStatusDown, Error: ctx.Err().Error(), CheckedAt: time.Now()}
+	}
+}
+
+// probe probes the district at base and caches the result. Probes outlive the
+// request which started them, so their result is cached for the requests after.
+func (checker *Checker) probe(base string) models.DistrictHealth {
+	ctx, cancel := context.WithTimeout(context.Background(), probeTimeout)
+	defer cancel()
+
+	result := models.DistrictHealth{Base: base, Status: models.HealthStatusUp, CheckedAt: time.Now()}
+	if err := checker.Scraper.Probe(ctx, base); err != nil {
+		result.Status = models.HealthStatusDown
+		result.Error = err.Error()
+	}
+
+	checker.mutex.Lock()
+	checker.results[base] = result
+	checker.mutex.Unlock()
+
+	return result
+}
+
+// readinessStatus sums up the statuses of the districts. The API is only down
+// if every district is, since it can still serve the districts which are up.
+// With no districts configured, nothing shows that HAC can be served, so the
+// API is down.
+func readinessStatus(districts []models.DistrictHealth) string {
+	if len(districts) == 0 {
+		return models.HealthStatusDown
+	}
+
+	down := 0
+	for _, district := range districts {
+		if district.Status != models.HealthStatusUp {
+			down++
+		}
+	}
+
+	switch {
+	case down == 0:
+		return models.HealthStatusUp
+	case down < len(districts):
+		return models.HealthStatusDegraded
+	}
+	return models.HealthStatusDown
+}
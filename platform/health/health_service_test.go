@@ -0,0 +1,131 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/Threqt1/HACApi/app/models"
+	"github.com/gocolly/colly"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+// Represents a dummy scraper, whose probes fail for the bases in Down.
+type testHealth_DummyScraper struct {
+	Down   map[string]bool
+	Probes *int32 // The amount of probes made
+}
+
+// Represents the Login method for a dummy scraper (not needed).
+func (scraper testHealth_DummyScraper) Login(ctx context.Context, base, username, password string) (*colly.Collector, error) {
+	return nil, nil
+}
+
+// Represents the Restore method for a dummy scraper (not needed).
+func (scraper testHealth_DummyScraper) Restore(base string, cookies []*http.Cookie) (*colly.Collector, error) {
+	return nil, nil
+}
+
+// Represents the Probe method for a dummy scraper.
+func (scraper testHealth_DummyScraper) Probe(ctx context.Context, base string) error {
+	atomic.AddInt32(scraper.Probes, 1)
+	if scraper.Down[base] {
+		return errors.New("server unreachable")
+	}
+	return nil
+}
+
+// Represents the Navigate method for a dummy scraper (not needed).
+func (scraper testHealth_DummyScraper) Navigate(ctx context.Context, collector *colly.Collector, url, endpoint string) (*colly.Collector, *goquery.Selection, error) {
+	return nil, nil, nil
+}
+
+// Represents the Post method for a dummy scraper (not needed).
+func (scraper testHealth_DummyScraper) Post(ctx context.Context, collector *colly.Collector, url, endpoint string, formData map[string]string) (*colly.Collector, *goquery.Selection, error) {
+	return nil, nil, nil
+}
+
+// Filter out when districts were probed.
+var testHealth_Comparer = cmpopts.IgnoreFields(models.DistrictHealth{}, "CheckedAt")
+
+// Test if Readiness() reports the status of every district.
+func TestReadiness(t *testing.T) {
+	tests := []struct {
+		Name     string
+		Down     map[string]bool
+		Expected models.ReadinessResponse
+	}{
+		{
+			Name: "All Up",
+			Down: map[string]bool{},
+			Expected: models.ReadinessResponse{Status: models.HealthStatusUp, Districts: []models.DistrictHealth{
+				{Base: "https://a.url", Status: models.HealthStatusUp},
+				{Base: "https://b.url", Status: models.HealthStatusUp},
+			}},
+		},
+		{
+			Name: "Some Down",
+			Down: map[string]bool{"https://b.url": true},
+			Expected: models.ReadinessResponse{Status: models.HealthStatusDegraded, Districts: []models.DistrictHealth{
+				{Base: "https://a.url", Status: models.HealthStatusUp},
+				{Base: "https://b.url", Status: models.HealthStatusDown, Error: "server unreachable"},
+			}},
+		},
+		{
+			Name: "All Down",
+			Down: map[string]bool{"https://a.url": true, "https://b.url": true},
+			Expected: models.ReadinessResponse{Status: models.HealthStatusDown, Districts: []models.DistrictHealth{
+				{Base: "https://a.url", Status: models.HealthStatusDown, Error: "server unreachable"},
+				{Base: "https://b.url", Status: models.HealthStatusDown, Error: "server unreachable"},
+			}},
+		},
+	}
+
+	for _, test := range tests {
+		scraper := testHealth_DummyScraper{Down: test.Down, Probes: new(int32)}
+		checker := NewChecker(scraper, []string{"https://a.url", "https://b.url"}, time.Minute)
+
+		if diff := cmp.Diff(test.Expected, checker.Readiness(context.Background()), testHealth_Comparer); diff != "" {
+			t.Fatalf("Failed for Readiness() %s (-want, +got)\n%s", test.Name, diff)
+		}
+	}
+}
+
+// Test if Readiness() with no districts is down.
+func TestReadiness_NoDistricts(t *testing.T) {
+	checker := NewChecker(testHealth_DummyScraper{Probes: new(int32)}, nil, time.Minute)
+
+	expected := models.ReadinessResponse{Status: models.HealthStatusDown, Districts: []models.DistrictHealth{}}
+
+	if diff := cmp.Diff(expected, checker.Readiness(context.Background())); diff != "" {
+		t.Fatalf("Failed for Readiness() with no districts (-want, +got)\n%s", diff)
+	}
+}
+
+// Test if Readiness() reuses probe results until they are stale.
+func TestReadiness_CachesProbes(t *testing.T) {
+	scraper := testHealth_DummyScraper{Probes: new(int32)}
+
+	// Results are fresh for a minute.
+	checker := NewChecker(scraper, []string{"https://a.url"}, time.Minute)
+	for i := 0; i < 3; i++ {
+		checker.Readiness(context.Background())
+	}
+	if probes := atomic.LoadInt32(scraper.Probes); probes != 1 {
+		t.Fatalf("Failed for Readiness() with fresh results, expected 1 probe, got %d", probes)
+	}
+
+	// Results are stale right away.
+	checker.TTL = 0
+	for i := 0; i < 3; i++ {
+		checker.Readiness(context.Background())
+	}
+	if probes := atomic.LoadInt32(scraper.Probes); probes != 4 {
+		t.Fatalf("Failed for Readiness() with stale results, expected 4 probes, got %d", probes)
+	}
+}
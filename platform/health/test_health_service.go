@@ -0,0 +1,32 @@
+package health
+
+import (
+	"context"
+	"time"
+
+	"github.com/Threqt1/HACApi/app/models"
+	"github.com/Threqt1/HACApi/pkg/repository"
+)
+
+// TestHealth is a health checker meant to be used
+// during testing, with a single fake district.
+type TestHealth struct {
+	Down bool // Whether the fake district is down
+}
+
+// Report the fake district as up or down, without probing it.
+func (health TestHealth) Readiness(ctx context.Context) models.ReadinessResponse {
+	district := models.DistrictHealth{Base: repository.FakeBase, Status: models.HealthStatusUp, CheckedAt: time.Time{}}
+	if health.Down {
+		district.Status = models.HealthStatusDown
+		district.Error = "server unreachable"
+	}
+
+	districts := []models.DistrictHealth{district}
+	return models.ReadinessResponse{Status: readinessStatus(districts), Districts: districts}
+}
+
+// NewTestHealth makes a new Test Health checker.
+func NewTestHealth(down bool) TestHealth {
+	return TestHealth{Down: down}
+}
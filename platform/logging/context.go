@@ -0,0 +1,95 @@
+package logging
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// contextKey is the key of the fields attached to a context.
+type contextKey struct{}
+
+// WithFields returns a copy of ctx carrying fields, along with the fields ctx
+// already carries, so entries logged with it are tagged with them.
+func WithFields(ctx context.Context, fields Fields) context.Context {
+	merged := make(Fields, len(fields))
+	for key, value := range ContextFields(ctx) {
+		merged[key] = value
+	}
+	for key, value := range fields {
+		merged[key] = value
+	}
+	return context.WithValue(ctx, contextKey{}, merged)
+}
+
+// ContextFields returns the fields ctx carries.
+func ContextFields(ctx context.Context) Fields {
+	if ctx == nil {
+		return nil
+	}
+	fields, _ := ctx.Value(contextKey{}).(Fields)
+	return fields
+}
+
+// Log writes an entry to the default logger, tagged with the fields ctx carries.
+func Log(ctx context.Context, level Level, msg string, fields Fields) {
+	if !Default.Enabled(level) {
+		return
+	}
+
+	merged := make(Fields, len(fields))
+	for key, value := range ContextFields(ctx) {
+		merged[key] = value
+	}
+	for key, value := range fields {
+		merged[key] = value
+	}
+	Default.Log(level, msg, merged)
+}
+
+// The key user identifiers are hashed with.
+var (
+	hashKey      []byte
+	hashKeyMutex sync.Mutex
+)
+
+// SetHashKey sets the key user identifiers are hashed with. Instances sharing
+// a key log the same identifier for the same user.
+func SetHashKey(key []byte) {
+	hashKeyMutex.Lock()
+	defer hashKeyMutex.Unlock()
+
+	hashKey = key
+}
+
+// HashUser returns an identifier for a user of a district, which can't be
+// turned back into the username. It is keyed, since usernames are often
+// short student IDs which could be guessed from a plain hash.
+func HashUser(username, base string) string {
+	hashKeyMutex.Lock()
+	if hashKey == nil {
+		// Without a configured key, identifiers are only stable for this process.
+		hashKey = make([]byte, 32)
+		rand.Read(hashKey)
+	}
+	key := hashKey
+	hashKeyMutex.Unlock()
+
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(strings.ToLower(username) + "\n" + District(base)))
+	return hex.EncodeToString(mac.Sum(nil))[:16]
+}
+
+// District returns the host of a district's base URL.
+func District(base string) string {
+	parsed, err := url.Parse(base)
+	if err != nil || parsed.Host == "" {
+		return ""
+	}
+	return strings.ToLower(parsed.Host)
+}
@@ -0,0 +1,147 @@
+package logging
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Level represents how important a log entry is.
+type Level int
+
+// The levels of log entries, from least to most important.
+const (
+	LevelDebug Level = iota
+	LevelInfo
+	LevelWarn
+	LevelError
+)
+
+func (level Level) String() string {
+	switch level {
+	case LevelDebug:
+		return "debug"
+	case LevelWarn:
+		return "warn"
+	case LevelError:
+		return "error"
+	}
+	return "info"
+}
+
+// ParseLevel returns the level named by name, or LevelInfo if there is none.
+func ParseLevel(name string) Level {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "debug":
+		return LevelDebug
+	case "warn":
+		return LevelWarn
+	case "error":
+		return LevelError
+	}
+	return LevelInfo
+}
+
+// Fields represents the fields attached to a log entry.
+type Fields map[string]interface{}
+
+// Logger writes log entries as JSON lines. Every entry is redacted
+// before it is written, so credentials can't end up in the logs.
+type Logger struct {
+	mutex sync.Mutex
+	out   io.Writer
+	level Level
+	now   func() time.Time
+}
+
+// Default is the logger used by the API.
+var Default = New(os.Stdout, LevelInfo)
+
+// New creates a new logger writing entries of at least level to out.
+func New(out io.Writer, level Level) *Logger {
+	return &Logger{out: out, level: level, now: time.Now}
+}
+
+// SetLevel changes the least important level of the entries written.
+func (logger *Logger) SetLevel(level Level) {
+	logger.mutex.Lock()
+	defer logger.mutex.Unlock()
+
+	logger.level = level
+}
+
+// Enabled returns whether entries of level are written.
+func (logger *Logger) Enabled(level Level) bool {
+	logger.mutex.Lock()
+	defer logger.mutex.Unlock()
+
+	return level >= logger.level
+}
+
+// Log writes an entry with the given level, message and fields.
+func (logger *Logger) Log(level Level, msg string, fields Fields) {
+	if !logger.Enabled(level) {
+		return
+	}
+
+	// Build the entry, fields can't replace the time, level or message.
+	entry := make(Fields, len(fields)+3)
+	for key, value := range Redact(fields) {
+		entry[key] = value
+	}
+	entry["time"] = logger.now().UTC().Format(time.RFC3339Nano)
+	entry["level"] = level.String()
+	entry["msg"] = Scrub(msg)
+
+	line, err := marshalEntry(entry)
+	if err != nil {
+		return
+	}
+
+	logger.mutex.Lock()
+	defer logger.mutex.Unlock()
+
+	logger.out.Write(line)
+}
+
+// marshalEntry marshals an entry into a JSON line, with the time, level and
+// message first and the other fields sorted after them.
+func marshalEntry(entry Fields) ([]byte, error) {
+	keys := make([]string, 0, len(entry))
+	for key := range entry {
+		if key != "time" && key != "level" && key != "msg" {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	keys = append([]string{"time", "level", "msg"}, keys...)
+
+	var line strings.Builder
+	line.WriteByte('{')
+	for i, key := range keys {
+		if i > 0 {
+			line.WriteByte(',')
+		}
+
+		keyJSON, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+		valueJSON, err := json.Marshal(entry[key])
+		if err != nil {
+			// Log values which can't be marshalled as strings, rather than losing the entry.
+			valueJSON, _ = json.Marshal(Scrub(err.Error()))
+		}
+
+		line.Write(keyJSON)
+		line.WriteByte(':')
+		line.Write(valueJSON)
+	}
+	line.WriteString("}\n")
+
+	return []byte(line.String()), nil
+}
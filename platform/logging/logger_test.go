@@ -0,0 +1,152 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// newTestLogger creates a logger writing to a buffer at a fixed time.
+func newTestLogger(level Level) (*Logger, *bytes.Buffer) {
+	var out bytes.Buffer
+	logger := New(&out, level)
+	logger.now = func() time.Time { return time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC) }
+	return logger, &out
+}
+
+// Test if entries are written as JSON lines, with their fields sorted.
+func TestLogger_Log(t *testing.T) {
+	logger, out := newTestLogger(LevelInfo)
+
+	logger.Log(LevelWarn, "request", Fields{"status": 400, "route": "/api/v1/classwork", "msg": "replaced"})
+
+	expected := `{"time":"2023-01-02T03:04:05Z","level":"warn","msg":"request","route":"/api/v1/classwork","status":400}` + "\n"
+
+	if diff := cmp.Diff(expected, out.String()); diff != "" {
+		t.Fatalf("Failed for Log() (-want, +got):\n%s", diff)
+	}
+}
+
+// Test if entries less important than the level are dropped.
+func TestLogger_Level(t *testing.T) {
+	logger, out := newTestLogger(LevelInfo)
+
+	logger.Log(LevelDebug, "hac request", nil)
+	if out.Len() != 0 {
+		t.Fatalf("Failed for Log() below the level, expected nothing, got:\n%s", out.String())
+	}
+
+	logger.SetLevel(LevelDebug)
+	logger.Log(LevelDebug, "hac request", nil)
+	if out.Len() == 0 {
+		t.Fatalf("Failed for Log() at the level, expected an entry")
+	}
+}
+
+// Test if credentials are redacted wherever they are in an entry.
+func TestLogger_Redacts(t *testing.T) {
+	logger, out := newTestLogger(LevelInfo)
+
+	logger.Log(LevelError, "hac request failed", Fields{
+		"password":                   "leaky-password",
+		"Username":                   "leaky-username",
+		"__RequestVerificationToken": "leaky-verification",
+		"form": map[string]string{
+			"LogOnDetails.Password": "leaky-password",
+			"Database":              "10",
+		},
+		"headers": http.Header{
+			"Cookie":        {"ASP.NET_SessionId=leaky-cookie"},
+			"Authorization": {"Bearer leaky-token"},
+			"Accept":        {"text/html"},
+		},
+		"url":   "https://fake.url/HomeAccess?session=leaky-session&page=1",
+		"error": errors.New(`Post "https://fake.url/LogOn": LogOnDetails.Password=leaky-password&Database=10`),
+		"note":  "sent Authorization: Bearer leaky-token",
+	})
+
+	for _, leaked := range []string{"leaky-password", "leaky-username", "leaky-verification", "leaky-cookie", "leaky-token", "leaky-session"} {
+		if strings.Contains(out.String(), leaked) {
+			t.Fatalf("Failed for Log(), %q leaked into:\n%s", leaked, out.String())
+		}
+	}
+
+	// Everything which isn't a credential is kept.
+	var entry map[string]interface{}
+	if err := json.Unmarshal(out.Bytes(), &entry); err != nil {
+		t.Fatalf("Failed for Log(), entry isn't JSON:\n%v", err)
+	}
+
+	expected := map[string]interface{}{
+		"time":                       "2023-01-02T03:04:05Z",
+		"level":                      "error",
+		"msg":                        "hac request failed",
+		"password":                   "[REDACTED]",
+		"Username":                   "[REDACTED]",
+		"__RequestVerificationToken": "[REDACTED]",
+		"form": map[string]interface{}{
+			"LogOnDetails.Password": "[REDACTED]",
+			"Database":              "10",
+		},
+		"headers": map[string]interface{}{
+			"Cookie":        "[REDACTED]",
+			"Authorization": "[REDACTED]",
+			"Accept":        "text/html",
+		},
+		"url":   "https://fake.url/HomeAccess?session=[REDACTED]&page=1",
+		"error": `Post "https://fake.url/LogOn": LogOnDetails.Password=[REDACTED]&Database=10`,
+		"note":  "sent Authorization: [REDACTED]",
+	}
+
+	if diff := cmp.Diff(expected, entry); diff != "" {
+		t.Fatalf("Failed for Log() redaction (-want, +got):\n%s", diff)
+	}
+}
+
+// Test if context fields are merged into entries.
+func TestLog_WithContextFields(t *testing.T) {
+	logger, out := newTestLogger(LevelInfo)
+
+	previous := Default
+	Default = logger
+	defer func() { Default = previous }()
+
+	ctx := WithFields(context.Background(), Fields{"requestId": "abc", "district": "fake.url"})
+	ctx = WithFields(ctx, Fields{"district": "other.url"})
+
+	Log(ctx, LevelInfo, "request", Fields{"status": 200})
+
+	expected := `{"time":"2023-01-02T03:04:05Z","level":"info","msg":"request","district":"other.url","requestId":"abc","status":200}` + "\n"
+
+	if diff := cmp.Diff(expected, out.String()); diff != "" {
+		t.Fatalf("Failed for Log() with context fields (-want, +got):\n%s", diff)
+	}
+}
+
+// Test if user identifiers are stable, keyed and don't contain the username.
+func TestHashUser(t *testing.T) {
+	SetHashKey([]byte("key"))
+
+	first := HashUser("Student123", "https://Fake.url")
+	if first != HashUser("student123", "https://fake.url/") {
+		t.Fatalf("Failed for HashUser(), expected the same identifier regardless of case")
+	}
+	if first == HashUser("student124", "https://fake.url") || first == HashUser("student123", "https://other.url") {
+		t.Fatalf("Failed for HashUser(), expected different identifiers for different users")
+	}
+	if strings.Contains(strings.ToLower(first), "student123") || len(first) != 16 {
+		t.Fatalf("Failed for HashUser(), got %q", first)
+	}
+
+	SetHashKey([]byte("other key"))
+	if first == HashUser("student123", "https://fake.url") {
+		t.Fatalf("Failed for HashUser(), expected the identifier to depend on the key")
+	}
+}
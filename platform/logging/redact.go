@@ -0,0 +1,116 @@
+package logging
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+// The value written in place of redacted values.
+const redacted = "[REDACTED]"
+
+// sensitiveKeys are the parts of field, header, form and query names whose
+// values are never logged. Names are compared in lower case.
+var sensitiveKeys = []string{
+	"password",
+	"username",
+	"cookie",
+	"requestverificationtoken",
+	"token",
+	"authorization",
+	"session",
+	"secret",
+}
+
+// sensitiveParams matches sensitive name=value pairs in strings, like the query
+// strings and form bodies which end up in URLs and colly errors.
+var sensitiveParams = regexp.MustCompile(`(?i)([\w.]*(?:password|username|cookie|requestverificationtoken|token|authorization|session|secret)[\w.]*)=[^&\s"]*`)
+
+// sensitiveHeaders matches sensitive headers in strings, like dumped requests.
+var sensitiveHeaders = regexp.MustCompile(`(?i)((?:set-)?cookie|authorization):[^\n]*`)
+
+// bearerTokens matches Bearer tokens in strings.
+var bearerTokens = regexp.MustCompile(`(?i)(bearer)\s+[\w\-.~+/]+=*`)
+
+// IsSensitive returns whether the value of a field, header, form or query
+// parameter named key must never be logged.
+func IsSensitive(key string) bool {
+	key = strings.ToLower(key)
+	for _, sensitive := range sensitiveKeys {
+		if strings.Contains(key, sensitive) {
+			return true
+		}
+	}
+	return false
+}
+
+// Scrub redacts the sensitive parameters, headers and tokens in a string.
+func Scrub(value string) string {
+	value = sensitiveParams.ReplaceAllString(value, "${1}="+redacted)
+	value = sensitiveHeaders.ReplaceAllString(value, "${1}: "+redacted)
+	return bearerTokens.ReplaceAllString(value, "${1} "+redacted)
+}
+
+// Redact returns a copy of fields with the values of sensitive fields
+// replaced, and every other value scrubbed.
+func Redact(fields Fields) Fields {
+	res := make(Fields, len(fields))
+	for key, value := range fields {
+		if IsSensitive(key) {
+			res[key] = redacted
+			continue
+		}
+		res[key] = redactValue(value)
+	}
+	return res
+}
+
+// redactValue redacts a single field value, going into maps and lists.
+func redactValue(value interface{}) interface{} {
+	switch value := value.(type) {
+	case nil:
+		return nil
+	case string:
+		return Scrub(value)
+	case error:
+		return Scrub(value.Error())
+	case fmt.Stringer:
+		return Scrub(value.String())
+	case bool, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		return value
+	case Fields:
+		return Redact(value)
+	case map[string]interface{}:
+		return Redact(value)
+	case map[string]string:
+		res := make(map[string]string, len(value))
+		for key, value := range value {
+			if IsSensitive(key) {
+				res[key] = redacted
+			} else {
+				res[key] = Scrub(value)
+			}
+		}
+		return res
+	case http.Header:
+		res := make(map[string]string, len(value))
+		for key := range value {
+			if IsSensitive(key) {
+				res[key] = redacted
+			} else {
+				res[key] = Scrub(value.Get(key))
+			}
+		}
+		return res
+	case []string:
+		res := make([]string, len(value))
+		for i, value := range value {
+			res[i] = Scrub(value)
+		}
+		return res
+	}
+
+	// Anything else could hold credentials in ways which can't be redacted.
+	return Scrub(fmt.Sprintf("%v", value))
+}
@@ -0,0 +1,50 @@
+package metrics
+
+// Metrics exposed on /metrics.
+//
+// Labels only ever hold values from a small, fixed set (route patterns,
+// HAC endpoint names, outcomes), never anything a user sent, like
+// usernames, passwords, bases or session tokens.
+
+// Default is the registry written on /metrics.
+var Default = NewRegistry()
+
+// FanOutBuckets are the histogram buckets for the amount of requests a pipeline makes.
+var FanOutBuckets = []float64{1, 2, 4, 6, 8, 12, 16, 24, 32}
+
+var (
+	// Requests counts the requests served, by method, route pattern and status.
+	Requests = Default.NewCounter("hacapi_http_requests_total",
+		"Requests served, by method, route and status.", "method", "route", "status")
+	// RequestDuration observes how long requests took to serve, by method and route pattern.
+	RequestDuration = Default.NewHistogram("hacapi_http_request_duration_seconds",
+		"How long requests took to serve, by method and route.", DefaultBuckets, "method", "route")
+
+	// HACRequestDuration observes how long requests to HAC took, by endpoint.
+	HACRequestDuration = Default.NewHistogram("hacapi_hac_request_duration_seconds",
+		"How long requests to HAC took, by endpoint.", DefaultBuckets, "endpoint")
+	// HACRequestErrors counts failed requests to HAC, by endpoint and reason.
+	HACRequestErrors = Default.NewCounter("hacapi_hac_request_errors_total",
+		"Failed requests to HAC, by endpoint and reason.", "endpoint", "reason")
+
+	// Logins counts logins to HAC, by result.
+	Logins = Default.NewCounter("hacapi_logins_total",
+		"Logins to HAC, by result.", "result")
+
+	// CacheHits counts logged-in collectors found in the cache.
+	CacheHits = Default.NewCounter("hacapi_cache_hits_total",
+		"Logged-in collectors found in the cache.")
+	// CacheMisses counts logged-in collectors missing from the cache.
+	CacheMisses = Default.NewCounter("hacapi_cache_misses_total",
+		"Logged-in collectors missing from the cache.")
+	// CacheEvictions counts logged-in collectors evicted from the cache, by reason.
+	CacheEvictions = Default.NewCounter("hacapi_cache_evictions_total",
+		"Logged-in collectors evicted from the cache, by reason.", "reason")
+	// CacheSize is the amount of logged-in collectors in the cache.
+	CacheSize = Default.NewGauge("hacapi_cache_size",
+		"Logged-in collectors in the cache.")
+
+	// PipelineFanOut observes the amount of requests each pipeline makes.
+	PipelineFanOut = Default.NewHistogram("hacapi_pipeline_fan_out",
+		"Requests made by each pipeline.", FanOutBuckets)
+)
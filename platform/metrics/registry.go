@@ -0,0 +1,274 @@
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefaultBuckets are the histogram buckets for latencies, in seconds.
+var DefaultBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30}
+
+// metric represents a metric which can be written in the Prometheus text format.
+type metric interface {
+	write(w *bufio.Writer)
+}
+
+// Registry holds metrics, and writes them in the Prometheus text format.
+type Registry struct {
+	mutex   sync.Mutex
+	metrics []metric
+}
+
+// NewRegistry creates a new empty registry.
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+func (registry *Registry) register(m metric) {
+	registry.mutex.Lock()
+	defer registry.mutex.Unlock()
+
+	registry.metrics = append(registry.metrics, m)
+}
+
+// Write writes every metric in the registry in the Prometheus text format.
+func (registry *Registry) Write(w io.Writer) error {
+	registry.mutex.Lock()
+	metrics := append([]metric(nil), registry.metrics...)
+	registry.mutex.Unlock()
+
+	buffered := bufio.NewWriter(w)
+	for _, m := range metrics {
+		m.write(buffered)
+	}
+	return buffered.Flush()
+}
+
+// vec holds the series of a metric, one per combination of label values.
+type vec[T any] struct {
+	name   string
+	help   string
+	kind   string
+	labels []string
+	mutex  sync.Mutex
+	series map[string]*T
+	values map[string][]string
+	create func() *T
+}
+
+func newVec[T any](name, help, kind string, labels []string, create func() *T) *vec[T] {
+	return &vec[T]{
+		name:   name,
+		help:   help,
+		kind:   kind,
+		labels: labels,
+		series: make(map[string]*T),
+		values: make(map[string][]string),
+		create: create,
+	}
+}
+
+// with returns the series for the given label values, creating it if needed.
+func (v *vec[T]) with(labelValues []string) *T {
+	if len(labelValues) != len(v.labels) {
+		panic(fmt.Sprintf("metrics: %s expects %d label values, got %d", v.name, len(v.labels), len(labelValues)))
+	}
+
+	key := strings.Join(labelValues, "\xff")
+
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+
+	series, ok := v.series[key]
+	if !ok {
+		series = v.create()
+		v.series[key] = series
+		v.values[key] = append([]string(nil), labelValues...)
+	}
+	return series
+}
+
+// each calls fn for every series in a stable order, along with its formatted labels.
+func (v *vec[T]) each(fn func(labels []string, series *T)) {
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+
+	keys := make([]string, 0, len(v.series))
+	for key := range v.series {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		pairs := make([]string, len(v.labels))
+		for i, label := range v.labels {
+			pairs[i] = formatLabel(label, v.values[key][i])
+		}
+		fn(pairs, v.series[key])
+	}
+}
+
+func (v *vec[T]) writeHeader(w *bufio.Writer) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", v.name, v.help, v.name, v.kind)
+}
+
+// labelEscaper escapes label values the way Prometheus expects them.
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// formatLabel formats a single label pair.
+func formatLabel(name, value string) string {
+	return name + `="` + labelEscaper.Replace(value) + `"`
+}
+
+// formatLabels formats label pairs the way Prometheus expects them.
+func formatLabels(pairs []string) string {
+	if len(pairs) == 0 {
+		return ""
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+// formatValue formats a sample value the way Prometheus expects it.
+func formatValue(value float64) string {
+	switch {
+	case math.IsInf(value, 1):
+		return "+Inf"
+	case math.IsInf(value, -1):
+		return "-Inf"
+	case math.IsNaN(value):
+		return "NaN"
+	}
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+// Counter represents a value which only goes up, like the amount of requests.
+type Counter struct {
+	vec *vec[float64]
+}
+
+// NewCounter creates a counter, partitioned by the given labels, in the registry.
+func (registry *Registry) NewCounter(name, help string, labels ...string) *Counter {
+	counter := &Counter{vec: newVec(name, help, "counter", labels, func() *float64 { return new(float64) })}
+	registry.register(counter)
+	return counter
+}
+
+// Inc adds one to the counter for the given label values.
+func (counter *Counter) Inc(labelValues ...string) {
+	counter.Add(1, labelValues...)
+}
+
+// Add adds to the counter for the given label values.
+func (counter *Counter) Add(value float64, labelValues ...string) {
+	series := counter.vec.with(labelValues)
+
+	counter.vec.mutex.Lock()
+	*series += value
+	counter.vec.mutex.Unlock()
+}
+
+func (counter *Counter) write(w *bufio.Writer) {
+	counter.vec.writeHeader(w)
+	counter.vec.each(func(labels []string, series *float64) {
+		fmt.Fprintf(w, "%s%s %s\n", counter.vec.name, formatLabels(labels), formatValue(*series))
+	})
+}
+
+// histogramSeries holds the observations of a histogram for one combination of label values.
+type histogramSeries struct {
+	counts []uint64 // The amount of observations in each bucket, not cumulative
+	count  uint64
+	sum    float64
+}
+
+// Histogram represents the distribution of observations, like request latencies.
+type Histogram struct {
+	vec     *vec[histogramSeries]
+	buckets []float64
+}
+
+// NewHistogram creates a histogram with the given upper bounds, partitioned by the given labels, in the registry.
+func (registry *Registry) NewHistogram(name, help string, buckets []float64, labels ...string) *Histogram {
+	histogram := &Histogram{buckets: buckets}
+	histogram.vec = newVec(name, help, "histogram", labels, func() *histogramSeries {
+		return &histogramSeries{counts: make([]uint64, len(buckets))}
+	})
+	registry.register(histogram)
+	return histogram
+}
+
+// Observe records an observation for the given label values.
+func (histogram *Histogram) Observe(value float64, labelValues ...string) {
+	series := histogram.vec.with(labelValues)
+
+	histogram.vec.mutex.Lock()
+	defer histogram.vec.mutex.Unlock()
+
+	for i, bound := range histogram.buckets {
+		if value <= bound {
+			series.counts[i]++
+			break
+		}
+	}
+	series.count++
+	series.sum += value
+}
+
+func (histogram *Histogram) write(w *bufio.Writer) {
+	histogram.vec.writeHeader(w)
+	histogram.vec.each(func(labels []string, series *histogramSeries) {
+		var cumulative uint64
+		for i, bound := range histogram.buckets {
+			cumulative += series.counts[i]
+			fmt.Fprintf(w, "%s_bucket%s %d\n", histogram.vec.name, formatLabels(append(labels, formatLabel("le", formatValue(bound)))), cumulative)
+		}
+		fmt.Fprintf(w, "%s_bucket%s %d\n", histogram.vec.name, formatLabels(append(labels, `le="+Inf"`)), series.count)
+		fmt.Fprintf(w, "%s_sum%s %s\n", histogram.vec.name, formatLabels(labels), formatValue(series.sum))
+		fmt.Fprintf(w, "%s_count%s %d\n", histogram.vec.name, formatLabels(labels), series.count)
+	})
+}
+
+// Gauge represents a value which goes up and down, like the size of a cache.
+type Gauge struct {
+	name  string
+	help  string
+	mutex sync.Mutex
+	value float64
+}
+
+// NewGauge creates a gauge in the registry.
+func (registry *Registry) NewGauge(name, help string) *Gauge {
+	gauge := &Gauge{name: name, help: help}
+	registry.register(gauge)
+	return gauge
+}
+
+// Add adds to the gauge, which can be negative.
+func (gauge *Gauge) Add(value float64) {
+	gauge.mutex.Lock()
+	defer gauge.mutex.Unlock()
+
+	gauge.value += value
+}
+
+// Set sets the gauge to value.
+func (gauge *Gauge) Set(value float64) {
+	gauge.mutex.Lock()
+	defer gauge.mutex.Unlock()
+
+	gauge.value = value
+}
+
+func (gauge *Gauge) write(w *bufio.Writer) {
+	gauge.mutex.Lock()
+	value := gauge.value
+	gauge.mutex.Unlock()
+
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s gauge\n%s %s\n", gauge.name, gauge.help, gauge.name, gauge.name, formatValue(value))
+}
@@ -0,0 +1,82 @@
+package metrics
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// Test if the registry writes every kind of metric in the Prometheus text format.
+func TestRegistry_Write(t *testing.T) {
+	registry := NewRegistry()
+
+	counter := registry.NewCounter("test_requests_total", "Requests.", "route", "status")
+	counter.Inc("/b", "200")
+	counter.Add(2, "/a", "500")
+
+	gauge := registry.NewGauge("test_size", "Size.")
+	gauge.Add(3)
+	gauge.Add(-1)
+
+	histogram := registry.NewHistogram("test_duration_seconds", "Duration.", []float64{0.1, 1}, "route")
+	histogram.Observe(0.05, "/a")
+	histogram.Observe(0.5, "/a")
+	histogram.Observe(5, "/a")
+
+	var output strings.Builder
+	if err := registry.Write(&output); err != nil {
+		t.Fatalf("Failed for Write():\n%v", err)
+	}
+
+	expected := `# HELP test_requests_total Requests.
+# TYPE test_requests_total counter
+test_requests_total{route="/a",status="500"} 2
+test_requests_total{route="/b",status="200"} 1
+# HELP test_size Size.
+# TYPE test_size gauge
+test_size 2
+# HELP test_duration_seconds Duration.
+# TYPE test_duration_seconds histogram
+test_duration_seconds_bucket{route="/a",le="0.1"} 1
+test_duration_seconds_bucket{route="/a",le="1"} 2
+test_duration_seconds_bucket{route="/a",le="+Inf"} 3
+test_duration_seconds_sum{route="/a"} 5.55
+test_duration_seconds_count{route="/a"} 3
+`
+
+	if diff := cmp.Diff(expected, output.String()); diff != "" {
+		t.Fatalf("Failed for Write() (-want, +got):\n%s", diff)
+	}
+}
+
+// Test if label values are escaped.
+func TestRegistry_WriteEscapesLabels(t *testing.T) {
+	registry := NewRegistry()
+
+	counter := registry.NewCounter("test_total", "Test.", "value")
+	counter.Inc("a\"b\\c\nd")
+
+	var output strings.Builder
+	if err := registry.Write(&output); err != nil {
+		t.Fatalf("Failed for Write():\n%v", err)
+	}
+
+	expected := `test_total{value="a\"b\\c\nd"} 1`
+	if !strings.Contains(output.String(), expected) {
+		t.Fatalf("Failed for Write() with escaped labels, expected %q in:\n%s", expected, output.String())
+	}
+}
+
+// Test if a series with the wrong amount of label values panics.
+func TestCounter_WrongLabelCount(t *testing.T) {
+	counter := NewRegistry().NewCounter("test_total", "Test.", "route")
+
+	defer func() {
+		if recover() == nil {
+			t.Fatalf("Failed for Inc() with the wrong amount of labels, expected a panic")
+		}
+	}()
+
+	counter.Inc("/a", "200")
+}
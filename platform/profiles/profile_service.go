@@ -0,0 +1,82 @@
+package profiles
+
+import (
+	"encoding/json"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/Threqt1/HACApi/pkg/repository"
+)
+
+// file format -
+// key: base URL of the district's HAC (Ex: https://homeaccess.katyisd.org)
+// val: district profile, where any omitted field falls back to the default
+type Profiles struct {
+	Profiles map[string]repository.DistrictProfile
+}
+
+// NewProfiles creates a new registry with no districts
+// configured, so every district uses the default profile.
+func NewProfiles() *Profiles {
+	return &Profiles{Profiles: map[string]repository.DistrictProfile{}}
+}
+
+// LoadProfiles creates a new registry with the district
+// profiles stored in a JSON file.
+func LoadProfiles(path string) (*Profiles, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var rawProfiles map[string]json.RawMessage
+	if err := json.Unmarshal(data, &rawProfiles); err != nil {
+		return nil, err
+	}
+
+	profiles := NewProfiles()
+	for base, rawProfile := range rawProfiles {
+		// Start from the default profile, so only the differences need to be configured.
+		profile := repository.DefaultDistrictProfile()
+		defaultLoginFields := profile.LoginFields
+		profile.LoginFields = nil
+
+		if err := json.Unmarshal(rawProfile, &profile); err != nil {
+			return nil, err
+		}
+
+		// Login fields replace the defaults as a whole, so fields can be removed as well.
+		if profile.LoginFields == nil {
+			profile.LoginFields = defaultLoginFields
+		}
+
+		profiles.Profiles[normalizeBase(base)] = profile
+	}
+
+	return profiles, nil
+}
+
+// Profile returns the profile of the district at base, or the default profile
+// if the district isn't configured.
+func (profiles Profiles) Profile(base string) repository.DistrictProfile {
+	if profile, ok := profiles.Profiles[normalizeBase(base)]; ok {
+		return profile
+	}
+	return repository.DefaultDistrictProfile()
+}
+
+// Bases returns the base URLs of the configured districts, in order.
+func (profiles Profiles) Bases() []string {
+	bases := make([]string, 0, len(profiles.Profiles))
+	for base := range profiles.Profiles {
+		bases = append(bases, base)
+	}
+	sort.Strings(bases)
+	return bases
+}
+
+// normalizeBase makes base URLs comparable regardless of case or trailing slashes.
+func normalizeBase(base string) string {
+	return strings.TrimRight(strings.ToLower(strings.TrimSpace(base)), "/")
+}
@@ -0,0 +1,66 @@
+package profiles
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Threqt1/HACApi/pkg/repository"
+	"github.com/google/go-cmp/cmp"
+)
+
+// TestLoadProfiles tests LoadProfiles() and Profile() with a profile overriding some defaults.
+func TestLoadProfiles(t *testing.T) {
+	// Write a profile file overriding the login fields, a route and a selector.
+	path := filepath.Join(t.TempDir(), "profiles.json")
+	data := `{
+		"https://hac.example.org/": {
+			"loginFields": {"Database": "11"},
+			"routes": {"ipr": "/HomeAccess/Content/Student/IPR.aspx"},
+			"selectors": {"iprDates": "#ddlDates"}
+		}
+	}`
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatalf("Failed to write profile file: %v", err)
+	}
+
+	profiles, err := LoadProfiles(path)
+	if err != nil {
+		t.Fatalf("Failed for LoadProfiles(): %v", err)
+	}
+
+	// Expected profile, the defaults with the overrides applied.
+	expected := repository.DefaultDistrictProfile()
+	expected.LoginFields = map[string]string{"Database": "11"}
+	expected.Routes.IPR = "/HomeAccess/Content/Student/IPR.aspx"
+	expected.Selectors.IPRDates = "#ddlDates"
+
+	// The base should match regardless of case or trailing slashes.
+	if diff := cmp.Diff(expected, profiles.Profile("https://HAC.example.org")); diff != "" {
+		t.Fatalf("Failed for Profile() with configured base (-want, +got)\n%s", diff)
+	}
+
+	// Other districts should use the default profile.
+	if diff := cmp.Diff(repository.DefaultDistrictProfile(), profiles.Profile("https://homeaccess.katyisd.org")); diff != "" {
+		t.Fatalf("Failed for Profile() with unconfigured base (-want, +got)\n%s", diff)
+	}
+}
+
+// TestLoadProfiles_WithInvalidFile tests that LoadProfiles() errors out on missing or malformed files.
+func TestLoadProfiles_WithInvalidFile(t *testing.T) {
+	dir := t.TempDir()
+
+	// Missing file.
+	if _, err := LoadProfiles(filepath.Join(dir, "missing.json")); err == nil {
+		t.Fatalf("Failed for LoadProfiles() with missing file")
+	}
+
+	// Malformed file.
+	path := filepath.Join(dir, "profiles.json")
+	if err := os.WriteFile(path, []byte(`{"https://hac.example.org": []}`), 0o600); err != nil {
+		t.Fatalf("Failed to write profile file: %v", err)
+	}
+	if _, err := LoadProfiles(path); err == nil {
+		t.Fatalf("Failed for LoadProfiles() with malformed file")
+	}
+}
@@ -0,0 +1,138 @@
+package subscriptions
+
+import (
+	"fmt"
+
+	"github.com/Threqt1/HACApi/app/models"
+)
+
+// classKey identifies a class across snapshots.
+func classKey(class models.Class) string {
+	return class.Course + "\n" + class.Name
+}
+
+// assignmentKeys identifies each assignment of a class across snapshots.
+// Assignments with the same name are told apart by their order. Dates are
+// left out, since teachers can move them.
+func assignmentKeys(assignments []models.Assignment) []string {
+	seen := map[string]int{}
+	keys := make([]string, len(assignments))
+	for i, assignment := range assignments {
+		keys[i] = fmt.Sprintf("%s\n%d", assignment.Name, seen[assignment.Name])
+		seen[assignment.Name]++
+	}
+	return keys
+}
+
+// DiffClasswork returns the changes between two snapshots of classwork. Marking
+// periods and classes missing from the previous snapshot aren't compared, since every
+// assignment in them would look added.
+func DiffClasswork(previous, current []models.Classwork) []models.ChangeEvent {
+	oldPeriods := make(map[int]models.Classwork, len(previous))
+	for _, classwork := range previous {
+		oldPeriods[classwork.MarkingPeriod] = classwork
+	}
+
+	var changes []models.ChangeEvent
+	for _, classwork := range current {
+		oldClasswork, ok := oldPeriods[classwork.MarkingPeriod]
+		if !ok {
+			continue
+		}
+
+		oldEntries := make(map[string]models.ClassworkEntry, len(oldClasswork.Entries))
+		for _, entry := range oldClasswork.Entries {
+			oldEntries[classKey(entry.Class)] = entry
+		}
+
+		for _, entry := range classwork.Entries {
+			oldEntry, ok := oldEntries[classKey(entry.Class)]
+			if !ok {
+				continue
+			}
+			changes = append(changes, diffEntry(classwork.MarkingPeriod, oldEntry, entry)...)
+		}
+	}
+
+	return changes
+}
+
+// diffEntry returns the changes between two snapshots of a class's classwork.
+func diffEntry(markingPeriod int, previous, current models.ClassworkEntry) []models.ChangeEvent {
+	var changes []models.ChangeEvent
+
+	oldAssignments := map[string]models.Assignment{}
+	for i, key := range assignmentKeys(previous.Assignments) {
+		oldAssignments[key] = previous.Assignments[i]
+	}
+
+	for i, key := range assignmentKeys(current.Assignments) {
+		class, assignment := current.Class, current.Assignments[i]
+
+		oldAssignment, ok := oldAssignments[key]
+		if !ok {
+			changes = append(changes, models.ChangeEvent{
+				Type:          models.ChangeAssignmentAdded,
+				MarkingPeriod: markingPeriod,
+				Class:         &class,
+				Assignment:    &assignment,
+				New:           assignment.Grade,
+			})
+			continue
+		}
+
+		if oldAssignment.DueDate != assignment.DueDate {
+			changes = append(changes, models.ChangeEvent{
+				Type:          models.ChangeAssignmentMoved,
+				MarkingPeriod: markingPeriod,
+				Class:         &class,
+				Assignment:    &assignment,
+				Old:           oldAssignment.DueDate,
+				New:           assignment.DueDate,
+			})
+		}
+		if oldAssignment.Grade != assignment.Grade {
+			changes = append(changes, models.ChangeEvent{
+				Type:          models.ChangeGradeChanged,
+				MarkingPeriod: markingPeriod,
+				Class:         &class,
+				Assignment:    &assignment,
+				Old:           oldAssignment.Grade,
+				New:           assignment.Grade,
+			})
+		}
+	}
+
+	if previous.Average != current.Average {
+		class := current.Class
+		changes = append(changes, models.ChangeEvent{
+			Type:          models.ChangeAverageChanged,
+			MarkingPeriod: markingPeriod,
+			Class:         &class,
+			Old:           previous.Average,
+			New:           current.Average,
+		})
+	}
+
+	return changes
+}
+
+// DiffIPR returns the interim progress reports posted between two snapshots.
+func DiffIPR(previous, current []models.IPR) []models.ChangeEvent {
+	oldDates := make(map[string]bool, len(previous))
+	for _, ipr := range previous {
+		oldDates[ipr.Date] = true
+	}
+
+	var changes []models.ChangeEvent
+	for _, ipr := range current {
+		if oldDates[ipr.Date] {
+			continue
+		}
+
+		ipr := ipr
+		changes = append(changes, models.ChangeEvent{Type: models.ChangeIPRPosted, IPR: &ipr})
+	}
+
+	return changes
+}
@@ -0,0 +1,92 @@
+package subscriptions
+
+import (
+	"testing"
+
+	"github.com/Threqt1/HACApi/app/models"
+	"github.com/google/go-cmp/cmp"
+)
+
+// testDiff_Class is the class used in the snapshots.
+var testDiff_Class = models.Class{Name: "Algebra", Course: "MTH101"}
+
+// testDiff_Classwork makes a snapshot of one class's classwork in the first marking period.
+func testDiff_Classwork(average string, assignments ...models.Assignment) []models.Classwork {
+	return []models.Classwork{{
+		MarkingPeriod: 1,
+		Entries:       []models.ClassworkEntry{{Class: testDiff_Class, Average: average, Assignments: assignments}},
+	}}
+}
+
+// Test if DiffClasswork() finds added and moved assignments, grade changes and average changes.
+func TestDiffClasswork(t *testing.T) {
+	quiz := models.Assignment{Name: "Quiz", AssignedDate: "09/01/2022", DueDate: "09/02/2022", Grade: ""}
+	gradedQuiz := quiz
+	gradedQuiz.Grade = "95.00"
+	test := models.Assignment{Name: "Test", AssignedDate: "09/05/2022", DueDate: "09/06/2022", Grade: "88.00"}
+	movedQuiz := quiz
+	movedQuiz.DueDate = "09/09/2022"
+
+	tests := []struct {
+		Name     string
+		Previous []models.Classwork
+		Current  []models.Classwork
+		Expected []models.ChangeEvent
+	}{
+		{
+			Name:     "No Changes",
+			Previous: testDiff_Classwork("90.00", quiz),
+			Current:  testDiff_Classwork("90.00", quiz),
+			Expected: nil,
+		},
+		{
+			Name:     "Assignment Added",
+			Previous: testDiff_Classwork("90.00", quiz),
+			Current:  testDiff_Classwork("90.00", quiz, test),
+			Expected: []models.ChangeEvent{
+				{Type: models.ChangeAssignmentAdded, MarkingPeriod: 1, Class: &testDiff_Class, Assignment: &test, New: "88.00"},
+			},
+		},
+		{
+			Name:     "Grade And Average Changed",
+			Previous: testDiff_Classwork("90.00", quiz),
+			Current:  testDiff_Classwork("92.50", gradedQuiz),
+			Expected: []models.ChangeEvent{
+				{Type: models.ChangeGradeChanged, MarkingPeriod: 1, Class: &testDiff_Class, Assignment: &gradedQuiz, Old: "", New: "95.00"},
+				{Type: models.ChangeAverageChanged, MarkingPeriod: 1, Class: &testDiff_Class, Old: "90.00", New: "92.50"},
+			},
+		},
+		{
+			Name:     "Assignment Moved",
+			Previous: testDiff_Classwork("90.00", quiz, test),
+			Current:  testDiff_Classwork("90.00", movedQuiz, test),
+			Expected: []models.ChangeEvent{
+				{Type: models.ChangeAssignmentMoved, MarkingPeriod: 1, Class: &testDiff_Class, Assignment: &movedQuiz, Old: "09/02/2022", New: "09/09/2022"},
+			},
+		},
+		{
+			Name:     "New Marking Period",
+			Previous: nil,
+			Current:  testDiff_Classwork("92.50", gradedQuiz, test),
+			Expected: nil,
+		},
+	}
+
+	for _, test := range tests {
+		if diff := cmp.Diff(test.Expected, DiffClasswork(test.Previous, test.Current)); diff != "" {
+			t.Fatalf("Failed for DiffClasswork() %s (-want, +got)\n%s", test.Name, diff)
+		}
+	}
+}
+
+// Test if DiffIPR() finds posted IPRs.
+func TestDiffIPR(t *testing.T) {
+	first := models.IPR{Date: "09/06/2022"}
+	second := models.IPR{Date: "10/04/2022"}
+
+	expected := []models.ChangeEvent{{Type: models.ChangeIPRPosted, IPR: &second}}
+
+	if diff := cmp.Diff(expected, DiffIPR([]models.IPR{first}, []models.IPR{second, first})); diff != "" {
+		t.Fatalf("Failed for DiffIPR() (-want, +got)\n%s", diff)
+	}
+}
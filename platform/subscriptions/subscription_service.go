@@ -0,0 +1,346 @@
+package subscriptions
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Threqt1/HACApi/app/models"
+	"github.com/Threqt1/HACApi/pkg/repository"
+	"github.com/Threqt1/HACApi/pkg/utils"
+	"github.com/Threqt1/HACApi/platform/logging"
+	"github.com/gocolly/colly"
+)
+
+// How often subscriptions are checked for being due.
+const tickInterval = 10 * time.Second
+
+// How long a single check of a subscription can take, including delivering its changes.
+const pollTimeout = 2 * time.Minute
+
+// The amount of random bytes in subscription IDs and secrets.
+const subscriptionTokenBytes = 32
+
+// The default limits on the amount of subscriptions, since each one polls HAC.
+const (
+	defaultMaxSubscriptions        = 1000 // Across every account
+	defaultMaxAccountSubscriptions = 5    // Per account, whether made with its credentials or sessions
+)
+
+// subscription holds a registered subscription along with
+// what it needs to poll HAC, and its last snapshot.
+type subscription struct {
+	models.Subscription
+
+	credentials models.BaseRequestBody // The credentials to log in with, if no session was given
+	token       string                 // The session token to log in with, if one was given
+	account     string                 // The account the subscription belongs to
+	next        time.Time              // When the subscription is checked next
+	running     bool                   // Whether the subscription is being checked
+
+	snapshotted bool               // Whether a snapshot was stored yet
+	classwork   []models.Classwork // The classwork of the last snapshot
+	ipr         []models.IPR       // The IPRs of the last snapshot
+}
+
+// Service keeps track of subscriptions, periodically checking their
+// resources for changes and posting them to their webhooks.
+//
+// Subscriptions are only kept in memory, so they have to be
+// registered again after a restart.
+type Service struct {
+	Cache       repository.CacheProvider   // Logs in to HAC, and resolves session tokens
+	Querier     repository.QuerierProvider // Queries the watched resources
+	WebhookURLs repository.BaseURLProvider // The policy webhook URLs were checked against
+	Client      *http.Client               // Posts to webhooks
+
+	MaxSubscriptions        int // The most subscriptions there can be
+	MaxAccountSubscriptions int // The most subscriptions a single account can have
+
+	mutex         sync.Mutex
+	subscriptions map[string]*subscription
+	now           func() time.Time
+}
+
+// NewService creates a new service with no subscriptions, and the default limits.
+// Webhooks are posted to through the transport of the webhook URL policy, so their
+// hosts can't resolve to addresses the policy doesn't allow by the time they're posted to.
+func NewService(cache repository.CacheProvider, querier repository.QuerierProvider, webhookURLs repository.BaseURLProvider) *Service {
+	return &Service{
+		Cache:                   cache,
+		Querier:                 querier,
+		WebhookURLs:             webhookURLs,
+		MaxSubscriptions:        defaultMaxSubscriptions,
+		MaxAccountSubscriptions: defaultMaxAccountSubscriptions,
+		Client: &http.Client{
+			Transport: webhookURLs.Transport(),
+			Timeout:   10 * time.Second,
+			// Redirects could lead to hosts the webhook URL policy doesn't allow.
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		subscriptions: map[string]*subscription{},
+		now:           time.Now,
+	}
+}
+
+// newToken generates a random hex token.
+func newToken() (string, error) {
+	tokenBytes := make([]byte, subscriptionTokenBytes)
+	if _, err := rand.Read(tokenBytes); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(tokenBytes), nil
+}
+
+// Subscribe registers a subscription. If token isn't empty, the subscription logs in
+// with the session it identifies, and ends along with it. Otherwise, it logs in with
+// the credentials in params, which must be those of the session if one is given.
+// The subscription sent back holds the secret its webhook payloads are signed with,
+// which isn't sent back again. It errors out with ErrorSubscriptionLimit if the
+// account or the service already has as many subscriptions as allowed.
+func (service *Service) Subscribe(params models.SubscriptionRequestBody, token string) (models.Subscription, error) {
+	id, err := newToken()
+	if err != nil {
+		return models.Subscription{}, err
+	}
+	secret, err := newToken()
+	if err != nil {
+		return models.Subscription{}, err
+	}
+
+	resources := params.Resources
+	if len(resources) == 0 {
+		resources = []string{models.SubscriptionResourceClasswork, models.SubscriptionResourceIPR}
+	}
+
+	sub := &subscription{
+		Subscription: models.Subscription{
+			ID:         id,
+			Base:       params.Base,
+			Interval:   params.Interval,
+			WebhookURL: params.WebhookURL,
+			Resources:  resources,
+			Secret:     secret,
+			Created:    service.now(),
+		},
+		token:   token,
+		account: accountKey(params.BaseRequestBody),
+		// Take the first snapshot right away, so changes are found from the first interval on.
+		next: service.now(),
+	}
+	if token == "" {
+		sub.credentials = params.BaseRequestBody
+	}
+
+	service.mutex.Lock()
+	defer service.mutex.Unlock()
+
+	// Check the limits.
+	if len(service.subscriptions) >= service.MaxSubscriptions {
+		return models.Subscription{}, fmt.Errorf("%w: the api has %d subscriptions", repository.ErrorSubscriptionLimit, service.MaxSubscriptions)
+	}
+	accountSubscriptions := 0
+	for _, existing := range service.subscriptions {
+		if existing.account == sub.account {
+			accountSubscriptions++
+		}
+	}
+	if accountSubscriptions >= service.MaxAccountSubscriptions {
+		return models.Subscription{}, fmt.Errorf("%w: accounts can have %d subscriptions", repository.ErrorSubscriptionLimit, service.MaxAccountSubscriptions)
+	}
+
+	service.subscriptions[id] = sub
+
+	return sub.Subscription, nil
+}
+
+// accountKey identifies the account credentials belong to.
+func accountKey(credentials models.BaseRequestBody) string {
+	return strings.ToLower(strings.TrimSpace(credentials.Username)) + "\n" + credentials.Base
+}
+
+// Unsubscribe removes a subscription.
+func (service *Service) Unsubscribe(id string) error {
+	service.mutex.Lock()
+	defer service.mutex.Unlock()
+
+	if _, ok := service.subscriptions[id]; !ok {
+		return repository.ErrorSubscriptionNotFound
+	}
+	delete(service.subscriptions, id)
+
+	return nil
+}
+
+// Run checks the subscriptions which are due until ctx is done.
+func (service *Service) Run(ctx context.Context) {
+	ticker := time.NewTicker(tickInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			service.pollDue(ctx)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// pollDue starts checking every subscription which is due and isn't being checked already.
+func (service *Service) pollDue(ctx context.Context) {
+	now := service.now()
+
+	service.mutex.Lock()
+	defer service.mutex.Unlock()
+
+	for id, sub := range service.subscriptions {
+		if sub.running || now.Before(sub.next) {
+			continue
+		}
+		sub.running = true
+
+		go func(id string) {
+			pollCtx, cancel := context.WithTimeout(ctx, pollTimeout)
+			defer cancel()
+
+			if err := service.Poll(pollCtx, id); err != nil {
+				logging.Log(pollCtx, logging.LevelWarn, "subscription check failed", logging.Fields{"subscription": id, "error": err})
+			}
+		}(id)
+	}
+}
+
+// Poll checks a subscription's resources right away, and posts the changes
+// since its last snapshot to its webhook. The first check only stores a snapshot.
+// The snapshot is only replaced once its changes were delivered, so changes
+// which couldn't be delivered are sent again with the next check.
+func (service *Service) Poll(ctx context.Context, id string) error {
+	service.mutex.Lock()
+	sub, ok := service.subscriptions[id]
+	if !ok {
+		service.mutex.Unlock()
+		return repository.ErrorSubscriptionNotFound
+	}
+	sub.running = true
+	registered, credentials, token := sub.Subscription, sub.credentials, sub.token
+	previousClasswork, previousIPR, snapshotted := sub.classwork, sub.ipr, sub.snapshotted
+	service.mutex.Unlock()
+
+	// Schedule the next check however this one ends.
+	defer func() {
+		service.mutex.Lock()
+		sub.running = false
+		sub.next = service.now().Add(time.Duration(registered.Interval) * time.Second)
+		service.mutex.Unlock()
+	}()
+
+	// Resolve the session, ending the subscription if it stopped working.
+	if token != "" {
+		var err error
+		if credentials, err = service.Cache.GetSession(token); err != nil {
+			return service.end(ctx, registered, repository.ErrorInvalidSession)
+		}
+	}
+
+	ctx = logging.WithFields(ctx, logging.Fields{
+		"subscription": registered.ID,
+		"district":     logging.District(credentials.Base),
+		"user":         logging.HashUser(credentials.Username, credentials.Base),
+	})
+
+	// Fetch the watched resources.
+	classwork, ipr, err := service.fetch(ctx, registered, credentials)
+	if errors.Is(err, utils.ErrorInvalidCredentials) || errors.Is(err, repository.ErrorInvalidAuthentication) {
+		return service.end(ctx, registered, repository.ErrorInvalidAuthentication)
+	}
+	if err != nil {
+		return err
+	}
+
+	// Find and deliver the changes.
+	var changes []models.ChangeEvent
+	if snapshotted {
+		changes = append(DiffClasswork(previousClasswork, classwork), DiffIPR(previousIPR, ipr)...)
+	}
+
+	if len(changes) > 0 {
+		deliveryID, err := newToken()
+		if err != nil {
+			return err
+		}
+
+		payload := models.WebhookPayload{ID: deliveryID, SubscriptionID: registered.ID, Sent: service.now(), Changes: changes}
+		if err := service.deliver(ctx, registered.WebhookURL, registered.Secret, payload); err != nil {
+			return fmt.Errorf("delivering changes: %w", err)
+		}
+	}
+
+	// Replace the snapshot.
+	service.mutex.Lock()
+	sub.classwork, sub.ipr, sub.snapshotted = classwork, ipr, true
+	service.mutex.Unlock()
+
+	return nil
+}
+
+// fetch logs in and queries the resources a subscription watches.
+func (service *Service) fetch(ctx context.Context, subscription models.Subscription, credentials models.BaseRequestBody) ([]models.Classwork, []models.IPR, error) {
+	cacheKey := fmt.Sprintf("%s\n%s\n%s", credentials.Username, credentials.Password, credentials.Base)
+
+	collector, err := service.Cache.GetOrLogin(ctx, cacheKey)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var classwork []models.Classwork
+	var ipr []models.IPR
+	for _, resource := range subscription.Resources {
+		switch resource {
+		case models.SubscriptionResourceClasswork:
+			classwork, err = utils.RetryOnExpiredSession(ctx, service.Cache, cacheKey, collector, func(collector *colly.Collector) ([]models.Classwork, error) {
+				classwork, _, err := service.Querier.GetClasswork(ctx, collector, models.ClassworkRequestBody{BaseRequestBody: credentials})
+				return classwork, err
+			})
+		case models.SubscriptionResourceIPR:
+			ipr, err = utils.RetryOnExpiredSession(ctx, service.Cache, cacheKey, collector, func(collector *colly.Collector) ([]models.IPR, error) {
+				ipr, _, err := service.Querier.GetIPRAll(ctx, collector, models.IprAllRequestBody{BaseRequestBody: credentials})
+				return ipr, err
+			})
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return classwork, ipr, nil
+}
+
+// end removes a subscription whose credentials or session stopped working,
+// and tells its webhook why.
+func (service *Service) end(ctx context.Context, subscription models.Subscription, reason error) error {
+	if err := service.Unsubscribe(subscription.ID); err != nil {
+		return err
+	}
+
+	deliveryID, err := newToken()
+	if err != nil {
+		return err
+	}
+
+	payload := models.WebhookPayload{
+		ID:             deliveryID,
+		SubscriptionID: subscription.ID,
+		Sent:           service.now(),
+		Changes:        []models.ChangeEvent{{Type: models.ChangeSubscriptionEnded, Reason: reason.Error()}},
+	}
+	return service.deliver(ctx, subscription.WebhookURL, subscription.Secret, payload)
+}
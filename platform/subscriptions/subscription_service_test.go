@@ -0,0 +1,269 @@
+package subscriptions
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Threqt1/HACApi/app/models"
+	"github.com/Threqt1/HACApi/app/queries"
+	"github.com/Threqt1/HACApi/pkg/repository"
+	"github.com/Threqt1/HACApi/pkg/utils"
+	"github.com/Threqt1/HACApi/platform/cache"
+	"github.com/gocolly/colly"
+	"github.com/google/go-cmp/cmp"
+)
+
+// testSubscription_Querier is a test querier whose classwork
+// and IPRs can be changed between checks.
+type testSubscription_Querier struct {
+	queries.TestQuerier
+	mutex     *sync.Mutex
+	classwork *[]models.Classwork
+	ipr       *[]models.IPR
+}
+
+func newTestSubscriptionQuerier() testSubscription_Querier {
+	return testSubscription_Querier{mutex: &sync.Mutex{}, classwork: &[]models.Classwork{}, ipr: &[]models.IPR{}}
+}
+
+// set replaces the classwork and IPRs sent back.
+func (querier testSubscription_Querier) set(classwork []models.Classwork, ipr []models.IPR) {
+	querier.mutex.Lock()
+	defer querier.mutex.Unlock()
+
+	*querier.classwork, *querier.ipr = classwork, ipr
+}
+
+func (querier testSubscription_Querier) GetClasswork(ctx context.Context, collector *colly.Collector, params models.ClassworkRequestBody) ([]models.Classwork, []models.ItemError, error) {
+	querier.mutex.Lock()
+	defer querier.mutex.Unlock()
+
+	return *querier.classwork, nil, nil
+}
+
+func (querier testSubscription_Querier) GetIPRAll(ctx context.Context, collector *colly.Collector, params models.IprAllRequestBody) ([]models.IPR, []models.ItemError, error) {
+	querier.mutex.Lock()
+	defer querier.mutex.Unlock()
+
+	return *querier.ipr, nil, nil
+}
+
+// testSubscription_Receiver is a local webhook receiver,
+// which records the payloads posted to it.
+type testSubscription_Receiver struct {
+	*httptest.Server
+	mutex    sync.Mutex
+	status   int
+	bodies   [][]byte
+	headers  []http.Header
+	payloads []models.WebhookPayload
+}
+
+func newTestSubscriptionReceiver() *testSubscription_Receiver {
+	receiver := &testSubscription_Receiver{status: http.StatusOK}
+	receiver.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+
+		var payload models.WebhookPayload
+		json.Unmarshal(body, &payload)
+
+		receiver.mutex.Lock()
+		defer receiver.mutex.Unlock()
+
+		receiver.bodies = append(receiver.bodies, body)
+		receiver.headers = append(receiver.headers, r.Header)
+		receiver.payloads = append(receiver.payloads, payload)
+		w.WriteHeader(receiver.status)
+	}))
+	return receiver
+}
+
+// newTestSubscriptionService creates a service which is allowed to post to the local receiver.
+func newTestSubscriptionService(querier repository.QuerierProvider) *Service {
+	return NewService(cache.NewTestCache(), querier, utils.NewBaseURLPolicy([]string{"127.0.0.1:*"}, true, true))
+}
+
+// testSubscription_Params are the body params of a subscription posting to webhookURL.
+func testSubscription_Params(webhookURL string) models.SubscriptionRequestBody {
+	return models.SubscriptionRequestBody{
+		BaseRequestBody: models.BaseRequestBody{
+			Username: repository.FakeUsername,
+			Password: repository.FakePassword,
+			Base:     repository.FakeBase,
+		},
+		Interval:   900,
+		WebhookURL: webhookURL,
+	}
+}
+
+// Test if Poll() posts the changes since the last check to the webhook, signed with the subscription's secret.
+func TestPoll_DeliversSignedChanges(t *testing.T) {
+	receiver := newTestSubscriptionReceiver()
+	defer receiver.Close()
+
+	querier := newTestSubscriptionQuerier()
+	service := newTestSubscriptionService(querier)
+
+	quiz := models.Assignment{Name: "Quiz", Grade: ""}
+	querier.set(testDiff_Classwork("90.00", quiz), []models.IPR{{Date: "09/06/2022"}})
+
+	subscription, err := service.Subscribe(testSubscription_Params(receiver.URL+"/hooks"), "")
+	if err != nil {
+		t.Fatalf("Failed for Subscribe():\n%v", err)
+	}
+
+	// The first check only takes a snapshot.
+	if err := service.Poll(context.Background(), subscription.ID); err != nil {
+		t.Fatalf("Failed for Poll() first check:\n%v", err)
+	}
+	if len(receiver.payloads) != 0 {
+		t.Fatalf("Failed for Poll() first check, expected no payloads, got %d", len(receiver.payloads))
+	}
+
+	// Grade the quiz and post an IPR.
+	gradedQuiz := quiz
+	gradedQuiz.Grade = "100.00"
+	ipr := models.IPR{Date: "10/04/2022"}
+	querier.set(testDiff_Classwork("100.00", gradedQuiz), []models.IPR{ipr, {Date: "09/06/2022"}})
+
+	if err := service.Poll(context.Background(), subscription.ID); err != nil {
+		t.Fatalf("Failed for Poll() second check:\n%v", err)
+	}
+	if len(receiver.payloads) != 1 {
+		t.Fatalf("Failed for Poll() second check, expected 1 payload, got %d", len(receiver.payloads))
+	}
+
+	expected := []models.ChangeEvent{
+		{Type: models.ChangeGradeChanged, MarkingPeriod: 1, Class: &testDiff_Class, Assignment: &gradedQuiz, New: "100.00"},
+		{Type: models.ChangeAverageChanged, MarkingPeriod: 1, Class: &testDiff_Class, Old: "90.00", New: "100.00"},
+		{Type: models.ChangeIPRPosted, IPR: &ipr},
+	}
+	if diff := cmp.Diff(expected, receiver.payloads[0].Changes); diff != "" {
+		t.Fatalf("Failed for Poll() changes (-want, +got)\n%s", diff)
+	}
+	if receiver.payloads[0].SubscriptionID != subscription.ID {
+		t.Fatalf("Failed for Poll(), expected subscription %s, got %s", subscription.ID, receiver.payloads[0].SubscriptionID)
+	}
+
+	// Verify the signature the way a receiver would.
+	signature := receiver.headers[0].Get(SignatureHeader)
+	timestamp, _, _ := strings.Cut(strings.TrimPrefix(signature, "t="), ",")
+	unix, _ := strconv.ParseInt(timestamp, 10, 64)
+	if signature == "" || signature != Sign(subscription.Secret, time.Unix(unix, 0), receiver.bodies[0]) {
+		t.Fatalf("Failed for Poll(), signature %q doesn't match the body", signature)
+	}
+}
+
+// Test if Poll() sends changes which couldn't be delivered again with the next check.
+func TestPoll_RedeliversFailedChanges(t *testing.T) {
+	receiver := newTestSubscriptionReceiver()
+	defer receiver.Close()
+
+	querier := newTestSubscriptionQuerier()
+	service := newTestSubscriptionService(querier)
+
+	subscription, _ := service.Subscribe(testSubscription_Params(receiver.URL), "")
+	if err := service.Poll(context.Background(), subscription.ID); err != nil {
+		t.Fatalf("Failed for Poll() first check:\n%v", err)
+	}
+
+	// Post an IPR, while the receiver rejects payloads.
+	querier.set(nil, []models.IPR{{Date: "09/06/2022"}})
+	receiver.status = http.StatusBadRequest
+
+	if err := service.Poll(context.Background(), subscription.ID); err == nil {
+		t.Fatalf("Failed for Poll() with a rejected payload, expected an error")
+	}
+
+	// The same change is sent once the receiver accepts it.
+	receiver.status = http.StatusOK
+
+	if err := service.Poll(context.Background(), subscription.ID); err != nil {
+		t.Fatalf("Failed for Poll() after a rejected payload:\n%v", err)
+	}
+	if len(receiver.payloads) != 2 || cmp.Diff(receiver.payloads[0].Changes, receiver.payloads[1].Changes) != "" {
+		t.Fatalf("Failed for Poll() after a rejected payload, expected the change to be sent again, got %+v", receiver.payloads)
+	}
+
+	// Nothing is sent once the change was delivered.
+	if err := service.Poll(context.Background(), subscription.ID); err != nil || len(receiver.payloads) != 2 {
+		t.Fatalf("Failed for Poll() after delivering, expected nothing to be sent, got %v", err)
+	}
+}
+
+// Test if Poll() ends a subscription whose session stopped working.
+func TestPoll_EndsWithInvalidSession(t *testing.T) {
+	receiver := newTestSubscriptionReceiver()
+	defer receiver.Close()
+
+	service := newTestSubscriptionService(newTestSubscriptionQuerier())
+
+	subscription, _ := service.Subscribe(testSubscription_Params(receiver.URL), "revoked-token")
+	if err := service.Poll(context.Background(), subscription.ID); err != nil {
+		t.Fatalf("Failed for Poll() with an invalid session:\n%v", err)
+	}
+
+	expected := []models.ChangeEvent{{Type: models.ChangeSubscriptionEnded, Reason: repository.ErrorInvalidSession.Error()}}
+	if len(receiver.payloads) != 1 {
+		t.Fatalf("Failed for Poll() with an invalid session, expected 1 payload, got %d", len(receiver.payloads))
+	}
+	if diff := cmp.Diff(expected, receiver.payloads[0].Changes); diff != "" {
+		t.Fatalf("Failed for Poll() with an invalid session (-want, +got)\n%s", diff)
+	}
+
+	if err := service.Unsubscribe(subscription.ID); err != repository.ErrorSubscriptionNotFound {
+		t.Fatalf("Failed for Poll() with an invalid session, expected the subscription to be removed, got %v", err)
+	}
+}
+
+// Test if Subscribe() errors out past the limits per account and overall.
+func TestSubscribe_Limits(t *testing.T) {
+	service := newTestSubscriptionService(newTestSubscriptionQuerier())
+	service.MaxSubscriptions, service.MaxAccountSubscriptions = 2, 1
+
+	params := testSubscription_Params("https://127.0.0.1/hooks")
+	if _, err := service.Subscribe(params, ""); err != nil {
+		t.Fatalf("Failed for Subscribe():\n%v", err)
+	}
+
+	// The account already has a subscription, even through a session.
+	if _, err := service.Subscribe(params, "token"); !errors.Is(err, repository.ErrorSubscriptionLimit) {
+		t.Fatalf("Failed for Subscribe() past the account limit, expected %v, got %v", repository.ErrorSubscriptionLimit, err)
+	}
+
+	// Another account can subscribe, until the overall limit.
+	params.Username = "other"
+	if _, err := service.Subscribe(params, ""); err != nil {
+		t.Fatalf("Failed for Subscribe() with another account:\n%v", err)
+	}
+	params.Username = "third"
+	if _, err := service.Subscribe(params, ""); !errors.Is(err, repository.ErrorSubscriptionLimit) {
+		t.Fatalf("Failed for Subscribe() past the overall limit, expected %v, got %v", repository.ErrorSubscriptionLimit, err)
+	}
+}
+
+// Test if webhooks aren't posted to once their host resolves to a private address.
+func TestPoll_RejectsPrivateWebhook(t *testing.T) {
+	receiver := newTestSubscriptionReceiver()
+	defer receiver.Close()
+
+	// Let the policy allow the URL, as if the host resolved to a public address when subscribing.
+	service := NewService(cache.NewTestCache(), newTestSubscriptionQuerier(), utils.NewBaseURLPolicy(nil, true, false))
+
+	subscription, _ := service.Subscribe(testSubscription_Params(receiver.URL), "revoked-token")
+	if err := service.Poll(context.Background(), subscription.ID); !errors.Is(err, utils.ErrorBasePrivateAddress) {
+		t.Fatalf("Failed for Poll() with a private webhook, expected %v, got %v", utils.ErrorBasePrivateAddress, err)
+	}
+	if len(receiver.payloads) != 0 {
+		t.Fatalf("Failed for Poll() with a private webhook, expected no payloads, got %d", len(receiver.payloads))
+	}
+}
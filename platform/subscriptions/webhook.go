@@ -0,0 +1,87 @@
+package subscriptions
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Threqt1/HACApi/app/models"
+	"github.com/Threqt1/HACApi/pkg/utils"
+)
+
+// The header webhook payloads are signed in.
+const SignatureHeader = "X-HACApi-Signature"
+
+// The amount of times a webhook delivery is attempted.
+const deliveryAttempts = 3
+
+// The delay before retrying a webhook delivery, doubled for every retry after.
+const deliveryRetryDelay = time.Second
+
+// Sign returns the signature of a webhook payload sent at timestamp, in the
+// format of SignatureHeader: "t=<unix timestamp>,v1=<hex HMAC-SHA256>". The
+// HMAC is of "<unix timestamp>.<body>", keyed with the subscription's secret.
+func Sign(secret string, timestamp time.Time, body []byte) string {
+	unix := strconv.FormatInt(timestamp.Unix(), 10)
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(unix + "."))
+	mac.Write(body)
+
+	return fmt.Sprintf("t=%s,v1=%s", unix, hex.EncodeToString(mac.Sum(nil)))
+}
+
+// deliver posts a payload to a webhook, retrying with backoff while it fails
+// with a connection error or a 5xx status code.
+func (service *Service) deliver(ctx context.Context, webhookURL, secret string, payload models.WebhookPayload) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	for attempt := 0; ; attempt++ {
+		retry, err := service.post(ctx, webhookURL, secret, body)
+		if err == nil || !retry || attempt >= deliveryAttempts-1 {
+			return err
+		}
+
+		// Wait before retrying, unless the context is done first.
+		timer := time.NewTimer(deliveryRetryDelay << attempt)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		}
+	}
+}
+
+// post makes a single delivery attempt, returning whether it is worth retrying if it failed.
+func (service *Service) post(ctx context.Context, webhookURL, secret string, body []byte) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhookURL, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(SignatureHeader, Sign(secret, service.now(), body))
+
+	// Don't retry connections the webhook URL policy refused.
+	res, err := service.Client.Do(req)
+	if err != nil {
+		return !errors.Is(err, utils.ErrorBasePrivateAddress), err
+	}
+	res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return res.StatusCode >= 500, fmt.Errorf("webhook responded with %s", res.Status)
+	}
+	return false, nil
+}